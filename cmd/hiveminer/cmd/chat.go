@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"belaykit"
+	"belaykit/claude"
+	"belaykit/codex"
+
+	"hiveminer/internal/agent"
+	"hiveminer/internal/schema"
+	"hiveminer/internal/session"
+)
+
+func cmdChat(args []string) error {
+	fs := flag.NewFlagSet("chat", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory for the run")
+	formsDir := fs.String("forms", "forms", "Directory the drafted form is saved to")
+	model := fs.String("model", "sonnet", "Model drafting the form")
+	discoveryModel := fs.String("discovery-model", "haiku", "Model for subreddit discovery")
+	useCodex := fs.Bool("codex", false, "Use Codex backend instead of Claude, for the chat and the run")
+	verbose := fs.Bool("verbose", false, "Show full agent log output")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.BoolVar(verbose, "v", false, "Verbose (shorthand)")
+	fs.Parse(args)
+
+	var client agent.Runner
+	backend := "claude"
+	if *useCodex {
+		client = codex.NewClient()
+		backend = "codex"
+		if !flagWasSet(fs, "model") {
+			*model = ""
+		}
+		if !flagWasSet(fs, "discovery-model") {
+			*discoveryModel = ""
+		}
+	} else {
+		client = claude.NewClient()
+	}
+	logger := func(name, model string) belaykit.EventHandler {
+		if !*verbose {
+			return nil
+		}
+		return belaykit.NewLogger(os.Stderr, belaykit.LogContent(true), belaykit.WithAgentName(name), belaykit.WithModelName(model))
+	}
+	prompts := os.DirFS("prompts")
+	designer := agent.NewClaudeDesigner(client, prompts, *model, logger("design", *model), backend)
+	discoverer := agent.NewClaudeDiscoverer(client, prompts, *discoveryModel, logger("discovery", *discoveryModel), backend)
+	ctx := context.Background()
+	in := bufio.NewScanner(os.Stdin)
+	ask := func(prompt string) (string, bool) {
+		fmt.Print(prompt)
+		if !in.Scan() {
+			fmt.Println()
+			return "", false
+		}
+		return strings.TrimSpace(in.Text()), true
+	}
+
+	fmt.Println("Describe what you'd like to research — what to compare, for whom, and anything to leave out.")
+	var request string
+	for request == "" {
+		line, ok := ask("> ")
+		if !ok {
+			return nil
+		}
+		request = line
+	}
+
+	var (
+		proposal *agent.RunProposal
+		feedback []string
+	)
+	for {
+		fmt.Println("Drafting a form...")
+		next, err := designer.DesignRun(ctx, request, proposal, feedback)
+		if err != nil {
+			if proposal == nil {
+				return fmt.Errorf("drafting form: %w", err)
+			}
+			fmt.Printf("Couldn't revise the draft: %v\n", err)
+			feedback = feedback[:len(feedback)-1]
+		} else {
+			proposal = next
+		}
+		if proposal.Limit <= 0 {
+			proposal.Limit = 20
+		}
+		printProposal(proposal)
+
+	choose:
+		for {
+			answer, ok := ask("\nRun it? [y]es, [d]iscover subreddits on Reddit, [q]uit, or describe what to change: ")
+			if !ok {
+				return nil
+			}
+			switch strings.ToLower(answer) {
+			case "":
+				continue
+			case "y", "yes":
+				return launchProposal(proposal, *formsDir, *outputDir, *useCodex)
+			case "q", "quit", "n", "no":
+				fmt.Println("Nothing was run.")
+				return nil
+			case "d", "discover":
+				fmt.Println("Searching Reddit for subreddits...")
+				subs, err := discoverer.DiscoverSubreddits(ctx, &proposal.Form, proposal.Query)
+				if err != nil {
+					fmt.Printf("Subreddit discovery failed: %v\n", err)
+					continue
+				}
+				proposal.Subreddits = subs
+				fmt.Printf("Subreddits: r/%s\n", strings.Join(subs, ", r/"))
+			default:
+				feedback = append(feedback, answer)
+				break choose
+			}
+		}
+	}
+}
+
+func printProposal(p *agent.RunProposal) {
+	fmt.Printf("\n%s%s%s\n", colorBold, p.Form.Title, colorReset)
+	if p.Form.Description != "" {
+		fmt.Printf("%s%s%s\n", colorDim, p.Form.Description, colorReset)
+	}
+	fmt.Println("\nFields:")
+	for _, f := range p.Form.Fields {
+		marker := ""
+		if f.Primary {
+			marker = " (primary)"
+		}
+		fmt.Printf("  %-22s %-8s %s%s\n", f.ID, f.Type, f.Question, marker)
+	}
+	for _, ex := range p.Form.Exclusions {
+		fmt.Printf("  Excluded: %s\n", ex.Description)
+	}
+	for _, line := range schema.AudienceLines(&p.Form) {
+		fmt.Printf("  %s\n", line)
+	}
+	fmt.Printf("\nQuery:      %s\n", p.Query)
+	if len(p.Subreddits) > 0 {
+		fmt.Printf("Subreddits: r/%s\n", strings.Join(p.Subreddits, ", r/"))
+	} else {
+		fmt.Println("Subreddits: discovered when the run starts")
+	}
+	budget := "no request cap"
+	if p.RequestBudget > 0 {
+		budget = fmt.Sprintf("at most %d Reddit requests", p.RequestBudget)
+	}
+	fmt.Printf("Run size:   %d threads, %s\n", p.Limit, budget)
+	if p.Notes != "" {
+		fmt.Printf("\n%s%s%s\n", colorDim, p.Notes, colorReset)
+	}
+}
+
+// launchProposal saves the drafted form and starts the run through the run
+// command, printing the equivalent command line for later runs
+func launchProposal(p *agent.RunProposal, formsDir, outputDir string, useCodex bool) error {
+	if err := os.MkdirAll(formsDir, 0755); err != nil {
+		return fmt.Errorf("creating forms directory: %w", err)
+	}
+	slug := session.FormSlug(p.Form.Title)
+	path := filepath.Join(formsDir, slug+".json")
+	for n := 2; ; n++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		path = filepath.Join(formsDir, fmt.Sprintf("%s-%d.json", slug, n))
+	}
+	data, err := json.MarshalIndent(p.Form, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling form: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing form: %w", err)
+	}
+	fmt.Printf("\nSaved the form to %s\n", path)
+
+	args := []string{"--form", path, "--query", p.Query, "--limit", strconv.Itoa(p.Limit), "-o", outputDir}
+	if len(p.Subreddits) > 0 {
+		args = append(args, "--subreddits", strings.Join(p.Subreddits, ","))
+	}
+	if p.RequestBudget > 0 {
+		args = append(args, "--request-budget", strconv.Itoa(p.RequestBudget))
+	}
+	if useCodex {
+		args = append(args, "--codex")
+	}
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = a
+		if strings.ContainsAny(a, " \"'$") {
+			quoted[i] = strconv.Quote(a)
+		}
+	}
+	fmt.Printf("Running: hiveminer run %s\n\n", strings.Join(quoted, " "))
+	return cmdRun(args)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"hiveminer/internal/orchestrator"
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// checkpointPreviewEntries bounds the entries shown at a checkpoint
+const checkpointPreviewEntries = 15
+
+// promptCheckpoint returns a checkpoint handler that previews the extracted
+// entries on the terminal and asks whether to continue, change the thread
+// limit or abort
+func promptCheckpoint(form *types.Form) func([]types.ThreadState, int) orchestrator.CheckpointDecision {
+	primaryID := schema.PrimaryField(form)
+	return func(threads []types.ThreadState, limit int) orchestrator.CheckpointDecision {
+		shown, total := 0, 0
+		fmt.Println()
+		for _, t := range threads {
+			fmt.Printf("%sr/%s · %s%s\n", colorDim, t.Subreddit, truncateTitle(t.Title, 70), colorReset)
+			if len(t.Entries) == 0 {
+				fmt.Printf("  %s(no entries)%s\n", colorYellow, colorReset)
+			}
+			for _, entry := range t.Entries {
+				total++
+				if shown >= checkpointPreviewEntries {
+					continue
+				}
+				shown++
+				fmt.Printf("  %s+%s %s%s%s\n", colorGreen, colorReset, colorBold, followEntryName(entry, primaryID), colorReset)
+				if details := followEntryDetails(entry, form, primaryID); details != "" {
+					fmt.Printf("    %s%s%s\n", colorDim, details, colorReset)
+				}
+			}
+		}
+		if total > shown {
+			fmt.Printf("  ... and %d more entries\n", total-shown)
+		}
+		fmt.Printf("\n%d entries from %d threads so far; the thread limit is %d.\n", total, len(threads), limit)
+
+		in := bufio.NewReader(os.Stdin)
+		for {
+			fmt.Print("Enter to continue, a number to change the limit, or a to abort: ")
+			line, err := in.ReadString('\n')
+			line = strings.ToLower(strings.TrimSpace(line))
+			switch {
+			case line == "" || line == "c" || line == "continue":
+				if err != nil {
+					fmt.Println("\nNo answer on stdin, continuing")
+				}
+				return orchestrator.CheckpointDecision{}
+			case line == "a" || line == "abort":
+				return orchestrator.CheckpointDecision{Abort: true}
+			}
+			if n, convErr := strconv.Atoi(line); convErr == nil && n > 0 {
+				return orchestrator.CheckpointDecision{Limit: n}
+			}
+			fmt.Printf("  Didn't understand %q\n", line)
+			if err != nil {
+				return orchestrator.CheckpointDecision{}
+			}
+		}
+	}
+}
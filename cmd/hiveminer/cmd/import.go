@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"hiveminer/internal/dump"
+)
+
+func cmdImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory the source is kept under")
+	name := fs.String("name", "", "Source name to import into (required); importing into an existing source adds to it")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+
+	fs.Usage = func() {
+		fmt.Println(`Import a Reddit data dump as a local source
+
+Usage:
+  hiveminer import --name <source> <file>... [options]
+
+Files are pushshift submission and comment archives (.zst, .gz or plain
+NDJSON) or the posts.csv and comments.csv of a Reddit data export. Run
+against the source with 'hiveminer run --source <source>'.
+
+Options:`)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *name == "" || fs.NArg() < 1 {
+		fs.Usage()
+		return fmt.Errorf("source name and dump files required")
+	}
+
+	im := dump.NewImporter()
+	for _, path := range fs.Args() {
+		posts, comments, err := im.AddFile(path)
+		if err != nil {
+			return fmt.Errorf("importing %s: %w", path, err)
+		}
+		fmt.Printf("  %s: %d posts, %d comments\n", path, posts, comments)
+	}
+
+	threads, orphaned := im.Threads()
+	if len(threads) == 0 {
+		fmt.Fprintln(os.Stderr, "No posts found; import the submissions alongside the comments")
+		return fmt.Errorf("nothing to import")
+	}
+	dir := dump.SourceDir(*outputDir, *name)
+	total, err := dump.Save(dir, threads)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %d threads into %s (%d posts in the source)\n", len(threads), dir, total)
+	if orphaned > 0 {
+		fmt.Printf("  Left out %d comments on posts that aren't in the dump\n", orphaned)
+	}
+	fmt.Printf("Run against it with: hiveminer run --form <form> --source %s\n", *name)
+	return nil
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"hiveminer/internal/kb"
+)
+
+func cmdKB(args []string) error {
+	if len(args) < 1 {
+		printKBUsage()
+		return nil
+	}
+
+	switch args[0] {
+	case "sync":
+		return cmdKBSync(args[1:])
+	case "search":
+		return cmdKBSearch(args[1:])
+	case "ls", "list":
+		return cmdKBLs(args[1:])
+	case "help", "-h", "--help":
+		printKBUsage()
+		return nil
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown kb subcommand: %s\n", args[0])
+		printKBUsage()
+		return fmt.Errorf("unknown kb subcommand: %s", args[0])
+	}
+}
+
+func printKBUsage() {
+	fmt.Println(`hiveminer kb - Knowledge base aggregated across all runs
+
+Usage:
+  hiveminer kb <command> [options]
+
+Commands:
+  sync     Rebuild the knowledge base from every run in the output directory
+  search   Find items sharing words with a description across all runs
+  ls       List the most-mentioned items, per form
+
+Examples:
+  hiveminer kb sync
+  hiveminer kb search "quiet beach towns for toddlers"
+  hiveminer kb ls --form "Family Vacation" -n 20`)
+}
+
+func cmdKBSync(args []string) error {
+	fs := flag.NewFlagSet("kb sync", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	k, runs, err := syncKB(*outputDir)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Knowledge base: %d items from %d runs\n", len(k.Items), runs)
+	return nil
+}
+
+// syncKB rebuilds and saves the knowledge base from every run in the output
+// directory, returning it and the number of runs
+func syncKB(outputDir string) (*kb.KB, int, error) {
+	infos, err := listSessions(outputDir)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var sessions []kb.Session
+	for _, s := range infos {
+		form, err := loadFormFromManifest(s.Manifest)
+		if err != nil {
+			form = deriveFormFromManifest(s.Manifest)
+		}
+		sessions = append(sessions, kb.Session{Name: s.Name, Manifest: s.Manifest, Form: form})
+	}
+
+	k := kb.Build(sessions, embedder)
+	if err := kb.Save(outputDir, k); err != nil {
+		return nil, 0, err
+	}
+	return k, len(sessions), nil
+}
+
+// loadKB loads the knowledge base, telling the user how to build it if missing
+func loadKB(outputDir string) (*kb.KB, error) {
+	k, err := kb.Load(outputDir)
+	if err != nil {
+		return nil, err
+	}
+	if k == nil || k.Embedder != embedder.Name() {
+		fmt.Fprintln(os.Stderr, "No knowledge base yet. Run 'hiveminer kb sync' first.")
+		return nil, fmt.Errorf("knowledge base not built")
+	}
+	return k, nil
+}
+
+func cmdKBSearch(args []string) error {
+	fs := flag.NewFlagSet("kb search", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	form := fs.String("form", "", "Only search items from this form title")
+	limit := fs.Int("n", 10, "Maximum number of results")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer kb search \"description\"")
+		return fmt.Errorf("search text required")
+	}
+	query := strings.Join(fs.Args(), " ")
+
+	k, err := loadKB(*outputDir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s%s%s\n\n", colorBold, query, colorReset)
+	for _, hit := range k.Search(embedder, query, *form, *limit) {
+		printKBItem(hit.Item, fmt.Sprintf("%.2f", hit.Score))
+	}
+	fmt.Println()
+	return nil
+}
+
+func cmdKBLs(args []string) error {
+	fs := flag.NewFlagSet("kb ls", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	form := fs.String("form", "", "Only list items from this form title")
+	limit := fs.Int("n", 20, "Maximum number of items (0 for all)")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	k, err := loadKB(*outputDir)
+	if err != nil {
+		return err
+	}
+
+	items := make([]kb.Item, 0, len(k.Items))
+	for _, item := range k.Items {
+		if *form == "" || strings.EqualFold(item.Form, *form) {
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if len(items[i].Sessions) != len(items[j].Sessions) {
+			return len(items[i].Sessions) > len(items[j].Sessions)
+		}
+		return items[i].Mentions > items[j].Mentions
+	})
+	if *limit > 0 && len(items) > *limit {
+		items = items[:*limit]
+	}
+
+	fmt.Printf("\n%s%s Knowledge base %s %s(%d items from %d runs)%s\n\n",
+		colorBold, colorCyan, colorReset, colorDim, len(k.Items), k.Sessions, colorReset)
+	for _, item := range items {
+		printKBItem(item, fmt.Sprintf("%d×", item.Mentions))
+	}
+	fmt.Println()
+	return nil
+}
+
+func printKBItem(item kb.Item, badge string) {
+	fmt.Printf(" %s%-6s%s %s%s%s  %s%s · %d runs · best %.0fpts%s\n",
+		colorGreen, badge, colorReset, colorBold, item.Primary, colorReset,
+		colorDim, item.Form, len(item.Sessions), item.BestScore, colorReset)
+	if len(item.Sources) > 0 {
+		fmt.Printf("        %s%s%s\n", colorDim, hyperlink(item.Sources[0], item.Sources[0]), colorReset)
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+
+	"hiveminer/internal/orchestrator"
+)
+
+// pipelineFlags maps phase parameters to the run flags they set. Flags given
+// on the command line win over the pipeline file.
+var pipelineFlags = []struct{ phase, param, flag string }{
+	{orchestrator.PhaseThreadDiscovery, "limit", "limit"},
+	{orchestrator.PhaseThreadDiscovery, "target_entries", "target-entries"},
+	{orchestrator.PhaseThreadDiscovery, "sort", "sort"},
+	{orchestrator.PhaseThreadDiscovery, "rounds", "rounds"},
+	{orchestrator.PhaseThreadDiscovery, "overprovision", "overprovision"},
+	{orchestrator.PhaseEvaluate, "workers", "workers"},
+	{orchestrator.PhaseEvaluate, "mode", "eval-mode"},
+	{orchestrator.PhaseExtract, "workers", "workers"},
+	{orchestrator.PhaseExtract, "chunk_size", "chunk-size"},
+	{orchestrator.PhaseExtract, "context_budget", "context-budget"},
+	{orchestrator.PhaseExtract, "truncation", "truncation"},
+	{orchestrator.PhaseExtract, "max_entries", "max-entries"},
+	{orchestrator.PhaseExtract, "decompose", "decompose"},
+	{orchestrator.PhaseExtract, "extractor", "extractor"},
+	{orchestrator.PhaseGeocode, "provider", "geocode"},
+	{orchestrator.PhaseEnrich, "config", "enrich"},
+	{orchestrator.PhaseRank, "tournament", "tournament"},
+	{orchestrator.PhaseRank, "normalize", "normalize-scores"},
+	{orchestrator.PhaseRank, "batch_size", "rank-batch"},
+	{orchestrator.PhaseRank, "workers", "rank-workers"},
+	{orchestrator.PhaseRank, "ranker", "ranker"},
+	{orchestrator.PhaseRank, "fallback", "rank-fallback"},
+}
+
+// codexModels are the models used for each model flag on the Codex backend
+// unless one is given ("" is the Codex CLI default)
+var codexModels = map[string]string{
+	"discovery-model": "",
+	"eval-model":      "",
+	"extract-model":   "gpt-5.1-codex-mini",
+	"rank-model":      "gpt-5.1-codex-mini",
+	"verify-model":    "",
+}
+
+// applyPipelineParams sets run flags from the pipeline's phase parameters,
+// leaving flags given explicitly alone
+func applyPipelineParams(fs *flag.FlagSet, p *orchestrator.Pipeline, explicit map[string]bool) error {
+	for _, pf := range pipelineFlags {
+		phase := p.Phase(pf.phase)
+		if phase == nil || explicit[pf.flag] {
+			continue
+		}
+		value, ok := phase.Params[pf.param]
+		if !ok {
+			continue
+		}
+		if err := fs.Set(pf.flag, fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("phase %s: parameter %s: %w", pf.phase, pf.param, err)
+		}
+	}
+	return nil
+}
+
+// pipelineBackend returns the backend a phase runs on
+func pipelineBackend(p *orchestrator.Pipeline, phase, fallback string) string {
+	if p != nil {
+		if pc := p.Phase(phase); pc != nil && pc.Backend != "" {
+			return pc.Backend
+		}
+	}
+	return fallback
+}
+
+// pipelineModel returns the model a phase's agent uses: the pipeline's, else
+// the flag if given, else the flag's default for the phase's backend
+func pipelineModel(fs *flag.FlagSet, p *orchestrator.Pipeline, phase, flagName, backend string, explicit map[string]bool) string {
+	if p != nil {
+		if pc := p.Phase(phase); pc != nil && pc.Model != "" {
+			return pc.Model
+		}
+	}
+	f := fs.Lookup(flagName)
+	if explicit[flagName] {
+		return f.Value.String()
+	}
+	if backend == "codex" {
+		return codexModels[flagName]
+	}
+	return f.DefValue
+}
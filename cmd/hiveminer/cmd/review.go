@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"hiveminer/internal/orchestrator"
+)
+
+// promptSubreddits asks on the terminal which discovered subreddits to search.
+// Enter accepts the list; "-name" drops and "+name" adds a subreddit, and any
+// other line replaces the list.
+func promptSubreddits(discovered []string) ([]string, error) {
+	subs := slices.Clone(discovered)
+	in := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Printf("\nSubreddits to search (%d):\n", len(subs))
+		for _, name := range subs {
+			fmt.Printf("  r/%s\n", name)
+		}
+		fmt.Print("Enter to accept, -name to remove, +name to add, or a new comma-separated list: ")
+		line, err := in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if err != nil && len(subs) == 0 {
+				return nil, fmt.Errorf("reading subreddit review: %w", err)
+			}
+			return subs, nil
+		}
+		edits := strings.Fields(strings.ReplaceAll(line, ",", " "))
+		if !strings.HasPrefix(edits[0], "+") && !strings.HasPrefix(edits[0], "-") {
+			subs = orchestrator.ParseSubredditList(line)
+			continue
+		}
+		for _, edit := range edits {
+			name := strings.TrimPrefix(edit[1:], "r/")
+			if name == "" {
+				continue
+			}
+			switch edit[0] {
+			case '+':
+				if !slices.Contains(subs, name) {
+					subs = append(subs, name)
+				}
+			case '-':
+				subs = slices.DeleteFunc(subs, func(s string) bool { return strings.EqualFold(s, name) })
+			default:
+				fmt.Printf("  Ignoring %q: prefix each edit with + or -\n", edit)
+			}
+		}
+		if err != nil {
+			return subs, nil
+		}
+	}
+}
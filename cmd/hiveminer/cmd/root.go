@@ -11,6 +11,8 @@ func Execute(args []string) error {
 	switch args[0] {
 	case "run":
 		return cmdRun(args[1:])
+	case "chat":
+		return cmdChat(args[1:])
 	case "runs":
 		return cmdRuns(args[1:])
 	case "search":
@@ -19,6 +21,14 @@ func Execute(args []string) error {
 		return cmdLs(args[1:])
 	case "thread":
 		return cmdThread(args[1:])
+	case "wiki":
+		return cmdWiki(args[1:])
+	case "user":
+		return cmdUser(args[1:])
+	case "kb":
+		return cmdKB(args[1:])
+	case "import":
+		return cmdImport(args[1:])
 	case "help", "-h", "--help":
 		printUsage()
 		return nil
@@ -37,10 +47,15 @@ Usage:
 
 Commands:
   run      Run an extraction pipeline
+  chat     Describe what to research and set up a run interactively
   runs     View extraction runs and results
   search   Search Reddit posts
   ls       List posts from a subreddit
   thread   View or export thread comments
+  wiki     View subreddit wiki pages and sidebars
+  user     List a user's posts or comments
+  kb       Search the knowledge base aggregated across all runs
+  import   Import a Reddit data dump as a local source for runs
 
 Run 'hiveminer <command> --help' for details on a specific command.`)
 }
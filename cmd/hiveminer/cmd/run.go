@@ -2,12 +2,17 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"belaykit"
 	"belaykit/claude"
@@ -15,9 +20,20 @@ import (
 	"belaykit/providers/belay"
 
 	"hiveminer/internal/agent"
+	"hiveminer/internal/control"
+	"hiveminer/internal/dump"
+	"hiveminer/internal/enrich"
+	"hiveminer/internal/geocode"
+	"hiveminer/internal/meter"
 	"hiveminer/internal/orchestrator"
+	"hiveminer/internal/ratelimit"
+	"hiveminer/internal/reviews"
 	"hiveminer/internal/schema"
 	"hiveminer/internal/search"
+	"hiveminer/internal/session"
+	"hiveminer/internal/transcripts"
+	"hiveminer/internal/webpage"
+	"hiveminer/pkg/agents"
 )
 
 type tracedRunner struct {
@@ -34,44 +50,146 @@ func (r tracedRunner) Run(ctx context.Context, prompt string, opts ...belaykit.R
 
 func cmdRun(args []string) error {
 	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	newSearcher := addTransportFlags(fs)
 	formPath := fs.String("form", "", "Path to form JSON file (required)")
 	query := fs.String("query", "", "Search query")
 	subreddits := fs.String("subreddits", "", "Comma-separated list of subreddits")
+	user := fs.String("user", "", "Mine a Reddit user's post/comment history instead of discovering threads")
+	source := fs.String("source", "", "Run against a dump imported with 'hiveminer import' instead of Reddit")
 	limit := fs.Int("limit", 20, "Maximum number of threads to process")
+	targetEntries := fs.Int("target-entries", 0, "Stop once this many entries are extracted, discovering threads by evaluation's entry estimates (0 = stop at --limit threads)")
 	sort := fs.String("sort", "hot", "Sort method for subreddit listing: hot, new, top, rising")
+	rounds := fs.Int("rounds", 3, "Thread discovery rounds before giving up on the limit")
+	overprovision := fs.Float64("overprovision", 0, "Threads to discover per thread wanted, e.g. 2.5 (0 = adapt to the observed keep rate, starting at 3)")
 	outputDir := fs.String("output", "./output", "Output directory for session")
+	resume := fs.String("session", "", "Resume/refresh an existing session (run ID or path)")
+	name := fs.String("name", "", "Name the new session's directory instead of deriving it from the query, e.g. gear-under-100")
 	workers := fs.Int("workers", 10, "Concurrent extraction workers")
 	discoveryModel := fs.String("discovery-model", "sonnet", "Model for phases 0+1 (subreddit/thread discovery)")
 	evalModel := fs.String("eval-model", "sonnet", "Model for phase 2 (thread evaluation)")
+	evalMode := fs.String("eval-mode", "agent", "Thread evaluation: agent (reads the thread with tools), lite (one prompt on the title, flair, post excerpt and top 3 comments) or a registered evaluator")
+	extractorName := fs.String("extractor", "agent", "Extractor: agent, or one registered by a package built into the binary")
+	rankerName := fs.String("ranker", "agent", "Ranker: agent, or one registered by a package built into the binary")
+	agentParams := fs.String("agent-params", "", "Settings for registered agents as comma-separated key=value pairs")
 	extractModel := fs.String("extract-model", "haiku", "Model for phase 3 (field extraction)")
 	rankModel := fs.String("rank-model", "haiku", "Model for phase 4 (entry ranking)")
+	verifyModel := fs.String("verify-model", "sonnet", "Model for the verify phase (checking entries against their threads)")
 	fs.StringVar(query, "q", "", "Search query (shorthand)")
 	fs.StringVar(subreddits, "r", "", "Subreddits (shorthand)")
 	fs.IntVar(limit, "l", 20, "Limit (shorthand)")
 	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
 	useCodex := fs.Bool("codex", false, "Use Codex backend instead of Claude")
+	pipelinePath := fs.String("pipeline", "", "Pipeline file (YAML) choosing the phases to run and their backend, model and parameters")
+	mineWiki := fs.Bool("wiki", false, "Also extract from subreddit wiki pages and sidebars")
+	reviewRefs := fs.String("reviews", "", "Also extract from these comma-separated product reviews: Amazon/Google Maps URLs, amazon:<ASIN> or google:<place_id>")
+	reviewPages := fs.Int("review-pages", 5, "Amazon review pages (10 reviews each) fetched per product")
+	mediaURLs := fs.String("transcripts", "", "Also extract from the transcripts of these comma-separated podcast/video URLs")
+	webPages := fs.String("web", "", "Also extract from these comma-separated article URLs (listicles, buying guides)")
+	transcribe := fs.String("transcribe", "", "Transcribe media without captions: "+strings.Join(transcripts.Transcribers, ", ")+" (default: captions only)")
+	noSkipList := fs.Bool("no-skiplist", false, "Rediscover threads rejected for this form in earlier sessions")
+	commentBudget := fs.Int("comment-budget", 1000, "Max comments fetched per megathread (500+ comments)")
+	chunkSize := fs.Int("chunk-size", 200, "Max comments per extraction call before a thread is chunked")
+	contextBudget := fs.Int("context-budget", 120000, "Max comment characters per extraction call before the thread is summarized")
+	truncation := fs.String("truncation", "score", "Comment truncation: flat, score, or score:top=N,high=N,min=N,depth=N")
+	commentSort := fs.String("comment-sort", "", "Comment sort threads are fetched with: top, best, new, controversial, old, qa (default: the form's comment_sort)")
+	maxSessionMB := fs.Int("max-session-mb", 0, "Stop collecting new threads once the session exceeds this many MB (0 = unlimited)")
+	maxThreadMB := fs.Int("max-thread-mb", 10, "Trim thread payloads larger than this many MB to their top comment trees (0 = unlimited)")
+	requestBudget := fs.Int("request-budget", 0, "Max Reddit requests for the run, including agents' (0 = unlimited)")
+	requestDelay := fs.Duration("request-delay", 0, "Minimum delay between Reddit requests, e.g. 1s")
+	requestsPerMinute := fs.Int("requests-per-minute", 60, "Max Reddit requests per minute, spread evenly (0 = no cap)")
+	feed := fs.Bool("feed", false, "Publish newly extracted high-scoring entries to the form's RSS/JSON feed")
+	feedMinScore := fs.Float64("feed-min-score", 60, "Minimum rank score for feed entries")
+	email := fs.String("email", "", "Email a digest of the top entries and changes to these comma-separated recipients")
+	digestSize := fs.Int("digest-size", 10, "Number of top entries in the email digest")
+	buildIdx := fs.Bool("index", false, "Build the session's word-overlap search index after the run")
+	normalizeScores := fs.String("normalize-scores", "none", "Rescale rank scores across the session: "+strings.Join(orchestrator.ScoreNormalizations, ", "))
+	rankBatch := fs.Int("rank-batch", 40, "Entries per ranking assessment call (0 = all entries in one call)")
+	rankFallback := fs.String("rank-fallback", "", "Comma-separated models to retry a ranking batch with when the rank model keeps failing, e.g. sonnet,opus")
+	rankWorkers := fs.Int("rank-workers", 4, "Ranking assessment batches run concurrently")
+	tournament := fs.Int("tournament", 0, "Reorder the top N entries by head-to-head LLM comparison, e.g. 30 (0 = off)")
+	maxEntries := fs.Int("max-entries", 0, "Cap entries per thread, overriding the form's entry_cap (0 = form's, default 25)")
+	maxAttempts := fs.Int("max-attempts", 3, "Quarantine threads after this many failed attempts across runs (0 = retry forever)")
+	geocodeProvider := fs.String("geocode", "", "Geocode the form's location fields: "+strings.Join(geocode.Providers, ", ")+" (default off)")
+	enrichConfig := fs.String("enrich", "", "Check product entries' price and availability against the retailer APIs in this config file (JSON)")
+	consensus := fs.Bool("consensus", false, "Count commenters endorsing vs warning against each entry and use it in ranking")
+	verify := fs.Bool("verify", false, "Have a second model check each entry against its thread before ranking, penalizing unsupported entries and jokes")
+	costTicker := fs.Duration("cost-ticker", 30*time.Second, "Print running token and estimated cost totals this often during the run (0 disables)")
+	decompose := fs.Int("decompose", 0, "Extract each group of related fields in its own prompt: the form's field groups, with ungrouped fields in chunks of this many (0 = one prompt)")
+	ensemble := fs.String("ensemble", "", "Comma-separated extra models to extract each thread with alongside --extract-model, recording their agreement per field")
+	reviewSubs := fs.Bool("review-subreddits", false, "Confirm or edit discovered subreddits at a prompt before searching them")
+	checkpointAt := fs.Int("checkpoint", 0, "Pause after this many threads are extracted to preview entries and continue, change the limit or abort (0 = off)")
+	subredditReview := fs.String("subreddit-review", "", "Write discovered subreddits to this file and stop; edit it, then resume the session to search them")
 	verbose := fs.Bool("verbose", false, "Show full agent log output")
 	fs.BoolVar(verbose, "v", false, "Verbose (shorthand)")
 
 	fs.Parse(args)
 
-	// When using codex, switch to codex-appropriate model defaults unless explicitly set
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	defaultBackend := "claude"
 	if *useCodex {
-		explicit := map[string]bool{}
-		fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
-		if !explicit["discovery-model"] {
-			*discoveryModel = "" // codex CLI default
+		defaultBackend = "codex"
+	}
+
+	// A resumed session (or one created by runs clone) supplies its form,
+	// topic and recorded settings for anything not given on the command line
+	var resumeDir string
+	var previousSettings map[string]string
+	var sessionName string
+	if *name != "" {
+		if *resume != "" {
+			return fmt.Errorf("--name names a new session; rename an existing one with 'hiveminer runs rename'")
+		}
+		n, err := newSessionName(*outputDir, *name)
+		if err != nil {
+			return err
+		}
+		sessionName = n
+	}
+	if *resume != "" {
+		dir, manifest, err := loadSession(*outputDir, *resume)
+		if err != nil {
+			return err
+		}
+		resumeDir = dir
+		previousSettings = manifest.Settings
+		if err := applySessionSettings(fs, manifest.Settings, explicit); err != nil {
+			return err
 		}
-		if !explicit["eval-model"] {
-			*evalModel = "" // codex CLI default
+		if *formPath == "" {
+			*formPath = manifest.Form.Path
 		}
-		if !explicit["extract-model"] {
-			*extractModel = "gpt-5.1-codex-mini"
+		if *query == "" && *subreddits == "" && *user == "" {
+			*query = manifest.Query
+			if !manifest.DiscoveredSubreddits {
+				*subreddits = strings.Join(manifest.Subreddits, ",")
+			}
+		}
+	}
+
+	var pipeline *orchestrator.Pipeline
+	if *pipelinePath != "" {
+		p, err := orchestrator.LoadPipeline(*pipelinePath)
+		if err != nil {
+			return err
 		}
-		if !explicit["rank-model"] {
-			*rankModel = "gpt-5.1-codex-mini"
+		if err := applyPipelineParams(fs, p, explicit); err != nil {
+			return err
 		}
+		pipeline = p
+	}
+	backendFor := func(phase string) string {
+		return pipelineBackend(pipeline, phase, defaultBackend)
+	}
+	modelFor := func(phase, flagName string) string {
+		return pipelineModel(fs, pipeline, phase, flagName, backendFor(phase), explicit)
 	}
+	*discoveryModel = modelFor(orchestrator.PhaseSubredditDiscovery, "discovery-model")
+	threadModel := modelFor(orchestrator.PhaseThreadDiscovery, "discovery-model")
+	*evalModel = modelFor(orchestrator.PhaseEvaluate, "eval-model")
+	*extractModel = modelFor(orchestrator.PhaseExtract, "extract-model")
+	*rankModel = modelFor(orchestrator.PhaseRank, "rank-model")
+	*verifyModel = modelFor(orchestrator.PhaseVerify, "verify-model")
 
 	if *formPath == "" {
 		fmt.Fprintln(os.Stderr, "Error: --form is required")
@@ -85,9 +203,61 @@ func cmdRun(args []string) error {
 		fmt.Fprintf(os.Stderr, "Error loading form: %v\n", err)
 		return err
 	}
+	if !schema.IsValidCommentSort(*commentSort) {
+		return fmt.Errorf("invalid --comment-sort %q", *commentSort)
+	}
+	truncationStrategy, err := search.ParseTruncation(*truncation)
+	if err != nil {
+		return fmt.Errorf("invalid --truncation: %w", err)
+	}
+	if !slices.Contains(orchestrator.ScoreNormalizations, *normalizeScores) {
+		return fmt.Errorf("invalid --normalize-scores %q (use %s)", *normalizeScores, strings.Join(orchestrator.ScoreNormalizations, ", "))
+	}
+	var resolvers []enrich.Resolver
+	if *enrichConfig != "" {
+		rc, err := enrich.LoadRetailerConfig(*enrichConfig)
+		if err != nil {
+			return err
+		}
+		resolvers = append(resolvers, enrich.NewRetailerResolver(rc))
+	}
+	var productReviews []string
+	for _, ref := range strings.Split(*reviewRefs, ",") {
+		if ref = strings.TrimSpace(ref); ref == "" {
+			continue
+		}
+		if _, err := reviews.ParseRef(ref); err != nil {
+			return fmt.Errorf("invalid --reviews: %w", err)
+		}
+		productReviews = append(productReviews, ref)
+	}
+	var media []string
+	for _, u := range strings.Split(*mediaURLs, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			media = append(media, u)
+		}
+	}
+	var pages []string
+	for _, u := range strings.Split(*webPages, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			pages = append(pages, u)
+		}
+	}
+	var transcriber transcripts.Transcriber
+	if *transcribe != "" {
+		if transcriber, err = transcripts.New(*transcribe); err != nil {
+			return fmt.Errorf("invalid --transcribe: %w", err)
+		}
+	}
+	var geocoder geocode.Geocoder
+	if *geocodeProvider != "" {
+		if geocoder, err = geocode.New(*geocodeProvider); err != nil {
+			return fmt.Errorf("invalid --geocode: %w", err)
+		}
+	}
 
 	// Infer query from form if not provided
-	if *query == "" && *subreddits == "" {
+	if *query == "" && *subreddits == "" && *user == "" {
 		if len(form.SearchHints) > 0 {
 			*query = form.SearchHints[0]
 		} else {
@@ -118,23 +288,28 @@ func cmdRun(args []string) error {
 		cancel()
 	}()
 
-	// Create shared client and prompt filesystem
-	var client agent.Runner
+	// Create a shared client per backend, and the prompt filesystem
 	var bp *belay.Provider
 	var traceID string
 	var belayHandler belaykit.EventHandler
-	backend := "claude"
-	if *useCodex {
-		client = codex.NewClient()
-		backend = "codex"
-	} else {
-		bp = belay.NewProvider(belay.WithPricing(claude.PricingForModel(*discoveryModel)), belay.WithContextWindow(200_000))
-		client = claude.NewClient(claude.WithObservability(bp))
-		traceID = bp.StartTrace(belaykit.TraceConfig{Name: form.Title}, nil)
-		belayHandler = bp.EventHandler()
-		client = tracedRunner{base: client, traceID: traceID}
+	clients := map[string]agent.Runner{}
+	clientFor := func(backend string) agent.Runner {
+		if client, ok := clients[backend]; ok {
+			return client
+		}
+		var client agent.Runner
+		if backend == "codex" {
+			client = codex.NewClient()
+		} else {
+			bp = belay.NewProvider(belay.WithPricing(claude.PricingForModel(*discoveryModel)), belay.WithContextWindow(200_000))
+			traceID = bp.StartTrace(belaykit.TraceConfig{Name: form.Title}, nil)
+			belayHandler = bp.EventHandler()
+			client = tracedRunner{base: claude.NewClient(claude.WithObservability(bp)), traceID: traceID}
+		}
+		clients[backend] = client
+		return client
 	}
-	agentLogger := func(name, model string) belaykit.EventHandler {
+	agentLogger := func(name, model, backend string) belaykit.EventHandler {
 		logOpts := []belaykit.LoggerOption{
 			belaykit.LogTokens(true),
 			belaykit.LogContent(*verbose),
@@ -148,60 +323,282 @@ func cmdRun(args []string) error {
 			)
 		}
 		logger := belaykit.NewLogger(os.Stderr, logOpts...)
-		if bp == nil {
-			return logger
-		}
 		return func(e belaykit.Event) {
 			logger(e)
-			belayHandler(e)
+			if belayHandler != nil {
+				belayHandler(e)
+			}
+		}
+	}
+	// Meter every agent call for the cost ticker, run log and summary; the
+	// Claude CLI reports spend, other calls are priced from token estimates
+	usage := meter.New(func(backend, model string) *belaykit.ModelPricing {
+		if backend == "codex" {
+			return nil
 		}
+		p := claude.PricingForModel(model)
+		return &p
+	})
+	runnerFor := func(name, backend string) agent.Runner {
+		return usage.Runner(clientFor(backend), name, backend)
 	}
 	prompts := os.DirFS("prompts")
 
 	// Create orchestrator with agentic phases
-	searcher := search.NewRedditSearcher()
+	// Exported so hiveminer subprocesses started by agents are bound too
+	if *requestBudget > 0 {
+		os.Setenv("HIVEMINER_REQUEST_BUDGET", strconv.Itoa(*requestBudget))
+	}
+	if *requestDelay > 0 {
+		os.Setenv("HIVEMINER_REQUEST_DELAY", requestDelay.String())
+	}
+	if flagWasSet(fs, "requests-per-minute") {
+		if *requestsPerMinute < 0 {
+			return fmt.Errorf("--requests-per-minute must not be negative")
+		}
+		os.Setenv("HIVEMINER_REQUESTS_PER_MINUTE", strconv.Itoa(*requestsPerMinute))
+	}
+	var searcher search.Searcher
+	if *source != "" {
+		// Exported so agents' hiveminer subprocesses read the dump too
+		dir, err := filepath.Abs(dump.SourceDir(*outputDir, *source))
+		if err != nil {
+			return err
+		}
+		src, err := dump.Open(dir)
+		if err != nil {
+			return fmt.Errorf("opening source %q: %w", *source, err)
+		}
+		os.Setenv("HIVEMINER_SOURCE", dir)
+		fmt.Printf("Source: %s (%d imported posts)\n", *source, src.Len())
+		searcher = src
+	} else {
+		rs, err := newSearcher()
+		if err != nil {
+			return err
+		}
+		searcher = rs
+	}
 	orch := orchestrator.New(searcher)
-	orch.SetDiscoverer(agent.NewClaudeDiscoverer(client, prompts, *discoveryModel, agentLogger("discovery", *discoveryModel), backend))
-	orch.SetThreadDiscoverer(agent.NewClaudeThreadDiscoverer(client, prompts, *discoveryModel, agentLogger("threads", *discoveryModel), backend))
-	orch.SetThreadEvaluator(agent.NewClaudeEvaluator(client, prompts, *evalModel, agentLogger("eval", *evalModel), backend))
-	orch.SetExtractor(agent.NewClaudeExtractor(client, prompts, *extractModel, agentLogger("extract", *extractModel), backend))
-	orch.SetRanker(agent.NewClaudeRanker(client, prompts, *rankModel, agentLogger("rank", *rankModel), backend))
+	b := backendFor(orchestrator.PhaseSubredditDiscovery)
+	orch.SetDiscoverer(agent.NewClaudeDiscoverer(runnerFor("discovery", b), prompts, *discoveryModel, agentLogger("discovery", *discoveryModel, b), b))
+	b = backendFor(orchestrator.PhaseThreadDiscovery)
+	orch.SetThreadDiscoverer(agent.NewClaudeThreadDiscoverer(runnerFor("threads", b), prompts, threadModel, agentLogger("threads", threadModel, b), b))
+	params, err := parseAgentParams(*agentParams)
+	if err != nil {
+		return err
+	}
+	agentConfig := func(name, phase, model string) agents.Config {
+		b := backendFor(phase)
+		return agents.Config{
+			Runner:  runnerFor(name, b),
+			Prompts: prompts,
+			Model:   model,
+			Logger:  agentLogger(name, model, b),
+			Backend: b,
+			Fetcher: searcher,
+			Params:  params,
+		}
+	}
+	evaluator, err := agents.NewEvaluator(*evalMode, agentConfig("eval", orchestrator.PhaseEvaluate, *evalModel))
+	if err != nil {
+		return fmt.Errorf("--eval-mode: %w", err)
+	}
+	if e, ok := evaluator.(*agent.ClaudeEvaluator); ok {
+		e.SetCommentSort(*commentSort)
+	}
+	orch.SetThreadEvaluator(evaluator)
+	b = backendFor(orchestrator.PhaseExtract)
+	extraction, err := agents.NewExtractor(*extractorName, agentConfig("extract", orchestrator.PhaseExtract, *extractModel))
+	if err != nil {
+		return fmt.Errorf("--extractor: %w", err)
+	}
+	extractor, builtin := extraction.(*agent.ClaudeExtractor)
+	if builtin {
+		extractor.SetConsensus(*consensus)
+	}
+	if models := splitList(*ensemble); len(models) > 0 {
+		if !builtin {
+			return fmt.Errorf("--ensemble needs the agent extractor, not %q", *extractorName)
+		}
+		members := []*agent.ClaudeExtractor{extractor}
+		for _, model := range models {
+			member := agent.NewClaudeExtractor(runnerFor("extract", b), prompts, model, agentLogger("extract", model, b), b)
+			member.SetConsensus(*consensus)
+			members = append(members, member)
+		}
+		fmt.Printf("Ensemble extraction: %s, %s\n", *extractModel, strings.Join(models, ", "))
+		extraction = agent.NewEnsembleExtractor(members...)
+	}
+	if *decompose > 0 {
+		if groups := schema.FieldGroups(form, *decompose); len(groups) > 1 {
+			streaming, ok := extraction.(agent.StreamingExtractor)
+			if !ok {
+				return fmt.Errorf("--decompose needs an extractor that streams its output, which %q doesn't", *extractorName)
+			}
+			fmt.Printf("Decomposed extraction: %d prompts per thread\n", len(groups))
+			extraction = agent.NewDecomposedExtractor(streaming, *decompose)
+		}
+	}
+	orch.SetExtractor(extraction)
+	orch.SetSummarizer(agent.NewClaudeSummarizer(runnerFor("summarize", b), prompts, *extractModel, agentLogger("summarize", *extractModel, b), b))
+	if *verify || pipeline != nil && pipeline.Phase(orchestrator.PhaseVerify) != nil {
+		b = backendFor(orchestrator.PhaseVerify)
+		orch.SetVerifier(agent.NewClaudeVerifier(runnerFor("verify", b), prompts, *verifyModel, agentLogger("verify", *verifyModel, b), b))
+	}
+	ranker, err := agents.NewRanker(*rankerName, agentConfig("rank", orchestrator.PhaseRank, *rankModel))
+	if err != nil {
+		return fmt.Errorf("--ranker: %w", err)
+	}
+	if r, ok := ranker.(*agent.ClaudeRanker); ok {
+		r.SetBatchSize(*rankBatch)
+		r.SetWorkers(*rankWorkers)
+		r.SetFallbackModels(splitList(*rankFallback))
+	}
+	orch.SetRanker(ranker)
+	if geocoder != nil {
+		orch.SetGeocoder(geocoder)
+	}
+	politeness, err := ratelimit.ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+	limiter := ratelimit.NewLimiter(politeness)
+	reviewClient := reviews.NewClient(*reviewPages)
+	reviewClient.SetLimiter(limiter)
+	orch.SetReviewFetcher(reviewClient)
+	transcriptClient := transcripts.NewClient(transcriber)
+	transcriptClient.SetLimiter(limiter)
+	orch.SetTranscriptFetcher(transcriptClient)
+	pageClient := webpage.NewClient()
+	pageClient.SetLimiter(limiter)
+	orch.SetPageFetcher(pageClient)
+	orch.SetResolvers(resolvers)
 
 	// Run extraction
 	config := orchestrator.RunConfig{
-		FormPath:       *formPath,
-		Form:           form,
-		Query:          *query,
-		Subreddits:     subs,
-		Limit:          *limit,
-		Sort:           *sort,
-		OutputDir:      *outputDir,
-		Workers:        *workers,
-		DiscoveryModel: *discoveryModel,
-		EvalModel:      *evalModel,
-		ExtractModel:   *extractModel,
-		RankModel:      *rankModel,
+		FormPath:            *formPath,
+		Form:                form,
+		Query:               *query,
+		Subreddits:          subs,
+		User:                strings.TrimPrefix(*user, "u/"),
+		Limit:               *limit,
+		TargetEntries:       *targetEntries,
+		Sort:                *sort,
+		OutputDir:           *outputDir,
+		SessionDir:          resumeDir,
+		Name:                sessionName,
+		Workers:             *workers,
+		DiscoveryModel:      *discoveryModel,
+		EvalModel:           *evalModel,
+		ExtractModel:        *extractModel,
+		RankModel:           *rankModel,
+		MineWiki:            *mineWiki,
+		Reviews:             productReviews,
+		Transcripts:         media,
+		WebPages:            pages,
+		IgnoreSkipList:      *noSkipList,
+		CommentBudget:       *commentBudget,
+		CommentSort:         *commentSort,
+		ContextBudget:       *contextBudget,
+		Truncation:          truncationStrategy,
+		ChunkSize:           *chunkSize,
+		MaxSessionBytes:     int64(*maxSessionMB) << 20,
+		MaxThreadBytes:      int64(*maxThreadMB) << 20,
+		NormalizeScores:     *normalizeScores,
+		TournamentSize:      *tournament,
+		MaxEntries:          *maxEntries,
+		MaxAttempts:         *maxAttempts,
+		DiscoveryRounds:     *rounds,
+		Overprovision:       *overprovision,
+		Settings:            sessionSettings(fs, previousSettings, explicit),
+		SubredditReviewFile: *subredditReview,
+		Control:             control.New(cancel),
 		OnPhaseStart: func(phaseName string) {
 			if belayHandler != nil {
 				belayHandler(belaykit.Event{Type: belaykit.EventPhase, PhaseName: phaseName})
 			}
 		},
 	}
+	if *reviewSubs {
+		config.ReviewSubreddits = promptSubreddits
+	}
+	if *checkpointAt > 0 {
+		config.Checkpoint = *checkpointAt
+		config.OnCheckpoint = promptCheckpoint(form)
+	}
+	if pipeline != nil {
+		config.Pipeline = pipeline
+	}
+	config.Cost = func() float64 { return usage.Total().CostUSD }
+	config.LLMCalls = func() int { return usage.Total().Calls }
+
+	config.SourceRequests = limiter.Counts
 
+	go usage.Ticker(ctx, os.Stdout, *costTicker)
 	sessionDir, err := orch.Run(ctx, config)
+	if total := usage.Total(); total.Calls > 0 {
+		fmt.Printf("\nAgent usage: %d calls, %s\n", total.Calls, total)
+		for _, line := range usage.Lines() {
+			fmt.Printf("  %s\n", line)
+		}
+	}
 
 	if bp != nil {
 		bp.EndTrace(traceID, nil)
 	}
+	if errors.Is(err, orchestrator.ErrAwaitingReview) {
+		fmt.Printf("\nDiscovered subreddits written to %s\n", *subredditReview)
+		fmt.Printf("Edit the list, then continue with: hiveminer run --session %s\n", sessionDir)
+		return nil
+	}
 	if err != nil {
 		if ctx.Err() == context.Canceled {
-			fmt.Println("Session saved. Run again to resume.")
+			if sessionDir != "" {
+				fmt.Printf("Session saved. Resume with --session %s\n", sessionDir)
+			} else {
+				fmt.Println("Session saved. Run again to resume.")
+			}
 			return nil
 		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return err
 	}
 
+	if *buildIdx || *feed || *email != "" {
+		manifest, err := session.LoadManifest(sessionDir)
+		if err == nil && manifest != nil {
+			if *buildIdx {
+				if err := buildIndex(sessionDir, manifest); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: building index failed: %v\n", err)
+				}
+			}
+			if *feed {
+				if err := publishFeed(*outputDir, manifest, *feedMinScore, true); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: publishing feed failed: %v\n", err)
+				}
+			}
+			if *email != "" {
+				if err := sendDigest(sessionDir, manifest, splitList(*email), *digestSize); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: sending digest failed: %v\n", err)
+				}
+			}
+		}
+	}
+
 	// Automatically show results
 	return cmdRunsShow([]string{sessionDir})
 }
+
+// parseAgentParams parses --agent-params "key=value,key=value"
+func parseAgentParams(s string) (map[string]string, error) {
+	params := map[string]string{}
+	for _, pair := range splitList(s) {
+		key, value, ok := strings.Cut(pair, "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			return nil, fmt.Errorf("--agent-params: %q isn't key=value", pair)
+		}
+		params[key] = strings.TrimSpace(value)
+	}
+	return params, nil
+}
@@ -1,15 +1,23 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"hiveminer/internal/export"
+	"hiveminer/internal/geocode"
+	"hiveminer/internal/orchestrator"
+	"hiveminer/internal/schema"
 	"hiveminer/internal/session"
 	"hiveminer/pkg/types"
 )
@@ -39,6 +47,46 @@ func cmdRuns(args []string) error {
 		return cmdRunsLs(args[1:])
 	case "show":
 		return cmdRunsShow(args[1:])
+	case "pin":
+		return cmdRunsPin(args[1:])
+	case "unpin":
+		return cmdRunsUnpin(args[1:])
+	case "skip":
+		return cmdRunsSkip(args[1:])
+	case "retry":
+		return cmdRunsRetry(args[1:])
+	case "rollback":
+		return cmdRunsRollback(args[1:])
+	case "layout":
+		return cmdRunsLayout(args[1:])
+	case "rename", "mv":
+		return cmdRunsRename(args[1:])
+	case "rm", "remove":
+		return cmdRunsRm(args[1:])
+	case "export":
+		return cmdRunsExport(args[1:])
+	case "publish":
+		return cmdRunsPublish(args[1:])
+	case "clone":
+		return cmdRunsClone(args[1:])
+	case "ask":
+		return cmdRunsAsk(args[1:])
+	case "advise":
+		return cmdRunsAdvise(args[1:])
+	case "index":
+		return cmdRunsIndex(args[1:])
+	case "feed":
+		return cmdRunsFeed(args[1:])
+	case "digest":
+		return cmdRunsDigest(args[1:])
+	case "authors":
+		return cmdRunsAuthors(args[1:])
+	case "pause", "resume", "cancel", "status":
+		return cmdRunsControl(args[0], args[1:])
+	case "search":
+		return cmdRunsSearch(args[1:])
+	case "similar":
+		return cmdRunsSimilar(args[1:])
 	case "help", "-h", "--help":
 		printRunsUsage()
 		return nil
@@ -58,13 +106,43 @@ Usage:
 Commands:
   ls       List all runs in the output directory
   show     Show extraction results for a run
+  pause    Pause a running extraction after its in-flight threads
+  resume   Resume a paused extraction
+  cancel   Cancel a running extraction, saving progress like Ctrl-C
+  status   Report whether a run's process is running or paused
+  pin      Pin threads to a run's watch list (never skipped, always refreshed)
+  unpin    Remove threads from a run's watch list
+  skip     Reject threads and add them to the form's skip list
+  retry    Reset failed threads, optionally by error code, for the next run
+  rollback Restore one of a run's earlier manifest versions
+  rename   Rename a run's directory, updating cross-run records
+  layout   Show the output directory's layout, or move runs into per-form folders
+  rm       Delete a run and its entries in cross-run records
+  export   Export a run's entries with their source links
+  publish  Bundle a run's report, entries and threads into a shareable zip
+  authors  List the authors whose comments back a run's entries
+  clone    Start a new session on another topic with a run's form and settings
+  ask      Ask a question about a run's entries, answered with citations
+  advise   Suggest form edits from a run's fill rates, skips and flags
+  digest   Email (or print) a digest of a run's top entries and changes
+  feed     Publish a run's top entries to the form's RSS/JSON feed
+  index    Build a run's word-overlap index over entries and evidence
+  search   Search a run's indexed entries and evidence by shared words
+  similar  Find earlier runs whose entries share words with a description
 
 Examples:
   hiveminer runs ls
   hiveminer runs ls -o ./output
   hiveminer runs show family-vacation-20260214-045927
   hiveminer runs show family-vacation -n 0       # show all results
-  hiveminer runs show ./output/family-vacation-20260214-045927`)
+  hiveminer runs show ./output/family-vacation-20260214-045927
+  hiveminer runs pin family-vacation /r/travel/comments/abc123/best_trips/
+  hiveminer runs export family-vacation --format jsonl --file trips.jsonl
+  hiveminer runs authors family-vacation --min-entries 2
+  hiveminer runs clone family-vacation --query "ski trips with kids"
+  hiveminer runs ask family-vacation "which options are under $500 and kid friendly?"
+  hiveminer runs advise family-vacation --write forms/vacation-v2.json
+  hiveminer runs similar "beach trips with toddlers"`)
 }
 
 type sessionInfo struct {
@@ -73,74 +151,124 @@ type sessionInfo struct {
 	Manifest *types.Manifest
 }
 
+// listSessions loads every session with a manifest in outputDir, in either
+// layout (see session.LayoutVersion)
+func listSessions(outputDir string) ([]sessionInfo, error) {
+	dirs, err := session.SessionDirs(outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []sessionInfo
+	for _, dir := range dirs {
+		manifest, err := session.LoadManifest(dir)
+		if err != nil || manifest == nil {
+			continue
+		}
+		sessions = append(sessions, sessionInfo{
+			Dir:      dir,
+			Name:     filepath.Base(dir),
+			Manifest: manifest,
+		})
+	}
+	return sessions, nil
+}
+
 func cmdRunsLs(args []string) error {
 	fs := flag.NewFlagSet("runs ls", flag.ExitOnError)
 	outputDir := fs.String("output", "./output", "Output directory to scan")
+	formFilter := fs.String("form", "", "Only list runs whose form title contains this text")
+	statusFilter := fs.String("status", "", "Only list runs whose last run is one of these (comma-separated): done, running, interrupted, failed")
+	since := fs.String("since", "", "Only list runs updated since a date (2006-01-02) or within a duration (36h, 7d)")
+	sortBy := fs.String("sort", "created", "Sort by: created, updated, entries, threads, name")
+	table := fs.Bool("table", false, "List one run per line")
+	jsonOut := fs.Bool("json", false, "Output the runs as JSON")
 	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.BoolVar(table, "t", false, "List one run per line (shorthand)")
 	fs.Parse(args)
 
-	entries, err := os.ReadDir(*outputDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Println("No output directory found. Run an extraction first.")
-			return nil
+	var statuses []string
+	for _, s := range splitList(*statusFilter) {
+		s = strings.ToLower(s)
+		if !slices.Contains(runStatuses, s) {
+			return fmt.Errorf("unknown --status %q (want %s)", s, strings.Join(runStatuses, ", "))
+		}
+		statuses = append(statuses, s)
+	}
+	var cutoff time.Time
+	if *since != "" {
+		t, err := parseSince(*since, time.Now())
+		if err != nil {
+			return err
+		}
+		cutoff = t
+	}
+	less, ok := runsSorts[*sortBy]
+	if !ok {
+		return fmt.Errorf("unknown --sort %q (want created, updated, entries, threads or name)", *sortBy)
+	}
+
+	if _, err := os.Stat(*outputDir); os.IsNotExist(err) {
+		if *jsonOut {
+			return printJSON([]runSummary{})
 		}
-		return fmt.Errorf("reading output directory: %w", err)
+		fmt.Println("No output directory found. Run an extraction first.")
+		return nil
+	}
+	all, err := listSessions(*outputDir)
+	if err != nil {
+		return err
 	}
 
 	var sessions []sessionInfo
-	for _, entry := range entries {
-		if !entry.IsDir() {
+	for _, s := range all {
+		m := s.Manifest
+		if *formFilter != "" && !strings.Contains(strings.ToLower(m.Form.Title), strings.ToLower(*formFilter)) {
 			continue
 		}
-		dir := filepath.Join(*outputDir, entry.Name())
-		manifest, err := session.LoadManifest(dir)
-		if err != nil || manifest == nil {
+		if len(statuses) > 0 && !slices.Contains(statuses, runStatus(m)) {
 			continue
 		}
-		sessions = append(sessions, sessionInfo{
-			Dir:      dir,
-			Name:     entry.Name(),
-			Manifest: manifest,
-		})
+		if !cutoff.IsZero() && lastActivity(m).Before(cutoff) {
+			continue
+		}
+		sessions = append(sessions, s)
 	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return less(sessions[i], sessions[j])
+	})
 
+	if *jsonOut {
+		summaries := make([]runSummary, len(sessions))
+		for i, s := range sessions {
+			summaries[i] = summarizeRun(s)
+		}
+		return printJSON(summaries)
+	}
 	if len(sessions) == 0 {
-		fmt.Println("No runs found.")
+		if len(all) > 0 {
+			fmt.Printf("No runs match (%d in total).\n", len(all))
+		} else {
+			fmt.Println("No runs found.")
+		}
+		return nil
+	}
+	if *table {
+		printRunsTable(sessions)
+		printLayoutHint(*outputDir)
 		return nil
 	}
-
-	// Sort by created_at descending (newest first)
-	sort.Slice(sessions, func(i, j int) bool {
-		return sessions[i].Manifest.CreatedAt.After(sessions[j].Manifest.CreatedAt)
-	})
 
 	fmt.Printf("\n%s%s Runs %s\n", colorBold, colorCyan, colorReset)
 	fmt.Println(strings.Repeat("─", 80))
 
+	// The first in sort order is listed last, nearest the prompt
 	for idx := len(sessions) - 1; idx >= 0; idx-- {
 		s := sessions[idx]
 		m := s.Manifest
 		counts := session.CountByStatus(m)
 
-		// Status indicator
-		statusColor := colorGreen
-		statusIcon := "done"
-		if len(m.Runs) > 0 {
-			lastRun := m.Runs[len(m.Runs)-1]
-			switch lastRun.Status {
-			case "running":
-				statusColor = colorYellow
-				statusIcon = "running"
-			case "interrupted":
-				statusColor = colorYellow
-				statusIcon = "interrupted"
-			case "failed":
-				statusColor = colorRed
-				statusIcon = "failed"
-			}
-		}
-
+		status := runStatus(m)
 		fmt.Printf("\n %s%s#%d%s  %s%s%s\n", colorBold, colorDim, idx+1, colorReset, colorBold, s.Name, colorReset)
 		fmt.Printf("     %sForm:%s  %s\n", colorCyan, colorReset, m.Form.Title)
 		if m.Query != "" {
@@ -157,44 +285,204 @@ func cmdRunsLs(args []string) error {
 
 		threadSummary := fmt.Sprintf("%d total", len(m.Threads))
 		parts := []string{}
-		if counts["ranked"] > 0 {
-			parts = append(parts, fmt.Sprintf("%s%d ranked%s", colorGreen, counts["ranked"], colorReset))
+		if counts[types.StatusRanked] > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d ranked%s", colorGreen, counts[types.StatusRanked], colorReset))
 		}
-		if counts["extracted"] > 0 {
-			parts = append(parts, fmt.Sprintf("%s%d extracted%s", colorGreen, counts["extracted"], colorReset))
+		if counts[types.StatusExtracted] > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d extracted%s", colorGreen, counts[types.StatusExtracted], colorReset))
 		}
-		if counts["collected"] > 0 {
-			parts = append(parts, fmt.Sprintf("%s%d collected%s", colorCyan, counts["collected"], colorReset))
+		if counts[types.StatusCollected] > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d collected%s", colorCyan, counts[types.StatusCollected], colorReset))
 		}
-		if counts["pending"] > 0 {
-			parts = append(parts, fmt.Sprintf("%s%d pending%s", colorYellow, counts["pending"], colorReset))
+		if counts[types.StatusPending] > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d pending%s", colorYellow, counts[types.StatusPending], colorReset))
 		}
-		if counts["skipped"] > 0 {
-			parts = append(parts, fmt.Sprintf("%s%d skipped%s", colorDim, counts["skipped"], colorReset))
+		if counts[types.StatusSkipped] > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d skipped%s", colorDim, counts[types.StatusSkipped], colorReset))
 		}
-		if counts["failed"] > 0 {
-			parts = append(parts, fmt.Sprintf("%s%d failed%s", colorRed, counts["failed"], colorReset))
+		if counts[types.StatusFailed] > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d failed: %s%s", colorRed, counts[types.StatusFailed],
+				orchestrator.FormatFailures(session.CountFailures(m)), colorReset))
+		}
+		if counts[types.StatusQuarantined] > 0 {
+			parts = append(parts, fmt.Sprintf("%s%d quarantined%s", colorRed, counts[types.StatusQuarantined], colorReset))
+		}
+		if unknown := session.UnknownStatuses(m); len(unknown) > 0 {
+			parts = append(parts, fmt.Sprintf("%sunknown status: %s%s", colorRed, session.FormatUnknownStatuses(unknown), colorReset))
 		}
 		if len(parts) > 0 {
 			threadSummary += " (" + strings.Join(parts, ", ") + ")"
 		}
 		fmt.Printf("     %sThreads:%s %s\n", colorCyan, colorReset, threadSummary)
+		if n := len(m.Runs); n > 0 && len(m.Runs[n-1].Phases) > 0 {
+			fmt.Printf("     %sTiming:%s  %s\n", colorCyan, colorReset, orchestrator.PhaseTimingSummary(m.Runs[n-1]))
+		}
 
-		fmt.Printf("     %sStatus:%s  %s%s%s", colorCyan, colorReset, statusColor, statusIcon, colorReset)
+		fmt.Printf("     %sStatus:%s  %s%s%s", colorCyan, colorReset, runStatusColor(status), status, colorReset)
 		fmt.Printf("  %s%s%s\n", colorDim, m.CreatedAt.Format("Jan 02 15:04"), colorReset)
 	}
 
 	fmt.Println()
+	printLayoutHint(*outputDir)
 	return nil
 }
 
+// printLayoutHint points out runs kept directly in the output directory,
+// which runs layout --migrate moves into per-form folders
+func printLayoutHint(outputDir string) {
+	if n := len(session.FlatSessionDirs(outputDir)); n > 0 {
+		fmt.Printf("%s%d runs are kept directly in %s; 'hiveminer runs layout --migrate' sorts them into per-form folders.%s\n", colorDim, n, outputDir, colorReset)
+	}
+}
+
+// runStatuses are the statuses runs ls reports a session's last run in
+var runStatuses = []string{"done", "running", "interrupted", "failed"}
+
+// runStatus returns the status of a session's last run, "done" when it
+// completed or hasn't run
+func runStatus(m *types.Manifest) string {
+	if n := len(m.Runs); n > 0 {
+		switch status := m.Runs[n-1].Status; status {
+		case "running", "interrupted", "failed":
+			return status
+		}
+	}
+	return "done"
+}
+
+func runStatusColor(status string) string {
+	switch status {
+	case "running", "interrupted":
+		return colorYellow
+	case "failed":
+		return colorRed
+	}
+	return colorGreen
+}
+
+// lastActivity returns when a session was last updated
+func lastActivity(m *types.Manifest) time.Time {
+	if m.UpdatedAt.IsZero() {
+		return m.CreatedAt
+	}
+	return m.UpdatedAt
+}
+
+// parseSince parses a --since value: a date, or a duration back from now in
+// hours, minutes or days ("7d")
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (want a date like 2006-01-02 or a duration like 36h or 7d)", s)
+}
+
+// runsSorts orders runs ls by each --sort key, largest or newest first
+var runsSorts = map[string]func(a, b sessionInfo) bool{
+	"created": func(a, b sessionInfo) bool { return a.Manifest.CreatedAt.After(b.Manifest.CreatedAt) },
+	"updated": func(a, b sessionInfo) bool { return lastActivity(a.Manifest).After(lastActivity(b.Manifest)) },
+	"entries": func(a, b sessionInfo) bool { return entryCount(a.Manifest) > entryCount(b.Manifest) },
+	"threads": func(a, b sessionInfo) bool { return len(a.Manifest.Threads) > len(b.Manifest.Threads) },
+	"name":    func(a, b sessionInfo) bool { return a.Name < b.Name },
+}
+
+// entryCount returns the number of entries a session holds
+func entryCount(m *types.Manifest) int {
+	n := 0
+	for _, t := range m.Threads {
+		if types.HasEntries(t.Status) {
+			n += len(t.Entries)
+		}
+	}
+	return n
+}
+
+// runSummary is a session as runs ls --json reports it
+type runSummary struct {
+	Name       string         `json:"name"`
+	Dir        string         `json:"dir"`
+	Form       string         `json:"form"`
+	Query      string         `json:"query,omitempty"`
+	Subreddits []string       `json:"subreddits"`
+	Status     string         `json:"status"`
+	Threads    int            `json:"threads"`
+	ByStatus   map[string]int `json:"by_status"`
+	Entries    int            `json:"entries"`
+	Runs       int            `json:"runs"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+func summarizeRun(s sessionInfo) runSummary {
+	m := s.Manifest
+	byStatus := map[string]int{}
+	for status, n := range session.CountByStatus(m) {
+		if n > 0 {
+			byStatus[status] = n
+		}
+	}
+	return runSummary{
+		Name:       s.Name,
+		Dir:        s.Dir,
+		Form:       m.Form.Title,
+		Query:      m.Query,
+		Subreddits: m.Subreddits,
+		Status:     runStatus(m),
+		Threads:    len(m.Threads),
+		ByStatus:   byStatus,
+		Entries:    entryCount(m),
+		Runs:       len(m.Runs),
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  lastActivity(m),
+	}
+}
+
+// printRunsTable lists runs one per line, the first in sort order last
+func printRunsTable(sessions []sessionInfo) {
+	const formWidth = 32
+	nameWidth := len("RUN")
+	for _, s := range sessions {
+		nameWidth = max(nameWidth, len(s.Name))
+	}
+	fmt.Printf("%s%4s  %-*s  %-*s  %-11s  %7s  %7s  %s%s\n", colorDim, "#", nameWidth, "RUN", formWidth, "FORM", "STATUS", "THREADS", "ENTRIES", "UPDATED", colorReset)
+	for idx := len(sessions) - 1; idx >= 0; idx-- {
+		s := sessions[idx]
+		m := s.Manifest
+		form := m.Form.Title
+		if r := []rune(form); len(r) > formWidth {
+			form = string(r[:formWidth-1]) + "…"
+		}
+		status := runStatus(m)
+		fmt.Printf("%4d  %-*s  %-*s  %s%-11s%s  %7d  %7d  %s\n", idx+1, nameWidth, s.Name, formWidth, form,
+			runStatusColor(status), status, colorReset, len(m.Threads), entryCount(m), lastActivity(m).Local().Format("Jan 02 15:04"))
+	}
+}
+
 func cmdRunsShow(args []string) error {
 	fs := flag.NewFlagSet("runs show", flag.ExitOnError)
 	outputDir := fs.String("output", "./output", "Output directory")
 	showInternal := fs.Bool("all", false, "Show internal fields")
 	maxResults := fs.Int("n", 10, "Maximum number of results to show (0 for all)")
+	flagFilter := fs.String("flag", "", "Only show entries with this rank flag (e.g. outlier, contradicted)")
+	hideFlagged := fs.String("hide-flagged", "", "Hide entries with a flag of this severity or worse: minor, moderate, severe")
+	answeredBy := fs.String("answered-by", "", "Only show entries with a field answered by: op, commenters")
+	verbose := fs.Bool("verbose", false, "Show each field's reasoning and source links")
+	anonymize := fs.String("anonymize", "", "Scrub usernames and personal details: hash (stable pseudonyms) or strip")
+	near := fs.String("near", "", "Only show entries with a geocoded location near this place or lat,lon")
+	within := fs.Float64("within", 50, "Distance in km for --near")
+	follow := fs.Bool("follow", false, "Stream entries as a running session extracts and ranks them, then show the results")
+	discovery := fs.Bool("discovery", false, "Show the searches discovery made and why each thread was picked, instead of entries")
 	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
 	fs.BoolVar(showInternal, "a", false, "Show internal fields (shorthand)")
+	fs.BoolVar(verbose, "v", false, "Show each field's reasoning and source links (shorthand)")
 	fs.Parse(args)
 
 	if fs.NArg() < 1 {
@@ -204,23 +492,9 @@ func cmdRunsShow(args []string) error {
 		return fmt.Errorf("run ID required")
 	}
 
-	target := fs.Arg(0)
-
-	// Resolve session directory - accept full path or just directory name
-	sessionDir := target
-	if _, err := os.Stat(filepath.Join(target, "manifest.json")); os.IsNotExist(err) {
-		// Try as a subdirectory of output
-		sessionDir = filepath.Join(*outputDir, target)
-		if _, err := os.Stat(filepath.Join(sessionDir, "manifest.json")); os.IsNotExist(err) {
-			// Try prefix match
-			matched := findSessionByPrefix(*outputDir, target)
-			if matched == "" {
-				fmt.Fprintf(os.Stderr, "Error: no run found matching %q\n", target)
-				fmt.Fprintln(os.Stderr, "  Run 'hiveminer runs ls' to see available runs")
-				return fmt.Errorf("run not found: %s", target)
-			}
-			sessionDir = matched
-		}
+	sessionDir, err := resolveSessionDir(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
 	}
 
 	manifest, err := session.LoadManifest(sessionDir)
@@ -232,6 +506,10 @@ func cmdRunsShow(args []string) error {
 		fmt.Fprintf(os.Stderr, "Error: no manifest found in %s\n", sessionDir)
 		return fmt.Errorf("no manifest found")
 	}
+	if *discovery {
+		printDiscovery(*outputDir, manifest)
+		return nil
+	}
 
 	// Load the form to get field metadata
 	form, err := loadFormFromManifest(manifest)
@@ -240,10 +518,35 @@ func cmdRunsShow(args []string) error {
 		form = deriveFormFromManifest(manifest)
 	}
 
+	if *follow {
+		if *anonymize != "" {
+			return fmt.Errorf("--follow can't be combined with --anonymize")
+		}
+		if err := followRun(sessionDir, form); err != nil {
+			return err
+		}
+		if manifest, err = session.LoadManifest(sessionDir); err != nil {
+			return fmt.Errorf("loading manifest: %w", err)
+		}
+	}
+
+	if err := checkFlagFilters(form, *flagFilter, *hideFlagged); err != nil {
+		return err
+	}
+	origin, err := resolveNear(*near)
+	if err != nil {
+		return err
+	}
+	if *anonymize != "" {
+		if err := export.AnonymizeManifest(manifest, *anonymize); err != nil {
+			return err
+		}
+	}
+
 	// Filter to extracted or ranked threads
 	var extracted []types.ThreadState
 	for _, t := range manifest.Threads {
-		if (t.Status == "extracted" || t.Status == "ranked") && len(t.Entries) > 0 {
+		if types.HasEntries(t.Status) && len(t.Entries) > 0 {
 			extracted = append(extracted, t)
 		}
 	}
@@ -254,13 +557,18 @@ func cmdRunsShow(args []string) error {
 		return nil
 	}
 
-	// Build visible fields list
+	// Build visible fields list, the primary field first
 	var fields []types.Field
+	primaryID := schema.PrimaryField(form)
 	for _, f := range form.Fields {
 		if f.Internal && !*showInternal {
 			continue
 		}
-		fields = append(fields, f)
+		if f.ID == primaryID {
+			fields = append([]types.Field{f}, fields...)
+		} else {
+			fields = append(fields, f)
+		}
 	}
 
 	// Print header
@@ -269,6 +577,36 @@ func cmdRunsShow(args []string) error {
 		fmt.Printf(" %sQuery: %s%s\n", colorDim, manifest.Query, colorReset)
 	}
 	fmt.Printf(" %s%d threads extracted%s\n", colorDim, len(extracted), colorReset)
+	partial := 0
+	for _, t := range extracted {
+		if orchestrator.PartialView(t) {
+			partial++
+		}
+	}
+	if partial > 0 {
+		fmt.Printf(" %s%d seen partially: under half their comments reached extraction%s\n", colorYellow, partial, colorReset)
+	}
+	if o := orchestrator.Outlook(manifest); o.EstimatedThreads > 0 || o.AwaitingEstimate > 0 {
+		if o.EstimatedThreads > 0 {
+			fmt.Printf(" %sEvaluation estimated %d entries for %d of them; extraction found %d%s\n", colorDim, o.Estimated, o.EstimatedThreads, o.EstimatedActual, colorReset)
+		}
+		target, _ := strconv.Atoi(manifest.Settings["target-entries"])
+		fmt.Printf(" %sOutlook: %s%s\n", colorDim, o.Line(target), colorReset)
+	}
+	if n := len(manifest.Runs); n > 0 && len(manifest.Runs[n-1].Phases) > 0 {
+		fmt.Printf(" %sLast run %s%s\n", colorDim, orchestrator.PhaseTimingSummary(manifest.Runs[n-1]), colorReset)
+		for _, line := range orchestrator.PhaseTimingLines(manifest.Runs[n-1]) {
+			fmt.Printf("   %s%s%s\n", colorDim, line, colorReset)
+		}
+	}
+	for i := len(manifest.Runs) - 1; i >= 0; i-- {
+		if r := manifest.Runs[i].Ranking; r != nil {
+			for _, line := range orchestrator.RankingSummaryLines(r) {
+				fmt.Printf(" %s%s%s\n", colorDim, line, colorReset)
+			}
+			break
+		}
+	}
 	fmt.Println()
 
 	// Collect all entries for sorting
@@ -276,27 +614,32 @@ func cmdRunsShow(args []string) error {
 		entry  types.Entry
 		thread types.ThreadState
 	}
-	var allEntries []rankedEntry
+	var allEntries, warnings []rankedEntry
 	for _, thread := range extracted {
 		for _, entry := range thread.Entries {
+			if !matchesFlagFilters(form, entry.RankFlags, *flagFilter, *hideFlagged) {
+				continue
+			}
+			if *answeredBy != "" && !hasAttribution(entry, *answeredBy) {
+				continue
+			}
+			if origin != nil {
+				if d, ok := geocode.Nearest(entry, *origin); !ok || d > *within {
+					continue
+				}
+			}
+			// Warnings against an item are listed apart unless asked for
+			if entry.AntiRecommendation != nil && *flagFilter != schema.AntiRecommendationFlag.ID {
+				warnings = append(warnings, rankedEntry{entry: entry, thread: thread})
+				continue
+			}
 			allEntries = append(allEntries, rankedEntry{entry: entry, thread: thread})
 		}
 	}
 
 	// Sort by rank score descending (highest first), unscored entries last
-	sort.Slice(allEntries, func(i, j int) bool {
-		si := allEntries[i].entry.RankScore
-		sj := allEntries[j].entry.RankScore
-		if si == nil && sj == nil {
-			return false
-		}
-		if si == nil {
-			return false
-		}
-		if sj == nil {
-			return true
-		}
-		return *si > *sj
+	sort.SliceStable(allEntries, func(i, j int) bool {
+		return export.RanksBefore(allEntries[i].entry, allEntries[j].entry)
 	})
 
 	// Limit displayed results
@@ -336,20 +679,84 @@ func cmdRunsShow(args []string) error {
 			var flagParts []string
 			for _, f := range entry.RankFlags {
 				flagColor := colorYellow
-				switch f {
-				case "spam", "off_topic":
+				if def := schema.FindFlag(form, f); def != nil && def.Severity == "severe" {
 					flagColor = colorRed
-				case "joke", "outdated":
-					flagColor = colorRed
-				case "duplicate", "low_effort":
-					flagColor = colorYellow
 				}
 				flagParts = append(flagParts, fmt.Sprintf("%s[%s]%s", flagColor, f, colorReset))
 			}
 			fmt.Printf("    %s\n", strings.Join(flagParts, " "))
+			if entry.RankReason != "" {
+				fmt.Printf("    %s%s%s\n", colorDim, entry.RankReason, colorReset)
+			}
+		}
+		if thread.Source != "" {
+			fmt.Printf("    %s%s  %s page%s\n", colorDim, threadOrigin(thread), thread.Source, colorReset)
+		} else {
+			fmt.Printf("    %sr/%s  ↑%d pts  %d comments%s\n",
+				colorDim, thread.Subreddit, thread.Score, thread.NumComments, colorReset)
+		}
+		if orchestrator.PartialView(thread) {
+			c := thread.Context
+			fmt.Printf("    %spartial view: extraction saw %d of %d comments%s\n", colorYellow, c.Sent+c.Summarized, c.Comments, colorReset)
+		}
+		if entry.SummaryDerived {
+			fmt.Printf("    %sfrom summaries of an oversized thread, not verbatim comments%s\n", colorDim, colorReset)
+		}
+		if entry.Overflow {
+			fmt.Printf("    %sover the thread's entry cap, not ranked%s\n", colorDim, colorReset)
+		}
+		if a := entry.Availability; a != nil {
+			statusColor := colorDim
+			if a.Status == types.AvailabilityDiscontinued {
+				statusColor = colorRed
+			}
+			line := strings.ReplaceAll(a.Status, "_", " ")
+			if a.Price != nil {
+				line += fmt.Sprintf(" · %s %s", formatValue(*a.Price), a.Currency)
+			}
+			fmt.Printf("    %s%s at %s, checked %s%s\n", statusColor, strings.TrimSpace(line), a.Source, a.CheckedAt.Format("Jan 02"), colorReset)
+		}
+		if a := entry.AntiRecommendation; a != nil {
+			line := "recommended against"
+			if a.Reason != "" {
+				line += ": " + a.Reason
+			}
+			fmt.Printf("    %s%s (%s)%s\n", colorRed, line, a.Source, colorReset)
+		}
+		if v := entry.Verification; v != nil && (v.Verdict != types.VerdictSupported || len(v.Issues) > 0) {
+			verdictColor := colorYellow
+			if v.Verdict == types.VerdictUnsupported || len(v.Issues) > 0 {
+				verdictColor = colorRed
+			}
+			line := v.Verdict
+			if len(v.Issues) > 0 {
+				line += " (" + strings.Join(v.Issues, ", ") + ")"
+			}
+			if v.Reason != "" {
+				line += ": " + v.Reason
+			}
+			fmt.Printf("    %sverified %s%s\n", verdictColor, line, colorReset)
+			ids := make([]string, 0, len(v.Fields))
+			for id := range v.Fields {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			for _, id := range ids {
+				fmt.Printf("    %s  %s unsupported: %s%s\n", colorDim, id, v.Fields[id], colorReset)
+			}
+		}
+		if c := entry.Consensus; c != nil {
+			fmt.Printf("    %s%d endorsed, %d warned against (net %+.2f)%s\n",
+				colorDim, c.Endorsements, c.Warnings, c.NetAgreement, colorReset)
+		}
+		if e := entry.Ensemble; e != nil && e.Found < len(e.Models) {
+			fmt.Printf("    %sfound by %d of %d models (%s)%s\n", colorYellow, e.Found, len(e.Models), strings.Join(e.Models, ", "), colorReset)
+		}
+		if *showInternal && entry.Provenance != nil {
+			p := entry.Provenance
+			fmt.Printf("    %sextracted by %s (prompt %s) in %s, %s%s\n",
+				colorDim, p.Model, p.PromptHash, p.RunID, p.ExtractedAt.Format("Jan 02 15:04"), colorReset)
 		}
-		fmt.Printf("    %sr/%s  ↑%d pts  %d comments%s\n",
-			colorDim, thread.Subreddit, thread.Score, thread.NumComments, colorReset)
 		fmt.Println()
 
 		// Field values
@@ -367,6 +774,21 @@ func cmdRunsShow(args []string) error {
 
 			// Confidence badge
 			confBadge := fmt.Sprintf("%s%.0f%%%s", confColor, fv.Confidence*100, colorReset)
+			switch fv.AnsweredBy {
+			case types.AnsweredByOP:
+				confBadge += fmt.Sprintf(" %s[OP]%s", colorGreen, colorReset)
+			case types.AnsweredByMixed:
+				confBadge += fmt.Sprintf(" %s[OP+]%s", colorGreen, colorReset)
+			}
+			if fv.Agreement != nil && entry.Ensemble != nil {
+				models := len(entry.Ensemble.Models)
+				agreed := int(math.Round(*fv.Agreement * float64(models)))
+				agreeColor := colorDim
+				if agreed < models {
+					agreeColor = colorYellow
+				}
+				confBadge += fmt.Sprintf(" %s[%d/%d models]%s", agreeColor, agreed, models, colorReset)
+			}
 
 			// Check if value is multiline (arrays)
 			lines := strings.Split(valueStr, "\n")
@@ -378,6 +800,55 @@ func cmdRunsShow(args []string) error {
 			} else {
 				fmt.Printf("    %s%-20s%s %s  %s\n", colorCyan, label, colorReset, valueStr, confBadge)
 			}
+
+			// Where a geocoded location value resolved to
+			if g := fv.Geo; g != nil {
+				where := fmt.Sprintf("%.4f, %.4f", g.Lat, g.Lon)
+				if g.Name != "" {
+					where = g.Name + " (" + where + ")"
+				}
+				if origin != nil {
+					where += fmt.Sprintf(", %.0f km away", geocode.Distance(*g, *origin))
+				}
+				fmt.Printf("    %-20s %s%s%s\n", "", colorDim, where, colorReset)
+			}
+
+			// Spread of numeric values across duplicate entries
+			if agg := fv.Aggregate; agg != nil {
+				fmt.Printf("    %-20s %sreported %d×: %s–%s, median %s%s\n", "", colorDim,
+					agg.Count, formatValue(agg.Min), formatValue(agg.Max), formatValue(agg.Median), colorReset)
+			}
+
+			// Conflicting values reported by duplicate entries
+			if len(fv.Alternatives) > 0 {
+				var alts []string
+				for _, alt := range fv.Alternatives {
+					alts = append(alts, fmt.Sprintf("%s (%.0f%%)", strings.ReplaceAll(formatValue(alt.Value), "\n", ", "), alt.Confidence*100))
+				}
+				fmt.Printf("    %-20s %salso reported: %s%s\n", "", colorDim, strings.Join(alts, "; "), colorReset)
+			}
+
+			// Why the model chose this value, and the comments behind it
+			if *verbose {
+				if fv.Reasoning != "" {
+					fmt.Printf("    %-20s %swhy: %s%s\n", "", colorDim, fv.Reasoning, colorReset)
+				}
+				for _, link := range fv.Links {
+					fullURL := export.FullURL(link)
+					fmt.Printf("    %-20s %s%s%s\n", "", colorDim, hyperlink(fullURL, fullURL), colorReset)
+				}
+			}
+		}
+
+		// Warnings: problems commenters raised, next to the praise above
+		if len(entry.Caveats) > 0 {
+			fmt.Printf("\n    %sWarnings:%s\n", colorYellow, colorReset)
+			for _, c := range entry.Caveats {
+				fmt.Printf("      %s⚠%s %s\n", colorYellow, colorReset, export.CaveatSummary(c))
+				if *verbose && len(c.Evidence) > 0 {
+					fmt.Printf("        %s\"%s\"%s\n", colorDim, truncateTitle(strings.Join(strings.Fields(c.Evidence[0].Text), " "), 70), colorReset)
+				}
+			}
 		}
 
 		// Sources: collect unique comment evidence across all fields
@@ -385,11 +856,12 @@ func cmdRunsShow(args []string) error {
 			Author string
 			Quote  string
 			Link   string
+			Meta   string // comment score and date, when known
 		}
 		seen := make(map[string]bool)
 		var sources []commentSource
 		for _, fv := range entry.Fields {
-			for i, ev := range fv.Evidence {
+			for _, ev := range fv.Evidence {
 				if ev.CommentID == "" || ev.CommentID == "post_content" {
 					continue
 				}
@@ -397,10 +869,7 @@ func cmdRunsShow(args []string) error {
 					continue
 				}
 				seen[ev.CommentID] = true
-				link := ""
-				if i < len(fv.Links) {
-					link = fv.Links[i]
-				}
+				link := export.CommentLink(fv.Links, ev.CommentID)
 				quote := ev.Text
 				if len(quote) > 60 {
 					quote = quote[:60] + "..."
@@ -409,6 +878,7 @@ func cmdRunsShow(args []string) error {
 					Author: ev.Author,
 					Quote:  quote,
 					Link:   link,
+					Meta:   evidenceMeta(ev),
 				})
 			}
 		}
@@ -419,21 +889,51 @@ func cmdRunsShow(args []string) error {
 				if author != "" && !strings.HasPrefix(author, "u/") {
 					author = "u/" + author
 				}
+				if src.Meta != "" {
+					author = strings.TrimSpace(author + " " + colorDim + src.Meta)
+				}
 				if author != "" {
 					fmt.Printf("      %s%s%s  %s\"%s\"%s\n", colorCyan, author, colorReset, colorWhite, src.Quote, colorReset)
 				} else {
 					fmt.Printf("      %s\"%s\"%s\n", colorWhite, src.Quote, colorReset)
 				}
 				if src.Link != "" {
-					fullURL := "https://reddit.com" + src.Link
+					fullURL := export.FullURL(src.Link)
 					fmt.Printf("      %s%s%s\n", colorDim, hyperlink(fullURL, fullURL), colorReset)
 				}
 			}
 		}
 
+		// View sources: every cited comment, strongest evidence first
+		if links := export.SourceLinks(entry); len(links) > 0 {
+			var refs []string
+			for n, link := range links {
+				refs = append(refs, hyperlink(export.FullURL(link), fmt.Sprintf("[%d]", n+1)))
+			}
+			fmt.Printf("\n    %sView sources:%s %s\n", colorDim, colorReset, strings.Join(refs, " "))
+		}
+
 		fmt.Printf("\n  %s%s%s\n\n", colorDim, strings.Repeat("·", 76), colorReset)
 	}
 
+	if len(warnings) > 0 {
+		fmt.Printf(" %s%sRecommended against%s %s(--flag %s to show in full)%s\n", colorBold, colorRed, colorReset, colorDim, schema.AntiRecommendationFlag.ID, colorReset)
+		for _, w := range warnings {
+			name := "?"
+			for _, fv := range w.entry.Fields {
+				if fv.ID == primaryID && fv.Value != nil {
+					name = strings.ReplaceAll(formatValue(fv.Value), "\n", ", ")
+				}
+			}
+			reason := w.entry.AntiRecommendation.Reason
+			if reason == "" {
+				reason = "flagged by " + w.entry.AntiRecommendation.Source
+			}
+			fmt.Printf("   %s✗%s %s %s— %s (%s)%s\n", colorRed, colorReset, name, colorDim, reason, truncateTitle(w.thread.Title, 50), colorReset)
+		}
+		fmt.Println()
+	}
+
 	if truncated {
 		fmt.Printf(" %sShowing top %d of %d results. Run %sruns show <id> -n 0%s%s to see all.%s\n\n",
 			colorDim, *maxResults, totalEntries, colorReset, colorBold, colorDim, colorReset)
@@ -443,37 +943,144 @@ func cmdRunsShow(args []string) error {
 	return nil
 }
 
-// findSessionByPrefix finds a session directory matching a prefix
-func findSessionByPrefix(outputDir, prefix string) string {
-	entries, err := os.ReadDir(outputDir)
+// checkFlagFilters validates --flag and --hide-flagged against the form's
+// flag taxonomy
+// resolveNear resolves a --near origin: "lat,lon", or a place name looked up
+// with Nominatim. Returns nil when near is empty.
+func resolveNear(near string) (*types.GeoPoint, error) {
+	if near == "" {
+		return nil, nil
+	}
+	if p, ok := geocode.ParsePoint(near); ok {
+		return p, nil
+	}
+	g, err := geocode.New("nominatim")
 	if err != nil {
-		return ""
+		return nil, err
+	}
+	p, err := g.Geocode(context.Background(), near)
+	if err != nil {
+		return nil, fmt.Errorf("geocoding --near: %w", err)
 	}
+	if p == nil {
+		return nil, fmt.Errorf("--near: place %q not found (try lat,lon)", near)
+	}
+	return p, nil
+}
 
-	prefix = strings.ToLower(prefix)
-	var matches []string
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
+func checkFlagFilters(form *types.Form, flagFilter, hideFlagged string) error {
+	if flagFilter != "" && schema.FindFlag(form, flagFilter) == nil {
+		var ids []string
+		for _, f := range schema.RankFlags(form) {
+			ids = append(ids, f.ID)
 		}
-		name := strings.ToLower(entry.Name())
-		if strings.HasPrefix(name, prefix) {
-			matches = append(matches, filepath.Join(outputDir, entry.Name()))
+		return fmt.Errorf("unknown flag %q (this form uses: %s)", flagFilter, strings.Join(ids, ", "))
+	}
+	if hideFlagged != "" && schema.SeverityLevel(hideFlagged) == 0 {
+		return fmt.Errorf("invalid severity %q (use %s)", hideFlagged, strings.Join(schema.FlagSeverities, ", "))
+	}
+	return nil
+}
+
+// matchesFlagFilters reports whether an entry's flags pass --flag and
+// --hide-flagged
+func matchesFlagFilters(form *types.Form, flags []string, flagFilter, hideFlagged string) bool {
+	if flagFilter != "" && !slices.Contains(flags, flagFilter) {
+		return false
+	}
+	if hideFlagged != "" && schema.SeverityLevel(schema.WorstSeverity(form, flags)) >= schema.SeverityLevel(hideFlagged) {
+		return false
+	}
+	return true
+}
+
+// evidenceMeta formats a quote's comment score and date, e.g. "+812 · Mar 2024"
+func evidenceMeta(ev types.Evidence) string {
+	var parts []string
+	if ev.Score != 0 {
+		parts = append(parts, fmt.Sprintf("%+d", ev.Score))
+	}
+	if ev.Created > 0 {
+		parts = append(parts, time.Unix(int64(ev.Created), 0).Format("Jan 2006"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// resolveSessionDir resolves a run ID to a session directory, accepting a full
+// path, a path under outputDir, a session name in any form's folder, or a
+// unique-ish name prefix
+func resolveSessionDir(outputDir, target string) (string, error) {
+	sessionDir := target
+	if _, err := os.Stat(filepath.Join(target, "manifest.json")); os.IsNotExist(err) {
+		// Try as a subdirectory of output
+		sessionDir = filepath.Join(outputDir, target)
+		if _, err := os.Stat(filepath.Join(sessionDir, "manifest.json")); os.IsNotExist(err) {
+			// Try a session name, then a prefix
+			sessionDir = session.FindSession(outputDir, target)
+			if sessionDir == "" {
+				sessionDir = findSessionByPrefix(outputDir, target)
+			}
+			if sessionDir == "" {
+				fmt.Fprintf(os.Stderr, "Error: no run found matching %q\n", target)
+				fmt.Fprintln(os.Stderr, "  Run 'hiveminer runs ls' to see available runs")
+				return "", fmt.Errorf("run not found: %s", target)
+			}
 		}
 	}
+	return sessionDir, nil
+}
 
-	if len(matches) == 1 {
-		return matches[0]
+// resolveUniqueSessionDir resolves a run ID like resolveSessionDir, but for
+// destructive commands: a prefix matching several runs is refused, listing
+// them, instead of picking one
+func resolveUniqueSessionDir(outputDir, target string) (string, error) {
+	_, direct := os.Stat(filepath.Join(target, "manifest.json"))
+	_, inOutput := os.Stat(filepath.Join(outputDir, target, "manifest.json"))
+	if direct != nil && inOutput != nil && session.FindSession(outputDir, target) == "" {
+		if matches := sessionsByPrefix(outputDir, target); len(matches) > 1 {
+			fmt.Fprintf(os.Stderr, "Error: %q matches %d runs:\n", target, len(matches))
+			for _, dir := range matches {
+				fmt.Fprintf(os.Stderr, "  %s\n", filepath.Base(dir))
+			}
+			return "", fmt.Errorf("ambiguous run ID %q; give more of the name", target)
+		}
 	}
-	if len(matches) > 1 {
-		// Return most recent (last alphabetically since names contain timestamps)
-		sort.Strings(matches)
-		return matches[len(matches)-1]
+	return resolveSessionDir(outputDir, target)
+}
+
+// findSessionByPrefix finds a session directory whose name matches a prefix
+func findSessionByPrefix(outputDir, prefix string) string {
+	matches := sessionsByPrefix(outputDir, prefix)
+	if len(matches) == 0 {
+		return ""
 	}
-	return ""
+	// Return most recent (last alphabetically since names contain timestamps)
+	return matches[len(matches)-1]
 }
 
-// loadFormFromManifest attempts to load the original form file
+// sessionsByPrefix returns the session directories whose names start with a
+// prefix, sorted by name
+func sessionsByPrefix(outputDir, prefix string) []string {
+	dirs, err := session.SessionDirs(outputDir)
+	if err != nil {
+		return nil
+	}
+
+	prefix = strings.ToLower(prefix)
+	var matches []string
+	for _, dir := range dirs {
+		if strings.HasPrefix(strings.ToLower(filepath.Base(dir)), prefix) {
+			matches = append(matches, dir)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return filepath.Base(matches[i]) < filepath.Base(matches[j])
+	})
+	return matches
+}
+
+// loadFormFromManifest attempts to load the original form file. With the
+// form at hand, entry IDs given on load are rederived from its primary field.
 func loadFormFromManifest(manifest *types.Manifest) (*types.Form, error) {
 	if manifest.Form.Path == "" {
 		return nil, fmt.Errorf("no form path in manifest")
@@ -488,6 +1095,7 @@ func loadFormFromManifest(manifest *types.Manifest) (*types.Form, error) {
 	if err := json.Unmarshal(data, &form); err != nil {
 		return nil, err
 	}
+	session.SettleEntryIDs(manifest, schema.PrimaryField(&form))
 	return &form, nil
 }
 
@@ -575,6 +1183,17 @@ func formatValue(v any) string {
 	}
 }
 
+// hasAttribution reports whether any field of an entry is answered by the given
+// party; mixed fields count for both OP and commenters
+func hasAttribution(entry types.Entry, by string) bool {
+	for _, fv := range entry.Fields {
+		if fv.AnsweredBy == by || (fv.AnsweredBy == types.AnsweredByMixed && by != types.AnsweredByMixed) {
+			return true
+		}
+	}
+	return false
+}
+
 // hyperlink renders an OSC 8 terminal hyperlink
 func hyperlink(url, text string) string {
 	return fmt.Sprintf("\033]8;;%s\033\\%s\033]8;;\033\\", url, text)
@@ -618,3 +1237,9 @@ func timeAgo(t time.Time) string {
 		return fmt.Sprintf("%d days ago", days)
 	}
 }
+
+// threadOrigin names where a thread came from: its subreddit, or the site of
+// a page from outside Reddit
+func threadOrigin(t types.ThreadState) string {
+	return export.Record{Subreddit: t.Subreddit, ThreadURL: export.FullURL(t.Permalink)}.Origin()
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"belaykit"
+	"belaykit/claude"
+	"belaykit/codex"
+
+	"hiveminer/internal/agent"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// adviseFlagReasons is how many of the ranker's flag reasons the advisor sees
+const adviseFlagReasons = 10
+
+func cmdRunsAdvise(args []string) error {
+	fs := flag.NewFlagSet("runs advise", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	model := fs.String("model", "sonnet", "Model proposing the edits")
+	useCodex := fs.Bool("codex", false, "Use Codex backend instead of Claude")
+	write := fs.String("write", "", "Write the form with the suggestions applied to this file")
+	statsOnly := fs.Bool("stats", false, "Only print the statistics, without asking for suggestions")
+	verbose := fs.Bool("verbose", false, "Show full agent log output")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.BoolVar(verbose, "v", false, "Verbose (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs advise <run-id> [--write forms/improved.json]")
+		return fmt.Errorf("run ID required")
+	}
+
+	_, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	form, err := loadFormFromManifest(manifest)
+	if err != nil {
+		if *write != "" {
+			return fmt.Errorf("loading the run's form: %w", err)
+		}
+		form = deriveFormFromManifest(manifest)
+	}
+	skipList, _ := session.LoadSkipList(*outputDir, manifest.Form.Title)
+	report := formReport(form, manifest, skipList)
+	if report.Threads == 0 {
+		fmt.Println("No evaluated threads yet.")
+		return nil
+	}
+
+	printFormReport(form, report)
+	if *statsOnly {
+		return nil
+	}
+	if report.Entries == 0 && len(report.SkipReasons) == 0 {
+		fmt.Println("\nNothing extracted or skipped yet to base suggestions on.")
+		return nil
+	}
+
+	var client agent.Runner
+	backend := "claude"
+	if *useCodex {
+		client = codex.NewClient()
+		backend = "codex"
+		if !flagWasSet(fs, "model") {
+			*model = ""
+		}
+	} else {
+		client = claude.NewClient()
+	}
+	var logger belaykit.EventHandler
+	if *verbose {
+		logger = belaykit.NewLogger(os.Stderr, belaykit.LogContent(true), belaykit.WithAgentName("advise"), belaykit.WithModelName(*model))
+	}
+
+	advisor := agent.NewClaudeAdvisor(client, os.DirFS("prompts"), *model, logger, backend)
+	advice, err := advisor.Advise(context.Background(), form, report)
+	if err != nil {
+		return fmt.Errorf("advising on form: %w", err)
+	}
+
+	fmt.Printf("\n%sSuggestions%s\n", colorBold, colorReset)
+	if advice.Summary != "" {
+		fmt.Printf("  %s\n", advice.Summary)
+	}
+	if len(advice.Suggestions) == 0 {
+		fmt.Println("  No changes suggested.")
+	}
+	for i, s := range advice.Suggestions {
+		target := "form"
+		if s.Field != "" {
+			target = s.Field
+		}
+		fmt.Printf("\n  %d. %s%s%s %s\n", i+1, colorCyan, s.Kind, colorReset, target)
+		fmt.Printf("     %s\n", s.Change)
+		if s.Reason != "" {
+			fmt.Printf("     %s%s%s\n", colorDim, s.Reason, colorReset)
+		}
+	}
+	fmt.Println()
+
+	if *write == "" {
+		if advice.Form != nil && len(advice.Suggestions) > 0 {
+			fmt.Println("Write the form with these edits applied with --write <file>.")
+		}
+		return nil
+	}
+	if advice.Form == nil {
+		if advice.FormError != "" {
+			return fmt.Errorf("the patched form isn't valid (%s); apply the suggestions by hand", advice.FormError)
+		}
+		return fmt.Errorf("no patched form was returned; apply the suggestions by hand")
+	}
+	data, err := json.MarshalIndent(advice.Form, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling form: %w", err)
+	}
+	if err := os.WriteFile(*write, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing form: %w", err)
+	}
+	fmt.Printf("Wrote the patched form to %s; diff it against %s before running it.\n", *write, manifest.Form.Path)
+	return nil
+}
+
+// formReport gathers the statistics an advisor works from: how often each
+// field was filled and how confidently, why threads were skipped and which
+// ranking flags entries drew
+func formReport(form *types.Form, manifest *types.Manifest, skipList *types.SkipList) agent.FormReport {
+	report := agent.FormReport{
+		Query:       manifest.Query,
+		SkipReasons: session.SkipReasons(manifest, skipList),
+		RankFlags:   map[string]int{},
+	}
+	type tally struct {
+		filled, low int
+		confidence  float64
+	}
+	tallies := map[string]*tally{}
+	for _, f := range form.Fields {
+		tallies[f.ID] = &tally{}
+	}
+	seenReasons := map[string]bool{}
+	for _, t := range manifest.Threads {
+		switch {
+		case t.Status == types.StatusSkipped:
+			report.Threads++
+			report.Skipped++
+		case types.HasEntries(t.Status):
+			report.Threads++
+			if len(t.Entries) > 0 {
+				report.Extracted++
+			}
+		default:
+			continue
+		}
+		for _, e := range t.Entries {
+			report.Entries++
+			for _, fv := range e.Fields {
+				tl, ok := tallies[fv.ID]
+				if !ok || !filledValue(fv.Value) {
+					continue
+				}
+				tl.filled++
+				tl.confidence += fv.Confidence
+				if fv.Confidence < 0.5 {
+					tl.low++
+				}
+			}
+			for _, id := range e.RankFlags {
+				report.RankFlags[id]++
+			}
+			if len(e.RankFlags) > 0 && e.RankReason != "" && len(report.FlagReasons) < adviseFlagReasons && !seenReasons[e.RankReason] {
+				seenReasons[e.RankReason] = true
+				report.FlagReasons = append(report.FlagReasons, e.RankReason)
+			}
+		}
+	}
+
+	for _, f := range form.Fields {
+		tl := tallies[f.ID]
+		fr := agent.FieldReport{ID: f.ID, Type: f.Type, Question: f.Question, Filled: tl.filled, LowConfidence: tl.low}
+		if report.Entries > 0 {
+			fr.FillRate = float64(tl.filled) / float64(report.Entries)
+		}
+		if tl.filled > 0 {
+			fr.AvgConfidence = tl.confidence / float64(tl.filled)
+		}
+		report.Fields = append(report.Fields, fr)
+	}
+	return report
+}
+
+// filledValue reports whether extraction found a value: not null, an empty
+// string or an empty list
+func filledValue(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case string:
+		return strings.TrimSpace(x) != ""
+	case []any:
+		return len(x) > 0
+	}
+	return true
+}
+
+func printFormReport(form *types.Form, report agent.FormReport) {
+	fmt.Printf("\n%s%s%s\n", colorBold, form.Title, colorReset)
+	fmt.Printf("%d threads evaluated: %d skipped, %d extracted into %d entries\n", report.Threads, report.Skipped, report.Extracted, report.Entries)
+
+	if report.Entries > 0 {
+		fmt.Printf("\n%sFields%s\n", colorBold, colorReset)
+		for _, f := range report.Fields {
+			fillColor := ""
+			if f.FillRate < 0.5 {
+				fillColor = colorYellow
+			}
+			line := fmt.Sprintf("  %-24s %s%4.0f%% filled%s", f.ID, fillColor, f.FillRate*100, colorReset)
+			if f.Filled > 0 {
+				line += fmt.Sprintf("  confidence %.2f", f.AvgConfidence)
+				if f.LowConfidence > 0 {
+					line += fmt.Sprintf(" (%d under 0.5)", f.LowConfidence)
+				}
+			}
+			fmt.Println(line)
+		}
+	}
+	if len(report.SkipReasons) > 0 {
+		fmt.Printf("\n%sSkip reasons%s\n", colorBold, colorReset)
+		for _, line := range session.FormatSkipReasons(report.SkipReasons, 0) {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	if len(report.RankFlags) > 0 {
+		fmt.Printf("\n%sRanking flags%s\n", colorBold, colorReset)
+		flags := make([]string, 0, len(report.RankFlags))
+		for id := range report.RankFlags {
+			flags = append(flags, id)
+		}
+		sort.Slice(flags, func(i, j int) bool {
+			if report.RankFlags[flags[i]] != report.RankFlags[flags[j]] {
+				return report.RankFlags[flags[i]] > report.RankFlags[flags[j]]
+			}
+			return flags[i] < flags[j]
+		})
+		for _, id := range flags {
+			fmt.Printf("  %-24s %d entries\n", id, report.RankFlags[id])
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"belaykit"
+	"belaykit/claude"
+	"belaykit/codex"
+
+	"hiveminer/internal/agent"
+	"hiveminer/internal/export"
+	"hiveminer/internal/index"
+)
+
+func cmdRunsAsk(args []string) error {
+	fs := flag.NewFlagSet("runs ask", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	model := fs.String("model", "sonnet", "Model used to answer")
+	useCodex := fs.Bool("codex", false, "Use Codex backend instead of Claude")
+	maxEntries := fs.Int("n", 50, "Maximum number of entries given as context: those sharing the most words with the question when the run is indexed, else the top-ranked (0 for all)")
+	verbose := fs.Bool("verbose", false, "Show full agent log output")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.BoolVar(verbose, "v", false, "Verbose (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs ask <run-id> \"question\"")
+		return fmt.Errorf("run ID and question required")
+	}
+	question := strings.Join(fs.Args()[1:], " ")
+
+	sessionDir, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	form, err := loadFormFromManifest(manifest)
+	if err != nil {
+		form = deriveFormFromManifest(manifest)
+	}
+
+	records := export.Records(manifest)
+	if len(records) == 0 {
+		fmt.Println("No extracted results yet.")
+		return nil
+	}
+	if *maxEntries > 0 && len(records) > *maxEntries {
+		// With an index, keep the entries most relevant to the question;
+		// otherwise keep the top-ranked ones
+		if idx := loadIndex(sessionDir); idx != nil {
+			records = relevantRecords(records, idx.RankEntries(embedder, question), *maxEntries)
+		} else {
+			records = records[:*maxEntries]
+		}
+	}
+
+	entries := make([]agent.AskEntry, len(records))
+	for i, r := range records {
+		entries[i] = agent.AskEntry{
+			Number:      r.Rank,
+			ThreadTitle: r.ThreadTitle,
+			Subreddit:   r.Subreddit,
+			Entry:       r.Entry,
+			Sources:     r.Sources,
+		}
+	}
+
+	var client agent.Runner
+	backend := "claude"
+	if *useCodex {
+		client = codex.NewClient()
+		backend = "codex"
+		if !flagWasSet(fs, "model") {
+			*model = ""
+		}
+	} else {
+		client = claude.NewClient()
+	}
+	var logger belaykit.EventHandler
+	if *verbose {
+		logger = belaykit.NewLogger(os.Stderr, belaykit.LogContent(true), belaykit.WithAgentName("ask"), belaykit.WithModelName(*model))
+	}
+
+	answerer := agent.NewClaudeAnswerer(client, os.DirFS("prompts"), *model, logger, backend)
+	answer, err := answerer.Answer(context.Background(), form, question, entries)
+	if err != nil {
+		return fmt.Errorf("answering question: %w", err)
+	}
+
+	fmt.Printf("\n%s%s%s\n\n", colorBold, question, colorReset)
+	fmt.Println(answer)
+	fmt.Println()
+	return nil
+}
+
+// relevantRecords keeps the n records whose entries rank highest for relevance,
+// preserving rank order
+func relevantRecords(records []export.Record, relevance []index.EntryKey, n int) []export.Record {
+	keep := map[index.EntryKey]bool{}
+	for _, key := range relevance {
+		if len(keep) >= n {
+			break
+		}
+		keep[key] = true
+	}
+	var out []export.Record
+	for _, r := range records {
+		if keep[index.EntryKey{PostID: r.ThreadID, EntryID: r.EntryID}] {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// flagWasSet reports whether a flag was given explicitly on the command line
+func flagWasSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"hiveminer/internal/export"
+	"hiveminer/internal/schema"
+)
+
+// dominantShare is the share of a session's entries above which one author's
+// evidence is called out as dominating the results
+const dominantShare = 0.2
+
+func cmdRunsAuthors(args []string) error {
+	fs := flag.NewFlagSet("runs authors", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	limit := fs.Int("n", 20, "Number of authors to show (0 = all)")
+	minEntries := fs.Int("min-entries", 1, "Only show authors backing at least this many entries")
+	jsonOut := fs.Bool("json", false, "Print the index as JSON")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs authors <run-id> [-n 20] [--min-entries 2] [--json]")
+		return fmt.Errorf("run ID required")
+	}
+
+	_, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	form, err := loadFormFromManifest(manifest)
+	if err != nil {
+		form = deriveFormFromManifest(manifest)
+	}
+
+	records := export.Records(manifest)
+	var authors []export.AuthorStats
+	for _, a := range export.Authors(records, schema.PrimaryField(form)) {
+		if a.Entries >= *minEntries {
+			authors = append(authors, a)
+		}
+	}
+	total := len(authors)
+	if *limit > 0 && len(authors) > *limit {
+		authors = authors[:*limit]
+	}
+
+	if *jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(authors)
+	}
+
+	fmt.Printf("\n%s%s%s — %d authors quoted as evidence\n\n", colorBold, manifest.Form.Title, colorReset, total)
+	if len(authors) == 0 {
+		fmt.Println("No quoted comments with authors")
+		return nil
+	}
+	fmt.Printf("  %-24s %8s %8s %8s %9s\n", "Author", "Entries", "Comments", "Threads", "Avg score")
+	for _, a := range authors {
+		avg := "-"
+		if a.AvgScore != nil {
+			avg = fmt.Sprintf("%.1f", *a.AvgScore)
+		}
+		fmt.Printf("  %-24s %8d %8d %8d %9s", truncateTitle(a.Author, 24), a.Entries, a.Comments, a.Threads, avg)
+		if a.Share >= dominantShare && a.Entries > 1 {
+			fmt.Printf("  %sbacks %.0f%% of entries%s", colorYellow, a.Share*100, colorReset)
+		}
+		fmt.Println()
+		if len(a.Top) > 0 {
+			fmt.Printf("  %s  %s%s\n", colorDim, strings.Join(a.Top, "; "), colorReset)
+		}
+	}
+	if total > len(authors) {
+		fmt.Printf("\n  ... %d more (use -n 0 to show all)\n", total-len(authors))
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"hiveminer/internal/session"
+)
+
+func cmdRunsClone(args []string) error {
+	fs := flag.NewFlagSet("runs clone", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	query := fs.String("query", "", "Search query for the new session")
+	subreddits := fs.String("subreddits", "", "Comma-separated subreddits for the new session (default: the source session's)")
+	rediscover := fs.Bool("rediscover", false, "Discover subreddits for the new topic instead of reusing the source session's")
+	name := fs.String("name", "", "Name the new session's directory instead of deriving it from the query")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.StringVar(query, "q", "", "Search query (shorthand)")
+	fs.StringVar(subreddits, "r", "", "Subreddits (shorthand)")
+	fs.Parse(args)
+	// Accept flags after the run ID too: runs clone <id> --query "..."
+	var target string
+	if fs.NArg() > 0 {
+		target = fs.Arg(0)
+		fs.Parse(fs.Args()[1:])
+	}
+
+	if target == "" || fs.NArg() > 0 || *query == "" {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs clone <run-id> --query \"new topic\" [--subreddits a,b] [--rediscover] [--name slug]")
+		return fmt.Errorf("run ID and --query required")
+	}
+	if *subreddits != "" && *rediscover {
+		return fmt.Errorf("--subreddits and --rediscover can't be combined")
+	}
+
+	_, source, err := loadSession(*outputDir, target)
+	if err != nil {
+		return err
+	}
+
+	var subs []string
+	discovered := false
+	switch {
+	case *subreddits != "":
+		for _, s := range strings.Split(*subreddits, ",") {
+			if s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "r/")); s != "" {
+				subs = append(subs, s)
+			}
+		}
+	case !*rediscover:
+		subs = append(subs, source.Subreddits...)
+		discovered = source.DiscoveredSubreddits
+	}
+
+	manifest := session.NewManifest(source.Form, *query, subs)
+	manifest.DiscoveredSubreddits = discovered && len(subs) > 0
+	manifest.Settings = map[string]string{}
+	for name, value := range source.Settings {
+		manifest.Settings[name] = value
+	}
+
+	slug := session.GenerateSlugFromQuery(*query)
+	if *name != "" {
+		if slug, err = newSessionName(*outputDir, *name); err != nil {
+			return err
+		}
+	}
+	parent, err := session.NewSessionParent(*outputDir, manifest.Form.Title)
+	if err != nil {
+		return err
+	}
+	sessionDir := filepath.Join(parent, slug)
+	if _, err := os.Stat(sessionDir); err == nil {
+		return fmt.Errorf("session %s already exists", sessionDir)
+	}
+	if err := os.MkdirAll(sessionDir, 0755); err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
+	if err := session.SaveManifest(sessionDir, manifest); err != nil {
+		return err
+	}
+	if err := session.RefreshFormIndex(sessionDir); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	fmt.Printf("Created %s from %s\n", slug, target)
+	fmt.Printf("  Form:       %s (%s)\n", manifest.Form.Title, manifest.Form.Path)
+	fmt.Printf("  Query:      %s\n", manifest.Query)
+	if len(subs) > 0 {
+		fmt.Printf("  Subreddits: r/%s\n", strings.Join(subs, ", r/"))
+	} else {
+		fmt.Println("  Subreddits: discovered on the first run")
+	}
+	if len(manifest.Settings) > 0 {
+		fmt.Printf("  Settings:   %s\n", formatSettings(manifest.Settings))
+	}
+	outputFlag := ""
+	if *outputDir != "./output" {
+		outputFlag = " -o " + *outputDir
+	}
+	fmt.Printf("\nRun it with: hiveminer run%s --session %s\n", outputFlag, slug)
+	return nil
+}
+
+// formatSettings lists settings as flags, sorted
+func formatSettings(settings map[string]string) string {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("--%s=%s", name, settings[name])
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"hiveminer/internal/control"
+)
+
+// cmdRunsControl sends a pause, resume or cancel command to a running session
+func cmdRunsControl(command string, args []string) error {
+	fs := flag.NewFlagSet("runs "+command, flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintf(os.Stderr, "Usage: hiveminer runs %s <run-id>\n", command)
+		return fmt.Errorf("run ID required")
+	}
+
+	sessionDir, err := resolveSessionDir(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	reply, err := control.Send(sessionDir, command)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return err
+	}
+	fmt.Printf("%s: %s\n", sessionDir, reply)
+	return nil
+}
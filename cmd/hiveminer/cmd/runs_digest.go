@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"hiveminer/internal/notify"
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+func cmdRunsDigest(args []string) error {
+	fs := flag.NewFlagSet("runs digest", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	email := fs.String("email", "", "Comma-separated recipients (prints the digest when empty)")
+	size := fs.Int("n", 10, "Number of top entries in the digest")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs digest <run-id> [--email a@example.com] [-n 10]")
+		return fmt.Errorf("run ID required")
+	}
+
+	sessionDir, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return sendDigest(sessionDir, manifest, splitList(*email), *size)
+}
+
+// sendDigest builds a digest of the session's top entries with changes since
+// the last digest, emails it (or prints it when there are no recipients), and
+// records the new top list for next time
+func sendDigest(sessionDir string, manifest *types.Manifest, to []string, size int) error {
+	form, err := loadFormFromManifest(manifest)
+	if err != nil {
+		form = deriveFormFromManifest(manifest)
+	}
+
+	prev, err := notify.LoadSnapshot(sessionDir)
+	if err != nil {
+		return err
+	}
+	digest, snap := notify.BuildDigest(manifest, schema.PrimaryField(form), prev, size)
+
+	if len(to) == 0 {
+		fmt.Printf("Subject: %s\n\n%s", digest.Subject(), digest.Text())
+		return nil
+	}
+
+	cfg, err := notify.SMTPConfigFromEnv(to)
+	if err != nil {
+		return err
+	}
+	if err := notify.NewSMTPNotifier(cfg).Send(digest); err != nil {
+		return err
+	}
+	fmt.Printf("Digest sent to %s\n", strings.Join(to, ", "))
+	return notify.SaveSnapshot(sessionDir, snap)
+}
+
+// splitList splits a comma-separated flag value, dropping blanks
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
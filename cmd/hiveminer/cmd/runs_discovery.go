@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// printDiscovery shows how a session's threads were found: the searches made
+// in each discovery round, then each discovered thread with why discovery
+// picked it and, if evaluation rejected it, why
+func printDiscovery(outputDir string, manifest *types.Manifest) {
+	fmt.Printf("\n%s%s%s\n", colorBold, manifest.Form.Title, colorReset)
+	if manifest.Query != "" {
+		fmt.Printf("Query: %s\n", manifest.Query)
+	}
+	if len(manifest.Subreddits) > 0 {
+		origin := "given"
+		if manifest.DiscoveredSubreddits {
+			origin = "discovered"
+		}
+		fmt.Printf("Subreddits (%s): r/%s\n", origin, strings.Join(manifest.Subreddits, ", r/"))
+	}
+
+	if len(manifest.SubredditYield) > 0 {
+		fmt.Printf("\n%sSubreddit yield%s\n", colorBold, colorReset)
+		for _, note := range session.YieldNotes(manifest.SubredditYield) {
+			fmt.Printf("  %s\n", note)
+		}
+	}
+
+	fmt.Printf("\n%sSearches%s\n", colorBold, colorReset)
+	if len(manifest.DiscoveryLog) == 0 {
+		fmt.Println("  No searches recorded")
+	}
+	lastRun, lastRound := "", -1
+	for _, s := range manifest.DiscoveryLog {
+		if s.Run != lastRun || s.Round != lastRound {
+			fmt.Printf("  %sRun %s, round %d — %s%s\n", colorDim, s.Run, s.Round, s.At.Local().Format("2006-01-02 15:04"), colorReset)
+			lastRun, lastRound = s.Run, s.Round
+		}
+		where := "all of Reddit"
+		if s.Subreddit != "" {
+			where = "r/" + s.Subreddit
+		}
+		resultColor := ""
+		if s.Results == 0 {
+			resultColor = colorYellow
+		}
+		switch s.Method {
+		case "listing":
+			fmt.Printf("    listed   %-24s %-40s %s%d results%s\n", where, s.Query, resultColor, s.Results, colorReset)
+		default:
+			fmt.Printf("    %-8s %-24s %-40q %s%d results%s\n", s.Method, where, s.Query, resultColor, s.Results, colorReset)
+		}
+	}
+
+	// Evaluation's rejections are kept on the form's skip list
+	skipList, _ := session.LoadSkipList(outputDir, manifest.Form.Title)
+
+	if groups := session.SkipReasons(manifest, skipList); len(groups) > 0 {
+		fmt.Printf("\n%sSkip reasons%s\n", colorBold, colorReset)
+		for _, line := range session.FormatSkipReasons(groups, 0) {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+
+	fmt.Printf("\n%sThreads (%d)%s\n", colorBold, len(manifest.Threads), colorReset)
+	for _, t := range manifest.Threads {
+		statusColor := colorGreen
+		switch t.Status {
+		case types.StatusSkipped, types.StatusFailed, types.StatusQuarantined:
+			statusColor = colorRed
+		case types.StatusPending, types.StatusCollected:
+			statusColor = colorYellow
+		}
+		fmt.Printf("  %s%-11s%s %-22s %s\n", statusColor, t.Status, colorReset, threadOrigin(t), truncateTitle(t.Title, 70))
+		if t.EstimatedEntries > 0 {
+			line := fmt.Sprintf("est. %d entries", t.EstimatedEntries)
+			if types.HasEntries(t.Status) {
+				line += fmt.Sprintf(", %d extracted", len(t.Entries))
+			}
+			fmt.Printf("    %s%s%s\n", colorDim, line, colorReset)
+		}
+		switch {
+		case t.DiscoveryReason != "":
+			fmt.Printf("    %spicked: %s%s\n", colorDim, t.DiscoveryReason, colorReset)
+		case t.Pinned:
+			fmt.Printf("    %spinned%s\n", colorDim, colorReset)
+		case t.Source != "":
+			fmt.Printf("    %s%s page%s\n", colorDim, t.Source, colorReset)
+		}
+		if skipList != nil && t.Status == types.StatusSkipped {
+			if s, ok := skipList.Threads[t.PostID]; ok && s.Reason != "" {
+				fmt.Printf("    %sskipped by %s: %s%s\n", colorDim, s.By, s.Reason, colorReset)
+			}
+		}
+	}
+}
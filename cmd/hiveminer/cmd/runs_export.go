@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"hiveminer/internal/export"
+	"hiveminer/internal/geocode"
+	"hiveminer/internal/schema"
+)
+
+func cmdRunsExport(args []string) error {
+	fs := flag.NewFlagSet("runs export", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	format := fs.String("format", "json", "Export format: "+strings.Join(export.Formats, ", "))
+	outFile := fs.String("file", "", "Write to file instead of stdout")
+	flagFilter := fs.String("flag", "", "Only export entries with this rank flag")
+	hideFlagged := fs.String("hide-flagged", "", "Skip entries with a flag of this severity or worse: minor, moderate, severe")
+	sources := fs.Bool("sources", false, "Append a sources appendix with every quoted comment's permalink and retrieval time (markdown)")
+	anonymize := fs.String("anonymize", "", "Scrub usernames and personal details: hash (stable pseudonyms) or strip")
+	near := fs.String("near", "", "Only export entries with a geocoded location near this place or lat,lon")
+	within := fs.Float64("within", 50, "Distance in km for --near")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.StringVar(format, "f", "json", "Export format (shorthand)")
+	fs.Parse(args)
+
+	if *sources && *format != "markdown" {
+		return fmt.Errorf("--sources needs --format markdown")
+	}
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs export <run-id> [--format json] [--file path]")
+		return fmt.Errorf("run ID required")
+	}
+
+	_, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	form, err := loadFormFromManifest(manifest)
+	if err != nil {
+		form = deriveFormFromManifest(manifest)
+	}
+	if err := checkFlagFilters(form, *flagFilter, *hideFlagged); err != nil {
+		return err
+	}
+	origin, err := resolveNear(*near)
+	if err != nil {
+		return err
+	}
+	if *anonymize != "" {
+		if err := export.AnonymizeManifest(manifest, *anonymize); err != nil {
+			return err
+		}
+	}
+
+	var records []export.Record
+	for _, r := range export.Records(manifest) {
+		if !matchesFlagFilters(form, r.RankFlags, *flagFilter, *hideFlagged) {
+			continue
+		}
+		if origin != nil {
+			d, ok := geocode.Nearest(r.Entry, *origin)
+			if !ok || d > *within {
+				continue
+			}
+			r.DistanceKM = &d
+		}
+		r.FlagSeverity = schema.WorstSeverity(form, r.RankFlags)
+		records = append(records, r)
+	}
+
+	var w io.Writer = os.Stdout
+	if *outFile != "" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "markdown" {
+		err = export.WriteMarkdown(w, records, export.MarkdownOptions{
+			Title:        form.Title,
+			PrimaryField: schema.PrimaryField(form),
+			Fields:       form.Fields,
+			Sources:      *sources,
+		})
+	} else if *format == "html" {
+		err = export.WriteHTML(w, records, export.HTMLOptions{
+			Title:        form.Title,
+			PrimaryField: schema.PrimaryField(form),
+			Fields:       form.Fields,
+		})
+	} else if *format == "csv" || *format == "tsv" {
+		err = export.WriteTable(w, *format, records, export.TableOptions{Fields: form.Fields})
+	} else if *format == "graph" || *format == "graphml" {
+		err = export.WriteGraph(w, *format, records, export.GraphOptions{PrimaryField: schema.PrimaryField(form)})
+	} else {
+		err = export.Write(w, *format, records)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+	if *outFile != "" {
+		fmt.Fprintf(os.Stderr, "Exported %d entries to %s\n", len(records), *outFile)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hiveminer/internal/export"
+	"hiveminer/internal/schema"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// feedDir is where a form's RSS and JSON feeds live
+func feedDir(outputDir, formTitle string) string {
+	return filepath.Join(outputDir, ".feeds", session.FormSlug(formTitle))
+}
+
+func cmdRunsFeed(args []string) error {
+	fs := flag.NewFlagSet("runs feed", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	minScore := fs.Float64("min-score", 60, "Minimum rank score for an entry to be published")
+	latest := fs.Bool("latest", false, "Only publish entries extracted in the run's latest invocation")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs feed <run-id> [--min-score 60] [--latest]")
+		return fmt.Errorf("run ID required")
+	}
+
+	_, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return publishFeed(*outputDir, manifest, *minScore, *latest)
+}
+
+// publishFeed adds a session's high-scoring entries to its form's feed. With
+// latestOnly, only entries extracted by the most recent run are considered, so
+// refreshes publish just what's new.
+func publishFeed(outputDir string, manifest *types.Manifest, minScore float64, latestOnly bool) error {
+	form, err := loadFormFromManifest(manifest)
+	if err != nil {
+		form = deriveFormFromManifest(manifest)
+	}
+
+	runID := ""
+	if latestOnly && len(manifest.Runs) > 0 {
+		runID = manifest.Runs[len(manifest.Runs)-1].InvocationID
+	}
+
+	var records []export.Record
+	for _, r := range export.Records(manifest) {
+		if r.RankScore == nil || *r.RankScore < minScore {
+			continue
+		}
+		if latestOnly && (r.Entry.Provenance == nil || r.Entry.Provenance.RunID != runID) {
+			continue
+		}
+		records = append(records, r)
+	}
+
+	dir := feedDir(outputDir, manifest.Form.Title)
+	added, err := export.PublishFeed(dir, manifest.Form.Title, schema.PrimaryField(form), records, time.Now())
+	if err != nil {
+		return err
+	}
+	jsonPath, rssPath := export.FeedPaths(dir)
+	fmt.Printf("Feed: %d new entries published to %s and %s\n", added, rssPath, jsonPath)
+	return nil
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"hiveminer/internal/control"
+	"hiveminer/internal/schema"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// followInterval is how often a followed session's manifest is checked
+const followInterval = 2 * time.Second
+
+// followRun tails a running session's manifest, printing thread progress and
+// each entry as it's extracted and then ranked, until the run ends or the
+// user interrupts. The run itself is unaffected by interrupting the follow.
+func followRun(sessionDir string, form *types.Form) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	manifestPath := filepath.Join(sessionDir, "manifest.json")
+	primaryID := schema.PrimaryField(form)
+	seen := map[string]bool{}   // entries printed, by entry ID
+	ranked := map[string]bool{} // entries whose score was printed, by entry ID
+	var lastMod time.Time
+	var lastProgress string
+	unreachable := 0
+
+	fmt.Printf("Following %s (Ctrl-C stops following; hiveminer runs cancel stops the run)\n\n", filepath.Base(sessionDir))
+	for {
+		if info, err := os.Stat(manifestPath); err == nil && info.ModTime() != lastMod {
+			lastMod = info.ModTime()
+			manifest, err := session.LoadManifest(sessionDir)
+			if err == nil && manifest != nil {
+				session.SettleEntryIDs(manifest, primaryID)
+				if p := followProgress(manifest); p != lastProgress {
+					fmt.Printf("%s%s%s\n", colorDim, p, colorReset)
+					lastProgress = p
+				}
+				printNewEntries(manifest, form, primaryID, seen, ranked)
+				if n := len(manifest.Runs); n > 0 && manifest.Runs[n-1].Status != "running" {
+					fmt.Printf("\nRun %s.\n", manifest.Runs[n-1].Status)
+					return nil
+				}
+			}
+		}
+
+		// A run that died without recording its end has no control socket
+		if _, err := control.Send(sessionDir, control.CmdStatus); err != nil {
+			unreachable++
+			if unreachable >= 3 {
+				fmt.Println("\nNo process is running this session.")
+				return nil
+			}
+		} else {
+			unreachable = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println()
+			return nil
+		case <-time.After(followInterval):
+		}
+	}
+}
+
+// followProgress summarizes a session's threads by status
+func followProgress(manifest *types.Manifest) string {
+	counts := session.CountByStatus(manifest)
+	var parts []string
+	for _, status := range types.ThreadStatuses {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	if unknown := session.UnknownStatuses(manifest); len(unknown) > 0 {
+		parts = append(parts, "unknown status: "+session.FormatUnknownStatuses(unknown))
+	}
+	if len(parts) == 0 {
+		return "Threads: none yet"
+	}
+	return "Threads: " + strings.Join(parts, ", ")
+}
+
+// printNewEntries prints entries not yet seen, and the scores of entries
+// ranked since the last check
+func printNewEntries(manifest *types.Manifest, form *types.Form, primaryID string, seen, ranked map[string]bool) {
+	for _, t := range manifest.Threads {
+		if !types.HasEntries(t.Status) {
+			continue
+		}
+		for _, entry := range t.Entries {
+			key := entry.ID
+			name := followEntryName(entry, primaryID)
+			if !seen[key] {
+				seen[key] = true
+				fmt.Printf("%s+%s %s%s%s  %sr/%s · %s%s\n", colorGreen, colorReset, colorBold, name, colorReset,
+					colorDim, t.Subreddit, truncateTitle(t.Title, 50), colorReset)
+				if details := followEntryDetails(entry, form, primaryID); details != "" {
+					fmt.Printf("    %s%s%s\n", colorDim, details, colorReset)
+				}
+			}
+			if entry.RankScore != nil && !ranked[key] {
+				ranked[key] = true
+				flags := ""
+				if len(entry.RankFlags) > 0 {
+					flags = " " + colorYellow + "[" + strings.Join(entry.RankFlags, "] [") + "]" + colorReset
+				}
+				fmt.Printf("%s★%s %s  %s%.0fpts%s%s\n", colorCyan, colorReset, name, colorGreen, *entry.RankScore, colorReset, flags)
+			}
+		}
+	}
+}
+
+// followEntryName is an entry's primary field value
+func followEntryName(entry types.Entry, primaryID string) string {
+	for _, fv := range entry.Fields {
+		if fv.ID == primaryID && fv.Value != nil {
+			return inlineValue(fv.Value)
+		}
+	}
+	return "(unnamed entry)"
+}
+
+// inlineValue renders a value on one line
+func inlineValue(v any) string {
+	if items, ok := v.([]any); ok {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return strings.ReplaceAll(formatValue(v), "\n", "; ")
+}
+
+// followEntryDetails lists an entry's other visible field values on one line
+func followEntryDetails(entry types.Entry, form *types.Form, primaryID string) string {
+	var parts []string
+	for _, f := range form.Fields {
+		if f.ID == primaryID || f.Internal {
+			continue
+		}
+		for _, fv := range entry.Fields {
+			if fv.ID == f.ID && fv.Value != nil {
+				parts = append(parts, fmt.Sprintf("%s: %s", f.ID, inlineValue(fv.Value)))
+			}
+		}
+	}
+	return truncateTitle(strings.Join(parts, " · "), 110)
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"hiveminer/internal/export"
+	"hiveminer/internal/index"
+	"hiveminer/pkg/types"
+)
+
+// embedder is the embedding scheme used for session indexes: lexical, so
+// searches match shared words rather than paraphrases
+var embedder index.Embedder = index.NewHashEmbedder(512)
+
+func cmdRunsIndex(args []string) error {
+	fs := flag.NewFlagSet("runs index", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs index <run-id>")
+		return fmt.Errorf("run ID required")
+	}
+
+	sessionDir, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return buildIndex(sessionDir, manifest)
+}
+
+// buildIndex (re)builds and saves a session's embedding index
+func buildIndex(sessionDir string, manifest *types.Manifest) error {
+	idx := index.Build(manifest, embedder)
+	if err := index.Save(sessionDir, idx); err != nil {
+		return err
+	}
+	fmt.Printf("Indexed %d items in %s\n", len(idx.Items), sessionDir)
+	return nil
+}
+
+// loadIndex loads a session's index if it exists and matches the current
+// embedder and index version
+func loadIndex(sessionDir string) *index.Index {
+	idx, err := index.Load(sessionDir)
+	if err != nil || idx == nil || idx.Embedder != embedder.Name() || idx.Version != index.Version {
+		return nil
+	}
+	return idx
+}
+
+func cmdRunsSearch(args []string) error {
+	fs := flag.NewFlagSet("runs search", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	limit := fs.Int("n", 10, "Maximum number of results")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs search <run-id> \"text\"")
+		return fmt.Errorf("run ID and search text required")
+	}
+	query := strings.Join(fs.Args()[1:], " ")
+
+	sessionDir, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	idx := loadIndex(sessionDir)
+	if idx == nil {
+		fmt.Fprintf(os.Stderr, "No index for this run. Run 'hiveminer runs index %s' first.\n", fs.Arg(0))
+		return fmt.Errorf("no index")
+	}
+
+	ranks := map[index.EntryKey]int{}
+	for _, r := range export.Records(manifest) {
+		ranks[index.EntryKey{PostID: r.ThreadID, EntryID: r.EntryID}] = r.Rank
+	}
+
+	fmt.Printf("\n%s%s%s\n\n", colorBold, query, colorReset)
+	for _, hit := range idx.Search(embedder, query, *limit) {
+		text := strings.ReplaceAll(hit.Item.Text, "\n", "; ")
+		if len(text) > 100 {
+			text = text[:100] + "..."
+		}
+		label := hit.Item.Kind
+		if hit.Item.FieldID != "" {
+			label += " · " + hit.Item.FieldID
+		}
+		fmt.Printf(" %s#%-3d%s %s%.2f%s  %s%s%s\n", colorMag, ranks[index.EntryKey{PostID: hit.Item.PostID, EntryID: hit.Item.EntryID}],
+			colorReset, colorGreen, hit.Score, colorReset, colorDim, label, colorReset)
+		fmt.Printf("      %s\n", text)
+	}
+	fmt.Println()
+	return nil
+}
+
+func cmdRunsSimilar(args []string) error {
+	fs := flag.NewFlagSet("runs similar", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	limit := fs.Int("n", 5, "Maximum number of sessions")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs similar \"topic\"")
+		return fmt.Errorf("topic required")
+	}
+	topic := strings.Join(fs.Args(), " ")
+	q := embedder.Embed(topic)
+
+	sessions, err := listSessions(*outputDir)
+	if err != nil {
+		return err
+	}
+
+	type match struct {
+		name  string
+		title string
+		score float64
+		best  string
+	}
+	var matches []match
+	for _, s := range sessions {
+		idx := loadIndex(s.Dir)
+		if idx == nil {
+			continue
+		}
+		m := match{name: s.Name, title: s.Manifest.Form.Title, score: index.Cosine(q, idx.Query)}
+		if hits := idx.Search(embedder, topic, 1); len(hits) > 0 && hits[0].Score > m.score {
+			m.score = hits[0].Score
+			m.best = hits[0].Item.Text
+		}
+		matches = append(matches, m)
+	}
+	if len(matches) == 0 {
+		fmt.Println("No indexed runs found. Build one with 'hiveminer runs index <run-id>'.")
+		return nil
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	if len(matches) > *limit {
+		matches = matches[:*limit]
+	}
+
+	fmt.Printf("\n%sRuns similar to:%s %s\n\n", colorBold, colorReset, topic)
+	for _, m := range matches {
+		fmt.Printf(" %s%.2f%s  %s%s%s  %s%s%s\n", colorGreen, m.score, colorReset, colorBold, m.name, colorReset, colorDim, m.title, colorReset)
+		if m.best != "" {
+			best := strings.ReplaceAll(m.best, "\n", "; ")
+			if len(best) > 90 {
+				best = best[:90] + "..."
+			}
+			fmt.Printf("        %s\n", best)
+		}
+	}
+	fmt.Println()
+	return nil
+}
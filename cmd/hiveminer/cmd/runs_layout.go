@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"hiveminer/internal/control"
+	"hiveminer/internal/session"
+)
+
+func cmdRunsLayout(args []string) error {
+	fs := flag.NewFlagSet("runs layout", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	migrate := fs.Bool("migrate", false, "Move sessions kept directly in the output directory into per-form folders")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	flat := session.FlatSessionDirs(*outputDir)
+	version := session.LayoutVersion(*outputDir)
+	if !*migrate {
+		switch version {
+		case session.LayoutFlat:
+			fmt.Printf("%s: flat layout (version %d), %d runs directly in the output directory\n", *outputDir, version, len(flat))
+			fmt.Println("Move them into per-form folders with 'hiveminer runs layout --migrate'.")
+		default:
+			fmt.Printf("%s: per-form layout (version %d)\n", *outputDir, version)
+			if len(flat) > 0 {
+				fmt.Printf("%d runs are still directly in the output directory; 'hiveminer runs layout --migrate' moves them.\n", len(flat))
+			}
+		}
+		return nil
+	}
+
+	// Sessions keep their names, so the knowledge base and yield histories
+	// still find them; only the form indexes change
+	formDirs := map[string]bool{}
+	left := 0
+	for _, dir := range flat {
+		name := filepath.Base(dir)
+		if _, err := control.Send(dir, "status"); err == nil {
+			fmt.Printf("  Skipped %s: a process is running it\n", name)
+			left++
+			continue
+		}
+		manifest, err := session.LoadManifest(dir)
+		if err != nil || manifest == nil {
+			fmt.Printf("  Skipped %s: %v\n", name, err)
+			left++
+			continue
+		}
+		formDir := session.FormDir(*outputDir, manifest.Form.Title)
+		if _, err := os.Stat(filepath.Join(formDir, "manifest.json")); err == nil {
+			fmt.Printf("  Skipped %s: its form's folder %s is a run; rename that run first\n", name, formDir)
+			left++
+			continue
+		}
+		target := filepath.Join(formDir, name)
+		if _, err := os.Stat(target); err == nil {
+			fmt.Printf("  Skipped %s: %s already exists\n", name, target)
+			left++
+			continue
+		}
+		if err := os.MkdirAll(formDir, 0755); err != nil {
+			return fmt.Errorf("creating form folder: %w", err)
+		}
+		if err := os.Rename(dir, target); err != nil {
+			return fmt.Errorf("moving %s: %w", name, err)
+		}
+		formDirs[formDir] = true
+		fmt.Printf("  %s -> %s\n", name, target)
+	}
+	for formDir := range formDirs {
+		if err := session.UpdateFormIndex(formDir); err != nil {
+			return err
+		}
+	}
+
+	if left > 0 {
+		fmt.Printf("Moved %d runs; %d left in place. The output directory stays flat until they're moved.\n", len(flat)-left, left)
+		return nil
+	}
+	if err := session.SetLayoutVersion(*outputDir, session.LayoutPerForm); err != nil {
+		return err
+	}
+	fmt.Printf("Moved %d runs into per-form folders (layout version %d).\n", len(flat), session.LayoutPerForm)
+	return nil
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+func cmdRunsPin(args []string) error {
+	fs := flag.NewFlagSet("runs pin", flag.ExitOnError)
+	newSearcher := addTransportFlags(fs)
+	outputDir := fs.String("output", "./output", "Output directory")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs pin <run-id> <permalink> [permalink...]")
+		return fmt.Errorf("run ID and permalink required")
+	}
+
+	sessionDir, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	searcher, err := newSearcher()
+	if err != nil {
+		return err
+	}
+	for _, permalink := range fs.Args()[1:] {
+		postID := postIDFromPermalink(permalink)
+		if postID == "" {
+			fmt.Fprintf(os.Stderr, "Skipping %s: not a thread permalink\n", permalink)
+			continue
+		}
+
+		ts := types.ThreadState{PostID: postID, Permalink: permalink}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		thread, err := searcher.GetThread(ctx, permalink, 1)
+		cancel()
+		if err == nil && thread.Post.ID != "" {
+			ts.PostID = thread.Post.ID
+			ts.Permalink = thread.Post.Permalink
+			ts.Title = thread.Post.Title
+			ts.Subreddit = thread.Post.Subreddit
+			ts.Score = thread.Post.Score
+			ts.NumComments = thread.Post.NumComments
+		} else if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not fetch %s (%v), pinning by ID\n", permalink, err)
+		}
+
+		session.PinThread(manifest, ts)
+		title := ts.Title
+		if title == "" {
+			title = ts.PostID
+		}
+		fmt.Printf("Pinned %s\n", title)
+	}
+
+	if err := session.SaveManifest(sessionDir, manifest); err != nil {
+		return fmt.Errorf("saving manifest: %w", err)
+	}
+	fmt.Printf("Run 'hiveminer run --form %s --session %s' to refresh.\n", manifest.Form.Path, sessionDir)
+	return nil
+}
+
+func cmdRunsUnpin(args []string) error {
+	fs := flag.NewFlagSet("runs unpin", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs unpin <run-id> <permalink|post-id> [...]")
+		return fmt.Errorf("run ID and permalink required")
+	}
+
+	sessionDir, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	for _, target := range fs.Args()[1:] {
+		postID := postIDFromPermalink(target)
+		if postID == "" {
+			postID = target
+		}
+		if session.UnpinThread(manifest, postID) {
+			fmt.Printf("Unpinned %s\n", postID)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s is not pinned\n", postID)
+		}
+	}
+
+	return session.SaveManifest(sessionDir, manifest)
+}
+
+// loadSession resolves a run ID and loads its manifest
+func loadSession(outputDir, target string) (string, *types.Manifest, error) {
+	sessionDir, err := resolveSessionDir(outputDir, target)
+	if err != nil {
+		return "", nil, err
+	}
+	manifest, err := session.LoadManifest(sessionDir)
+	if err != nil {
+		return "", nil, fmt.Errorf("loading manifest: %w", err)
+	}
+	if manifest == nil {
+		return "", nil, fmt.Errorf("no manifest found in %s", sessionDir)
+	}
+	return sessionDir, manifest, nil
+}
+
+// postIDFromPermalink extracts the post ID from a thread permalink or URL
+// like /r/sub/comments/abc123/title/
+func postIDFromPermalink(permalink string) string {
+	parts := strings.Split(strings.Trim(permalink, "/"), "/")
+	for i, p := range parts {
+		if p == "comments" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"hiveminer/internal/export"
+	"hiveminer/internal/orchestrator"
+	"hiveminer/internal/schema"
+)
+
+func cmdRunsPublish(args []string) error {
+	fs := flag.NewFlagSet("runs publish", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	outFile := fs.String("file", "", "Bundle path (default: <run-id>.zip in the current directory)")
+	anonymize := fs.String("anonymize", "", "Scrub usernames and personal details: "+strings.Join(export.AnonymizeModes, ", ")+"; leaves the thread archive out")
+	noSources := fs.Bool("no-sources", false, "Leave the thread archive out of the bundle")
+	flagFilter := fs.String("flag", "", "Only publish entries with this rank flag")
+	hideFlagged := fs.String("hide-flagged", "", "Leave out entries with a flag of at least this severity")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs publish <run-id> [--file bundle.zip] [--anonymize hash|strip]")
+		return fmt.Errorf("run ID required")
+	}
+
+	sessionDir, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	name := filepath.Base(sessionDir)
+	form, err := loadFormFromManifest(manifest)
+	if err != nil {
+		form = deriveFormFromManifest(manifest)
+	}
+	if err := checkFlagFilters(form, *flagFilter, *hideFlagged); err != nil {
+		return err
+	}
+	if *anonymize != "" {
+		if err := export.AnonymizeManifest(manifest, *anonymize); err != nil {
+			return err
+		}
+	}
+
+	var records []export.Record
+	threads := map[string]bool{}
+	for _, r := range export.Records(manifest) {
+		if !matchesFlagFilters(form, r.RankFlags, *flagFilter, *hideFlagged) {
+			continue
+		}
+		r.FlagSeverity = schema.WorstSeverity(form, r.RankFlags)
+		records = append(records, r)
+		threads[r.ThreadID] = true
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("no extracted entries to publish")
+	}
+
+	if *outFile == "" {
+		*outFile = name + ".zip"
+	}
+	absSession, _ := filepath.Abs(sessionDir)
+	absOutput, _ := filepath.Abs(*outputDir)
+	cwd, _ := os.Getwd()
+	redactor := export.NewRedactor(map[string]string{
+		"[session]": absSession,
+		"[output]":  absOutput,
+		".":         cwd,
+	})
+
+	// Everything goes through the redactor on its way into the archive
+	type bundleFile struct {
+		name string
+		data []byte
+	}
+	var files []bundleFile
+	add := func(name string, data []byte) {
+		files = append(files, bundleFile{name, []byte(redactor.Redact(string(data)))})
+	}
+
+	var buf bytes.Buffer
+	if err := export.WriteHTML(&buf, records, export.HTMLOptions{
+		Title:        form.Title,
+		PrimaryField: schema.PrimaryField(form),
+		Fields:       form.Fields,
+	}); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	add("report.html", buf.Bytes())
+	buf.Reset()
+	if err := export.Write(&buf, "jsonl", records); err != nil {
+		return fmt.Errorf("writing entries: %w", err)
+	}
+	add("entries.jsonl", buf.Bytes())
+	if data, err := json.MarshalIndent(form, "", "  "); err == nil {
+		add("form.json", append(data, '\n'))
+	}
+
+	archived, missing := 0, 0
+	if *anonymize == "" && !*noSources {
+		for _, t := range manifest.Threads {
+			if !threads[t.PostID] {
+				continue
+			}
+			data, err := orchestrator.ThreadPayload(sessionDir, t.PostID)
+			if err != nil {
+				missing++
+				continue
+			}
+			add(fmt.Sprintf("sources/thread_%s.json", t.PostID), data)
+			archived++
+		}
+	}
+	add("README.txt", []byte(bundleReadme(form.Title, manifest.Query, len(records), len(threads), archived)))
+
+	f, err := os.Create(*outFile)
+	if err != nil {
+		return fmt.Errorf("creating bundle: %w", err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	now := time.Now()
+	for _, bf := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name + "/" + bf.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return fmt.Errorf("writing bundle: %w", err)
+		}
+		if _, err := w.Write(bf.data); err != nil {
+			return fmt.Errorf("writing bundle: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+
+	fmt.Printf("Published %d entries from %d threads to %s\n", len(records), len(threads), *outFile)
+	switch {
+	case *anonymize != "":
+		fmt.Println("  Thread archive left out: raw threads name their commenters.")
+	case *noSources:
+	case missing > 0:
+		fmt.Printf("  %d thread payloads weren't saved in the session and are missing from the archive.\n", missing)
+	}
+	return nil
+}
+
+// bundleReadme describes a published bundle's contents
+func bundleReadme(title, query string, entries, threads, archived int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", title)
+	if query != "" {
+		fmt.Fprintf(&b, "Query: %s\n", query)
+	}
+	fmt.Fprintf(&b, "%d entries from %d threads, published %s by hiveminer.\n\n", entries, threads, time.Now().UTC().Format("2006-01-02 15:04 MST"))
+	b.WriteString("report.html    the entries as a standalone web page, best first\n")
+	b.WriteString("entries.jsonl  one JSON object per entry, with its fields, evidence and source links\n")
+	b.WriteString("form.json      the form the entries were extracted with\n")
+	if archived > 0 {
+		fmt.Fprintf(&b, "sources/       the %d Reddit threads as retrieved, one JSON file each\n", archived)
+	}
+	b.WriteString("\nLocal paths and credentials were replaced with placeholders such as [session] and [redacted].\n")
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"hiveminer/internal/control"
+	"hiveminer/internal/kb"
+	"hiveminer/internal/session"
+)
+
+func cmdRunsRename(args []string) error {
+	fs := flag.NewFlagSet("runs rename", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs rename <run-id> <new-name>")
+		return fmt.Errorf("run ID and new name required")
+	}
+
+	sessionDir, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if _, err := control.Send(sessionDir, "status"); err == nil {
+		return fmt.Errorf("a process is running this session; wait for it or cancel it before renaming")
+	}
+	newName, err := newSessionName(*outputDir, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	name := filepath.Base(sessionDir)
+	newDir := filepath.Join(filepath.Dir(sessionDir), newName)
+	if _, err := os.Stat(newDir); err == nil {
+		return fmt.Errorf("%s already exists", newDir)
+	}
+	if err := os.Rename(sessionDir, newDir); err != nil {
+		return fmt.Errorf("renaming session: %w", err)
+	}
+	fmt.Printf("Renamed %s to %s\n", name, newName)
+	if err := session.RefreshFormIndex(newDir); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	// The cross-session records name sessions by directory
+	k, err := kb.Load(*outputDir)
+	if err != nil {
+		return err
+	}
+	if k != nil && k.RenameSession(name, newName) {
+		if err := kb.Save(*outputDir, k); err != nil {
+			return err
+		}
+		fmt.Println("  Updated the knowledge base.")
+	}
+	history, err := session.LoadYieldHistory(*outputDir, manifest.Form.Title)
+	if err != nil {
+		return err
+	}
+	if yields, ok := history.Sessions[name]; ok {
+		delete(history.Sessions, name)
+		history.Sessions[newName] = yields
+		if err := session.SaveYieldHistory(*outputDir, history); err != nil {
+			return err
+		}
+		fmt.Println("  Updated the form's subreddit yield history.")
+	}
+	return nil
+}
+
+// newSessionName returns the directory name for a session named by the user,
+// failing if it's empty or another session in outputDir has it
+func newSessionName(outputDir, name string) (string, error) {
+	slug := session.NameSlug(name)
+	if slug == "" {
+		return "", fmt.Errorf("session name %q has no letters or digits", name)
+	}
+	if dir := session.FindSession(outputDir, slug); dir != "" {
+		return "", fmt.Errorf("session %s already exists", dir)
+	}
+	return slug, nil
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"hiveminer/internal/orchestrator"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+func cmdRunsRetry(args []string) error {
+	fs := flag.NewFlagSet("runs retry", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	codes := fs.String("code", "", "Comma-separated error codes or stages to retry, e.g. fetch_429,parse_failure or fetch (default: all failures)")
+	dryRun := fs.Bool("dry-run", false, "List the failed threads that would be retried without changing the run")
+	quarantined := fs.Bool("quarantined", false, "Also release quarantined threads, resetting their attempt count")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs retry [--code fetch_429,parse_failure] [--quarantined] [--dry-run] <run-id>")
+		return fmt.Errorf("run ID required")
+	}
+
+	sessionDir, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var targets []string
+	for _, c := range strings.Split(*codes, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			targets = append(targets, c)
+		}
+	}
+
+	failures := session.CountFailures(manifest)
+	held := session.CountByStatus(manifest)[types.StatusQuarantined]
+	if len(failures) == 0 && (held == 0 || !*quarantined) {
+		fmt.Println("No failed threads.")
+		if held > 0 {
+			fmt.Printf("%d quarantined threads; pass --quarantined to release them.\n", held)
+		}
+		return nil
+	}
+	if len(failures) > 0 {
+		fmt.Printf("Failed threads: %s\n", orchestrator.FormatFailures(failures))
+	}
+	if held > 0 {
+		fmt.Printf("Quarantined threads: %d\n", held)
+	}
+
+	retried := 0
+	for i := range manifest.Threads {
+		t := &manifest.Threads[i]
+		if t.Status != types.StatusFailed && (t.Status != types.StatusQuarantined || !*quarantined) {
+			continue
+		}
+		code := t.ErrorCode
+		if code == "" {
+			code = types.ErrUnknown
+		}
+		if !matchesErrorCode(code, targets) {
+			continue
+		}
+		retried++
+		if *dryRun {
+			fmt.Printf("  %-16s %s  %s%s%s\n", code, truncateTitle(t.Title, 50), colorDim, t.Error, colorReset)
+			if t.Status == types.StatusQuarantined {
+				for _, e := range t.ErrorHistory {
+					fmt.Printf("  %16s   %s%s%s\n", "", colorDim, e, colorReset)
+				}
+			}
+			continue
+		}
+
+		// A released thread gets a fresh set of attempts
+		if t.Status == types.StatusQuarantined {
+			t.Attempts = 0
+		}
+		orchestrator.ResetFailedThread(t, sessionDir)
+	}
+
+	if retried == 0 {
+		fmt.Println("No threads match the given codes.")
+		return nil
+	}
+	if *dryRun {
+		fmt.Printf("%d threads would be retried.\n", retried)
+		return nil
+	}
+	if err := session.SaveManifest(sessionDir, manifest); err != nil {
+		return fmt.Errorf("saving manifest: %w", err)
+	}
+	fmt.Printf("Reset %d threads. Run 'hiveminer run --form %s --session %s' to retry them.\n",
+		retried, manifest.Form.Path, sessionDir)
+	return nil
+}
+
+// matchesErrorCode reports whether code is one of targets, or belongs to a
+// target stage (e.g. "fetch" matches fetch_429). No targets matches all.
+func matchesErrorCode(code string, targets []string) bool {
+	if len(targets) == 0 {
+		return true
+	}
+	for _, t := range targets {
+		if code == t || strings.HasPrefix(code, t+"_") {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateTitle shortens a title to n characters
+func truncateTitle(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"hiveminer/internal/control"
+	"hiveminer/internal/kb"
+	"hiveminer/internal/session"
+)
+
+func cmdRunsRm(args []string) error {
+	fs := flag.NewFlagSet("runs rm", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	force := fs.Bool("force", false, "Delete without asking for confirmation")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.BoolVar(force, "f", false, "Delete without asking for confirmation (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs rm [--force] <run-id>")
+		return fmt.Errorf("run ID required")
+	}
+
+	sessionDir, err := resolveUniqueSessionDir(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if _, err := control.Send(sessionDir, "status"); err == nil {
+		return fmt.Errorf("a process is running this session; cancel it before deleting it")
+	}
+	name := filepath.Base(sessionDir)
+
+	var formTitle string
+	desc := name
+	if manifest, err := session.LoadManifest(sessionDir); err == nil && manifest != nil {
+		formTitle = manifest.Form.Title
+		entries := 0
+		for _, t := range manifest.Threads {
+			entries += len(t.Entries)
+		}
+		desc = fmt.Sprintf("%s (%s: %d threads, %d entries, %s)", name, formTitle, len(manifest.Threads), entries, session.FormatBytes(session.DirSize(sessionDir)))
+	}
+
+	if !*force {
+		fmt.Printf("Delete %s? [y/N] ", desc)
+		line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+			fmt.Println("Not deleted.")
+			return nil
+		}
+	}
+
+	if err := os.RemoveAll(sessionDir); err != nil {
+		return fmt.Errorf("removing session: %w", err)
+	}
+	fmt.Printf("Deleted %s\n", desc)
+	if err := session.RefreshFormIndex(sessionDir); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	// Drop the session from the cross-session records that name it. The
+	// knowledge base is rebuilt, since items it shared with other runs may
+	// have taken their fields, score and mentions from it.
+	k, err := kb.Load(*outputDir)
+	if err != nil {
+		return err
+	}
+	if k != nil && k.HasSession(name) {
+		k, _, err := syncKB(*outputDir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  Rebuilt the knowledge base without it: %d items.\n", len(k.Items))
+	}
+	if formTitle != "" {
+		history, err := session.LoadYieldHistory(*outputDir, formTitle)
+		if err != nil {
+			return err
+		}
+		if _, ok := history.Sessions[name]; ok {
+			delete(history.Sessions, name)
+			if err := session.SaveYieldHistory(*outputDir, history); err != nil {
+				return err
+			}
+			fmt.Println("  Removed from the form's subreddit yield history.")
+		}
+	}
+	return nil
+}
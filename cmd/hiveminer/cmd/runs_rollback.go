@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"hiveminer/internal/control"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+func cmdRunsRollback(args []string) error {
+	fs := flag.NewFlagSet("runs rollback", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	to := fs.Int("to", 1, "Backup to restore, 1 = the most recent (see --list)")
+	list := fs.Bool("list", false, "List the run's manifest backups without restoring one")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs rollback [--list] [--to n] <run-id>")
+		return fmt.Errorf("run ID required")
+	}
+
+	sessionDir, err := resolveSessionDir(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	backups := session.ListBackups(sessionDir)
+	if *list {
+		if len(backups) == 0 {
+			fmt.Println("No manifest backups. One is kept each time the session is resumed.")
+			return nil
+		}
+		for _, b := range backups {
+			fmt.Printf("  %d  %s\n", b.N, describeBackup(b.Manifest))
+		}
+		return nil
+	}
+
+	if _, err := control.Send(sessionDir, "status"); err == nil {
+		return fmt.Errorf("a process is running this session; cancel it before rolling back")
+	}
+	restored, err := session.RestoreBackup(sessionDir, *to)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rolled back to backup %d: %s\n", *to, describeBackup(restored))
+	fmt.Printf("The manifest it replaced is now backup 1; undo with 'hiveminer runs rollback --to 1 %s'.\n", fs.Arg(0))
+	return nil
+}
+
+// describeBackup summarizes a manifest backup: when it was saved, its last
+// run and its threads by status
+func describeBackup(m *types.Manifest) string {
+	desc := fmt.Sprintf("saved %s, %d runs", m.UpdatedAt.Local().Format("2006-01-02 15:04"), len(m.Runs))
+	if n := len(m.Runs); n > 0 {
+		desc += fmt.Sprintf(" (last %s)", m.Runs[n-1].Status)
+	}
+	counts := session.CountByStatus(m)
+	desc += fmt.Sprintf(", %d threads", len(m.Threads))
+	for _, status := range types.ThreadStatuses {
+		if counts[status] > 0 {
+			desc += fmt.Sprintf(", %d %s", counts[status], status)
+		}
+	}
+	return desc
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+func cmdRunsSkip(args []string) error {
+	fs := flag.NewFlagSet("runs skip", flag.ExitOnError)
+	outputDir := fs.String("output", "./output", "Output directory")
+	reason := fs.String("reason", "", "Why the thread was rejected")
+	fs.StringVar(outputDir, "o", "./output", "Output directory (shorthand)")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: hiveminer runs skip <run-id> <permalink|post-id> [...] [--reason text]")
+		return fmt.Errorf("run ID and thread required")
+	}
+
+	sessionDir, manifest, err := loadSession(*outputDir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	skipList, err := session.LoadSkipList(*outputDir, manifest.Form.Title)
+	if err != nil {
+		return err
+	}
+
+	for _, target := range fs.Args()[1:] {
+		postID := postIDFromPermalink(target)
+		if postID == "" {
+			postID = target
+		}
+		thread := session.FindThread(manifest, postID)
+		if thread == nil {
+			fmt.Fprintf(os.Stderr, "Thread %s not found in %s\n", postID, sessionDir)
+			continue
+		}
+		thread.Status = types.StatusSkipped
+		thread.Pinned = false
+		thread.SkipReason = *reason
+		session.AddSkip(skipList, *thread, *reason, "reviewer")
+		fmt.Printf("Skipped %s\n", thread.Title)
+	}
+
+	if err := session.SaveSkipList(*outputDir, skipList); err != nil {
+		return err
+	}
+	return session.SaveManifest(sessionDir, manifest)
+}
@@ -9,11 +9,13 @@ import (
 	"time"
 
 	"hiveminer/internal/search"
+	"hiveminer/internal/session"
 	"hiveminer/pkg/types"
 )
 
 func cmdSearch(args []string) error {
 	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	newSearcher := addTransportFlags(fs)
 	subreddit := fs.String("subreddit", "", "Limit search to specific subreddit")
 	rShort := fs.String("r", "", "Limit search to specific subreddit (shorthand)")
 	limit := fs.Int("limit", 10, "Number of results")
@@ -50,12 +52,14 @@ Options:`)
 		lim = *lShort
 	}
 
-	searcher := search.NewRedditSearcher()
+	searcher, err := openSearcher(newSearcher)
+	if err != nil {
+		return err
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	var posts []types.Post
-	var err error
 
 	if sub == "" {
 		sub = "all"
@@ -90,6 +94,7 @@ Options:`)
 
 func cmdLs(args []string) error {
 	fs := flag.NewFlagSet("ls", flag.ExitOnError)
+	newSearcher := addTransportFlags(fs)
 	sort := fs.String("sort", "hot", "Sort by: hot, new, rising, top, controversial")
 	sShort := fs.String("s", "hot", "Sort (shorthand)")
 	limit := fs.Int("limit", 10, "Number of posts")
@@ -126,7 +131,10 @@ Options:`)
 		lim = *lShort
 	}
 
-	searcher := search.NewRedditSearcher()
+	searcher, err := openSearcher(newSearcher)
+	if err != nil {
+		return err
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -159,11 +167,15 @@ Options:`)
 
 func cmdThread(args []string) error {
 	fs := flag.NewFlagSet("thread", flag.ExitOnError)
+	newSearcher := addTransportFlags(fs)
 	searchQuery := fs.String("search", "", "Filter comments containing this text")
 	sShort := fs.String("s", "", "Filter comments (shorthand)")
 	limit := fs.Int("limit", 25, "Number of comments to fetch")
 	lShort := fs.Int("l", 25, "Number of comments (shorthand)")
 	jsonOut := fs.Bool("json", false, "Output thread JSON")
+	outFile := fs.String("out", "", "Write the thread JSON to this file, replacing it atomically")
+	commentSort := fs.String("sort", "", "Comment sort: top, best, new, controversial, old, qa")
+	budget := fs.Int("budget", 0, "Expand collapsed comments until this many are fetched (megathreads)")
 
 	fs.Usage = func() {
 		fmt.Println(`View thread comments
@@ -194,15 +206,34 @@ Options:`)
 		lim = *lShort
 	}
 
-	searcher := search.NewRedditSearcher()
+	searcher, err := openSearcher(newSearcher)
+	if err != nil {
+		return err
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	thread, err := searcher.GetThread(ctx, permalink, lim)
+	var thread *types.Thread
+
+	if pf, ok := searcher.(search.PagedThreadFetcher); ok && (*budget > 0 || *commentSort != "") {
+		thread, err = pf.GetThreadPaged(ctx, permalink, search.ThreadOptions{Sort: *commentSort, CommentBudget: *budget, Limit: lim})
+	} else {
+		thread, err = searcher.GetThread(ctx, permalink, lim)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to fetch thread: %w", err)
 	}
 
+	if *outFile != "" {
+		data, err := json.MarshalIndent(thread, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding thread JSON: %w", err)
+		}
+		if err := session.WriteFileAtomic(*outFile, data, 0644); err != nil {
+			return fmt.Errorf("writing thread JSON: %w", err)
+		}
+		return nil
+	}
 	if *jsonOut {
 		return printJSON(thread)
 	}
@@ -223,6 +254,158 @@ Options:`)
 	return nil
 }
 
+func cmdWiki(args []string) error {
+	fs := flag.NewFlagSet("wiki", flag.ExitOnError)
+	newSearcher := addTransportFlags(fs)
+	sidebar := fs.Bool("sidebar", false, "Show the subreddit sidebar instead of a wiki page")
+	jsonOut := fs.Bool("json", false, "Output page as thread JSON")
+
+	fs.Usage = func() {
+		fmt.Println(`View subreddit wiki pages
+
+Usage:
+  hiveminer wiki <subreddit>          list wiki pages
+  hiveminer wiki <subreddit> <page>   show a wiki page
+  hiveminer wiki --sidebar <subreddit>
+
+Options:`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return fmt.Errorf("subreddit name is required")
+	}
+
+	subreddit := fs.Arg(0)
+	searcher, err := newSearcher()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var thread *types.Thread
+
+	switch {
+	case *sidebar:
+		thread, err = searcher.GetSidebar(ctx, subreddit)
+	case fs.NArg() >= 2:
+		thread, err = searcher.GetWikiPage(ctx, subreddit, fs.Arg(1))
+	default:
+		pages, err := searcher.ListWikiPages(ctx, subreddit)
+		if err != nil {
+			return fmt.Errorf("failed to list wiki pages: %w", err)
+		}
+		if *jsonOut {
+			return printJSON(pages)
+		}
+		for _, page := range pages {
+			fmt.Printf("%s\n", page)
+		}
+		if len(pages) == 0 {
+			fmt.Println("No wiki pages found.")
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch page: %w", err)
+	}
+
+	if *jsonOut {
+		return printJSON(thread)
+	}
+
+	fmt.Printf("%s\n", thread.Post.Title)
+	fmt.Printf("  https://reddit.com%s\n\n", thread.Post.Permalink)
+	fmt.Println(thread.Post.Selftext)
+
+	return nil
+}
+
+func cmdUser(args []string) error {
+	fs := flag.NewFlagSet("user", flag.ExitOnError)
+	newSearcher := addTransportFlags(fs)
+	comments := fs.Bool("comments", false, "List comments instead of submitted posts")
+	sort := fs.String("sort", "top", "Sort by: hot, new, top, controversial")
+	limit := fs.Int("limit", 25, "Number of items")
+	fs.IntVar(limit, "l", 25, "Number of items (shorthand)")
+	jsonOut := fs.Bool("json", false, "Output results as JSON")
+
+	fs.Usage = func() {
+		fmt.Println(`List a Reddit user's history
+
+Usage:
+  hiveminer user <username> [options]
+
+Options:`)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return fmt.Errorf("username is required")
+	}
+
+	username := strings.TrimPrefix(fs.Arg(0), "u/")
+	searcher, err := newSearcher()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if *comments {
+		results, err := searcher.ListUserComments(ctx, username, *sort, *limit)
+		if err != nil {
+			return fmt.Errorf("failed to list comments: %w", err)
+		}
+		if *jsonOut {
+			return printJSON(results)
+		}
+		for _, c := range results {
+			fmt.Printf("↑ %d  https://reddit.com%s\n", c.Score, c.Permalink)
+			body := c.Body
+			if len(body) > 300 {
+				body = body[:300] + "..."
+			}
+			for _, line := range strings.Split(body, "\n") {
+				fmt.Printf("  %s\n", line)
+			}
+			fmt.Println()
+		}
+		if len(results) == 0 {
+			fmt.Println("No comments found.")
+		}
+		return nil
+	}
+
+	posts, err := searcher.ListUserSubmissions(ctx, username, *sort, *limit)
+	if err != nil {
+		return fmt.Errorf("failed to list submissions: %w", err)
+	}
+	if *jsonOut {
+		return printJSON(posts)
+	}
+	for _, p := range posts {
+		fmt.Printf("%s\n", p.Title)
+		fmt.Printf("  ↑ %d  💬 %d  r/%s  (%s)\n", p.Score, p.NumComments, p.Subreddit, p.Domain)
+		fmt.Printf("  https://reddit.com%s\n\n", p.Permalink)
+	}
+	if len(posts) == 0 {
+		fmt.Println("No posts found.")
+	}
+	return nil
+}
+
 func filterNSFW(posts []types.Post, includeNSFW bool) []types.Post {
 	if includeNSFW {
 		return posts
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+)
+
+// unrecordedFlags are run flags that describe one invocation rather than the
+// session's configuration: its topic, where it runs, how it's supervised,
+// and network details that may hold credentials. They aren't saved as
+// session settings.
+var unrecordedFlags = map[string]bool{
+	"form": true, "query": true, "q": true, "subreddits": true, "r": true, "user": true,
+	"reviews": true, "transcripts": true, "web": true,
+	"session": true, "name": true, "output": true, "o": true, "verbose": true, "v": true, "checkpoint": true,
+	"proxy": true, "ca-bundle": true, "header": true, "mirrors": true,
+}
+
+// runShorthands maps run's shorthand flags to the flags they alias; settings
+// are recorded under the long name
+var runShorthands = map[string]string{"q": "query", "r": "subreddits", "l": "limit", "o": "output", "v": "verbose"}
+
+// givenExplicitly reports whether a flag or its shorthand was on the command line
+func givenExplicitly(name string, explicit map[string]bool) bool {
+	if explicit[name] {
+		return true
+	}
+	for short, long := range runShorthands {
+		if long == name && explicit[short] {
+			return true
+		}
+	}
+	return false
+}
+
+// applySessionSettings sets run flags from a session's recorded settings,
+// leaving flags given explicitly alone
+func applySessionSettings(fs *flag.FlagSet, settings map[string]string, explicit map[string]bool) error {
+	for name, value := range settings {
+		if givenExplicitly(name, explicit) || unrecordedFlags[name] || fs.Lookup(name) == nil {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("session setting %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// sessionSettings returns the settings to record for a run: the session's
+// earlier settings overlaid with the flags given explicitly this time
+func sessionSettings(fs *flag.FlagSet, previous map[string]string, explicit map[string]bool) map[string]string {
+	settings := map[string]string{}
+	for name, value := range previous {
+		if !unrecordedFlags[name] {
+			settings[name] = value
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		name := f.Name
+		if long, ok := runShorthands[name]; ok {
+			name = long
+		}
+		if explicit[f.Name] && !unrecordedFlags[name] {
+			settings[name] = f.Value.String()
+		}
+	})
+	return settings
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"hiveminer/internal/dump"
+	"hiveminer/internal/ratelimit"
+	"hiveminer/internal/search"
+)
+
+// headerFlags collects repeated --header "Name: value" flags
+type headerFlags []string
+
+func (h *headerFlags) String() string { return strings.Join(*h, ", ") }
+
+func (h *headerFlags) Set(s string) error {
+	*h = append(*h, s)
+	return nil
+}
+
+// addTransportFlags registers --proxy, --ca-bundle, --header, --mirrors and
+// --politeness on fs and returns a constructor for a Reddit searcher using
+// them. Flags override the HIVEMINER_PROXY, HIVEMINER_CA_BUNDLE,
+// HIVEMINER_HEADERS, HIVEMINER_MIRRORS and HIVEMINER_POLITENESS environment,
+// and are exported to it so hiveminer subprocesses started by agents connect
+// the same way.
+func addTransportFlags(fs *flag.FlagSet) func() (*search.RedditSearcher, error) {
+	proxy := fs.String("proxy", "", "HTTP(S) or SOCKS5 proxy URL for Reddit requests")
+	caBundle := fs.String("ca-bundle", "", "PEM file of extra CA certificates to trust")
+	var headers headerFlags
+	fs.Var(&headers, "header", "Extra request header \"Name: value\" (repeatable)")
+	mirrors := fs.String("mirrors", "", "Comma-separated fallback endpoints serving Reddit's JSON API")
+	politeness := fs.String("politeness", "", "JSON file of per-source request delay, retries, backoff and User-Agent")
+
+	return func() (*search.RedditSearcher, error) {
+		if *proxy != "" {
+			os.Setenv("HIVEMINER_PROXY", *proxy)
+		}
+		if *caBundle != "" {
+			os.Setenv("HIVEMINER_CA_BUNDLE", *caBundle)
+		}
+		if *mirrors != "" {
+			os.Setenv("HIVEMINER_MIRRORS", *mirrors)
+		}
+		if *politeness != "" {
+			path, err := filepath.Abs(*politeness)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := ratelimit.LoadConfig(path); err != nil {
+				return nil, err
+			}
+			os.Setenv("HIVEMINER_POLITENESS", path)
+		}
+		if len(headers) > 0 {
+			if _, err := search.ParseHeaders(headers); err != nil {
+				return nil, err
+			}
+			lines := headers
+			if env := os.Getenv("HIVEMINER_HEADERS"); env != "" {
+				lines = append(strings.Split(env, "\n"), headers...)
+			}
+			os.Setenv("HIVEMINER_HEADERS", strings.Join(lines, "\n"))
+		}
+
+		cfg, err := search.TransportConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return search.NewRedditSearcherWithTransport(cfg)
+	}
+}
+
+// openSearcher returns the imported source a run set in HIVEMINER_SOURCE
+// (run --source), so agents' searches and fetches stay inside the dump, and
+// otherwise a Reddit searcher
+func openSearcher(newSearcher func() (*search.RedditSearcher, error)) (search.Searcher, error) {
+	if dir := os.Getenv("HIVEMINER_SOURCE"); dir != "" {
+		return dump.Open(dir)
+	}
+	rs, err := newSearcher()
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
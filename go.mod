@@ -2,7 +2,10 @@ module hiveminer
 
 go 1.25.5
 
-require belaykit v0.0.0
+require (
+	belaykit v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require github.com/google/uuid v1.6.0 // indirect
 
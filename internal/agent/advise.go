@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"text/template"
+
+	"belaykit"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// ClaudeAdvisor implements Advisor using Claude
+type ClaudeAdvisor struct {
+	runner  Runner
+	prompts fs.FS
+	model   string
+	logger  belaykit.EventHandler
+	backend string
+}
+
+// NewClaudeAdvisor creates a new Claude-based form advisor
+func NewClaudeAdvisor(runner Runner, prompts fs.FS, model string, logger belaykit.EventHandler, backend string) *ClaudeAdvisor {
+	return &ClaudeAdvisor{runner: runner, prompts: prompts, model: model, logger: logger, backend: backend}
+}
+
+// Advise asks for form edits addressing the report's weak spots. A patched
+// form that doesn't validate is dropped, keeping the suggestions.
+func (a *ClaudeAdvisor) Advise(ctx context.Context, form *types.Form, report FormReport) (*FormAdvice, error) {
+	funcMap := template.FuncMap{
+		"pct": func(f float64) string { return fmt.Sprintf("%.0f%%", f*100) },
+	}
+	pt, err := belaykit.LoadPromptTemplate(a.prompts, "advise.md", funcMap)
+	if err != nil {
+		return nil, fmt.Errorf("loading advise template: %w", err)
+	}
+	formJSON, err := json.MarshalIndent(form, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling form: %w", err)
+	}
+
+	prompt, err := pt.Render(struct {
+		FormTitle       string
+		FormDescription string
+		Audience        []string
+		FormJSON        string
+		Report          FormReport
+	}{
+		FormTitle:       form.Title,
+		FormDescription: form.Description,
+		Audience:        schema.AudienceLines(form),
+		FormJSON:        string(formJSON),
+		Report:          report,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rendering advise prompt: %w", err)
+	}
+
+	opts := []belaykit.RunOption{belaykit.WithModel(a.model)}
+	if a.backend != "codex" {
+		opts = append(opts, belaykit.WithMaxTurns(1))
+	}
+	if a.logger != nil {
+		opts = append(opts, belaykit.WithEventHandler(a.logger))
+	}
+
+	var lastErr error
+	const maxAttempts = 2
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		result, err := a.runner.Run(ctx, prompt, opts...)
+		if err != nil {
+			lastErr = fmt.Errorf("running agent (attempt %d/%d): %w", attempt, maxAttempts, err)
+			continue
+		}
+		var advice FormAdvice
+		if err := belaykit.ExtractJSON(result.Text, &advice); err != nil {
+			lastErr = fmt.Errorf("parsing advice (attempt %d/%d): %w", attempt, maxAttempts, err)
+			continue
+		}
+		if advice.Form != nil {
+			if err := schema.Validate(advice.Form); err != nil {
+				advice.Form, advice.FormError = nil, err.Error()
+			}
+		}
+		return &advice, nil
+	}
+	return nil, lastErr
+}
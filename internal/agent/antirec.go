@@ -0,0 +1,89 @@
+package agent
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// antiPatterns are phrases that advise against an item
+var antiPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\bavoid(ed)?\b`),
+	regexp.MustCompile(`\bstay(ed)? away\b`),
+	regexp.MustCompile(`\bsteer clear\b`),
+	regexp.MustCompile(`\bdo(n't| not) (buy|get|go|use|book|bother|waste)\b`),
+	regexp.MustCompile(`\b(wouldn't|would not|don't|do not|can't|cannot|never) recommend\b`),
+	regexp.MustCompile(`\bwaste of (money|time)\b`),
+	regexp.MustCompile(`\bnever again\b`),
+}
+
+// sarcasmMarker is the "/s" that ends a sarcastic comment
+var sarcasmMarker = regexp.MustCompile(`(^|\s)/s\b`)
+
+var sentenceBreak = regexp.MustCompile(`[.!?\n]+`)
+
+// markAntiRecommendations is a cheap check after extraction for entries the
+// model took as recommendations although every quote backing the primary
+// value advises against the item or is marked sarcastic. Entries the model
+// already marked are left alone.
+func markAntiRecommendations(result *types.ExtractionResult, form *types.Form) {
+	primaryID := schema.PrimaryField(form)
+	for i := range result.Entries {
+		entry := &result.Entries[i]
+		if entry.AntiRecommendation != nil {
+			continue
+		}
+		fv, ok := fieldByID(*entry, primaryID)
+		if !ok || fv.Value == nil || len(fv.Evidence) == 0 {
+			continue
+		}
+		item := normalizePrimary(fmt.Sprintf("%v", fv.Value))
+		var phrase string
+		for _, ev := range fv.Evidence {
+			phrase = antiPhrase(ev.Text, item)
+			if phrase == "" {
+				break
+			}
+		}
+		if phrase != "" {
+			entry.AntiRecommendation = &types.AntiRecommendation{
+				Source: types.AntiSourceHeuristic,
+				Reason: fmt.Sprintf("quoted as %q", phrase),
+			}
+		}
+	}
+}
+
+// antiPhrase returns the phrase by which a quote advises against an item, or
+// "" if it doesn't. Outside sarcastic quotes, the phrase must share a
+// sentence with the item unless the quote is a single sentence, and "to
+// avoid" is read as a purpose ("get X to avoid blisters"), not a warning.
+func antiPhrase(quote, item string) string {
+	text := strings.ToLower(strings.ReplaceAll(quote, "’", "'"))
+	if m := sarcasmMarker.FindString(text); m != "" {
+		return strings.TrimSpace(m)
+	}
+	var sentences []string
+	for _, s := range sentenceBreak.Split(text, -1) {
+		if strings.TrimSpace(s) != "" {
+			sentences = append(sentences, s)
+		}
+	}
+	for _, s := range sentences {
+		if len(sentences) > 1 && item != "" && !strings.Contains(s, item) {
+			continue
+		}
+		for _, p := range antiPatterns {
+			if m := p.FindString(s); m != "" {
+				if strings.HasPrefix(m, "avoid") && strings.Contains(s, "to "+m) {
+					continue
+				}
+				return m
+			}
+		}
+	}
+	return ""
+}
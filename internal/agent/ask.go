@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strings"
+	"text/template"
+
+	"belaykit"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// maxAskQuotes caps the evidence quotes per field included in the ask prompt
+const maxAskQuotes = 2
+
+// ClaudeAnswerer implements Answerer using Claude
+type ClaudeAnswerer struct {
+	runner  Runner
+	prompts fs.FS
+	model   string
+	logger  belaykit.EventHandler
+	backend string
+}
+
+// NewClaudeAnswerer creates a new Claude-based answerer
+func NewClaudeAnswerer(runner Runner, prompts fs.FS, model string, logger belaykit.EventHandler, backend string) *ClaudeAnswerer {
+	return &ClaudeAnswerer{runner: runner, prompts: prompts, model: model, logger: logger, backend: backend}
+}
+
+// Answer answers a question grounded in the given entries
+func (a *ClaudeAnswerer) Answer(ctx context.Context, form *types.Form, question string, entries []AskEntry) (string, error) {
+	funcMap := template.FuncMap{
+		"json": func(v any) string {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Sprintf("%v", v)
+			}
+			return string(b)
+		},
+		"present": func(v any) bool { return v != nil },
+		"quotes": func(ev []types.Evidence) []types.Evidence {
+			if len(ev) > maxAskQuotes {
+				return ev[:maxAskQuotes]
+			}
+			return ev
+		},
+	}
+
+	pt, err := belaykit.LoadPromptTemplate(a.prompts, "ask.md", funcMap)
+	if err != nil {
+		return "", fmt.Errorf("loading ask template: %w", err)
+	}
+
+	prompt, err := pt.Render(struct {
+		FormTitle       string
+		FormDescription string
+		Audience        []string
+		Question        string
+		Entries         []AskEntry
+	}{
+		FormTitle:       form.Title,
+		FormDescription: form.Description,
+		Audience:        schema.AudienceLines(form),
+		Question:        question,
+		Entries:         entries,
+	})
+	if err != nil {
+		return "", fmt.Errorf("rendering ask prompt: %w", err)
+	}
+
+	opts := []belaykit.RunOption{belaykit.WithModel(a.model)}
+	if a.backend != "codex" {
+		opts = append(opts, belaykit.WithMaxTurns(1))
+	}
+	if a.logger != nil {
+		opts = append(opts, belaykit.WithEventHandler(a.logger))
+	}
+
+	result, err := a.runner.Run(ctx, prompt, opts...)
+	if err != nil {
+		return "", fmt.Errorf("running agent: %w", err)
+	}
+	return strings.TrimSpace(result.Text), nil
+}
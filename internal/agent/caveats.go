@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"hiveminer/pkg/types"
+)
+
+// caveatSimilarity is the share of content words two issues must share to be
+// treated as the same caveat ("battery dies fast" / "battery dies quickly")
+const caveatSimilarity = 0.5
+
+// caveatStopWords are left out when comparing issues
+var caveatStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "after": true, "its": true,
+	"are": true, "was": true, "were": true, "has": true, "have": true, "too": true,
+	"very": true, "can": true, "not": true, "some": true, "gets": true, "get": true,
+}
+
+// mergeCaveats combines caveat lists, from several threads or models, into
+// one: caveats about the same issue are folded together with their evidence
+// and threads, commenters are recounted so nobody counts twice, and the most
+// reported issues come first
+func mergeCaveats(lists ...[]types.Caveat) []types.Caveat {
+	var merged []types.Caveat
+	for _, list := range lists {
+		for _, c := range list {
+			found := -1
+			for i := range merged {
+				if similarIssue(merged[i].Issue, c.Issue) {
+					found = i
+					break
+				}
+			}
+			if found < 0 {
+				c.Evidence = append([]types.Evidence(nil), c.Evidence...)
+				c.Threads = append([]string(nil), c.Threads...)
+				merged = append(merged, c)
+				continue
+			}
+			m := &merged[found]
+			for _, ev := range c.Evidence {
+				if !hasEvidence(m.Evidence, ev) {
+					m.Evidence = append(m.Evidence, ev)
+				}
+			}
+			for _, t := range c.Threads {
+				if !slices.Contains(m.Threads, t) {
+					m.Threads = append(m.Threads, t)
+				}
+			}
+		}
+	}
+	for i := range merged {
+		merged[i].Commenters = max(caveatCommenters(merged[i].Evidence), 1)
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].Commenters > merged[j].Commenters
+	})
+	return merged
+}
+
+// caveatCommenters counts the distinct commenters behind a caveat's
+// evidence; quotes without an author count once per comment
+func caveatCommenters(evs []types.Evidence) int {
+	seen := map[string]bool{}
+	for _, ev := range evs {
+		key := "u:" + normalizeAuthor(ev.Author)
+		if ev.Author == "" {
+			key = "c:" + ev.CommentID + ev.Text
+		}
+		seen[key] = true
+	}
+	return len(seen)
+}
+
+// similarIssue reports whether two caveats describe the same issue: equal
+// ignoring case, or sharing most of their content words
+func similarIssue(a, b string) bool {
+	wa, wb := issueWords(a), issueWords(b)
+	if len(wa) == 0 || len(wb) == 0 {
+		return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+	}
+	shared := 0
+	for w := range wa {
+		if wb[w] {
+			shared++
+		}
+	}
+	return float64(shared)/float64(min(len(wa), len(wb))) >= caveatSimilarity && shared > 0
+}
+
+func issueWords(s string) map[string]bool {
+	words := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) {
+		if len(w) >= 3 && !caveatStopWords[w] {
+			words[strings.TrimSuffix(w, "s")] = true
+		}
+	}
+	return words
+}
+
+func hasEvidence(evs []types.Evidence, ev types.Evidence) bool {
+	for _, e := range evs {
+		if e.CommentID == ev.CommentID && e.Text == ev.Text {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package agent
+
+import (
+	"fmt"
+	"slices"
+
+	"hiveminer/pkg/types"
+)
+
+// outlierFactor is how far (as a multiple of the median) a numeric value must
+// sit from the consensus to be flagged as an outlier
+const outlierFactor = 5.0
+
+// minConsensusValues is the fewest values needed before a median is trusted
+const minConsensusValues = 5
+
+// applyConsensusChecks flags entries whose values disagree with the rest of the
+// data so reviewers know where to look. Numeric values far from the median of
+// all entries are flagged "outlier"; merged entries whose duplicates reported
+// opposite booleans or widely different numbers are flagged "contradicted".
+// Scores are left unchanged.
+func applyConsensusChecks(form *types.Form, entries []RankInput, outputs []RankOutput) {
+	fieldsOf := func(i int) []types.FieldValue {
+		if outputs[i].MergedFields != nil {
+			return outputs[i].MergedFields
+		}
+		return entries[i].Entry.Fields
+	}
+
+	for _, field := range form.Fields {
+		switch field.Type {
+		case types.FieldTypeNumber:
+			values := map[int]float64{}
+			var all []float64
+			for i := range outputs {
+				for _, fv := range fieldsOf(i) {
+					if fv.ID != field.ID || fv.Value == nil {
+						continue
+					}
+					if n, ok := parseNumber(fv.Value); ok {
+						values[i] = n
+						all = append(all, n)
+					}
+					if agg := fv.Aggregate; agg != nil && agg.Min > 0 && agg.Max >= agg.Min*3 {
+						flagForReview(&outputs[i], "contradicted", fmt.Sprintf("%s reported from %s to %s across duplicates",
+							field.ID, formatNumber(agg.Min), formatNumber(agg.Max)))
+					}
+				}
+			}
+			if len(all) < minConsensusValues {
+				continue
+			}
+			median := aggregateNumbers(all).Median
+			if median <= 0 {
+				continue
+			}
+			for i, v := range values {
+				if v >= median*outlierFactor || v <= median/outlierFactor {
+					flagForReview(&outputs[i], "outlier", fmt.Sprintf("%s %s vs median %s",
+						field.ID, formatNumber(v), formatNumber(median)))
+				}
+			}
+
+		case types.FieldTypeBoolean:
+			for i := range outputs {
+				for _, fv := range fieldsOf(i) {
+					if fv.ID != field.ID || fv.Value == nil || len(fv.Alternatives) == 0 {
+						continue
+					}
+					for _, alt := range fv.Alternatives {
+						if valueKey(alt.Value) != valueKey(fv.Value) {
+							flagForReview(&outputs[i], "contradicted", fmt.Sprintf("%s reported as both %v and %v",
+								field.ID, fv.Value, alt.Value))
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// flagForReview adds a review flag and its explanation to an output
+func flagForReview(out *RankOutput, flag, reason string) {
+	if slices.Contains(out.Flags, flag) {
+		return
+	}
+	out.Flags = append(out.Flags, flag)
+	if out.Reason == "" {
+		out.Reason = reason
+	} else {
+		out.Reason += "; " + reason
+	}
+}
+
+// formatNumber renders a float without trailing decimals when whole
+func formatNumber(n float64) string {
+	if n == float64(int64(n)) {
+		return fmt.Sprintf("%d", int64(n))
+	}
+	return fmt.Sprintf("%.2f", n)
+}
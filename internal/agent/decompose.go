@@ -0,0 +1,184 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// StreamingExtractor is an extractor that can direct its output to a writer
+type StreamingExtractor interface {
+	Extractor
+	ExtractFieldsWithOutput(ctx context.Context, thread *types.Thread, form *types.Form, output io.Writer) (*types.ExtractionResult, error)
+}
+
+// DecomposedExtractor extracts long forms with one focused prompt per group
+// of related fields instead of a single prompt for every field. Each prompt
+// also asks for the primary field, by which the groups' entries are matched
+// and merged into whole entries.
+type DecomposedExtractor struct {
+	inner     StreamingExtractor
+	groupSize int
+}
+
+// NewDecomposedExtractor returns an extractor splitting forms into groups of
+// at most groupSize fields besides the primary one (see schema.FieldGroups),
+// extracting each group with inner
+func NewDecomposedExtractor(inner StreamingExtractor, groupSize int) *DecomposedExtractor {
+	return &DecomposedExtractor{inner: inner, groupSize: groupSize}
+}
+
+// ExtractFields extracts a thread group by group and merges the results
+func (d *DecomposedExtractor) ExtractFields(ctx context.Context, thread *types.Thread, form *types.Form) (*types.ExtractionResult, error) {
+	return d.ExtractFieldsWithOutput(ctx, thread, form, nil)
+}
+
+// ExtractFieldsWithOutput runs the groups' prompts concurrently, streaming
+// the first group's output. Forms fitting one group are extracted as usual.
+// A group that fails leaves its fields empty; the call fails only if every
+// group does.
+func (d *DecomposedExtractor) ExtractFieldsWithOutput(ctx context.Context, thread *types.Thread, form *types.Form, output io.Writer) (*types.ExtractionResult, error) {
+	groups := schema.FieldGroups(form, d.groupSize)
+	if len(groups) <= 1 {
+		return d.inner.ExtractFieldsWithOutput(ctx, thread, form, output)
+	}
+
+	primaryID := schema.PrimaryField(form)
+	primary := schema.GetField(form, primaryID)
+	results := make([]*types.ExtractionResult, len(groups))
+	errs := make([]error, len(groups))
+	var wg sync.WaitGroup
+	for i, group := range groups {
+		sub := *form
+		sub.Fields = groupFields(form, primary, group)
+		wg.Add(1)
+		go func(i int, sub types.Form) {
+			defer wg.Done()
+			out := output
+			if i > 0 {
+				out = io.Discard
+			}
+			results[i], errs[i] = d.inner.ExtractFieldsWithOutput(ctx, thread, &sub, out)
+		}(i, sub)
+	}
+	wg.Wait()
+
+	var ok []*types.ExtractionResult
+	for i, r := range results {
+		if errs[i] != nil {
+			fmt.Printf("  Warning: [%s] field group %d of %d failed: %v\n", thread.Post.ID, i+1, len(groups), errs[i])
+			continue
+		}
+		ok = append(ok, r)
+	}
+	if len(ok) == 0 {
+		return nil, errs[0]
+	}
+	return mergeFieldGroups(form, ok), nil
+}
+
+// groupFields returns the fields one group's prompt asks for: the primary
+// field, the form's entity key fields, the group's fields, and the fields
+// they depend on (see schema.FieldStages), so values stay attached to the
+// right item
+func groupFields(form *types.Form, primary *types.Field, group []types.Field) []types.Field {
+	want := map[string]bool{primary.ID: true}
+	for _, id := range schema.EntityKey(form) {
+		want[id] = true
+	}
+	var add func(f types.Field)
+	add = func(f types.Field) {
+		want[f.ID] = true
+		for _, dep := range f.DependsOn {
+			if d := schema.GetField(form, dep); d != nil && !want[dep] {
+				add(*d)
+			}
+		}
+	}
+	for _, f := range group {
+		add(f)
+	}
+	fields := []types.Field{*primary}
+	for _, f := range form.Fields {
+		if want[f.ID] && f.ID != primary.ID {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// mergeFieldGroups joins the entries each group's prompt found into whole
+// entries, matching them by primary value. Entries only some groups found
+// are kept, with the other groups' fields null.
+func mergeFieldGroups(form *types.Form, results []*types.ExtractionResult) *types.ExtractionResult {
+	primaryID := schema.PrimaryField(form)
+	type merged struct {
+		key     string
+		entry   types.Entry
+		fields  map[string]types.FieldValue
+		caveats [][]types.Caveat
+		groups  map[int]bool
+	}
+	var entries []*merged
+	for g, r := range results {
+		for _, e := range r.Entries {
+			key := normalizePrimary(primaryFieldString(e, primaryID))
+			var m *merged
+			if key != "" {
+				for _, cand := range entries {
+					if cand.key != "" && !cand.groups[g] && areSimilar(cand.key, key) {
+						m = cand
+						break
+					}
+				}
+			}
+			if m == nil {
+				m = &merged{key: key, entry: e, fields: map[string]types.FieldValue{}, groups: map[int]bool{}}
+				entries = append(entries, m)
+			}
+			m.groups[g] = true
+			m.caveats = append(m.caveats, e.Caveats)
+			if m.entry.AntiRecommendation == nil {
+				m.entry.AntiRecommendation = e.AntiRecommendation
+			}
+			m.entry.Links = mergeLinks(m.entry.Links, e.Links)
+			for _, fv := range e.Fields {
+				have, seen := m.fields[fv.ID]
+				switch {
+				case !seen || have.Value == nil:
+					m.fields[fv.ID] = fv
+				case fv.ID == primaryID && fv.Value != nil:
+					// Every group reports the primary value; pool their evidence
+					have.Evidence = mergeEvidence(have.Evidence, fv.Evidence)
+					have.Links = mergeLinks(have.Links, fv.Links)
+					have.Confidence = math.Max(have.Confidence, fv.Confidence)
+					m.fields[fv.ID] = have
+				}
+			}
+		}
+	}
+
+	combined := &types.ExtractionResult{}
+	for _, m := range entries {
+		entry := m.entry
+		entry.Fields = make([]types.FieldValue, 0, len(form.Fields))
+		for _, f := range form.Fields {
+			fv, ok := m.fields[f.ID]
+			if !ok {
+				fv = types.FieldValue{ID: f.ID}
+			}
+			entry.Fields = append(entry.Fields, fv)
+		}
+		entry.Caveats = mergeCaveats(m.caveats...)
+		combined.Entries = append(combined.Entries, entry)
+	}
+	for _, r := range results {
+		combined.PromptTokens += r.PromptTokens
+	}
+	return combined
+}
@@ -0,0 +1,97 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"belaykit"
+
+	"hiveminer/internal/schema"
+)
+
+// ClaudeDesigner implements FormDesigner using Claude
+type ClaudeDesigner struct {
+	runner  Runner
+	prompts fs.FS
+	model   string
+	logger  belaykit.EventHandler
+	backend string
+}
+
+// NewClaudeDesigner creates a new Claude-based form designer
+func NewClaudeDesigner(runner Runner, prompts fs.FS, model string, logger belaykit.EventHandler, backend string) *ClaudeDesigner {
+	return &ClaudeDesigner{runner: runner, prompts: prompts, model: model, logger: logger, backend: backend}
+}
+
+// DesignRun drafts or revises a run proposal. A draft whose form doesn't
+// validate is sent back once with the validation error.
+func (d *ClaudeDesigner) DesignRun(ctx context.Context, request string, previous *RunProposal, feedback []string) (*RunProposal, error) {
+	pt, err := belaykit.LoadPromptTemplate(d.prompts, "design_form.md", nil)
+	if err != nil {
+		return nil, fmt.Errorf("loading design template: %w", err)
+	}
+	var previousJSON string
+	if previous != nil {
+		data, err := json.MarshalIndent(previous, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshaling previous proposal: %w", err)
+		}
+		previousJSON = string(data)
+	}
+	fieldTypes := make([]string, 0, len(schema.ValidFieldTypes))
+	for t := range schema.ValidFieldTypes {
+		fieldTypes = append(fieldTypes, string(t))
+	}
+	sort.Strings(fieldTypes)
+	prompt, err := pt.Render(struct {
+		Request    string
+		Previous   string
+		Feedback   []string
+		FieldTypes string
+	}{
+		Request:    request,
+		Previous:   previousJSON,
+		Feedback:   feedback,
+		FieldTypes: strings.Join(fieldTypes, ", "),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rendering design prompt: %w", err)
+	}
+
+	opts := []belaykit.RunOption{belaykit.WithModel(d.model)}
+	if d.backend != "codex" {
+		opts = append(opts, belaykit.WithMaxTurns(1))
+	}
+	if d.logger != nil {
+		opts = append(opts, belaykit.WithEventHandler(d.logger))
+	}
+
+	var lastErr error
+	const maxAttempts = 2
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		result, err := d.runner.Run(ctx, prompt, opts...)
+		if err != nil {
+			lastErr = fmt.Errorf("running agent (attempt %d/%d): %w", attempt, maxAttempts, err)
+			continue
+		}
+		var proposal RunProposal
+		if err := belaykit.ExtractJSON(result.Text, &proposal); err != nil {
+			lastErr = fmt.Errorf("parsing proposal (attempt %d/%d): %w", attempt, maxAttempts, err)
+			continue
+		}
+		if err := schema.Validate(&proposal.Form); err != nil {
+			lastErr = fmt.Errorf("validating form (attempt %d/%d): %w", attempt, maxAttempts, err)
+			prompt += fmt.Sprintf("\n\nYour previous form was rejected: %v. Fix it and respond with the complete JSON again.", err)
+			continue
+		}
+		for i, sub := range proposal.Subreddits {
+			proposal.Subreddits[i] = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(sub), "/"), "r/")
+		}
+		return &proposal, nil
+	}
+	return nil, lastErr
+}
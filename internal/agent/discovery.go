@@ -10,6 +10,7 @@ import (
 
 	"belaykit"
 
+	"hiveminer/internal/schema"
 	"hiveminer/pkg/types"
 )
 
@@ -20,6 +21,8 @@ type ClaudeDiscoverer struct {
 	model   string
 	logger  belaykit.EventHandler
 	backend string
+
+	yieldNotes []string // how subreddits did for the form in earlier sessions
 }
 
 // NewClaudeDiscoverer creates a new Claude-based subreddit discoverer
@@ -27,6 +30,12 @@ func NewClaudeDiscoverer(runner Runner, prompts fs.FS, model string, logger bela
 	return &ClaudeDiscoverer{runner: runner, prompts: prompts, model: model, logger: logger, backend: backend}
 }
 
+// SetYieldNotes gives the agent the subreddits' yield in earlier sessions
+// for the form, one line per subreddit
+func (d *ClaudeDiscoverer) SetYieldNotes(notes []string) {
+	d.yieldNotes = notes
+}
+
 type discoveryResponse struct {
 	Subreddits []struct {
 		Name   string `json:"name"`
@@ -119,13 +128,19 @@ func (d *ClaudeDiscoverer) renderPrompt(form *types.Form, query string, executab
 	data := struct {
 		FormTitle       string
 		FormDescription string
+		Audience        []string
 		SearchHints     string
+		Exclusions      []types.Exclusion
+		YieldNotes      []string
 		Query           string
 		Executable      string
 	}{
 		FormTitle:       form.Title,
 		FormDescription: form.Description,
+		Audience:        schema.AudienceLines(form),
 		SearchHints:     strings.Join(form.SearchHints, ", "),
+		Exclusions:      form.Exclusions,
+		YieldNotes:      d.yieldNotes,
 		Query:           query,
 		Executable:      executable,
 	}
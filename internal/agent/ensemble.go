@@ -0,0 +1,236 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// EnsembleExtractor extracts each thread with several models and combines
+// their entries. Entries are matched across models by primary value; each
+// field takes the value most models agree on, records the share of models
+// that agree, and has its confidence scaled by that agreement, so values one
+// model made up rank lower than values every model found.
+type EnsembleExtractor struct {
+	members []*ClaudeExtractor
+}
+
+// NewEnsembleExtractor returns an extractor running every member on each
+// thread; the first member's entries win ties
+func NewEnsembleExtractor(members ...*ClaudeExtractor) *EnsembleExtractor {
+	return &EnsembleExtractor{members: members}
+}
+
+// ExtractFields extracts a thread with every member and combines the results
+func (e *EnsembleExtractor) ExtractFields(ctx context.Context, thread *types.Thread, form *types.Form) (*types.ExtractionResult, error) {
+	return e.ExtractFieldsWithOutput(ctx, thread, form, nil)
+}
+
+// ExtractFieldsWithOutput runs the members concurrently, streaming the first
+// member's output. Members that fail are left out of the agreement; the call
+// fails only if all of them do.
+func (e *EnsembleExtractor) ExtractFieldsWithOutput(ctx context.Context, thread *types.Thread, form *types.Form, output io.Writer) (*types.ExtractionResult, error) {
+	results := make([]*types.ExtractionResult, len(e.members))
+	errs := make([]error, len(e.members))
+	var wg sync.WaitGroup
+	for i, m := range e.members {
+		wg.Add(1)
+		go func(i int, m *ClaudeExtractor) {
+			defer wg.Done()
+			out := output
+			if i > 0 {
+				out = io.Discard
+			}
+			results[i], errs[i] = m.ExtractFieldsWithOutput(ctx, thread, form, out)
+		}(i, m)
+	}
+	wg.Wait()
+
+	var ok []*types.ExtractionResult
+	var models []string
+	for i, r := range results {
+		if errs[i] != nil {
+			fmt.Printf("  Warning: ensemble model %s failed: %v\n", e.members[i].model, errs[i])
+			continue
+		}
+		ok = append(ok, r)
+		models = append(models, e.members[i].model)
+	}
+	if len(ok) == 0 {
+		return nil, errs[0]
+	}
+	return combineExtractions(form, ok, models), nil
+}
+
+// ensembleMember is one model's version of an entry
+type ensembleMember struct {
+	model int
+	entry types.Entry
+}
+
+// combineExtractions matches entries across the models' results by primary
+// value and combines each match into one entry
+func combineExtractions(form *types.Form, results []*types.ExtractionResult, models []string) *types.ExtractionResult {
+	primaryID := schema.PrimaryField(form)
+	var clusters [][]ensembleMember
+	var keys []string
+	for m, r := range results {
+		for _, entry := range r.Entries {
+			key := normalizePrimary(primaryFieldString(entry, primaryID))
+			found := -1
+			if key != "" {
+				for c := range clusters {
+					if keys[c] != "" && !hasModel(clusters[c], m) && areSimilar(keys[c], key) {
+						found = c
+						break
+					}
+				}
+			}
+			if found < 0 {
+				clusters = append(clusters, nil)
+				keys = append(keys, key)
+				found = len(clusters) - 1
+			}
+			clusters[found] = append(clusters[found], ensembleMember{m, entry})
+		}
+	}
+
+	combined := &types.ExtractionResult{}
+	for _, cluster := range clusters {
+		combined.Entries = append(combined.Entries, combineEntry(cluster, models, primaryID))
+	}
+	for _, r := range results {
+		combined.PromptTokens += r.PromptTokens
+	}
+	return combined
+}
+
+func hasModel(cluster []ensembleMember, model int) bool {
+	for _, m := range cluster {
+		if m.model == model {
+			return true
+		}
+	}
+	return false
+}
+
+// combineEntry combines the models' versions of one entry. Each field takes
+// the value with the most models behind it (the earliest model's on a tie),
+// with the evidence of every model that agrees. Agreement is counted against
+// all models run, so a model that missed the entry counts as disagreeing.
+// Primary values agree when they matched the entries up.
+func combineEntry(cluster []ensembleMember, models []string, primaryID string) types.Entry {
+	entry := cluster[0].entry
+	n := float64(len(models))
+
+	var order []string
+	for _, m := range cluster {
+		for _, fv := range m.entry.Fields {
+			if !slices.Contains(order, fv.ID) {
+				order = append(order, fv.ID)
+			}
+		}
+	}
+
+	fields := make([]types.FieldValue, 0, len(order))
+	for _, id := range order {
+		// Group the models' values for the field by agreement
+		type vote struct {
+			fv      types.FieldValue
+			members []types.FieldValue
+		}
+		var votes []vote
+		for _, m := range cluster {
+			fv, ok := fieldByID(m.entry, id)
+			if !ok {
+				fv = types.FieldValue{ID: id}
+			}
+			placed := false
+			for i := range votes {
+				if sameValue(votes[i].fv.Value, fv.Value) || id == primaryID && similarPrimary(votes[i].fv.Value, fv.Value) {
+					votes[i].members = append(votes[i].members, fv)
+					placed = true
+					break
+				}
+			}
+			if !placed {
+				votes = append(votes, vote{fv: fv, members: []types.FieldValue{fv}})
+			}
+		}
+		best := 0
+		for i := range votes {
+			if len(votes[i].members) > len(votes[best].members) {
+				best = i
+			}
+		}
+
+		fv := votes[best].fv
+		fv.Evidence = append([]types.Evidence(nil), fv.Evidence...)
+		fv.Links = append([]string(nil), fv.Links...)
+		for _, other := range votes[best].members[1:] {
+			fv.Evidence = mergeEvidence(fv.Evidence, other.Evidence)
+			fv.Links = mergeLinks(fv.Links, other.Links)
+			fv.Confidence = math.Max(fv.Confidence, other.Confidence)
+		}
+		agreement := float64(len(votes[best].members)) / n
+		if fv.Value != nil {
+			fv.Confidence *= 0.5 + 0.5*agreement
+		}
+		fv.Agreement = &agreement
+		fields = append(fields, fv)
+	}
+	entry.Fields = fields
+	entry.Links = nil
+	for _, m := range cluster {
+		entry.Links = mergeLinks(entry.Links, m.entry.Links)
+	}
+	var caveats [][]types.Caveat
+	for _, m := range cluster {
+		caveats = append(caveats, m.entry.Caveats)
+	}
+	entry.Caveats = mergeCaveats(caveats...)
+	entry.Ensemble = &types.Ensemble{Models: models, Found: len(cluster)}
+	if entry.Provenance != nil {
+		p := *entry.Provenance
+		p.Model = strings.Join(models, "+")
+		entry.Provenance = &p
+	}
+	return entry
+}
+
+func fieldByID(entry types.Entry, id string) (types.FieldValue, bool) {
+	for _, fv := range entry.Fields {
+		if fv.ID == id {
+			return fv, true
+		}
+	}
+	return types.FieldValue{}, false
+}
+
+// sameValue reports whether two models extracted the same value: equal
+// ignoring case and surrounding space, or numbers within 10% of each other
+func sameValue(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if x, ok := schema.NumberValue(a); ok {
+		if y, ok := schema.NumberValue(b); ok {
+			return math.Abs(x-y) <= 0.1*math.Max(math.Abs(x), math.Abs(y))
+		}
+	}
+	return valueKey(a) == valueKey(b)
+}
+
+func similarPrimary(a, b any) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return areSimilar(normalizePrimary(fmt.Sprintf("%v", a)), normalizePrimary(fmt.Sprintf("%v", b)))
+}
@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"sort"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// EnforceEntity holds a thread's freshly extracted entries to the form's
+// entity definition, since models split and lump items differently from
+// thread to thread. Entries without a value for any key field don't name an
+// entity and are dropped; entries whose key values all match name the same
+// one and are folded into the strongest of them. Forms without an entity are
+// left alone. Returns the entries and how many were merged away and dropped.
+func EnforceEntity(form *types.Form, threadID string, entries []types.Entry) ([]types.Entry, int, int) {
+	if form.Entity == nil {
+		return entries, 0, 0
+	}
+	keyIDs := schema.EntityKey(form)
+	if len(keyIDs) == 0 {
+		return entries, 0, 0
+	}
+
+	var kept []types.Entry
+	var keys [][]string
+	dropped := 0
+	for _, entry := range entries {
+		key := make([]string, len(keyIDs))
+		named := false
+		for i, id := range keyIDs {
+			key[i] = normalizePrimary(primaryFieldString(entry, id))
+			named = named || key[i] != ""
+		}
+		if !named {
+			dropped++
+			continue
+		}
+		kept = append(kept, entry)
+		keys = append(keys, key)
+	}
+
+	// Group entries by the first entry whose key they match
+	var groups [][]int
+	for i := range kept {
+		placed := false
+		for g, group := range groups {
+			if sameEntity(keys[group[0]], keys[i]) {
+				groups[g] = append(groups[g], i)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			groups = append(groups, []int{i})
+		}
+	}
+
+	inputs := make([]RankInput, len(kept))
+	for i, entry := range kept {
+		inputs[i] = RankInput{ThreadPostID: threadID, EntryIndex: i, Entry: entry}
+	}
+	drop := map[int]bool{}
+	for _, group := range groups {
+		if len(group) <= 1 {
+			continue
+		}
+		items := make([]indexedEntry, len(group))
+		for i, idx := range group {
+			items[i] = indexedEntry{idx: idx, algoScore: entryStrength(kept[idx])}
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].algoScore > items[j].algoScore
+		})
+		best := &kept[items[0].idx]
+		caveats := [][]types.Caveat{best.Caveats}
+		best.Fields = mergeDuplicates(form, inputs, items)
+		for _, item := range items[1:] {
+			other := kept[item.idx]
+			best.Links = mergeLinks(best.Links, other.Links)
+			caveats = append(caveats, other.Caveats)
+			drop[item.idx] = true
+		}
+		best.Caveats = mergeCaveats(caveats...)
+	}
+	if len(drop) == 0 {
+		return kept, 0, dropped
+	}
+
+	merged := make([]types.Entry, 0, len(kept)-len(drop))
+	for i, entry := range kept {
+		if !drop[i] {
+			merged = append(merged, entry)
+		}
+	}
+	return merged, len(drop), dropped
+}
+
+// sameEntity reports whether two entries' normalized key values name the same
+// entity: every key field has a value in both, and the values are similar
+func sameEntity(a, b []string) bool {
+	for i := range a {
+		if a[i] == "" || b[i] == "" || !areSimilar(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
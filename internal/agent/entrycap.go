@@ -0,0 +1,104 @@
+package agent
+
+import (
+	"sort"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// CapEntries enforces a thread's entry cap on freshly extracted entries. With
+// the merge policy, entries naming the same item (by the form's primary
+// field) are first folded into the strongest of them; entries still past the
+// cap, weakest first, are marked Overflow. Entries keep their extraction
+// order. Returns the entries and how many were merged away and flagged.
+func CapEntries(form *types.Form, policy types.EntryCapPolicy, threadID string, entries []types.Entry) ([]types.Entry, int, int) {
+	if policy.Max <= 0 || len(entries) <= policy.Max {
+		return entries, 0, 0
+	}
+
+	merged := 0
+	if policy.Overflow == "merge" {
+		before := len(entries)
+		entries = mergeThreadDuplicates(form, threadID, entries)
+		merged = before - len(entries)
+	}
+	if len(entries) <= policy.Max {
+		return entries, merged, 0
+	}
+
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return entryStrength(entries[order[a]]) > entryStrength(entries[order[b]])
+	})
+	for _, i := range order[policy.Max:] {
+		entries[i].Overflow = true
+	}
+	return entries, merged, len(order) - policy.Max
+}
+
+// mergeThreadDuplicates folds entries of one thread whose primary values
+// match into the strongest entry of each group
+func mergeThreadDuplicates(form *types.Form, threadID string, entries []types.Entry) []types.Entry {
+	primaryID := schema.PrimaryField(form)
+	if primaryID == "" {
+		return entries
+	}
+
+	inputs := make([]RankInput, len(entries))
+	var items []indexedEntry
+	for i, entry := range entries {
+		inputs[i] = RankInput{ThreadPostID: threadID, EntryIndex: i, Entry: entry}
+		raw := primaryFieldString(entry, primaryID)
+		if raw == "" {
+			continue
+		}
+		items = append(items, indexedEntry{
+			idx:       i,
+			rawValue:  raw,
+			normValue: normalizePrimary(raw),
+			algoScore: entryStrength(entry),
+		})
+	}
+
+	drop := map[int]bool{}
+	for _, group := range groupBySimlarity(items) {
+		if len(group) <= 1 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].algoScore > group[j].algoScore
+		})
+		best := &entries[group[0].idx]
+		best.Fields = mergeDuplicates(form, inputs, group)
+		for _, item := range group[1:] {
+			best.Links = mergeLinks(best.Links, entries[item.idx].Links)
+			drop[item.idx] = true
+		}
+	}
+	if len(drop) == 0 {
+		return entries
+	}
+
+	kept := make([]types.Entry, 0, len(entries)-len(drop))
+	for i, entry := range entries {
+		if !drop[i] {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// entryStrength sums the strength of an entry's filled fields
+func entryStrength(entry types.Entry) float64 {
+	var total float64
+	for _, fv := range entry.Fields {
+		if fv.Value != nil {
+			total += valueStrength(fv)
+		}
+	}
+	return total
+}
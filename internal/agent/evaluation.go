@@ -10,16 +10,18 @@ import (
 
 	"belaykit"
 
+	"hiveminer/internal/schema"
 	"hiveminer/pkg/types"
 )
 
 // ClaudeEvaluator uses Claude CLI to evaluate individual thread relevance
 type ClaudeEvaluator struct {
-	runner  Runner
-	prompts fs.FS
-	model   string
-	logger  belaykit.EventHandler
-	backend string
+	runner      Runner
+	prompts     fs.FS
+	model       string
+	logger      belaykit.EventHandler
+	backend     string
+	commentSort string
 }
 
 // NewClaudeEvaluator creates a new Claude-based thread evaluator
@@ -27,6 +29,12 @@ func NewClaudeEvaluator(runner Runner, prompts fs.FS, model string, logger belay
 	return &ClaudeEvaluator{runner: runner, prompts: prompts, model: model, logger: logger, backend: backend}
 }
 
+// SetCommentSort sets the comment sort the agent fetches threads with,
+// overriding the form's comment_sort
+func (e *ClaudeEvaluator) SetCommentSort(sort string) {
+	e.commentSort = sort
+}
+
 // evalFileResult is the JSON structure the agent writes to the eval output file
 type evalFileResult struct {
 	PostID           string `json:"post_id"`
@@ -116,26 +124,37 @@ func (e *ClaudeEvaluator) renderPrompt(form *types.Form, thread types.ThreadStat
 		return "", fmt.Errorf("loading template: %w", err)
 	}
 
+	commentSort := e.commentSort
+	if commentSort == "" {
+		commentSort = form.CommentSort
+	}
+
 	data := struct {
 		FormTitle       string
 		FormDescription string
+		Audience        []string
 		Fields          []types.Field
+		Exclusions      []types.Exclusion
 		ThreadTitle     string
 		Permalink       string
 		PostID          string
 		Executable      string
 		EvalPath        string
 		ThreadPath      string
+		CommentSort     string
 	}{
 		FormTitle:       form.Title,
 		FormDescription: form.Description,
+		Audience:        schema.AudienceLines(form),
 		Fields:          form.Fields,
+		Exclusions:      form.Exclusions,
 		ThreadTitle:     thread.Title,
 		Permalink:       thread.Permalink,
 		PostID:          thread.PostID,
 		Executable:      executable,
 		EvalPath:        evalPath,
 		ThreadPath:      threadPath,
+		CommentSort:     commentSort,
 	}
 
 	return pt.Render(data)
@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"belaykit"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+const (
+	litePreviewComments = 3    // top comments shown to the lite evaluator
+	liteSelftextChars   = 1500 // post text shown to the lite evaluator
+	liteCommentChars    = 600  // text shown per comment
+)
+
+// ThreadFetcher fetches a thread with up to commentLimit comments
+type ThreadFetcher interface {
+	GetThread(ctx context.Context, permalink string, commentLimit int) (*types.Thread, error)
+}
+
+// LiteEvaluator judges thread relevance from a preview — the title, flair,
+// the start of the post and its top few comments, fetched in one request —
+// with a single prompt and no tool use. It costs a fraction of the agentic
+// evaluator and suits forms whose relevant threads are easy to recognize.
+// Kept threads aren't saved; extraction fetches them in full.
+type LiteEvaluator struct {
+	runner  Runner
+	prompts fs.FS
+	model   string
+	logger  belaykit.EventHandler
+	backend string
+	fetcher ThreadFetcher
+}
+
+// NewLiteEvaluator creates a thread evaluator previewing threads with fetcher
+func NewLiteEvaluator(runner Runner, prompts fs.FS, model string, logger belaykit.EventHandler, backend string, fetcher ThreadFetcher) *LiteEvaluator {
+	return &LiteEvaluator{runner: runner, prompts: prompts, model: model, logger: logger, backend: backend, fetcher: fetcher}
+}
+
+// EvaluateThread previews a thread and asks for a verdict on it. Threads
+// from other sources than Reddit (wiki pages, reviews, transcripts, web
+// pages) were asked for by name and are kept.
+func (e *LiteEvaluator) EvaluateThread(ctx context.Context, form *types.Form, thread types.ThreadState, sessionDir string) (*EvalResult, error) {
+	if thread.Source != "" {
+		return &EvalResult{PostID: thread.PostID, Verdict: "keep", Reason: fmt.Sprintf("%s source, kept without preview", thread.Source)}, nil
+	}
+
+	preview, err := e.fetcher.GetThread(ctx, thread.Permalink, litePreviewComments)
+	if err != nil {
+		return nil, fmt.Errorf("fetching preview: %w", err)
+	}
+	prompt, err := e.renderPrompt(form, thread, preview)
+	if err != nil {
+		return nil, fmt.Errorf("rendering prompt: %w", err)
+	}
+
+	opts := []belaykit.RunOption{belaykit.WithModel(e.model)}
+	if e.backend != "codex" {
+		opts = append(opts, belaykit.WithMaxTurns(1))
+	}
+	if e.logger != nil {
+		opts = append(opts, belaykit.WithEventHandler(e.logger))
+	}
+
+	var lastErr error
+	const maxAttempts = 2
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		result, err := e.runner.Run(ctx, prompt, opts...)
+		if err != nil {
+			lastErr = fmt.Errorf("running agent (attempt %d/%d): %w", attempt, maxAttempts, err)
+			continue
+		}
+		verdict, err := parseLiteVerdict(result.Text)
+		if err != nil {
+			lastErr = fmt.Errorf("parsing verdict (attempt %d/%d): %w", attempt, maxAttempts, err)
+			continue
+		}
+		verdict.PostID = thread.PostID
+		return verdict, nil
+	}
+	return nil, lastErr
+}
+
+func (e *LiteEvaluator) renderPrompt(form *types.Form, thread types.ThreadState, preview *types.Thread) (string, error) {
+	pt, err := belaykit.LoadPromptTemplate(e.prompts, "evaluate_lite.md", nil)
+	if err != nil {
+		return "", fmt.Errorf("loading template: %w", err)
+	}
+
+	post := preview.Post
+	title := post.Title
+	if title == "" {
+		title = thread.Title
+	}
+	subreddit := post.Subreddit
+	if subreddit == "" {
+		subreddit = thread.Subreddit
+	}
+
+	var comments []string
+	for _, c := range topComments(preview.Comments, litePreviewComments) {
+		comments = append(comments, fmt.Sprintf("(%+d) %s", c.Score, excerpt(c.Body, liteCommentChars)))
+	}
+
+	return pt.Render(struct {
+		FormTitle       string
+		FormDescription string
+		Audience        []string
+		Fields          []types.Field
+		Exclusions      []types.Exclusion
+		ThreadTitle     string
+		Subreddit       string
+		Flair           string
+		Score           int
+		NumComments     int
+		Selftext        string
+		Comments        []string
+	}{
+		FormTitle:       form.Title,
+		FormDescription: form.Description,
+		Audience:        schema.AudienceLines(form),
+		Fields:          form.Fields,
+		Exclusions:      form.Exclusions,
+		ThreadTitle:     title,
+		Subreddit:       subreddit,
+		Flair:           post.Flair,
+		Score:           max(post.Score, thread.Score),
+		NumComments:     max(post.NumComments, thread.NumComments),
+		Selftext:        excerpt(post.Selftext, liteSelftextChars),
+		Comments:        comments,
+	})
+}
+
+// parseLiteVerdict reads the lite evaluator's JSON verdict
+func parseLiteVerdict(response string) (*EvalResult, error) {
+	var parsed struct {
+		Verdict          string `json:"verdict"`
+		Reason           string `json:"reason"`
+		EstimatedEntries int    `json:"estimated_entries"`
+	}
+	if err := belaykit.ExtractJSON(response, &parsed); err != nil {
+		return nil, err
+	}
+	verdict := strings.ToLower(strings.TrimSpace(parsed.Verdict))
+	if verdict != "keep" && verdict != "skip" {
+		return nil, fmt.Errorf("unknown verdict %q", parsed.Verdict)
+	}
+	return &EvalResult{Verdict: verdict, Reason: parsed.Reason, EstimatedEntries: parsed.EstimatedEntries}, nil
+}
+
+// topComments returns the n highest-scored top-level comments, skipping
+// deleted ones
+func topComments(comments []*types.Comment, n int) []*types.Comment {
+	var top []*types.Comment
+	for _, c := range comments {
+		if body := strings.TrimSpace(c.Body); body != "" && body != "[deleted]" && body != "[removed]" {
+			top = append(top, c)
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].Score > top[j].Score
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
+
+// excerpt returns the first limit characters of s, marking a cut with "…"
+func excerpt(s string, limit int) string {
+	s = strings.TrimSpace(s)
+	if r := []rune(s); len(r) > limit {
+		return strings.TrimSpace(string(r[:limit])) + "…"
+	}
+	return s
+}
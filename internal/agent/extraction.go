@@ -2,12 +2,18 @@ package agent
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"io/fs"
+	"slices"
+	"strings"
+	"time"
 
 	"belaykit"
 
+	"hiveminer/internal/schema"
 	"hiveminer/pkg/types"
 )
 
@@ -18,6 +24,8 @@ type ClaudeExtractor struct {
 	model   string
 	logger  belaykit.EventHandler
 	backend string
+
+	consensus bool
 }
 
 // NewClaudeExtractor creates a new Claude CLI extractor
@@ -31,6 +39,11 @@ func NewClaudeExtractor(runner Runner, prompts fs.FS, model string, logger belay
 	}
 }
 
+// SetConsensus enables per-entry endorsement/warning counts in extraction
+func (c *ClaudeExtractor) SetConsensus(enabled bool) {
+	c.consensus = enabled
+}
+
 // ExtractFields extracts all form fields from a thread using Claude
 func (c *ClaudeExtractor) ExtractFields(ctx context.Context, thread *types.Thread, form *types.Form) (*types.ExtractionResult, error) {
 	return c.ExtractFieldsWithOutput(ctx, thread, form, nil)
@@ -72,11 +85,51 @@ func (c *ClaudeExtractor) ExtractFieldsWithOutput(ctx context.Context, thread *t
 	}
 
 	// Build comment links from evidence
-	populateLinks(parsed, thread.Post.Permalink)
+	populateLinks(parsed, thread)
+
+	// Attribute each field to OP or commenters
+	attributeEvidence(parsed, thread)
+
+	// Catch warnings the model took for recommendations
+	markAntiRecommendations(parsed, form)
+
+	// Count the commenters behind each caveat, folding repeated issues
+	for i := range parsed.Entries {
+		caveats := parsed.Entries[i].Caveats
+		for j := range caveats {
+			caveats[j].Threads = []string{thread.Post.ID}
+		}
+		if len(caveats) > 0 {
+			parsed.Entries[i].Caveats = mergeCaveats(caveats)
+		}
+	}
+
+	parsed.PromptTokens = belaykit.EstimateTokens(prompt)
+
+	// Record which model and prompt version produced the entries
+	promptHash := c.promptHash()
+	for i := range parsed.Entries {
+		parsed.Entries[i].Provenance = &types.Provenance{
+			Model:      c.model,
+			Backend:    c.backend,
+			PromptHash: promptHash,
+		}
+	}
 
 	return parsed, nil
 }
 
+// promptHash returns a short hash of the extraction prompt template, so entries
+// can be attributed to a prompt version
+func (c *ClaudeExtractor) promptHash() string {
+	data, err := fs.ReadFile(c.prompts, "extract.md")
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
 // renderPrompt renders the extraction prompt template
 func (c *ClaudeExtractor) renderPrompt(thread *types.Thread, form *types.Form) (string, error) {
 	pt, err := belaykit.LoadPromptTemplate(c.prompts, "extract.md", nil)
@@ -86,39 +139,96 @@ func (c *ClaudeExtractor) renderPrompt(thread *types.Thread, form *types.Form) (
 
 	// Format comments
 	var comments string
+	var summarized bool
 	for _, comment := range flattenComments(thread.Comments) {
-		comments += fmt.Sprintf("[comment_id:%s][%d points] u/%s:\n%s\n\n", comment.ID, comment.Score, comment.Author, comment.Body)
+		comments += fmt.Sprintf("%s u/%s:\n%s\n\n", commentTag(comment), comment.Author, comment.Body)
+		if strings.HasPrefix(comment.ID, "summary_") {
+			summarized = true
+		}
 	}
 
 	data := struct {
 		FormTitle       string
 		FormDescription string
+		Audience        []string
 		ThreadTitle     string
 		Subreddit       string
 		Author          string
 		Score           int
+		Source          string
 		PostContent     string
 		Comments        string
 		Fields          []types.Field
+		Exclusions      []types.Exclusion
+		Consensus       bool
+		Summarized      bool
+		Stages          []promptStage
+		Entity          string
+		EntityKey       string
 	}{
 		FormTitle:       form.Title,
 		FormDescription: form.Description,
+		Audience:        schema.AudienceLines(form),
 		ThreadTitle:     thread.Post.Title,
 		Subreddit:       thread.Post.Subreddit,
 		Author:          thread.Post.Author,
 		Score:           thread.Post.Score,
+		Source:          thread.Post.Source,
 		PostContent:     thread.Post.Selftext,
 		Comments:        comments,
 		Fields:          form.Fields,
+		Exclusions:      form.Exclusions,
+		Consensus:       c.consensus,
+		Summarized:      summarized,
+		Stages:          promptStages(form),
+	}
+	if form.Entity != nil {
+		data.Entity = form.Entity.Description
+		data.EntityKey = strings.Join(schema.EntityKey(form), ", ")
 	}
 
 	return pt.Render(data)
 }
 
+// promptStage is one stage of the extraction order rendered for forms
+// whose fields depend on others
+type promptStage struct {
+	Number int
+	Fields string // the stage's field IDs
+	After  string // the earlier fields they depend on
+}
+
+// promptStages renders the form's field stages (see schema.FieldStages)
+func promptStages(form *types.Form) []promptStage {
+	var stages []promptStage
+	for i, fields := range schema.FieldStages(form) {
+		var ids, after []string
+		for _, f := range fields {
+			ids = append(ids, f.ID)
+			for _, dep := range f.DependsOn {
+				if schema.GetField(form, dep) != nil && !slices.Contains(after, dep) {
+					after = append(after, dep)
+				}
+			}
+		}
+		stages = append(stages, promptStage{Number: i + 1, Fields: strings.Join(ids, ", "), After: strings.Join(after, ", ")})
+	}
+	return stages
+}
+
 // parseResponse parses Claude's JSON response into extraction results
 func (c *ClaudeExtractor) parseResponse(response string, form *types.Form) (*types.ExtractionResult, error) {
 	var parsed struct {
 		Entries []struct {
+			Consensus *struct {
+				Endorsements int `json:"endorsements"`
+				Warnings     int `json:"warnings"`
+			} `json:"consensus"`
+			AntiRecommendation any `json:"anti_recommendation"`
+			Caveats            []struct {
+				Issue    string     `json:"issue"`
+				Evidence []evidence `json:"evidence"`
+			} `json:"caveats"`
 			Fields []struct {
 				ID         string     `json:"id"`
 				Value      any        `json:"value"`
@@ -155,12 +265,47 @@ func (c *ClaudeExtractor) parseResponse(response string, form *types.Form) (*typ
 				Evidence:   ev,
 			})
 		}
-		result.Entries = append(result.Entries, types.Entry{Fields: fields})
+		e := types.Entry{Fields: fields}
+		if entry.Consensus != nil {
+			e.Consensus = newConsensus(entry.Consensus.Endorsements, entry.Consensus.Warnings)
+		}
+		for _, c := range entry.Caveats {
+			if strings.TrimSpace(c.Issue) == "" || len(c.Evidence) == 0 {
+				continue
+			}
+			caveat := types.Caveat{Issue: strings.TrimSpace(c.Issue)}
+			for _, ev := range c.Evidence {
+				caveat.Evidence = append(caveat.Evidence, types.Evidence{Text: ev.Text, CommentID: ev.CommentID, Author: ev.Author})
+			}
+			e.Caveats = append(e.Caveats, caveat)
+		}
+		// Models answer with a reason, or sometimes just true
+		switch v := entry.AntiRecommendation.(type) {
+		case string:
+			if v = strings.TrimSpace(v); v != "" && v != "false" {
+				e.AntiRecommendation = &types.AntiRecommendation{Source: types.AntiSourceExtract, Reason: v}
+			}
+		case bool:
+			if v {
+				e.AntiRecommendation = &types.AntiRecommendation{Source: types.AntiSourceExtract}
+			}
+		}
+		result.Entries = append(result.Entries, e)
 	}
 
 	return result, nil
 }
 
+// newConsensus builds a Consensus with its net agreement computed
+func newConsensus(endorsements, warnings int) *types.Consensus {
+	endorsements, warnings = max(endorsements, 0), max(warnings, 0)
+	c := &types.Consensus{Endorsements: endorsements, Warnings: warnings}
+	if total := endorsements + warnings; total > 0 {
+		c.NetAgreement = float64(endorsements-warnings) / float64(total)
+	}
+	return c
+}
+
 type evidence struct {
 	Text      string `json:"text"`
 	CommentID string `json:"comment_id,omitempty"`
@@ -168,8 +313,12 @@ type evidence struct {
 }
 
 // populateLinks builds Reddit comment permalink arrays on each field and entry
-// from the comment_ids found in evidence.
-func populateLinks(result *types.ExtractionResult, postPermalink string) {
+// from the comment_ids found in evidence. Each link is the comment's own
+// permalink from the fetched thread, which stays correct for deeply nested
+// comments and "continue this thread" contexts; comments missing one fall
+// back to the post permalink plus the comment ID.
+func populateLinks(result *types.ExtractionResult, thread *types.Thread) {
+	postPermalink := thread.Post.Permalink
 	if postPermalink == "" {
 		return
 	}
@@ -177,6 +326,12 @@ func populateLinks(result *types.ExtractionResult, postPermalink string) {
 	if postPermalink[len(postPermalink)-1] != '/' {
 		postPermalink += "/"
 	}
+	permalinks := map[string]string{}
+	for _, c := range flattenComments(thread.Comments) {
+		if p := commentPermalink(c.Permalink); p != "" {
+			permalinks[c.ID] = p
+		}
+	}
 
 	for i := range result.Entries {
 		seen := map[string]bool{}
@@ -187,21 +342,104 @@ func populateLinks(result *types.ExtractionResult, postPermalink string) {
 				if cid == "" || cid == "post_content" {
 					continue
 				}
-				link := postPermalink + cid + "/"
+				link, ok := permalinks[cid]
+				if !ok {
+					if strings.HasPrefix(cid, "summary_") {
+						continue
+					}
+					link = postPermalink + cid + "/"
+				}
 				if !fieldSeen[link] {
 					fieldSeen[link] = true
 					result.Entries[i].Fields[j].Links = append(result.Entries[i].Fields[j].Links, link)
 				}
+				// Entry-level deduped links, in first-cited order
 				if !seen[link] {
 					seen[link] = true
+					result.Entries[i].Links = append(result.Entries[i].Links, link)
 				}
 			}
 		}
-		// Entry-level deduped links
-		for link := range seen {
-			result.Entries[i].Links = append(result.Entries[i].Links, link)
+	}
+}
+
+// commentPermalink normalizes a comment permalink to a host-less path with a
+// trailing slash
+func commentPermalink(p string) string {
+	p = strings.TrimPrefix(p, "https://www.reddit.com")
+	p = strings.TrimPrefix(p, "https://reddit.com")
+	if p == "" || !strings.HasPrefix(p, "/") {
+		return ""
+	}
+	if !strings.HasSuffix(p, "/") {
+		p += "/"
+	}
+	return p
+}
+
+// attributeEvidence records whether each field's evidence came from the
+// thread's author, other commenters, or both. Each quote gets its comment's
+// score and creation time, and a missing author, from the thread's comments.
+func attributeEvidence(result *types.ExtractionResult, thread *types.Thread) {
+	op := normalizeAuthor(thread.Post.Author)
+	byID := map[string]*types.Comment{}
+	for _, c := range flattenComments(thread.Comments) {
+		if !strings.HasPrefix(c.ID, "summary_") {
+			byID[c.ID] = c
 		}
 	}
+
+	for i := range result.Entries {
+		for j := range result.Entries[i].Fields {
+			fv := &result.Entries[i].Fields[j]
+			fromOP, fromOthers := false, false
+			for k := range fv.Evidence {
+				ev := &fv.Evidence[k]
+				if c, ok := byID[ev.CommentID]; ok {
+					if ev.Author == "" {
+						ev.Author = c.Author
+					}
+					ev.Score = c.Score
+					ev.Created = c.Created
+				}
+				switch {
+				case ev.CommentID == "post_content":
+					fromOP = true
+				case ev.Author == "":
+					continue
+				case op != "" && normalizeAuthor(ev.Author) == op:
+					fromOP = true
+				default:
+					fromOthers = true
+				}
+			}
+			switch {
+			case fromOP && fromOthers:
+				fv.AnsweredBy = types.AnsweredByMixed
+			case fromOP:
+				fv.AnsweredBy = types.AnsweredByOP
+			case fromOthers:
+				fv.AnsweredBy = types.AnsweredByCommenters
+			}
+		}
+	}
+}
+
+// commentTag formats the [comment_id:...][N points][date] prefix comments
+// carry in prompts
+func commentTag(c *types.Comment) string {
+	tag := fmt.Sprintf("[comment_id:%s][%d points]", c.ID, c.Score)
+	if c.Created > 0 {
+		tag += "[" + time.Unix(int64(c.Created), 0).UTC().Format("2006-01-02") + "]"
+	}
+	return tag
+}
+
+// normalizeAuthor lowercases a username and strips any u/ prefix
+func normalizeAuthor(author string) string {
+	author = strings.ToLower(strings.TrimSpace(author))
+	author = strings.TrimPrefix(author, "/")
+	return strings.TrimPrefix(author, "u/")
 }
 
 // flattenComments flattens nested comments into a list
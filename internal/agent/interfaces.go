@@ -12,6 +12,19 @@ type Extractor interface {
 	ExtractFields(ctx context.Context, thread *types.Thread, form *types.Form) (*types.ExtractionResult, error)
 }
 
+// Summarizer condenses comments of threads too large for one extraction call
+type Summarizer interface {
+	// SummarizeComments summarizes a block of a thread's comments for the form's fields
+	SummarizeComments(ctx context.Context, form *types.Form, thread *types.Thread) (string, error)
+}
+
+// Verifier reviews extracted entries against the thread they came from
+type Verifier interface {
+	// VerifyEntries checks each entry against the thread, returning one
+	// verification per entry in order; nil where the model gave no verdict
+	VerifyEntries(ctx context.Context, form *types.Form, thread *types.Thread, entries []types.Entry) ([]*types.Verification, error)
+}
+
 // Discoverer defines the interface for discovering relevant subreddits
 type Discoverer interface {
 	// DiscoverSubreddits finds relevant subreddits for a form and query
@@ -52,15 +65,110 @@ type RankInput struct {
 	Entry        types.Entry
 	ThreadScore  int
 	NumComments  int
+	Source       string // the thread's source, empty for Reddit threads
 }
 
 // RankOutput holds the ranking result for a single entry
 type RankOutput struct {
 	ThreadPostID string   // identifies which thread
 	EntryIndex   int      // identifies which entry within thread
+	EntryID      string   // the entry's stable ID, when it has one
 	AlgoScore    float64  // algorithmic score 0-100
 	Penalty      float64  // agentic penalty (negative)
 	FinalScore   float64  // algo + penalty, clamped >= 0
 	Flags        []string // spam, joke, etc.
 	Reason       string   // Claude's assessment text
+
+	// SaturationPenalty is the part of Penalty from sharing a thread with
+	// better entries
+	SaturationPenalty float64
+
+	// MergedFields is set on the best entry of a duplicate group: its fields
+	// combined with those of its duplicates (see mergeDuplicates)
+	MergedFields []types.FieldValue
+
+	// MergedCaveats is set with MergedFields: the caveats of the whole
+	// duplicate group, so warnings from every thread reach the best entry
+	MergedCaveats []types.Caveat
+}
+
+// Answerer defines the interface for answering questions about a session's entries
+type Answerer interface {
+	// Answer answers a question using only the given entries, citing them by number
+	Answer(ctx context.Context, form *types.Form, question string, entries []AskEntry) (string, error)
+}
+
+// AskEntry is a numbered entry offered as context to an Answerer
+type AskEntry struct {
+	Number      int // rank position, as shown by runs show
+	ThreadTitle string
+	Subreddit   string
+	Entry       types.Entry
+	Sources     []string // full comment URLs, strongest evidence first
+}
+
+// Advisor proposes form edits from how a session went
+type Advisor interface {
+	// Advise suggests changes to the form given a session's statistics
+	Advise(ctx context.Context, form *types.Form, report FormReport) (*FormAdvice, error)
+}
+
+// FormReport summarizes how a form fared in a session, for an Advisor
+type FormReport struct {
+	Query       string
+	Threads     int // threads evaluated or given
+	Skipped     int
+	Extracted   int // threads with entries
+	Entries     int
+	Fields      []FieldReport
+	SkipReasons []types.SkipReasonGroup
+	RankFlags   map[string]int // entries by ranking flag
+	FlagReasons []string       // sample of the ranker's reasons for flagging
+}
+
+// FieldReport is how often extraction filled a field and how sure it was
+type FieldReport struct {
+	ID            string
+	Type          types.FieldType
+	Question      string
+	Filled        int     // entries with a value
+	FillRate      float64 // share of entries with a value
+	AvgConfidence float64 // over filled values
+	LowConfidence int     // filled values under 0.5 confidence
+}
+
+// FormAdvice is an Advisor's proposed form edits
+type FormAdvice struct {
+	Summary     string           `json:"summary"`
+	Suggestions []FormSuggestion `json:"suggestions"`
+	// Form is the form with the suggestions applied, when the model gave one
+	Form *types.Form `json:"form,omitempty"`
+	// FormError is why the model's patched form was dropped, if it was
+	FormError string `json:"-"`
+}
+
+// FormSuggestion is one proposed form edit
+type FormSuggestion struct {
+	Kind   string `json:"kind"`            // reword, hint, drop, add, exclusion, description
+	Field  string `json:"field,omitempty"` // the field it changes, if any
+	Change string `json:"change"`
+	Reason string `json:"reason"`
+}
+
+// FormDesigner drafts a form and run settings from a plain description of
+// what the user wants to research
+type FormDesigner interface {
+	// DesignRun proposes a run for the request, revising previous (nil for
+	// a first draft) with the user's feedback
+	DesignRun(ctx context.Context, request string, previous *RunProposal, feedback []string) (*RunProposal, error)
+}
+
+// RunProposal is a drafted form with the run settings proposed for it
+type RunProposal struct {
+	Form          types.Form `json:"form"`
+	Query         string     `json:"query"`
+	Subreddits    []string   `json:"subreddits"`
+	Limit         int        `json:"limit"`          // threads to extract
+	RequestBudget int        `json:"request_budget"` // Reddit requests, 0 for unlimited
+	Notes         string     `json:"notes,omitempty"`
 }
@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"hiveminer/pkg/types"
+)
+
+// mergeDuplicates combines the fields of a duplicate group into the fields of
+// its best entry (group[0]). For each field the strongest value, by confidence
+// and evidence, becomes the primary; agreeing values contribute their evidence
+// and disagreeing values are kept as alternatives instead of being dropped.
+// Number fields also get min/median/max across all reported values.
+func mergeDuplicates(form *types.Form, entries []RankInput, group []indexedEntry) []types.FieldValue {
+	numeric := map[string]bool{}
+	for _, f := range form.Fields {
+		numeric[f.ID] = f.Type == types.FieldTypeNumber
+	}
+
+	type candidate struct {
+		fv       types.FieldValue
+		threadID string
+	}
+
+	best := entries[group[0].idx].Entry
+	var order []string
+	byField := map[string][]candidate{}
+	for _, fv := range best.Fields {
+		order = append(order, fv.ID)
+	}
+	for _, item := range group {
+		input := entries[item.idx]
+		for _, fv := range input.Entry.Fields {
+			if !slices.Contains(order, fv.ID) {
+				order = append(order, fv.ID)
+			}
+			if fv.Value == nil {
+				continue
+			}
+			byField[fv.ID] = append(byField[fv.ID], candidate{fv, input.ThreadPostID})
+		}
+	}
+
+	merged := make([]types.FieldValue, 0, len(order))
+	for _, id := range order {
+		cands := byField[id]
+		if len(cands) == 0 {
+			// No member has a value; keep the best entry's (null) field as is
+			for _, fv := range best.Fields {
+				if fv.ID == id {
+					merged = append(merged, fv)
+				}
+			}
+			continue
+		}
+
+		sort.SliceStable(cands, func(i, j int) bool {
+			return valueStrength(cands[i].fv) > valueStrength(cands[j].fv)
+		})
+
+		primary := cands[0].fv
+		primary.Evidence = append([]types.Evidence(nil), primary.Evidence...)
+		primary.Links = append([]string(nil), primary.Links...)
+		primary.Alternatives = nil
+		primary.Aggregate = nil
+
+		primaryKey := valueKey(primary.Value)
+		altIndex := map[string]int{}
+		for _, c := range cands[1:] {
+			key := valueKey(c.fv.Value)
+			if key == primaryKey {
+				primary.Evidence = mergeEvidence(primary.Evidence, c.fv.Evidence)
+				primary.Links = mergeLinks(primary.Links, c.fv.Links)
+				continue
+			}
+			if i, ok := altIndex[key]; ok {
+				alt := &primary.Alternatives[i]
+				alt.Evidence = mergeEvidence(alt.Evidence, c.fv.Evidence)
+				alt.Links = mergeLinks(alt.Links, c.fv.Links)
+				continue
+			}
+			altIndex[key] = len(primary.Alternatives)
+			primary.Alternatives = append(primary.Alternatives, types.AlternativeValue{
+				Value:      c.fv.Value,
+				Confidence: c.fv.Confidence,
+				Evidence:   c.fv.Evidence,
+				Links:      c.fv.Links,
+				ThreadID:   c.threadID,
+			})
+		}
+		if numeric[id] {
+			var nums []float64
+			for _, c := range cands {
+				if n, ok := parseNumber(c.fv.Value); ok {
+					nums = append(nums, n)
+				}
+			}
+			primary.Aggregate = aggregateNumbers(nums)
+		}
+		merged = append(merged, primary)
+	}
+	return merged
+}
+
+var numberPattern = regexp.MustCompile(`-?\d+(?:\.\d+)?`)
+
+// parseNumber reads a numeric field value, tolerating strings like "$1,200"
+func parseNumber(v any) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case int:
+		return float64(val), true
+	case string:
+		m := numberPattern.FindString(strings.ReplaceAll(val, ",", ""))
+		if m == "" {
+			return 0, false
+		}
+		n, err := strconv.ParseFloat(m, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// aggregateNumbers computes min/median/max, or nil for fewer than two values
+func aggregateNumbers(nums []float64) *types.NumericAggregate {
+	if len(nums) < 2 {
+		return nil
+	}
+	sorted := slices.Clone(nums)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	median := sorted[mid]
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return &types.NumericAggregate{
+		Count:  len(sorted),
+		Min:    sorted[0],
+		Median: median,
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// valueStrength scores how well-supported a field value is: its confidence,
+// boosted by the number of supporting quotes and their upvotes
+func valueStrength(fv types.FieldValue) float64 {
+	upvotes := 0
+	for _, ev := range fv.Evidence {
+		if ev.Score > 0 {
+			upvotes += ev.Score
+		}
+	}
+	quotes := math.Min(float64(len(fv.Evidence)), 5)
+	return fv.Confidence + quotes*0.05 + math.Log2(float64(upvotes)+1)*0.02
+}
+
+// valueKey normalizes a field value for equality comparison
+func valueKey(v any) string {
+	return strings.ToLower(strings.TrimSpace(fmt.Sprintf("%v", v)))
+}
+
+// mergeEvidence appends evidence not already present (by comment ID and text)
+func mergeEvidence(dst, src []types.Evidence) []types.Evidence {
+	for _, ev := range src {
+		dup := false
+		for _, have := range dst {
+			if have.CommentID == ev.CommentID && have.Text == ev.Text {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			dst = append(dst, ev)
+		}
+	}
+	return dst
+}
+
+// mergeLinks appends links not already present
+func mergeLinks(dst, src []string) []string {
+	for _, l := range src {
+		if !slices.Contains(dst, l) {
+			dst = append(dst, l)
+		}
+	}
+	return dst
+}
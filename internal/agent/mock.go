@@ -32,6 +32,7 @@ func (m *MockRanker) RankEntries(ctx context.Context, form *types.Form, entries
 		outputs[i] = RankOutput{
 			ThreadPostID: input.ThreadPostID,
 			EntryIndex:   input.EntryIndex,
+			EntryID:      input.Entry.ID,
 			AlgoScore:    50,
 			FinalScore:   50,
 		}
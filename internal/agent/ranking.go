@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"io/fs"
 	"math"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"unicode"
 
 	"belaykit"
 
+	"hiveminer/internal/schema"
 	"hiveminer/pkg/types"
 )
 
@@ -23,6 +26,20 @@ type ClaudeRanker struct {
 	model   string
 	logger  belaykit.EventHandler
 	backend string
+
+	// batchSize caps the entries per assessment call (0 = all in one) and
+	// workers is how many batches are assessed at once
+	batchSize int
+	workers   int
+
+	// fallbacks are the models tried in turn when the rank model keeps
+	// failing on a batch
+	fallbacks []string
+
+	// malformed records assessments rejected by validation in the last run
+	malformed []string
+	// batches records how each batch of the last run was assessed
+	batches []types.RankBatch
 }
 
 // NewClaudeRanker creates a new ranker
@@ -36,6 +53,24 @@ func NewClaudeRanker(runner Runner, prompts fs.FS, model string, logger belaykit
 	}
 }
 
+// SetBatchSize caps how many entries each assessment call sees; 0 sends
+// every entry in one call
+func (r *ClaudeRanker) SetBatchSize(n int) {
+	r.batchSize = n
+}
+
+// SetFallbackModels sets the models a batch is retried with, in order, when
+// the rank model fails on it, before its entries fall back to algorithmic
+// scores
+func (r *ClaudeRanker) SetFallbackModels(models []string) {
+	r.fallbacks = models
+}
+
+// SetWorkers sets how many assessment batches run concurrently
+func (r *ClaudeRanker) SetWorkers(n int) {
+	r.workers = n
+}
+
 // RankEntries scores entries algorithmically, then sends to Claude for quality assessment
 func (r *ClaudeRanker) RankEntries(ctx context.Context, form *types.Form, entries []RankInput) ([]RankOutput, error) {
 	if len(entries) == 0 {
@@ -49,20 +84,38 @@ func (r *ClaudeRanker) RankEntries(ctx context.Context, form *types.Form, entrie
 	applyDiversityPenalty(form, entries, outputs)
 
 	// Step 3: Thread saturation penalty — penalize multiple entries from same thread
-	applyThreadSaturation(entries, outputs)
+	applyThreadSaturation(schema.Saturation(form), entries, outputs)
 
 	// Step 4: Agentic assessment
 	assessed, err := r.AssessWithClaude(ctx, form, entries, outputs)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		// If Claude assessment fails, return algorithmic scores only
 		fmt.Printf("  Warning: agentic assessment failed: %v\n", err)
 		fmt.Println("  Using algorithmic scores only")
-		return outputs, nil
+		assessed = outputs
 	}
 
+	// Step 5: Penalize products retailers report as discontinued
+	applyDiscontinuedPenalty(entries, assessed)
+
+	// Step 6: Penalize entries the verify phase found unsupported
+	applyVerificationPenalty(entries, assessed)
+
+	// Step 7: Keep warnings against an item out of the top results
+	applyAntiRecommendations(entries, assessed)
+
+	// Step 8: Flag outliers and contradictions for review (no score change)
+	applyConsensusChecks(form, entries, assessed)
+
 	return assessed, nil
 }
 
+// summaryConfidenceDiscount scales the confidence of summary-derived entries
+const summaryConfidenceDiscount = 0.75
+
 // ScoreAlgorithmic computes pure algorithmic scores for entries (no Claude needed)
 func (r *ClaudeRanker) ScoreAlgorithmic(form *types.Form, entries []RankInput) []RankOutput {
 	outputs := make([]RankOutput, len(entries))
@@ -81,10 +134,17 @@ func (r *ClaudeRanker) ScoreAlgorithmic(form *types.Form, entries []RankInput) [
 		if confCount > 0 {
 			confidenceScore = (confSum / float64(confCount)) * 100
 		}
+		// Summaries lose nuance; trust their confidence less
+		if input.Entry.SummaryDerived {
+			confidenceScore *= summaryConfidenceDiscount
+		}
 
-		// Completeness component (25%): non-null fields / total, required weighted 2x
+		// Completeness component (25%): non-null fields / total, required
+		// weighted 2x, each filled field counting in proportion to its
+		// confidence. Missing a critical field zeroes the score.
 		var totalWeight float64
 		var filledWeight float64
+		missingCritical := false
 		fieldMap := make(map[string]types.FieldValue)
 		for _, fv := range input.Entry.Fields {
 			fieldMap[fv.ID] = fv
@@ -96,7 +156,9 @@ func (r *ClaudeRanker) ScoreAlgorithmic(form *types.Form, entries []RankInput) [
 			}
 			totalWeight += weight
 			if fv, ok := fieldMap[field.ID]; ok && fv.Value != nil {
-				filledWeight += weight
+				filledWeight += weight * fv.Confidence
+			} else if field.Critical {
+				missingCritical = true
 			}
 		}
 		var completenessScore float64
@@ -119,12 +181,27 @@ func (r *ClaudeRanker) ScoreAlgorithmic(form *types.Form, entries []RankInput) [
 		// Weighted sum
 		algoScore := confidenceScore*0.40 + completenessScore*0.25 + upvoteScore*0.20 + commentScore*0.15
 
+		// Consensus component (20%, when extracted): net agreement among
+		// commenters, trusted in proportion to how many weighed in
+		if c := input.Entry.Consensus; c != nil {
+			votes := math.Min(float64(c.Endorsements+c.Warnings)/5, 1.0)
+			consensusScore := 50 + 50*c.NetAgreement*votes
+			algoScore = algoScore*0.80 + consensusScore*0.20
+		}
+
+		// Scale by how much the form trusts the thread's source
+		algoScore *= schema.SourceWeight(form, input.Source)
+
 		// Clamp to 0-100
 		algoScore = math.Max(0, math.Min(100, algoScore))
+		if missingCritical {
+			algoScore = 0
+		}
 
 		outputs[i] = RankOutput{
 			ThreadPostID: input.ThreadPostID,
 			EntryIndex:   input.EntryIndex,
+			EntryID:      input.Entry.ID,
 			AlgoScore:    algoScore,
 			FinalScore:   algoScore,
 		}
@@ -145,17 +222,8 @@ type indexedEntry struct {
 // "Walt Disney World" vs "Walt Disney World (Magic Kingdom, EPCOT, ...)"
 // without relying on the LLM.
 func applyDiversityPenalty(form *types.Form, entries []RankInput, outputs []RankOutput) {
-	// Find the primary field ID (first required field, or just first field)
-	primaryID := ""
-	for _, f := range form.Fields {
-		if f.Required {
-			primaryID = f.ID
-			break
-		}
-	}
-	if primaryID == "" && len(form.Fields) > 0 {
-		primaryID = form.Fields[0].ID
-	}
+	// Find the primary field ID (marked primary, else first required, else first)
+	primaryID := schema.PrimaryField(form)
 	if primaryID == "" {
 		return
 	}
@@ -190,6 +258,14 @@ func applyDiversityPenalty(form *types.Form, entries []RankInput, outputs []Rank
 			return group[i].algoScore > group[j].algoScore
 		})
 
+		// Fold duplicates' field values into the best entry
+		outputs[group[0].idx].MergedFields = mergeDuplicates(form, entries, group)
+		var caveats [][]types.Caveat
+		for _, item := range group {
+			caveats = append(caveats, entries[item.idx].Entry.Caveats)
+		}
+		outputs[group[0].idx].MergedCaveats = mergeCaveats(caveats...)
+
 		// Penalize all but the best
 		for rank, item := range group {
 			if rank == 0 {
@@ -212,9 +288,14 @@ func applyDiversityPenalty(form *types.Form, entries []RankInput, outputs []Rank
 }
 
 // applyThreadSaturation penalizes entries when too many come from the same thread.
-// A single thread with 20 entries shouldn't dominate the top results. The best
-// entry from each thread is untouched; the 2nd gets -5, the 3rd -10, etc.
-func applyThreadSaturation(entries []RankInput, outputs []RankOutput) {
+// A single thread with 20 entries shouldn't dominate the top results. By default
+// the best entry from each thread is untouched; the 2nd gets -5, the 3rd -10,
+// etc. The form's saturation policy can change the curve or turn it off.
+func applyThreadSaturation(policy types.SaturationPolicy, entries []RankInput, outputs []RankOutput) {
+	if policy.Disabled {
+		return
+	}
+
 	// Group output indices by thread, sorted by current FinalScore descending
 	type scored struct {
 		idx        int
@@ -235,20 +316,73 @@ func applyThreadSaturation(entries []RankInput, outputs []RankOutput) {
 			return group[i].finalScore > group[j].finalScore
 		})
 
-		// Penalize 2nd+ entries from this thread
-		for rank := 1; rank < len(group); rank++ {
+		// Penalize entries past the free ones from this thread
+		for rank := policy.Free; rank < len(group); rank++ {
 			idx := group[rank].idx
-			penalty := -5.0 * float64(rank) // -5, -10, -15, -20, ...
-			if penalty < -30 {
-				penalty = -30 // cap at -30
+			penalty := -policy.Step * float64(rank-policy.Free+1) // -5, -10, -15, -20, ...
+			if penalty < -policy.Cap {
+				penalty = -policy.Cap
 			}
 
 			outputs[idx].Penalty += penalty
+			outputs[idx].SaturationPenalty = penalty
 			outputs[idx].FinalScore = math.Max(0, outputs[idx].AlgoScore+outputs[idx].Penalty)
 		}
 	}
 }
 
+// discontinuedPenalty is the score lost by an entry whose product is
+// discontinued: still worth knowing about, but no longer a recommendation
+const discontinuedPenalty = -40.0
+
+// applyDiscontinuedPenalty flags and penalizes entries whose product the
+// enrich phase found discontinued
+func applyDiscontinuedPenalty(entries []RankInput, outputs []RankOutput) {
+	for i, input := range entries {
+		a := input.Entry.Availability
+		if a == nil || a.Status != types.AvailabilityDiscontinued {
+			continue
+		}
+		outputs[i].Penalty += discontinuedPenalty
+		outputs[i].FinalScore = math.Max(0, outputs[i].AlgoScore+outputs[i].Penalty)
+		outputs[i].Flags = appendUnique(outputs[i].Flags, schema.DiscontinuedFlag.ID)
+	}
+}
+
+// unverifiedPenalty is applied to entries the verify phase found the thread
+// doesn't support or that rest on a joke, sarcasm, negation or hypothetical
+const unverifiedPenalty = -30.0
+
+// applyVerificationPenalty flags and penalizes entries failing verification.
+// Partially supported entries aren't penalized here: their unsupported
+// values already lost confidence. Anti-recommendations are left to
+// applyAntiRecommendations.
+func applyVerificationPenalty(entries []RankInput, outputs []RankOutput) {
+	for i, input := range entries {
+		v := input.Entry.Verification
+		if v == nil || v.Verdict != types.VerdictUnsupported && len(v.Issues) == 0 || input.Entry.AntiRecommendation != nil {
+			continue
+		}
+		outputs[i].Penalty += unverifiedPenalty
+		outputs[i].FinalScore = math.Max(0, outputs[i].AlgoScore+outputs[i].Penalty)
+		outputs[i].Flags = appendUnique(outputs[i].Flags, schema.UnverifiedFlag.ID)
+	}
+}
+
+// applyAntiRecommendations flags entries whose evidence advises against the
+// item and scores them 0, ranking them below every recommendation
+func applyAntiRecommendations(entries []RankInput, outputs []RankOutput) {
+	for i, input := range entries {
+		if input.Entry.AntiRecommendation == nil {
+			continue
+		}
+		outputs[i].Penalty = math.Min(outputs[i].Penalty, -outputs[i].AlgoScore)
+		outputs[i].SaturationPenalty = 0 // the score is 0 regardless
+		outputs[i].FinalScore = 0
+		outputs[i].Flags = appendUnique(outputs[i].Flags, schema.AntiRecommendationFlag.ID)
+	}
+}
+
 // primaryFieldString extracts the string value of the primary field from an entry
 func primaryFieldString(entry types.Entry, fieldID string) string {
 	for _, fv := range entry.Fields {
@@ -385,7 +519,10 @@ func appendUnique(slice []string, s string) []string {
 type rankPromptData struct {
 	FormTitle       string
 	FormDescription string
+	Audience        []string
 	Fields          []types.Field
+	PrimaryField    string          // field identifying an entry's item
+	Flags           []types.FlagDef // quality flags to assess for
 	Entries         []rankPromptEntry
 }
 
@@ -399,6 +536,34 @@ type rankPromptField struct {
 	ID         string
 	Value      any
 	Confidence float64
+	AnsweredBy string
+	Quotes     int // supporting quotes
+	BestScore  int // highest Reddit score among the quoted comments
+	WorstScore int // lowest Reddit score among the quoted comments
+}
+
+// rankPromptFields summarizes an entry's fields for ranking prompts
+func rankPromptFields(entry types.Entry) []rankPromptField {
+	fields := make([]rankPromptField, 0, len(entry.Fields))
+	for _, fv := range entry.Fields {
+		field := rankPromptField{
+			ID:         fv.ID,
+			Value:      fv.Value,
+			Confidence: fv.Confidence,
+			AnsweredBy: fv.AnsweredBy,
+			Quotes:     len(fv.Evidence),
+		}
+		for k, ev := range fv.Evidence {
+			if k == 0 || ev.Score > field.BestScore {
+				field.BestScore = ev.Score
+			}
+			if k == 0 || ev.Score < field.WorstScore {
+				field.WorstScore = ev.Score
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
 }
 
 // claudeAssessment represents Claude's response for a single flagged entry
@@ -409,81 +574,262 @@ type claudeAssessment struct {
 	Reason  string   `json:"reason"`
 }
 
-// AssessWithClaude sends all entries to Claude for quality/spam assessment
+// assessmentFlags returns the flags the rank prompt allows Claude to assign:
+// the form's quality flags plus duplicate
+func assessmentFlags(form *types.Form) []string {
+	var ids []string
+	for _, f := range schema.QualityFlags(form) {
+		ids = append(ids, f.ID)
+	}
+	return append(ids, schema.DuplicateFlag.ID)
+}
+
+// maxAssessmentPenalty is the largest penalty an assessment may apply
+const maxAssessmentPenalty = 50
+
+// MalformedAssessments returns the assessments rejected by validation during
+// the last ranking, each with the reason it was rejected
+func (r *ClaudeRanker) MalformedAssessments() []string {
+	return r.malformed
+}
+
+// RankBatches returns how each assessment batch of the last ranking was
+// scored: the model whose assessment was applied and the attempts that failed
+func (r *ClaudeRanker) RankBatches() []types.RankBatch {
+	return r.batches
+}
+
+// rankAttempts is how many times each model is tried on a batch
+const rankAttempts = 2
+
+// AssessWithClaude sends the entries to Claude for quality/spam assessment,
+// in batches run concurrently by the ranker's workers. Batches hold entries
+// in order of their primary values, so likely duplicates are assessed
+// together. A failed batch leaves its entries with their algorithmic scores;
+// the call fails if every batch does or ctx is cancelled.
 func (r *ClaudeRanker) AssessWithClaude(ctx context.Context, form *types.Form, inputs []RankInput, outputs []RankOutput) ([]RankOutput, error) {
-	// Build prompt data
-	promptEntries := make([]rankPromptEntry, len(inputs))
+	batches := rankBatches(form, inputs, r.batchSize)
+	workers := min(max(r.workers, 1), len(batches))
+	if len(batches) > 1 {
+		fmt.Printf("  Assessing %d entries in %d batches (%d workers)\n", len(inputs), len(batches), workers)
+	}
+
+	r.malformed = nil
+	r.batches = make([]types.RankBatch, len(batches))
+	var (
+		mu          sync.Mutex
+		assessments []claudeAssessment
+		failed      int
+		lastErr     error
+		done        int
+	)
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range work {
+				label := ""
+				if len(batches) > 1 {
+					label = fmt.Sprintf("batch %d/%d: ", b+1, len(batches))
+				}
+				found, problems, record, err := r.assessBatch(ctx, form, inputs, outputs, batches[b], label)
+				mu.Lock()
+				done++
+				r.batches[b] = record
+				for _, p := range problems {
+					r.malformed = append(r.malformed, label+p)
+				}
+				if err != nil {
+					failed++
+					lastErr = err
+					if ctx.Err() == nil && len(batches) > 1 {
+						fmt.Printf("  Warning: %sassessment failed, its entries keep algorithmic scores: %v\n", label, err)
+					}
+				} else {
+					assessments = append(assessments, found...)
+					if len(batches) > 1 {
+						fmt.Printf("  Assessed batch %d/%d (%d of %d done, %d flagged)\n", b+1, len(batches), done, len(batches), len(found))
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	// Stop handing out batches once the run is cancelled; in-flight ones finish
+feed:
+	for b := range batches {
+		select {
+		case work <- b:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if failed == len(batches) {
+		return nil, lastErr
+	}
+
+	// Apply penalties on top of the diversity and saturation ones
+	scored := make([]RankOutput, len(outputs))
+	copy(scored, outputs)
+
+	for _, a := range assessments {
+		penalty := math.Max(a.Penalty, -maxAssessmentPenalty)
+		if penalty > -10 && len(a.Flags) > 0 {
+			penalty = -10 // Minimum penalty if flagged
+		}
+
+		out := &scored[a.Index]
+		out.Penalty += penalty
+		out.FinalScore = math.Max(0, out.AlgoScore+out.Penalty)
+		for _, f := range a.Flags {
+			out.Flags = appendUnique(out.Flags, f)
+		}
+		if a.Reason != "" {
+			out.Reason = a.Reason
+		}
+	}
+
+	return scored, nil
+}
+
+// rankBatches splits entry indices into batches of at most size entries
+// (all in one when size is 0), ordered by normalized primary value so that
+// near-duplicates tend to share a batch
+func rankBatches(form *types.Form, inputs []RankInput, size int) [][]int {
+	order := make([]int, len(inputs))
+	for i := range order {
+		order[i] = i
+	}
+	if size <= 0 || len(inputs) <= size {
+		return [][]int{order}
+	}
+	primaryID := schema.PrimaryField(form)
+	keys := make([]string, len(inputs))
 	for i, input := range inputs {
-		fields := make([]rankPromptField, 0, len(input.Entry.Fields))
-		for _, fv := range input.Entry.Fields {
-			fields = append(fields, rankPromptField{
-				ID:         fv.ID,
-				Value:      fv.Value,
-				Confidence: fv.Confidence,
-			})
+		keys[i] = normalizePrimary(primaryFieldString(input.Entry, primaryID))
+	}
+	sort.SliceStable(order, func(a, b int) bool { return keys[order[a]] < keys[order[b]] })
+
+	var batches [][]int
+	for start := 0; start < len(order); start += size {
+		batches = append(batches, order[start:min(start+size, len(order))])
+	}
+	return batches
+}
+
+// assessBatch assesses a batch with the rank model, retrying and then moving
+// down the fallback models while attempts fail, and records the path taken
+func (r *ClaudeRanker) assessBatch(ctx context.Context, form *types.Form, inputs []RankInput, outputs []RankOutput, batch []int, label string) ([]claudeAssessment, []string, types.RankBatch, error) {
+	record := types.RankBatch{Entries: len(batch)}
+	models := append([]string{r.model}, r.fallbacks...)
+	var lastErr error
+	for m, model := range models {
+		if m > 0 {
+			fmt.Printf("  %sfalling back to %s\n", label, model)
 		}
+		for attempt := 1; attempt <= rankAttempts; attempt++ {
+			found, problems, err := r.assessBatchWith(ctx, model, form, inputs, outputs, batch, label)
+			if err == nil {
+				record.Model = model
+				if model == "" {
+					record.Model = "default"
+				}
+				return found, problems, record, nil
+			}
+			if ctx.Err() != nil {
+				return nil, nil, record, ctx.Err()
+			}
+			lastErr = err
+			record.Failures = append(record.Failures, fmt.Sprintf("%s: %v", displayModel(model), err))
+			if len(models) > 1 || attempt < rankAttempts {
+				fmt.Printf("  %s%s failed (attempt %d/%d): %v\n", label, displayModel(model), attempt, rankAttempts, err)
+			}
+		}
+	}
+	return nil, nil, record, lastErr
+}
+
+// displayModel names a model for messages; "" is the backend's default
+func displayModel(model string) string {
+	if model == "" {
+		return "default model"
+	}
+	return model
+}
+
+// assessBatchWith asks a model to assess the entries at the given indices,
+// returning assessments indexed into inputs and the malformed ones it dropped
+func (r *ClaudeRanker) assessBatchWith(ctx context.Context, model string, form *types.Form, inputs []RankInput, outputs []RankOutput, batch []int, label string) ([]claudeAssessment, []string, error) {
+	// Build prompt data
+	promptEntries := make([]rankPromptEntry, len(batch))
+	for i, idx := range batch {
 		promptEntries[i] = rankPromptEntry{
 			Index:     i,
-			AlgoScore: outputs[i].AlgoScore,
-			Fields:    fields,
+			AlgoScore: outputs[idx].AlgoScore,
+			Fields:    rankPromptFields(inputs[idx].Entry),
 		}
 	}
 
 	data := rankPromptData{
 		FormTitle:       form.Title,
 		FormDescription: form.Description,
+		Audience:        schema.AudienceLines(form),
 		Fields:          form.Fields,
+		PrimaryField:    schema.PrimaryField(form),
+		Flags:           schema.QualityFlags(form),
 		Entries:         promptEntries,
 	}
 
 	// Render prompt
 	prompt, err := r.renderPrompt(data)
 	if err != nil {
-		return nil, fmt.Errorf("rendering rank prompt: %w", err)
+		return nil, nil, fmt.Errorf("rendering rank prompt: %w", err)
 	}
 
 	// Call Claude
-	opts := []belaykit.RunOption{belaykit.WithModel(r.model)}
+	opts := []belaykit.RunOption{belaykit.WithModel(model)}
 	if r.logger != nil {
 		opts = append(opts, belaykit.WithEventHandler(r.logger))
 	}
 	result, err := r.runner.Run(ctx, prompt, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("running agent: %w", err)
+		return nil, nil, fmt.Errorf("running agent: %w", err)
 	}
 
-	// Parse response
-	assessments, err := parseAssessments(result.Text)
-	if err != nil {
-		return nil, fmt.Errorf("parsing assessment: %w", err)
-	}
-
-	// Apply penalties
-	scored := make([]RankOutput, len(outputs))
-	copy(scored, outputs)
-
-	for _, a := range assessments {
-		if a.Index < 0 || a.Index >= len(scored) {
-			continue
+	// Parse and validate the response, re-prompting once to repair it
+	allowed := assessmentFlags(form)
+	assessments, problems, err := checkAssessments(result.Text, len(batch), allowed)
+	if err != nil || len(problems) > 0 {
+		if err != nil {
+			problems = append(problems, err.Error())
 		}
-		penalty := a.Penalty
-		if penalty > 0 {
-			penalty = -penalty // Ensure penalty is negative
+		fmt.Printf("  %sAssessment has %d problem(s), asking for a corrected response...\n", label, len(problems))
+		repaired, repairProblems, repairErr := r.repairAssessments(ctx, model, result.Text, problems, len(batch), allowed)
+		switch {
+		case repairErr == nil:
+			assessments, problems = repaired, repairProblems
+		case err != nil:
+			return nil, nil, fmt.Errorf("parsing assessment: %w (repair failed: %v)", err, repairErr)
 		}
-		if penalty < -50 {
-			penalty = -50
-		}
-		if penalty > -10 && len(a.Flags) > 0 {
-			penalty = -10 // Minimum penalty if flagged
-		}
-
-		scored[a.Index].Penalty = penalty
-		scored[a.Index].FinalScore = math.Max(0, scored[a.Index].AlgoScore+penalty)
-		scored[a.Index].Flags = a.Flags
-		scored[a.Index].Reason = a.Reason
+	}
+	if len(problems) > 0 {
+		fmt.Printf("  Warning: %signoring %d malformed assessment(s)\n", label, len(problems))
+		assessments = validAssessments(assessments, len(batch), allowed)
 	}
 
-	return scored, nil
+	// Map batch positions back to entry indices
+	for i := range assessments {
+		assessments[i].Index = batch[assessments[i].Index]
+	}
+	return assessments, problems, nil
 }
 
 func (r *ClaudeRanker) renderPrompt(data rankPromptData) (string, error) {
@@ -505,6 +851,86 @@ func (r *ClaudeRanker) renderPrompt(data rankPromptData) (string, error) {
 	return pt.Render(data)
 }
 
+// repairAssessments makes a single non-agentic Claude call to correct an
+// assessment response that failed to parse or validate
+func (r *ClaudeRanker) repairAssessments(ctx context.Context, model, response string, problems []string, numEntries int, allowed []string) ([]claudeAssessment, []string, error) {
+	prompt := fmt.Sprintf(`The following entry assessment response is malformed:
+
+%s
+
+Problems:
+- %s
+
+Return ONLY a corrected JSON array (no other text) in this format:
+[{"index": 3, "flags": ["duplicate"], "penalty": -25, "reason": "why"}]
+
+Rules:
+- "index" is an entry index from 0 to %d, each appearing at most once
+- "flags" uses only: %s
+- "penalty" is between -%d and 0
+- Return [] if no entries need flagging`, response, strings.Join(problems, "\n- "), numEntries-1,
+		strings.Join(allowed, ", "), maxAssessmentPenalty)
+
+	opts := []belaykit.RunOption{belaykit.WithModel(model)}
+	if r.backend != "codex" {
+		opts = append(opts, belaykit.WithMaxTurns(1))
+	}
+	result, err := r.runner.Run(ctx, prompt, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("repair call: %w", err)
+	}
+	return checkAssessments(result.Text, numEntries, allowed)
+}
+
+// checkAssessments parses a response and validates its assessments
+func checkAssessments(response string, numEntries int, allowed []string) ([]claudeAssessment, []string, error) {
+	assessments, err := parseAssessments(response)
+	if err != nil {
+		return nil, nil, err
+	}
+	var problems []string
+	for i, a := range assessments {
+		if msg := assessmentProblem(a, assessments[:i], numEntries, allowed); msg != "" {
+			problems = append(problems, msg)
+		}
+	}
+	return assessments, problems, nil
+}
+
+// assessmentProblem describes why an assessment is invalid, given the ones
+// before it, or returns "" if it's valid
+func assessmentProblem(a claudeAssessment, earlier []claudeAssessment, numEntries int, allowed []string) string {
+	if a.Index < 0 || a.Index >= numEntries {
+		return fmt.Sprintf("entry %d: index out of range (0-%d)", a.Index, numEntries-1)
+	}
+	for _, e := range earlier {
+		if e.Index == a.Index {
+			return fmt.Sprintf("entry %d: assessed more than once", a.Index)
+		}
+	}
+	for _, f := range a.Flags {
+		if !slices.Contains(allowed, f) {
+			return fmt.Sprintf("entry %d: unknown flag %q", a.Index, f)
+		}
+	}
+	if a.Penalty > 0 || a.Penalty < -maxAssessmentPenalty {
+		return fmt.Sprintf("entry %d: penalty %g outside -%d to 0", a.Index, a.Penalty, maxAssessmentPenalty)
+	}
+	return ""
+}
+
+// validAssessments drops invalid assessments entirely rather than applying
+// the parts that look usable
+func validAssessments(assessments []claudeAssessment, numEntries int, allowed []string) []claudeAssessment {
+	var valid []claudeAssessment
+	for i, a := range assessments {
+		if assessmentProblem(a, assessments[:i], numEntries, allowed) == "" {
+			valid = append(valid, a)
+		}
+	}
+	return valid
+}
+
 func parseAssessments(response string) ([]claudeAssessment, error) {
 	var assessments []claudeAssessment
 	err := belaykit.ExtractJSONArray(response, &assessments)
@@ -0,0 +1,109 @@
+package agent
+
+import (
+	"context"
+	"math"
+	"os"
+	"slices"
+	"testing"
+
+	"belaykit"
+
+	"hiveminer/pkg/types"
+)
+
+// replyRunner answers every prompt with the same text
+type replyRunner string
+
+func (r replyRunner) Run(ctx context.Context, prompt string, opts ...belaykit.RunOption) (belaykit.Result, error) {
+	return belaykit.Result{Text: string(r)}, nil
+}
+
+var tentForm = &types.Form{
+	Title:       "Tents",
+	Description: "Tents campers recommend",
+	Fields: []types.Field{
+		{ID: "name", Type: "string", Question: "Which tent is recommended?", Required: true},
+	},
+	Saturation: &types.SaturationPolicy{Step: 20, Cap: 40},
+}
+
+// tentInputs are three tents from thread a1 and a weaker duplicate of the
+// first from thread b2
+func tentInputs() []RankInput {
+	var inputs []RankInput
+	add := func(postID, name string, score int) {
+		inputs = append(inputs, RankInput{
+			ThreadPostID: postID,
+			EntryIndex:   len(inputs),
+			Entry:        types.Entry{ID: postID + "-" + name, Fields: []types.FieldValue{{ID: "name", Value: name, Confidence: 0.9}}},
+			ThreadScore:  score,
+			NumComments:  50,
+		})
+	}
+	add("a1", "Duplex", 100)
+	add("a1", "Stratospire", 100)
+	add("a1", "Lunar Solo", 100)
+	add("b2", "Duplex", 10)
+	return inputs
+}
+
+func rankTents(t *testing.T, reply string) []RankOutput {
+	t.Helper()
+	r := NewClaudeRanker(replyRunner(reply), os.DirFS("../../prompts"), "", nil, "claude")
+	outputs, err := r.RankEntries(context.Background(), tentForm, tentInputs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.MalformedAssessments()) > 0 {
+		t.Fatalf("assessments rejected: %v", r.MalformedAssessments())
+	}
+	return outputs
+}
+
+func TestAssessmentAddsToEarlierPenalties(t *testing.T) {
+	base := rankTents(t, "[]")
+	if !slices.Contains(base[3].Flags, "duplicate") {
+		t.Fatalf("b2's Duplex not flagged duplicate: %v", base[3].Flags)
+	}
+	var saturation []float64
+	for _, out := range base {
+		if out.SaturationPenalty < 0 {
+			saturation = append(saturation, out.SaturationPenalty)
+		}
+	}
+	slices.Sort(saturation)
+	if !slices.Equal(saturation, []float64{-40, -20}) {
+		t.Fatalf("saturation penalties %v, want the form's curve of -20 and -40", saturation)
+	}
+
+	assessed := rankTents(t, `[
+		{"index": 1, "flags": ["spam"], "penalty": -15, "reason": "affiliate link"},
+		{"index": 3, "flags": [], "penalty": -5, "reason": "vague"}
+	]`)
+	extra := []float64{0, -15, 0, -5}
+	for i, out := range assessed {
+		if want := base[i].Penalty + extra[i]; out.Penalty != want {
+			t.Errorf("entry %d: penalty %g, want %g (earlier %g plus the assessment's %g)", i, out.Penalty, want, base[i].Penalty, extra[i])
+		}
+		if want := math.Max(0, out.AlgoScore+out.Penalty); out.FinalScore != want {
+			t.Errorf("entry %d: final score %g, want %g", i, out.FinalScore, want)
+		}
+		for _, f := range base[i].Flags {
+			if !slices.Contains(out.Flags, f) {
+				t.Errorf("entry %d: lost flag %q after assessment: %v", i, f, out.Flags)
+			}
+		}
+	}
+	if !slices.Contains(assessed[1].Flags, "spam") {
+		t.Errorf("entry 1: flags %v, want spam", assessed[1].Flags)
+	}
+}
+
+func TestFlaggedAssessmentPenalizesAtLeastTen(t *testing.T) {
+	base := rankTents(t, "[]")
+	assessed := rankTents(t, `[{"index": 0, "flags": ["low_effort"], "penalty": 0, "reason": "no detail"}]`)
+	if want := base[0].Penalty - 10; assessed[0].Penalty != want {
+		t.Errorf("penalty %g, want %g", assessed[0].Penalty, want)
+	}
+}
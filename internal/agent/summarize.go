@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"belaykit"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// ClaudeSummarizer implements Summarizer using Claude
+type ClaudeSummarizer struct {
+	runner  Runner
+	prompts fs.FS
+	model   string
+	logger  belaykit.EventHandler
+	backend string
+}
+
+// NewClaudeSummarizer creates a new Claude-based comment summarizer
+func NewClaudeSummarizer(runner Runner, prompts fs.FS, model string, logger belaykit.EventHandler, backend string) *ClaudeSummarizer {
+	return &ClaudeSummarizer{runner: runner, prompts: prompts, model: model, logger: logger, backend: backend}
+}
+
+// SummarizeComments condenses a block of a thread's comments into notes on
+// what they say about the form's fields
+func (s *ClaudeSummarizer) SummarizeComments(ctx context.Context, form *types.Form, thread *types.Thread) (string, error) {
+	pt, err := belaykit.LoadPromptTemplate(s.prompts, "summarize.md", nil)
+	if err != nil {
+		return "", fmt.Errorf("loading summarize template: %w", err)
+	}
+
+	var comments strings.Builder
+	for _, c := range flattenComments(thread.Comments) {
+		fmt.Fprintf(&comments, "%s u/%s:\n%s\n\n", commentTag(c), c.Author, c.Body)
+	}
+
+	prompt, err := pt.Render(struct {
+		FormTitle       string
+		FormDescription string
+		Audience        []string
+		ThreadTitle     string
+		Subreddit       string
+		Comments        string
+		Fields          []types.Field
+	}{
+		FormTitle:       form.Title,
+		FormDescription: form.Description,
+		Audience:        schema.AudienceLines(form),
+		ThreadTitle:     thread.Post.Title,
+		Subreddit:       thread.Post.Subreddit,
+		Comments:        comments.String(),
+		Fields:          form.Fields,
+	})
+	if err != nil {
+		return "", fmt.Errorf("rendering summarize prompt: %w", err)
+	}
+
+	opts := []belaykit.RunOption{belaykit.WithModel(s.model)}
+	if s.backend != "codex" {
+		opts = append(opts, belaykit.WithMaxTurns(1))
+	}
+	if s.logger != nil {
+		opts = append(opts, belaykit.WithEventHandler(s.logger))
+	}
+
+	result, err := s.runner.Run(ctx, prompt, opts...)
+	if err != nil {
+		return "", fmt.Errorf("running agent: %w", err)
+	}
+	summary := strings.TrimSpace(result.Text)
+	if summary == "" {
+		return "", fmt.Errorf("empty summary")
+	}
+	return summary, nil
+}
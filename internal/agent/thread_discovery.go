@@ -9,9 +9,11 @@ import (
 	"path/filepath"
 	"strings"
 	"text/template"
+	"time"
 
 	"belaykit"
 
+	"hiveminer/internal/schema"
 	"hiveminer/pkg/types"
 )
 
@@ -22,6 +24,9 @@ type ClaudeThreadDiscoverer struct {
 	model   string
 	logger  belaykit.EventHandler
 	backend string
+
+	searchLog  []types.DiscoverySearch // searches of the last DiscoverThreads call
+	yieldNotes []string                // how subreddits did for the form so far
 }
 
 // NewClaudeThreadDiscoverer creates a new Claude-based thread discoverer
@@ -49,6 +54,7 @@ type discoveryResult struct {
 
 // DiscoverThreads uses Claude to search Reddit and identify the most relevant threads
 func (d *ClaudeThreadDiscoverer) DiscoverThreads(ctx context.Context, form *types.Form, query string, subreddits []string, limit int, sessionDir string) ([]types.Post, error) {
+	d.searchLog = nil
 	executable, err := os.Executable()
 	if err != nil {
 		return nil, fmt.Errorf("getting executable path: %w", err)
@@ -86,6 +92,18 @@ func (d *ClaudeThreadDiscoverer) DiscoverThreads(ctx context.Context, form *type
 	return d.parseOutputFile(outputPath)
 }
 
+// SetYieldNotes gives the agent the subreddits' yield so far for the form,
+// one line per subreddit
+func (d *ClaudeThreadDiscoverer) SetYieldNotes(notes []string) {
+	d.yieldNotes = notes
+}
+
+// SearchLog returns the searches the agent reported in its last
+// DiscoverThreads call
+func (d *ClaudeThreadDiscoverer) SearchLog() []types.DiscoverySearch {
+	return d.searchLog
+}
+
 func (d *ClaudeThreadDiscoverer) renderPrompt(form *types.Form, query string, subreddits []string, limit int, executable string, outputPath string) (string, error) {
 	funcMap := template.FuncMap{
 		"joinHints": func(hints []string) string {
@@ -101,8 +119,11 @@ func (d *ClaudeThreadDiscoverer) renderPrompt(form *types.Form, query string, su
 	data := struct {
 		FormTitle       string
 		FormDescription string
+		Audience        []string
 		SearchHints     string
 		Fields          []types.Field
+		Exclusions      []types.Exclusion
+		YieldNotes      []string
 		Query           string
 		Subreddits      string
 		TargetCount     int
@@ -111,8 +132,11 @@ func (d *ClaudeThreadDiscoverer) renderPrompt(form *types.Form, query string, su
 	}{
 		FormTitle:       form.Title,
 		FormDescription: form.Description,
+		Audience:        schema.AudienceLines(form),
 		SearchHints:     strings.Join(form.SearchHints, ", "),
 		Fields:          form.Fields,
+		Exclusions:      form.Exclusions,
+		YieldNotes:      d.yieldNotes,
 		Query:           query,
 		Subreddits:      strings.Join(subreddits, ", "),
 		TargetCount:     limit,
@@ -139,8 +163,16 @@ func (d *ClaudeThreadDiscoverer) parseOutputFile(path string) ([]types.Post, err
 	}
 
 	// Log search activity
+	now := time.Now()
 	for _, entry := range result.SearchLog {
 		fmt.Printf("  Searched r/%s for '%s': %d results\n", entry.Subreddit, entry.Query, entry.Results)
+		d.searchLog = append(d.searchLog, types.DiscoverySearch{
+			Method:    "agent",
+			Query:     entry.Query,
+			Subreddit: strings.TrimPrefix(entry.Subreddit, "r/"),
+			Results:   entry.Results,
+			At:        now,
+		})
 	}
 
 	posts := make([]types.Post, len(result.Posts))
@@ -152,6 +184,8 @@ func (d *ClaudeThreadDiscoverer) parseOutputFile(path string) ([]types.Post, err
 			Subreddit:   p.Subreddit,
 			Score:       p.Score,
 			NumComments: p.NumComments,
+
+			DiscoveryReason: p.Reason,
 		}
 	}
 
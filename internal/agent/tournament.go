@@ -0,0 +1,238 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"text/template"
+
+	"belaykit"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// Tournament settings: entries are compared in groups of tournamentGroupSize,
+// reshuffled over tournamentRounds rounds so each meets a dozen or so others
+const (
+	tournamentGroupSize = 5
+	tournamentRounds    = 3
+)
+
+// tournamentPromptData holds data for the rank_tournament.md template
+type tournamentPromptData struct {
+	FormTitle       string
+	FormDescription string
+	Audience        []string
+	Fields          []types.Field
+	Entries         []rankPromptEntry
+}
+
+// RankHead reorders the head of the ranking by comparing its entries against
+// each other in small groups. head is in its current order, best first; the
+// result is a permutation of head's indices, best first. Each group the model
+// orders yields a win for every pair in it, and entries are sorted by their
+// Bradley-Terry strength fitted to those wins, which accounts for who each
+// entry was up against. Entries whose comparisons all failed keep their place.
+func (r *ClaudeRanker) RankHead(ctx context.Context, form *types.Form, head []RankInput) ([]int, error) {
+	n := len(head)
+	if n < 2 {
+		return identity(n), nil
+	}
+
+	pt, err := belaykit.LoadPromptTemplate(r.prompts, "rank_tournament.md", template.FuncMap{
+		"json": func(v any) string {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Sprintf("%v", v)
+			}
+			return string(b)
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading tournament template: %w", err)
+	}
+
+	// wins[i][j] counts how often entry i was ordered above entry j
+	var mu sync.Mutex
+	wins := make([][]float64, n)
+	for i := range wins {
+		wins[i] = make([]float64, n)
+	}
+	failed := 0
+
+	rng := rand.New(rand.NewSource(1)) // fixed seed: same groups run to run
+	for round := 0; round < tournamentRounds; round++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// The first round compares neighbors in the current order; later
+		// rounds mix the field
+		order := identity(n)
+		if round > 0 {
+			rng.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+		}
+
+		var wg sync.WaitGroup
+		for _, group := range splitGroups(order, tournamentGroupSize) {
+			if len(group) < 2 {
+				continue
+			}
+			wg.Add(1)
+			go func(group []int) {
+				defer wg.Done()
+				ranked, err := r.compareGroup(ctx, pt, form, head, group)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					failed++
+					return
+				}
+				for a := range ranked {
+					for b := a + 1; b < len(ranked); b++ {
+						wins[ranked[a]][ranked[b]]++
+					}
+				}
+			}(group)
+		}
+		wg.Wait()
+	}
+	if failed > 0 {
+		fmt.Printf("  Warning: %d tournament comparisons failed\n", failed)
+	}
+
+	strength := bradleyTerry(wins)
+	result := identity(n)
+	sort.SliceStable(result, func(a, b int) bool {
+		return strength[result[a]] > strength[result[b]]
+	})
+	return result, nil
+}
+
+// bradleyTerry fits a strength per player to pairwise win counts with the
+// standard MM iteration. Each player gets half a win and half a loss against
+// its neighbors in the current order, which keeps unbeaten or uncompared
+// entries finite and lets uncompared ones keep their place.
+func bradleyTerry(wins [][]float64) []float64 {
+	n := len(wins)
+	w := make([][]float64, n)
+	for i := range w {
+		w[i] = append([]float64(nil), wins[i]...)
+	}
+	for i := 0; i+1 < n; i++ {
+		w[i][i+1] += 0.5
+		w[i+1][i] += 0.5
+	}
+	// A slight tilt toward the current order breaks ties the same way
+	for i := 0; i+1 < n; i++ {
+		w[i][i+1] += 0.01
+	}
+
+	p := make([]float64, n)
+	for i := range p {
+		p[i] = 1
+	}
+	for iter := 0; iter < 100; iter++ {
+		next := make([]float64, n)
+		var total float64
+		for i := 0; i < n; i++ {
+			var won, denom float64
+			for j := 0; j < n; j++ {
+				if games := w[i][j] + w[j][i]; games > 0 && i != j {
+					won += w[i][j]
+					denom += games / (p[i] + p[j])
+				}
+			}
+			next[i] = p[i]
+			if denom > 0 {
+				next[i] = won / denom
+			}
+			total += next[i]
+		}
+		for i := range next {
+			next[i] *= float64(n) / total
+		}
+		p = next
+	}
+	return p
+}
+
+// compareGroup asks the model to order a group of head entries, returning
+// the group's head indices best first
+func (r *ClaudeRanker) compareGroup(ctx context.Context, pt *belaykit.PromptTemplate, form *types.Form, head []RankInput, group []int) ([]int, error) {
+	data := tournamentPromptData{
+		FormTitle:       form.Title,
+		FormDescription: form.Description,
+		Audience:        schema.AudienceLines(form),
+		Fields:          form.Fields,
+	}
+	for i, idx := range group {
+		data.Entries = append(data.Entries, rankPromptEntry{
+			Index:  i,
+			Fields: rankPromptFields(head[idx].Entry),
+		})
+	}
+
+	prompt, err := pt.Render(data)
+	if err != nil {
+		return nil, fmt.Errorf("rendering tournament prompt: %w", err)
+	}
+	opts := []belaykit.RunOption{belaykit.WithModel(r.model)}
+	if r.backend != "codex" {
+		opts = append(opts, belaykit.WithMaxTurns(1))
+	}
+	if r.logger != nil {
+		opts = append(opts, belaykit.WithEventHandler(r.logger))
+	}
+	result, err := r.runner.Run(ctx, prompt, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("running agent: %w", err)
+	}
+
+	var resp struct {
+		Order []int `json:"order"`
+	}
+	if err := belaykit.ExtractJSON(result.Text, &resp); err != nil {
+		return nil, fmt.Errorf("parsing tournament order: %w", err)
+	}
+	if len(resp.Order) != len(group) {
+		return nil, fmt.Errorf("tournament order has %d entries, want %d", len(resp.Order), len(group))
+	}
+	seen := make([]bool, len(group))
+	ranked := make([]int, len(group))
+	for pos, i := range resp.Order {
+		if i < 0 || i >= len(group) || seen[i] {
+			return nil, fmt.Errorf("tournament order %v is not a permutation", resp.Order)
+		}
+		seen[i] = true
+		ranked[pos] = group[i]
+	}
+	return ranked, nil
+}
+
+// splitGroups splits order into consecutive groups of at most size, folding a
+// lone leftover entry into the previous group
+func splitGroups(order []int, size int) [][]int {
+	var groups [][]int
+	for start := 0; start < len(order); start += size {
+		end := min(start+size, len(order))
+		groups = append(groups, order[start:end])
+	}
+	if k := len(groups); k > 1 && len(groups[k-1]) == 1 {
+		groups[k-2] = append(groups[k-2], groups[k-1][0])
+		groups = groups[:k-1]
+	}
+	return groups
+}
+
+// identity returns 0..n-1
+func identity(n int) []int {
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	return idx
+}
@@ -0,0 +1,210 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+
+	"belaykit"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// verifyCommentBudget caps the comment text, in bytes, a verification prompt
+// carries. Larger threads are cut down to the quoted comments and their
+// replies, where corrections and "/s" usually are.
+const verifyCommentBudget = 60000
+
+// VerificationIssues are the reasons a verifier may give for an entry
+// misleading even when its values appear in the thread
+var VerificationIssues = []string{"joke", "sarcasm", "negation", "hypothetical"}
+
+// ClaudeVerifier implements Verifier using Claude
+type ClaudeVerifier struct {
+	runner  Runner
+	prompts fs.FS
+	model   string
+	logger  belaykit.EventHandler
+	backend string
+}
+
+// NewClaudeVerifier creates a new Claude-based entry verifier
+func NewClaudeVerifier(runner Runner, prompts fs.FS, model string, logger belaykit.EventHandler, backend string) *ClaudeVerifier {
+	return &ClaudeVerifier{runner: runner, prompts: prompts, model: model, logger: logger, backend: backend}
+}
+
+// verifyPromptEntry is an entry as shown to the verifier
+type verifyPromptEntry struct {
+	Index  int
+	Fields []verifyPromptField
+}
+
+type verifyPromptField struct {
+	ID     string
+	Value  string
+	Quotes []string
+}
+
+// VerifyEntries asks Claude whether the thread supports each entry's values
+// and whether the entry comes from a joke, sarcasm, a negation ("don't buy
+// X") or a hypothetical
+func (v *ClaudeVerifier) VerifyEntries(ctx context.Context, form *types.Form, thread *types.Thread, entries []types.Entry) ([]*types.Verification, error) {
+	pt, err := belaykit.LoadPromptTemplate(v.prompts, "verify.md", nil)
+	if err != nil {
+		return nil, fmt.Errorf("loading verify template: %w", err)
+	}
+
+	promptEntries := make([]verifyPromptEntry, len(entries))
+	for i, entry := range entries {
+		pe := verifyPromptEntry{Index: i}
+		for _, fv := range entry.Fields {
+			if fv.Value == nil {
+				continue
+			}
+			value, _ := json.Marshal(fv.Value)
+			f := verifyPromptField{ID: fv.ID, Value: string(value)}
+			for _, ev := range fv.Evidence {
+				f.Quotes = append(f.Quotes, fmt.Sprintf("[comment_id:%s] %q", ev.CommentID, ev.Text))
+			}
+			pe.Fields = append(pe.Fields, f)
+		}
+		promptEntries[i] = pe
+	}
+
+	prompt, err := pt.Render(struct {
+		FormTitle       string
+		FormDescription string
+		Fields          []types.Field
+		ThreadTitle     string
+		Subreddit       string
+		Author          string
+		PostContent     string
+		Comments        string
+		Partial         bool
+		Entries         []verifyPromptEntry
+		Issues          []string
+	}{
+		FormTitle:       form.Title,
+		FormDescription: form.Description,
+		Fields:          form.Fields,
+		ThreadTitle:     thread.Post.Title,
+		Subreddit:       thread.Post.Subreddit,
+		Author:          thread.Post.Author,
+		PostContent:     thread.Post.Selftext,
+		Comments:        verifyComments(thread, entries),
+		Partial:         threadTextSize(thread) > verifyCommentBudget,
+		Entries:         promptEntries,
+		Issues:          VerificationIssues,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rendering verify prompt: %w", err)
+	}
+
+	opts := []belaykit.RunOption{belaykit.WithModel(v.model)}
+	if v.backend != "codex" {
+		opts = append(opts, belaykit.WithMaxTurns(1))
+	}
+	if v.logger != nil {
+		opts = append(opts, belaykit.WithEventHandler(v.logger))
+	}
+
+	result, err := v.runner.Run(ctx, prompt, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("running agent: %w", err)
+	}
+	return parseVerifications(result.Text, form, len(entries), v.model)
+}
+
+// verifyComments formats the thread's comments for the verify prompt: all
+// of them when they fit the budget, else the comments quoted as evidence and
+// their direct replies
+func verifyComments(thread *types.Thread, entries []types.Entry) string {
+	all := flattenComments(thread.Comments)
+	keep := func(*types.Comment) bool { return true }
+	if threadTextSize(thread) > verifyCommentBudget {
+		quoted := map[string]bool{}
+		for _, entry := range entries {
+			for _, fv := range entry.Fields {
+				for _, ev := range fv.Evidence {
+					quoted[ev.CommentID] = true
+				}
+			}
+		}
+		replies := map[string]bool{}
+		for _, c := range all {
+			if quoted[c.ID] {
+				for _, r := range c.Replies {
+					replies[r.ID] = true
+				}
+			}
+		}
+		keep = func(c *types.Comment) bool { return quoted[c.ID] || replies[c.ID] }
+	}
+
+	var b strings.Builder
+	for _, c := range all {
+		if keep(c) {
+			fmt.Fprintf(&b, "%s u/%s:\n%s\n\n", commentTag(c), c.Author, c.Body)
+		}
+	}
+	return b.String()
+}
+
+func threadTextSize(thread *types.Thread) int {
+	n := 0
+	for _, c := range flattenComments(thread.Comments) {
+		n += len(c.Body)
+	}
+	return n
+}
+
+// parseVerifications reads the verifier's JSON array into one verification
+// per entry, dropping verdicts for unknown entries, fields or issues
+func parseVerifications(response string, form *types.Form, n int, model string) ([]*types.Verification, error) {
+	var parsed []struct {
+		Index              int               `json:"index"`
+		Verdict            string            `json:"verdict"`
+		Issues             []string          `json:"issues"`
+		AntiRecommendation bool              `json:"anti_recommendation"`
+		UnsupportedFields  map[string]string `json:"unsupported_fields"`
+		Reason             string            `json:"reason"`
+	}
+	if err := belaykit.ExtractJSONArray(response, &parsed); err != nil {
+		return nil, fmt.Errorf("parsing verifications: %w", err)
+	}
+
+	now := time.Now()
+	out := make([]*types.Verification, n)
+	for _, p := range parsed {
+		if p.Index < 0 || p.Index >= n {
+			continue
+		}
+		verdict := strings.ToLower(strings.TrimSpace(p.Verdict))
+		if verdict != types.VerdictSupported && verdict != types.VerdictPartial && verdict != types.VerdictUnsupported {
+			continue
+		}
+		v := &types.Verification{Verdict: verdict, AntiRecommendation: p.AntiRecommendation, Reason: p.Reason, Model: model, VerifiedAt: now}
+		for _, issue := range p.Issues {
+			issue = strings.ToLower(strings.TrimSpace(issue))
+			if slices.Contains(VerificationIssues, issue) && !slices.Contains(v.Issues, issue) {
+				v.Issues = append(v.Issues, issue)
+			}
+		}
+		for id, why := range p.UnsupportedFields {
+			if schema.GetField(form, id) == nil {
+				continue
+			}
+			if v.Fields == nil {
+				v.Fields = map[string]string{}
+			}
+			v.Fields[id] = why
+		}
+		out[p.Index] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,216 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SocketName is the control socket created in a running session's directory
+const SocketName = "control.sock"
+
+// Commands accepted on the control socket
+const (
+	CmdPause  = "pause"
+	CmdResume = "resume"
+	CmdCancel = "cancel"
+	CmdStatus = "status"
+)
+
+// ErrRunning is returned by Listen when another process is running the session
+var ErrRunning = errors.New("session is already running")
+
+// Controller lets other processes pause, resume and cancel a running
+// extraction through a Unix socket in the session directory
+type Controller struct {
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	paused   bool
+	resumeCh chan struct{}
+	ln       net.Listener
+	path     string
+}
+
+// New creates a controller; cancel is called on a cancel command
+func New(cancel context.CancelFunc) *Controller {
+	return &Controller{cancel: cancel}
+}
+
+// Listen starts serving the control socket in sessionDir
+func (c *Controller) Listen(sessionDir string) error {
+	path := filepath.Join(sessionDir, SocketName)
+
+	// A leftover socket from a crashed run refuses connections; remove it.
+	// A live one means another process is running this session.
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("%w (control socket %s in use)", ErrRunning, path)
+	}
+	os.Remove(path)
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("listening on control socket: %w", err)
+	}
+	c.mu.Lock()
+	c.ln = ln
+	c.path = path
+	c.mu.Unlock()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go c.serve(conn)
+		}
+	}()
+	return nil
+}
+
+// Close stops the control socket and releases any paused waiters
+func (c *Controller) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ln != nil {
+		c.ln.Close()
+		os.Remove(c.path)
+		c.ln = nil
+	}
+	if c.paused {
+		c.paused = false
+		close(c.resumeCh)
+	}
+}
+
+// Wait blocks while the run is paused. It returns the context's error if the
+// context is cancelled while waiting. A nil controller never pauses.
+func (c *Controller) Wait(ctx context.Context) error {
+	if c == nil {
+		return ctx.Err()
+	}
+	c.mu.Lock()
+	if !c.paused {
+		c.mu.Unlock()
+		return ctx.Err()
+	}
+	ch := c.resumeCh
+	c.mu.Unlock()
+
+	select {
+	case <-ch:
+		return ctx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Pause stops workers from starting new threads; in-flight work finishes
+func (c *Controller) Pause() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.paused {
+		return false
+	}
+	c.paused = true
+	c.resumeCh = make(chan struct{})
+	return true
+}
+
+// Resume releases paused workers
+func (c *Controller) Resume() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.paused {
+		return false
+	}
+	c.paused = false
+	close(c.resumeCh)
+	return true
+}
+
+// Cancel releases paused workers and cancels the run
+func (c *Controller) Cancel() {
+	c.Resume()
+	c.cancel()
+}
+
+// Paused reports whether the run is paused
+func (c *Controller) Paused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused
+}
+
+func (c *Controller) serve(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && line == "" {
+		return
+	}
+
+	var reply string
+	switch strings.TrimSpace(line) {
+	case CmdPause:
+		if c.Pause() {
+			fmt.Println("\nPaused by control request — in-flight threads will finish, then workers wait")
+			reply = "paused"
+		} else {
+			reply = "already paused"
+		}
+	case CmdResume:
+		if c.Resume() {
+			fmt.Println("\nResumed by control request")
+			reply = "resumed"
+		} else {
+			reply = "not paused"
+		}
+	case CmdCancel:
+		fmt.Println("\nCancelled by control request, saving progress...")
+		c.Cancel()
+		reply = "cancelling"
+	case CmdStatus:
+		reply = "running"
+		if c.Paused() {
+			reply = "paused"
+		}
+	default:
+		reply = "error: unknown command " + strings.TrimSpace(line)
+	}
+	fmt.Fprintln(conn, reply)
+}
+
+// Send sends a command to the run in sessionDir and returns its reply
+func Send(sessionDir, cmd string) (string, error) {
+	path := filepath.Join(sessionDir, SocketName)
+	conn, err := net.DialTimeout("unix", path, 5*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("no running process for this session: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	if _, err := fmt.Fprintln(conn, cmd); err != nil {
+		return "", fmt.Errorf("sending command: %w", err)
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && reply == "" {
+		return "", fmt.Errorf("reading reply: %w", err)
+	}
+	reply = strings.TrimSpace(reply)
+	if strings.HasPrefix(reply, "error: ") {
+		return "", fmt.Errorf("%s", strings.TrimPrefix(reply, "error: "))
+	}
+	return reply, nil
+}
@@ -0,0 +1,355 @@
+// Package dump imports Reddit data dumps — pushshift NDJSON archives (.zst,
+// .gz or plain) and the CSVs of Reddit's own data export — into local
+// sources that runs can search and extract from without Reddit's API.
+package dump
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"hiveminer/pkg/types"
+)
+
+// Importer collects posts and comments from dump files and assembles them
+// into threads
+type Importer struct {
+	posts    map[string]*types.Post
+	comments map[string][]comment // by post ID
+}
+
+// comment is a dump comment before it's placed in its thread's tree
+type comment struct {
+	types.Comment
+	parentID string // parent comment ID, empty for top-level comments
+}
+
+// NewImporter returns an empty importer
+func NewImporter() *Importer {
+	return &Importer{posts: map[string]*types.Post{}, comments: map[string][]comment{}}
+}
+
+// AddFile reads one dump file. Pushshift archives are NDJSON of submissions
+// or comments, compressed with zstd (.zst, needing the zstd command), gzip
+// (.gz) or not at all. Data export CSVs are recognized by their columns:
+// posts.csv has a title, comments.csv a link.
+func (im *Importer) AddFile(path string) (posts, comments int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	name := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(name, ".zst"):
+		// Pushshift archives use a long zstd window
+		cmd := exec.Command("zstd", "-dc", "--long=31")
+		cmd.Stdin = f
+		out, pipeErr := cmd.StdoutPipe()
+		if pipeErr != nil {
+			return 0, 0, pipeErr
+		}
+		if startErr := cmd.Start(); startErr != nil {
+			return 0, 0, fmt.Errorf("decompressing %s needs the zstd command: %w", path, startErr)
+		}
+		defer func() {
+			out.Close() // stops zstd if reading ended early
+			if waitErr := cmd.Wait(); waitErr != nil && err == nil {
+				err = fmt.Errorf("decompressing %s: %w", path, waitErr)
+			}
+		}()
+		r = out
+		name = strings.TrimSuffix(name, ".zst")
+	case strings.HasSuffix(name, ".gz"):
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return 0, 0, fmt.Errorf("decompressing %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+		name = strings.TrimSuffix(name, ".gz")
+	}
+
+	if strings.HasSuffix(name, ".csv") {
+		return im.addCSV(r)
+	}
+	return im.addNDJSON(r)
+}
+
+// record is a pushshift submission or comment. Numbers are loose: older
+// archives store some of them as strings.
+type record struct {
+	ID          string          `json:"id"`
+	Title       *string         `json:"title"`
+	Selftext    string          `json:"selftext"`
+	URL         string          `json:"url"`
+	Domain      string          `json:"domain"`
+	Permalink   string          `json:"permalink"`
+	Body        *string         `json:"body"`
+	LinkID      string          `json:"link_id"`
+	ParentID    string          `json:"parent_id"`
+	Author      string          `json:"author"`
+	Subreddit   string          `json:"subreddit"`
+	NSFW        bool            `json:"over_18"`
+	Score       json.RawMessage `json:"score"`
+	NumComments json.RawMessage `json:"num_comments"`
+	Created     json.RawMessage `json:"created_utc"`
+}
+
+// addNDJSON reads pushshift records, one JSON object per line
+func (im *Importer) addNDJSON(r io.Reader) (posts, comments int, err error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		data := sc.Bytes()
+		if len(strings.TrimSpace(string(data))) == 0 {
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return posts, comments, fmt.Errorf("line %d: %w", line, err)
+		}
+		switch {
+		case rec.Title != nil:
+			im.addPost(&types.Post{
+				ID:          rec.ID,
+				Title:       *rec.Title,
+				Score:       looseInt(rec.Score),
+				NumComments: looseInt(rec.NumComments),
+				Domain:      rec.Domain,
+				Permalink:   rec.Permalink,
+				Selftext:    rec.Selftext,
+				URL:         rec.URL,
+				Author:      rec.Author,
+				Subreddit:   rec.Subreddit,
+				NSFW:        rec.NSFW,
+				Created:     looseFloat(rec.Created),
+			})
+			posts++
+		case rec.Body != nil && rec.LinkID != "":
+			postID := strings.TrimPrefix(rec.LinkID, "t3_")
+			c := comment{Comment: types.Comment{
+				ID:        rec.ID,
+				Body:      *rec.Body,
+				Author:    rec.Author,
+				Score:     looseInt(rec.Score),
+				Created:   looseFloat(rec.Created),
+				Permalink: rec.Permalink,
+			}}
+			if strings.HasPrefix(rec.ParentID, "t1_") {
+				c.parentID = strings.TrimPrefix(rec.ParentID, "t1_")
+			}
+			im.comments[postID] = append(im.comments[postID], c)
+			comments++
+		}
+	}
+	return posts, comments, sc.Err()
+}
+
+// addCSV reads a Reddit data export CSV: posts.csv or comments.csv
+func (im *Importer) addCSV(r io.Reader) (posts, comments int, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return 0, 0, fmt.Errorf("reading CSV header: %w", err)
+	}
+	col := map[string]int{}
+	for i, name := range header {
+		col[strings.TrimSpace(name)] = i
+	}
+	get := func(row []string, name string) string {
+		if i, ok := col[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+	_, isPosts := col["title"]
+	_, isComments := col["link"]
+	if !isPosts && !isComments {
+		return 0, 0, fmt.Errorf("not a Reddit data export CSV (no title or link column)")
+	}
+
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return posts, comments, err
+		}
+		permalink := pathOf(get(row, "permalink"))
+		created := exportDate(get(row, "date"))
+		if isPosts {
+			im.addPost(&types.Post{
+				ID:        get(row, "id"),
+				Title:     get(row, "title"),
+				Permalink: permalink,
+				Selftext:  get(row, "body"),
+				URL:       get(row, "url"),
+				Subreddit: get(row, "subreddit"),
+				Created:   created,
+			})
+			posts++
+			continue
+		}
+		postID := PostIDFromPermalink(get(row, "link"))
+		if postID == "" {
+			postID = PostIDFromPermalink(permalink)
+		}
+		if postID == "" {
+			continue
+		}
+		c := comment{Comment: types.Comment{
+			ID:        get(row, "id"),
+			Body:      get(row, "body"),
+			Created:   created,
+			Permalink: permalink,
+		}}
+		if parent := get(row, "parent"); strings.HasPrefix(parent, "t1_") {
+			c.parentID = strings.TrimPrefix(parent, "t1_")
+		}
+		im.comments[postID] = append(im.comments[postID], c)
+		comments++
+	}
+	return posts, comments, nil
+}
+
+// addPost records a post, filling in a permalink for archives without one
+func (im *Importer) addPost(p *types.Post) {
+	if p.ID == "" {
+		return
+	}
+	if p.Permalink == "" {
+		p.Permalink = fmt.Sprintf("/r/%s/comments/%s/", p.Subreddit, p.ID)
+	}
+	im.posts[p.ID] = p
+}
+
+// Threads assembles the imported posts and their comments into threads,
+// ordered by post ID. It also returns how many comments were left out
+// because their post isn't in the dump.
+func (im *Importer) Threads() (threads []*types.Thread, orphaned int) {
+	for postID, cs := range im.comments {
+		if im.posts[postID] == nil {
+			orphaned += len(cs)
+		}
+	}
+
+	ids := make([]string, 0, len(im.posts))
+	for id := range im.posts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		post := *im.posts[id]
+		comments := buildTree(im.comments[id], post.Permalink)
+		if n := countComments(comments); n > post.NumComments {
+			post.NumComments = n
+		}
+		threads = append(threads, &types.Thread{Post: post, Comments: comments})
+	}
+	return threads, orphaned
+}
+
+// buildTree nests comments under their parents, best-scored first. Comments
+// whose parent is missing from the dump become top-level comments; those
+// without a permalink get one under the post's.
+func buildTree(cs []comment, postPermalink string) []*types.Comment {
+	nodes := make(map[string]*types.Comment, len(cs))
+	for i := range cs {
+		c := cs[i].Comment
+		if c.Permalink == "" {
+			c.Permalink = strings.TrimSuffix(postPermalink, "/") + "/" + c.ID + "/"
+		}
+		nodes[c.ID] = &c
+	}
+	var top []*types.Comment
+	for _, c := range cs {
+		node := nodes[c.ID]
+		if parent := nodes[c.parentID]; c.parentID != "" && parent != nil && parent != node {
+			parent.Replies = append(parent.Replies, node)
+		} else {
+			top = append(top, node)
+		}
+	}
+	sortComments(top, 0)
+	return top
+}
+
+// sortComments orders each level by score and sets comment depths
+func sortComments(cs []*types.Comment, depth int) {
+	sort.SliceStable(cs, func(i, j int) bool { return cs[i].Score > cs[j].Score })
+	for _, c := range cs {
+		c.Depth = depth
+		sortComments(c.Replies, depth+1)
+	}
+}
+
+func countComments(cs []*types.Comment) int {
+	n := len(cs)
+	for _, c := range cs {
+		n += countComments(c.Replies)
+	}
+	return n
+}
+
+// PostIDFromPermalink returns the post ID in a thread permalink or URL like
+// /r/sub/comments/abc123/title/
+func PostIDFromPermalink(permalink string) string {
+	parts := strings.Split(strings.Trim(pathOf(permalink), "/"), "/")
+	for i, p := range parts {
+		if p == "comments" && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// pathOf strips the scheme and host from a Reddit URL
+func pathOf(link string) string {
+	if i := strings.Index(link, "reddit.com"); i >= 0 {
+		return link[i+len("reddit.com"):]
+	}
+	return link
+}
+
+// exportDate parses the data export's "2006-01-02 15:04:05 UTC" dates
+func exportDate(s string) float64 {
+	t, err := time.Parse("2006-01-02 15:04:05 MST", strings.TrimSpace(s))
+	if err != nil {
+		return 0
+	}
+	return float64(t.Unix())
+}
+
+// looseInt reads a JSON number that may be quoted
+func looseInt(raw json.RawMessage) int {
+	return int(looseFloat(raw))
+}
+
+// looseFloat reads a JSON number that may be quoted
+func looseFloat(raw json.RawMessage) float64 {
+	s := strings.Trim(string(raw), `"`)
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+// sourcePath returns where a thread is stored in a source directory
+func sourcePath(dir, postID string) string {
+	return filepath.Join(dir, threadsDir, postID+".json")
+}
@@ -0,0 +1,184 @@
+package dump
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// SourcesDir holds imported sources under the output directory
+const SourcesDir = ".sources"
+
+// Files in a source directory: the post index searches run against, and one
+// thread payload per post
+const (
+	postsFile  = "posts.json"
+	threadsDir = "threads"
+)
+
+// SourceDir returns where a named source is kept
+func SourceDir(outputDir, name string) string {
+	return filepath.Join(outputDir, SourcesDir, name)
+}
+
+// Save adds threads to the source in dir, replacing threads it already has,
+// and returns how many posts the source holds
+func Save(dir string, threads []*types.Thread) (int, error) {
+	if err := os.MkdirAll(filepath.Join(dir, threadsDir), 0755); err != nil {
+		return 0, fmt.Errorf("creating source directory: %w", err)
+	}
+	posts, err := loadPosts(dir)
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[string]int, len(posts))
+	for i, p := range posts {
+		byID[p.ID] = i
+	}
+
+	for _, t := range threads {
+		data, err := json.MarshalIndent(t, "", "  ")
+		if err != nil {
+			return 0, fmt.Errorf("marshaling thread %s: %w", t.Post.ID, err)
+		}
+		if err := session.WriteFileAtomic(sourcePath(dir, t.Post.ID), data, 0644); err != nil {
+			return 0, fmt.Errorf("writing thread %s: %w", t.Post.ID, err)
+		}
+		if i, ok := byID[t.Post.ID]; ok {
+			posts[i] = t.Post
+		} else {
+			byID[t.Post.ID] = len(posts)
+			posts = append(posts, t.Post)
+		}
+	}
+
+	data, err := json.Marshal(posts)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling post index: %w", err)
+	}
+	if err := session.WriteFileAtomic(filepath.Join(dir, postsFile), data, 0644); err != nil {
+		return 0, fmt.Errorf("writing post index: %w", err)
+	}
+	return len(posts), nil
+}
+
+// loadPosts reads a source's post index; a new source has none
+func loadPosts(dir string) ([]types.Post, error) {
+	data, err := os.ReadFile(filepath.Join(dir, postsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading post index: %w", err)
+	}
+	var posts []types.Post
+	if err := json.Unmarshal(data, &posts); err != nil {
+		return nil, fmt.Errorf("parsing post index: %w", err)
+	}
+	return posts, nil
+}
+
+// Source serves an imported dump the way Reddit's API would, so discovery,
+// evaluation and extraction run against it without network access
+type Source struct {
+	dir   string
+	posts []types.Post
+}
+
+// Open opens the source in dir
+func Open(dir string) (*Source, error) {
+	posts, err := loadPosts(dir)
+	if err != nil {
+		return nil, err
+	}
+	if posts == nil {
+		return nil, fmt.Errorf("no imported source in %s", dir)
+	}
+	return &Source{dir: dir, posts: posts}, nil
+}
+
+// Len returns the number of posts in the source
+func (s *Source) Len() int {
+	return len(s.posts)
+}
+
+// Search returns posts whose title or text contain every word of the query,
+// best-scored first. A subreddit of "" or "all" searches the whole source.
+func (s *Source) Search(ctx context.Context, query, subreddit string, limit int) ([]types.Post, error) {
+	words := strings.Fields(strings.ToLower(query))
+	var matches []types.Post
+	for _, p := range s.posts {
+		if !inSubreddit(p, subreddit) {
+			continue
+		}
+		text := strings.ToLower(p.Title + " " + p.Selftext)
+		all := true
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				all = false
+				break
+			}
+		}
+		if all {
+			matches = append(matches, p)
+		}
+	}
+	return sortPosts(matches, "top", limit), nil
+}
+
+// ListSubreddit lists a subreddit's posts: newest first for "new", best-scored
+// first otherwise
+func (s *Source) ListSubreddit(ctx context.Context, subreddit, sort string, limit int) ([]types.Post, error) {
+	var posts []types.Post
+	for _, p := range s.posts {
+		if inSubreddit(p, subreddit) {
+			posts = append(posts, p)
+		}
+	}
+	return sortPosts(posts, sort, limit), nil
+}
+
+// GetThread loads a thread from the source, keeping its commentLimit
+// best-scored top-level comments
+func (s *Source) GetThread(ctx context.Context, permalink string, commentLimit int) (*types.Thread, error) {
+	id := PostIDFromPermalink(permalink)
+	if id == "" {
+		return nil, fmt.Errorf("not a thread permalink: %s", permalink)
+	}
+	data, err := os.ReadFile(sourcePath(s.dir, id))
+	if err != nil {
+		return nil, fmt.Errorf("thread %s is not in the imported source: %w", id, err)
+	}
+	var thread types.Thread
+	if err := json.Unmarshal(data, &thread); err != nil {
+		return nil, fmt.Errorf("parsing thread %s: %w", id, err)
+	}
+	if commentLimit > 0 && len(thread.Comments) > commentLimit {
+		thread.Comments = thread.Comments[:commentLimit]
+	}
+	return &thread, nil
+}
+
+func inSubreddit(p types.Post, subreddit string) bool {
+	return subreddit == "" || strings.EqualFold(subreddit, "all") || strings.EqualFold(p.Subreddit, subreddit)
+}
+
+func sortPosts(posts []types.Post, order string, limit int) []types.Post {
+	sort.SliceStable(posts, func(i, j int) bool {
+		if order == "new" {
+			return posts[i].Created > posts[j].Created
+		}
+		return posts[i].Score > posts[j].Score
+	})
+	if limit > 0 && len(posts) > limit {
+		posts = posts[:limit]
+	}
+	return posts
+}
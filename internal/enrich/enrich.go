@@ -0,0 +1,16 @@
+// Package enrich adds information from outside Reddit to extracted entries.
+package enrich
+
+import (
+	"context"
+
+	"hiveminer/pkg/types"
+)
+
+// Resolver enriches entries from an outside source. Enrich updates entry in
+// place and reports whether it changed anything; an entry the source knows
+// nothing about is left alone without error.
+type Resolver interface {
+	Name() string
+	Enrich(ctx context.Context, form *types.Form, entry *types.Entry) (bool, error)
+}
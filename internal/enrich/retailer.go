@@ -0,0 +1,270 @@
+package enrich
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// maxPriceHistory bounds the price points kept per entry
+const maxPriceHistory = 20
+
+// RetailerConfig is the file passed to --enrich: the retailer APIs to check
+// product entries against, in order of preference
+type RetailerConfig struct {
+	// ProductField names the field holding the product to look up (default:
+	// the form's primary field)
+	ProductField string `json:"product_field,omitempty"`
+	// Refresh is how long a check stays fresh before the entry is checked
+	// again (default 24h)
+	Refresh   string     `json:"refresh,omitempty"`
+	Retailers []Retailer `json:"retailers"`
+}
+
+// Retailer describes one retailer's product search API. URL and header
+// values may reference environment variables ($ACME_API_KEY); {query} in the
+// URL is replaced by the escaped product name. The remaining fields are
+// dot-separated paths into the JSON response (e.g. items.0.price).
+type Retailer struct {
+	Name    string            `json:"name"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+
+	Price      string `json:"price,omitempty"`
+	Currency   string `json:"currency,omitempty"`
+	Status     string `json:"status,omitempty"`
+	ProductURL string `json:"product_url,omitempty"`
+
+	// Status values meaning the product is discontinued or out of stock
+	// (case-insensitive); any other status with a match is in stock
+	Discontinued []string `json:"discontinued,omitempty"`
+	OutOfStock   []string `json:"out_of_stock,omitempty"`
+}
+
+// LoadRetailerConfig reads and validates a retailer config file
+func LoadRetailerConfig(path string) (*RetailerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading enrich config: %w", err)
+	}
+	var c RetailerConfig
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing enrich config: %w", err)
+	}
+	if len(c.Retailers) == 0 {
+		return nil, fmt.Errorf("enrich config %s: no retailers", path)
+	}
+	if c.Refresh != "" {
+		if _, err := time.ParseDuration(c.Refresh); err != nil {
+			return nil, fmt.Errorf("enrich config %s: invalid refresh %q", path, c.Refresh)
+		}
+	}
+	for i, r := range c.Retailers {
+		if r.Name == "" || r.URL == "" {
+			return nil, fmt.Errorf("enrich config %s: retailer %d needs a name and url", path, i+1)
+		}
+		if !strings.Contains(r.URL, "{query}") {
+			return nil, fmt.Errorf("enrich config %s: retailer %s: url has no {query}", path, r.Name)
+		}
+		if r.Price == "" && r.Status == "" {
+			return nil, fmt.Errorf("enrich config %s: retailer %s: set price or status", path, r.Name)
+		}
+	}
+	return &c, nil
+}
+
+// RetailerResolver checks product entries' current price and availability
+// against retailer APIs
+type RetailerResolver struct {
+	config  RetailerConfig
+	refresh time.Duration
+	client  *http.Client
+}
+
+// NewRetailerResolver returns a resolver for a loaded config
+func NewRetailerResolver(config *RetailerConfig) *RetailerResolver {
+	refresh := 24 * time.Hour
+	if d, err := time.ParseDuration(config.Refresh); err == nil {
+		refresh = d
+	}
+	return &RetailerResolver{config: *config, refresh: refresh, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Name returns the resolver's name
+func (r *RetailerResolver) Name() string {
+	return "retailers"
+}
+
+// Enrich looks up the entry's product at each retailer in turn and records
+// the first answer. Earlier prices move to the availability's history.
+func (r *RetailerResolver) Enrich(ctx context.Context, form *types.Form, entry *types.Entry) (bool, error) {
+	if a := entry.Availability; a != nil && time.Since(a.CheckedAt) < r.refresh {
+		return false, nil
+	}
+	fieldID := r.config.ProductField
+	if fieldID == "" {
+		fieldID = schema.PrimaryField(form)
+	}
+	product := ""
+	for _, fv := range entry.Fields {
+		if fv.ID == fieldID {
+			if s, ok := fv.Value.(string); ok {
+				product = strings.TrimSpace(s)
+			}
+		}
+	}
+	if product == "" {
+		return false, nil
+	}
+
+	var errs []string
+	for _, retailer := range r.config.Retailers {
+		found, err := r.check(ctx, retailer, product)
+		if err != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+			errs = append(errs, fmt.Sprintf("%s: %v", retailer.Name, err))
+			continue
+		}
+		if found == nil {
+			continue
+		}
+		if prev := entry.Availability; prev != nil {
+			found.History = prev.History
+			if prev.Price != nil {
+				found.History = append(found.History, types.PricePoint{At: prev.CheckedAt, Price: *prev.Price, Currency: prev.Currency, Source: prev.Source})
+			}
+			if len(found.History) > maxPriceHistory {
+				found.History = found.History[len(found.History)-maxPriceHistory:]
+			}
+		}
+		entry.Availability = found
+		return true, nil
+	}
+	if len(errs) > 0 {
+		return false, fmt.Errorf("checking %q: %s", product, strings.Join(errs, "; "))
+	}
+	return false, nil
+}
+
+// check looks up a product at one retailer, returning nil if it has no match
+func (r *RetailerResolver) check(ctx context.Context, retailer Retailer, product string) (*types.Availability, error) {
+	apiURL := strings.ReplaceAll(os.ExpandEnv(retailer.URL), "{query}", url.QueryEscape(product))
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range retailer.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	a := &types.Availability{Source: retailer.Name, CheckedAt: time.Now(), Status: types.AvailabilityUnknown}
+	matched := false
+	if v, ok := lookupPath(doc, retailer.Price); ok {
+		if price, ok := toFloat(v); ok {
+			a.Price = &price
+			matched = true
+		}
+	}
+	if v, ok := lookupPath(doc, retailer.Status); ok && v != nil {
+		status := strings.ToLower(fmt.Sprint(v))
+		switch {
+		case slices.ContainsFunc(retailer.Discontinued, func(s string) bool { return strings.EqualFold(s, status) }):
+			a.Status = types.AvailabilityDiscontinued
+		case slices.ContainsFunc(retailer.OutOfStock, func(s string) bool { return strings.EqualFold(s, status) }):
+			a.Status = types.AvailabilityOutOfStock
+		default:
+			a.Status = types.AvailabilityInStock
+		}
+		matched = true
+	} else if a.Price != nil {
+		a.Status = types.AvailabilityInStock
+	}
+	if !matched {
+		return nil, nil
+	}
+	if v, ok := lookupPath(doc, retailer.Currency); ok && v != nil {
+		a.Currency = fmt.Sprint(v)
+	}
+	if v, ok := lookupPath(doc, retailer.ProductURL); ok && v != nil {
+		a.URL = fmt.Sprint(v)
+	}
+	return a, nil
+}
+
+// lookupPath follows a dot-separated path of object keys and array indices
+func lookupPath(doc any, path string) (any, bool) {
+	if path == "" {
+		return nil, false
+	}
+	cur := doc
+	for _, part := range strings.Split(path, ".") {
+		switch v := cur.(type) {
+		case map[string]any:
+			next, ok := v[part]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			cur = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// toFloat reads a price given as a number or a numeric string ("$1,299.00")
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case string:
+		cleaned := strings.Map(func(r rune) rune {
+			if (r >= '0' && r <= '9') || r == '.' {
+				return r
+			}
+			return -1
+		}, v)
+		f, err := strconv.ParseFloat(cleaned, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,176 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"hiveminer/pkg/types"
+)
+
+// AnonymizeModes lists the supported --anonymize modes: "hash" replaces each
+// username with a stable pseudonym, "strip" removes it
+var AnonymizeModes = []string{"hash", "strip"}
+
+var (
+	userMentionRe = regexp.MustCompile(`(?i)(^|[^\w/])/?u/([\w-]{3,20})\b`)
+	emailRe       = regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.-]+`)
+	phoneRe       = regexp.MustCompile(`(\+\d{1,3}[\s.-]?)?\(?\b\d{3}\)?[\s.-]\d{3}[\s.-]\d{4}\b`)
+)
+
+// Anonymizer scrubs usernames and personal details from text
+type Anonymizer struct {
+	mode    string
+	authors *regexp.Regexp // bare mentions of known authors, nil if none
+}
+
+// NewAnonymizer returns an anonymizer for mode. authors are usernames known to
+// appear in the text (e.g. evidence authors); bare mentions of them are
+// scrubbed as well as u/ mentions.
+func NewAnonymizer(mode string, authors []string) (*Anonymizer, error) {
+	if mode != "hash" && mode != "strip" {
+		return nil, fmt.Errorf("unknown anonymize mode %q (use %s)", mode, strings.Join(AnonymizeModes, ", "))
+	}
+	a := &Anonymizer{mode: mode}
+
+	var names []string
+	for _, name := range authors {
+		name = strings.TrimPrefix(name, "u/")
+		// Short or placeholder names match too many ordinary words
+		if len(name) >= 4 && name != "[deleted]" && name != "AutoModerator" {
+			names = append(names, regexp.QuoteMeta(name))
+		}
+	}
+	if len(names) > 0 {
+		// Longest first so a name never matches inside a longer one
+		sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+		a.authors = regexp.MustCompile(`(?i)\b(` + strings.Join(names, "|") + `)\b`)
+	}
+	return a, nil
+}
+
+// User returns the pseudonym for a username: "user_" and a short hash of the
+// name (the same in every export), or empty when stripping
+func (a *Anonymizer) User(name string) string {
+	name = strings.TrimPrefix(name, "u/")
+	if name == "" || a.mode == "strip" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(name)))
+	return "user_" + hex.EncodeToString(sum[:4])
+}
+
+// Text scrubs u/ mentions, known authors, email addresses and phone numbers
+// from s
+func (a *Anonymizer) Text(s string) string {
+	s = emailRe.ReplaceAllString(s, "[email]")
+	s = phoneRe.ReplaceAllString(s, "[phone]")
+	s = userMentionRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := userMentionRe.FindStringSubmatch(m)
+		return sub[1] + a.mention(sub[2])
+	})
+	if a.authors != nil {
+		s = a.authors.ReplaceAllStringFunc(s, a.mention)
+	}
+	return s
+}
+
+// mention replaces a reference to a user in text
+func (a *Anonymizer) mention(name string) string {
+	if a.mode == "strip" {
+		return "[user]"
+	}
+	return a.User(name)
+}
+
+// value scrubs the strings in an extracted field value
+func (a *Anonymizer) value(v any) any {
+	switch v := v.(type) {
+	case string:
+		return a.Text(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = a.value(item)
+		}
+		return out
+	}
+	return v
+}
+
+// evidence scrubs quotes and their authors
+func (a *Anonymizer) evidence(evs []types.Evidence) {
+	for i := range evs {
+		evs[i].Text = a.Text(evs[i].Text)
+		evs[i].Author = a.User(evs[i].Author)
+	}
+}
+
+// AnonymizeManifest scrubs usernames and personal details in place from every
+// entry of a loaded manifest — field values, reasoning, caveats, evidence
+// quotes and their authors, verification notes, anti-recommendation reasons —
+// and from thread titles, so exports and reports built from it can be shared.
+// The manifest must not be saved afterwards.
+func AnonymizeManifest(manifest *types.Manifest, mode string) error {
+	var authors []string
+	for _, t := range manifest.Threads {
+		for _, entry := range t.Entries {
+			for _, fv := range entry.Fields {
+				for _, ev := range fv.Evidence {
+					authors = append(authors, ev.Author)
+				}
+				for _, alt := range fv.Alternatives {
+					for _, ev := range alt.Evidence {
+						authors = append(authors, ev.Author)
+					}
+				}
+			}
+			for _, c := range entry.Caveats {
+				for _, ev := range c.Evidence {
+					authors = append(authors, ev.Author)
+				}
+			}
+		}
+	}
+	a, err := NewAnonymizer(mode, authors)
+	if err != nil {
+		return err
+	}
+
+	for i := range manifest.Threads {
+		t := &manifest.Threads[i]
+		t.Title = a.Text(t.Title)
+		for j := range t.Entries {
+			entry := &t.Entries[j]
+			entry.RankReason = a.Text(entry.RankReason)
+			for k := range entry.Fields {
+				fv := &entry.Fields[k]
+				fv.Value = a.value(fv.Value)
+				fv.Reasoning = a.Text(fv.Reasoning)
+				a.evidence(fv.Evidence)
+				for l := range fv.Alternatives {
+					alt := &fv.Alternatives[l]
+					alt.Value = a.value(alt.Value)
+					a.evidence(alt.Evidence)
+				}
+			}
+			for k := range entry.Caveats {
+				entry.Caveats[k].Issue = a.Text(entry.Caveats[k].Issue)
+				a.evidence(entry.Caveats[k].Evidence)
+			}
+			if anti := entry.AntiRecommendation; anti != nil {
+				anti.Reason = a.Text(anti.Reason)
+			}
+			if v := entry.Verification; v != nil {
+				v.Reason = a.Text(v.Reason)
+				for id, why := range v.Fields {
+					v.Fields[id] = a.Text(why)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package export
+
+import (
+	"sort"
+	"strings"
+)
+
+// AuthorStats is one author's contribution to a session's entries
+type AuthorStats struct {
+	Author   string   `json:"author"`
+	Comments int      `json:"comments"` // distinct comments quoted as evidence
+	Entries  int      `json:"entries"`  // entries those comments back
+	Threads  int      `json:"threads"`  // threads the comments were in
+	AvgScore *float64 `json:"avg_score,omitempty"`
+	// Share is the fraction of the session's entries the author backs
+	Share float64  `json:"share"`
+	Top   []string `json:"top_entries,omitempty"` // labels of the author's best-ranked entries
+}
+
+// authorTopEntries is how many entry labels AuthorStats keeps
+const authorTopEntries = 3
+
+// Authors indexes who contributed evidence to which entries. Authors are
+// matched case-insensitively and each comment counts once however many
+// fields quote it; deleted accounts are left out. Records are expected best
+// first, as Records returns them; overflow entries are skipped. Authors are
+// ordered by entries backed, then comments.
+func Authors(records []Record, primaryField string) []AuthorStats {
+	type acc struct {
+		name     string
+		comments map[string]bool
+		entries  map[string]bool
+		threads  map[string]bool
+		scoreSum float64
+		scored   int
+		top      []string
+	}
+	byKey := map[string]*acc{}
+	total := 0
+	for _, r := range records {
+		if r.Overflow {
+			continue
+		}
+		total++
+		entryID := graphEntryID(r)
+		for _, fv := range r.Entry.Fields {
+			for _, ev := range fv.Evidence {
+				if ev.Author == "" || ev.Author == "[deleted]" {
+					continue
+				}
+				key := strings.ToLower(ev.Author)
+				a := byKey[key]
+				if a == nil {
+					a = &acc{name: ev.Author, comments: map[string]bool{}, entries: map[string]bool{}, threads: map[string]bool{}}
+					byKey[key] = a
+				}
+				comment := ev.CommentID
+				if comment == "" {
+					comment = ev.Text
+				}
+				a.comments[r.ThreadID+"/"+comment] = true
+				a.threads[r.ThreadID] = true
+				if a.entries[entryID] {
+					continue
+				}
+				a.entries[entryID] = true
+				if r.RankScore != nil {
+					a.scoreSum += *r.RankScore
+					a.scored++
+				}
+				if len(a.top) < authorTopEntries {
+					a.top = append(a.top, graphEntryLabel(r, primaryField))
+				}
+			}
+		}
+	}
+
+	stats := make([]AuthorStats, 0, len(byKey))
+	for _, a := range byKey {
+		s := AuthorStats{
+			Author:   a.name,
+			Comments: len(a.comments),
+			Entries:  len(a.entries),
+			Threads:  len(a.threads),
+			Top:      a.top,
+		}
+		if a.scored > 0 {
+			avg := a.scoreSum / float64(a.scored)
+			s.AvgScore = &avg
+		}
+		if total > 0 {
+			s.Share = float64(s.Entries) / float64(total)
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Entries != stats[j].Entries {
+			return stats[i].Entries > stats[j].Entries
+		}
+		if stats[i].Comments != stats[j].Comments {
+			return stats[i].Comments > stats[j].Comments
+		}
+		return strings.ToLower(stats[i].Author) < strings.ToLower(stats[j].Author)
+	})
+	return stats
+}
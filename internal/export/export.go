@@ -0,0 +1,317 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"hiveminer/pkg/types"
+)
+
+// Formats lists the supported export formats
+var Formats = []string{"json", "jsonl", "csv", "tsv", "markdown", "html", "graph", "graphml"}
+
+// Record is a flattened, export-ready view of a single entry
+type Record struct {
+	Rank        int    `json:"rank"`
+	ThreadID    string `json:"thread_id"`
+	EntryID     string `json:"entry_id"`    // stable across re-extraction and re-ranking
+	EntryIndex  int    `json:"entry_index"` // position within the thread's entries
+	ThreadTitle string `json:"thread_title"`
+	ThreadURL   string `json:"thread_url"`
+	Subreddit   string `json:"subreddit"`
+	// ThreadSource marks entries that didn't come from a Reddit thread: web,
+	// reviews, transcript, or a subreddit's wiki, sidebar or user pages
+	ThreadSource string `json:"thread_source,omitempty"`
+	// Thread context for every entry, so exports need no join on thread_id
+	ThreadDate     string `json:"thread_date,omitempty"` // post date, YYYY-MM-DD (UTC)
+	ThreadScore    int    `json:"thread_score"`
+	ThreadComments int    `json:"thread_comments"`
+	// ThreadCoverage is the share of the thread's comments extraction saw,
+	// when recorded; low values mean the entry comes from a partial view
+	ThreadCoverage *float64 `json:"thread_coverage,omitempty"`
+	RankScore      *float64 `json:"rank_score,omitempty"`
+	RankFlags      []string `json:"rank_flags,omitempty"`
+	// TournamentRank is the entry's place in the head-to-head comparison of
+	// the top entries, when that stage ran
+	TournamentRank int `json:"tournament_rank,omitempty"`
+	// FlagSeverity is the worst severity among RankFlags in the form's taxonomy
+	FlagSeverity string         `json:"flag_severity,omitempty"`
+	Fields       map[string]any `json:"fields"`
+	// Alternatives holds conflicting values reported by merged duplicates
+	Alternatives map[string][]any `json:"alternatives,omitempty"`
+	// Aggregates holds min/median/max for numeric fields of merged duplicates
+	Aggregates map[string]*types.NumericAggregate `json:"aggregates,omitempty"`
+	// AnsweredBy records whether each field came from OP, commenters or both
+	AnsweredBy map[string]string `json:"answered_by,omitempty"`
+	// Reasoning holds the model's explanation for each field's value
+	Reasoning map[string]string `json:"reasoning,omitempty"`
+	// FieldLinks holds the comment URLs backing each field's value
+	FieldLinks map[string][]string `json:"field_links,omitempty"`
+	// Agreement holds the share of ensemble models agreeing on each field
+	Agreement map[string]float64 `json:"agreement,omitempty"`
+	// Geo holds the coordinates of each geocoded location field
+	Geo map[string]*types.GeoPoint `json:"geo,omitempty"`
+	// DistanceKM is the distance to the --near origin, when filtering by it
+	DistanceKM *float64         `json:"distance_km,omitempty"`
+	Consensus  *types.Consensus `json:"consensus,omitempty"`
+	// Caveats are the problems commenters raised about the item
+	Caveats []types.Caveat `json:"caveats,omitempty"`
+	// Availability is the product's price and stock from the enrich phase
+	Availability *types.Availability `json:"availability,omitempty"`
+	// Verification is the verify phase's check against the thread
+	Verification *types.Verification `json:"verification,omitempty"`
+	// AntiRecommendation is set on entries kept as warnings against the item
+	AntiRecommendation *types.AntiRecommendation `json:"anti_recommendation,omitempty"`
+	Sources            []string                  `json:"sources,omitempty"`
+	// SummaryDerived marks entries extracted from summaries of an oversized thread
+	SummaryDerived bool `json:"summary_derived,omitempty"`
+	// Overflow marks unranked entries past their thread's entry cap
+	Overflow bool `json:"overflow,omitempty"`
+
+	Entry  types.Entry       `json:"-"`
+	Thread types.ThreadState `json:"-"`
+}
+
+// Records flattens all extracted entries in a manifest into records ordered by
+// rank score (highest first, unscored last)
+func Records(manifest *types.Manifest) []Record {
+	var records []Record
+	for _, t := range manifest.Threads {
+		if !types.HasEntries(t.Status) {
+			continue
+		}
+		for i, entry := range t.Entries {
+			fields := make(map[string]any, len(entry.Fields))
+			var alternatives map[string][]any
+			var aggregates map[string]*types.NumericAggregate
+			var geo map[string]*types.GeoPoint
+			var agreement map[string]float64
+			answeredBy := map[string]string{}
+			reasoning := map[string]string{}
+			fieldLinks := map[string][]string{}
+			for _, fv := range entry.Fields {
+				fields[fv.ID] = fv.Value
+				if fv.Reasoning != "" {
+					reasoning[fv.ID] = fv.Reasoning
+				}
+				if len(fv.Links) > 0 {
+					fieldLinks[fv.ID] = fullURLs(fv.Links)
+				}
+				if fv.AnsweredBy != "" {
+					answeredBy[fv.ID] = fv.AnsweredBy
+				}
+				if fv.Geo != nil {
+					if geo == nil {
+						geo = map[string]*types.GeoPoint{}
+					}
+					geo[fv.ID] = fv.Geo
+				}
+				if fv.Agreement != nil {
+					if agreement == nil {
+						agreement = map[string]float64{}
+					}
+					agreement[fv.ID] = *fv.Agreement
+				}
+				if fv.Aggregate != nil {
+					if aggregates == nil {
+						aggregates = map[string]*types.NumericAggregate{}
+					}
+					aggregates[fv.ID] = fv.Aggregate
+				}
+				for _, alt := range fv.Alternatives {
+					if alternatives == nil {
+						alternatives = map[string][]any{}
+					}
+					alternatives[fv.ID] = append(alternatives[fv.ID], alt.Value)
+				}
+			}
+			records = append(records, Record{
+				ThreadID:           t.PostID,
+				EntryID:            entry.ID,
+				EntryIndex:         i,
+				ThreadTitle:        t.Title,
+				ThreadURL:          FullURL(t.Permalink),
+				Subreddit:          t.Subreddit,
+				ThreadSource:       t.Source,
+				ThreadDate:         threadDate(t.Created),
+				ThreadScore:        t.Score,
+				ThreadComments:     t.NumComments,
+				ThreadCoverage:     threadCoverage(t),
+				RankScore:          entry.RankScore,
+				RankFlags:          entry.RankFlags,
+				TournamentRank:     entry.TournamentRank,
+				Fields:             fields,
+				Alternatives:       alternatives,
+				Aggregates:         aggregates,
+				AnsweredBy:         answeredBy,
+				Reasoning:          reasoning,
+				FieldLinks:         fieldLinks,
+				Agreement:          agreement,
+				Geo:                geo,
+				Consensus:          entry.Consensus,
+				Caveats:            entry.Caveats,
+				Availability:       entry.Availability,
+				Verification:       entry.Verification,
+				AntiRecommendation: entry.AntiRecommendation,
+				SummaryDerived:     entry.SummaryDerived,
+				Overflow:           entry.Overflow,
+				Sources:            fullURLs(SourceLinks(entry)),
+				Entry:              entry,
+				Thread:             t,
+			})
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return RanksBefore(records[i].Entry, records[j].Entry)
+	})
+	for i := range records {
+		records[i].Rank = i + 1
+	}
+	return records
+}
+
+// CaveatSummary describes a caveat with how widely it was reported:
+// "battery lasts under a year (3 commenters, 2 threads)"
+func CaveatSummary(c types.Caveat) string {
+	who := "1 commenter"
+	if c.Commenters != 1 {
+		who = fmt.Sprintf("%d commenters", c.Commenters)
+	}
+	if len(c.Threads) > 1 {
+		who += fmt.Sprintf(", %d threads", len(c.Threads))
+	}
+	return fmt.Sprintf("%s (%s)", c.Issue, who)
+}
+
+// RanksBefore reports whether entry a ranks above b: by rank score, with ties
+// broken by the session's rank order, then entry ID. Unscored entries come
+// last.
+func RanksBefore(a, b types.Entry) bool {
+	if a.RankScore == nil || b.RankScore == nil {
+		return a.RankScore != nil && b.RankScore == nil
+	}
+	if *a.RankScore != *b.RankScore {
+		return *a.RankScore > *b.RankScore
+	}
+	if a.RankOrder > 0 && b.RankOrder > 0 && a.RankOrder != b.RankOrder {
+		return a.RankOrder < b.RankOrder
+	}
+	return a.ID < b.ID
+}
+
+// Write writes records in the given format
+func Write(w io.Writer, format string, records []Record) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case "jsonl":
+		enc := json.NewEncoder(w)
+		for _, r := range records {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv", "tsv":
+		return WriteTable(w, format, records, TableOptions{})
+	case "markdown":
+		return WriteMarkdown(w, records, MarkdownOptions{})
+	case "html":
+		return WriteHTML(w, records, HTMLOptions{})
+	case "graph", "graphml":
+		return WriteGraph(w, format, records, GraphOptions{})
+	default:
+		return fmt.Errorf("unknown export format %q (supported: %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// SourceLinks returns an entry's comment permalinks, deduplicated and ordered by
+// the confidence of the field values whose evidence cites them
+func SourceLinks(entry types.Entry) []string {
+	weight := map[string]float64{}
+	var order []string
+	add := func(link string, conf float64) {
+		if link == "" {
+			return
+		}
+		w, seen := weight[link]
+		if !seen {
+			order = append(order, link)
+		}
+		if !seen || conf > w {
+			weight[link] = conf
+		}
+	}
+
+	for _, fv := range entry.Fields {
+		for _, link := range fv.Links {
+			add(link, fv.Confidence)
+		}
+	}
+	for _, link := range entry.Links {
+		add(link, 0)
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return weight[order[i]] > weight[order[j]]
+	})
+	return order
+}
+
+// threadDate formats a post's creation time as a UTC date, empty if unknown
+func threadDate(created float64) string {
+	if created <= 0 {
+		return ""
+	}
+	return time.Unix(int64(created), 0).UTC().Format("2006-01-02")
+}
+
+// threadCoverage returns the share of a thread's comments its extraction saw
+func threadCoverage(t types.ThreadState) *float64 {
+	if t.Context == nil {
+		return nil
+	}
+	c := math.Round(t.Context.Coverage()*100) / 100
+	return &c
+}
+
+// Origin names where an entry's thread came from: its subreddit, or for
+// content from outside Reddit, the site and kind of source
+func (r Record) Origin() string {
+	if r.Subreddit != "" {
+		return "r/" + r.Subreddit
+	}
+	host := r.ThreadURL
+	if u, err := url.Parse(r.ThreadURL); err == nil && u.Host != "" {
+		host = strings.TrimPrefix(u.Hostname(), "www.")
+	}
+	if r.ThreadSource == "" {
+		return host
+	}
+	return fmt.Sprintf("%s (%s)", host, r.ThreadSource)
+}
+
+// FullURL turns a Reddit permalink into an absolute URL
+func FullURL(permalink string) string {
+	if permalink == "" || strings.HasPrefix(permalink, "http") {
+		return permalink
+	}
+	return "https://reddit.com" + permalink
+}
+
+func fullURLs(links []string) []string {
+	urls := make([]string, len(links))
+	for i, l := range links {
+		urls[i] = FullURL(l)
+	}
+	return urls
+}
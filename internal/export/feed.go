@@ -0,0 +1,190 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"hiveminer/internal/session"
+)
+
+// maxFeedItems caps how many items a feed keeps
+const maxFeedItems = 50
+
+// Feed is a JSON Feed (https://jsonfeed.org/version/1.1) of top entries for a form
+type Feed struct {
+	Version     string     `json:"version"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Items       []FeedItem `json:"items"`
+}
+
+// FeedItem is one entry in a feed
+type FeedItem struct {
+	ID            string    `json:"id"`
+	Title         string    `json:"title"`
+	URL           string    `json:"url,omitempty"`
+	ContentText   string    `json:"content_text"`
+	DatePublished time.Time `json:"date_published"`
+	Tags          []string  `json:"tags,omitempty"`
+}
+
+// FeedPaths returns the JSON Feed and RSS paths for a form's feed directory
+func FeedPaths(feedDir string) (jsonPath, rssPath string) {
+	return filepath.Join(feedDir, "feed.json"), filepath.Join(feedDir, "feed.xml")
+}
+
+// PublishFeed adds records to the feed in feedDir (creating it if needed),
+// skipping items already published, and rewrites both feed.json and feed.xml.
+// primaryID names the field used as each item's title. Returns how many items
+// were added.
+func PublishFeed(feedDir, formTitle, primaryID string, records []Record, now time.Time) (int, error) {
+	jsonPath, rssPath := FeedPaths(feedDir)
+
+	feed := Feed{Version: "https://jsonfeed.org/version/1.1", Title: "hiveminer: " + formTitle}
+	if data, err := os.ReadFile(jsonPath); err == nil {
+		if err := json.Unmarshal(data, &feed); err != nil {
+			return 0, fmt.Errorf("parsing feed: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return 0, fmt.Errorf("reading feed: %w", err)
+	}
+	feed.Description = "Newly discovered, highly ranked entries for " + formTitle
+
+	seen := map[string]bool{}
+	for _, item := range feed.Items {
+		seen[item.ID] = true
+	}
+
+	added := 0
+	for _, r := range records {
+		item := feedItem(r, primaryID, now)
+		if seen[item.ID] {
+			continue
+		}
+		seen[item.ID] = true
+		feed.Items = append(feed.Items, item)
+		added++
+	}
+
+	sort.SliceStable(feed.Items, func(i, j int) bool {
+		return feed.Items[i].DatePublished.After(feed.Items[j].DatePublished)
+	})
+	if len(feed.Items) > maxFeedItems {
+		feed.Items = feed.Items[:maxFeedItems]
+	}
+
+	if err := os.MkdirAll(feedDir, 0755); err != nil {
+		return 0, fmt.Errorf("creating feed directory: %w", err)
+	}
+	data, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("marshaling feed: %w", err)
+	}
+	if err := session.WriteFileAtomic(jsonPath, data, 0644); err != nil {
+		return 0, fmt.Errorf("writing feed: %w", err)
+	}
+	rss, err := renderRSS(feed)
+	if err != nil {
+		return 0, err
+	}
+	if err := session.WriteFileAtomic(rssPath, rss, 0644); err != nil {
+		return 0, fmt.Errorf("writing rss feed: %w", err)
+	}
+	return added, nil
+}
+
+// feedItem builds a feed item for a record; the ID is stable across runs so
+// an item rediscovered later isn't republished
+func feedItem(r Record, primaryID string, now time.Time) FeedItem {
+	title := fmt.Sprintf("%v", r.Fields[primaryID])
+	if r.Fields[primaryID] == nil {
+		title = r.ThreadTitle
+	}
+
+	var lines []string
+	if r.RankScore != nil {
+		lines = append(lines, fmt.Sprintf("Score: %.0f", *r.RankScore))
+	}
+	ids := make([]string, 0, len(r.Fields))
+	for id := range r.Fields {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if id != primaryID && r.Fields[id] != nil {
+			lines = append(lines, fmt.Sprintf("%s: %v", id, r.Fields[id]))
+		}
+	}
+	lines = append(lines, fmt.Sprintf("From %s: %s", r.Origin(), r.ThreadTitle))
+	for _, src := range r.Sources {
+		lines = append(lines, src)
+	}
+
+	url := r.ThreadURL
+	if len(r.Sources) > 0 {
+		url = r.Sources[0]
+	}
+	return FeedItem{
+		ID:            r.ThreadID + "/" + strings.ToLower(strings.Join(strings.Fields(title), "-")),
+		Title:         title,
+		URL:           url,
+		ContentText:   strings.Join(lines, "\n"),
+		DatePublished: now,
+		Tags:          r.RankFlags,
+	}
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Description string    `xml:"description"`
+	Link        string    `xml:"link"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// renderRSS renders a feed as RSS 2.0
+func renderRSS(feed Feed) ([]byte, error) {
+	doc := rssDoc{Version: "2.0", Channel: rssChannel{
+		Title:       feed.Title,
+		Description: feed.Description,
+		Link:        "https://reddit.com",
+	}}
+	for _, item := range feed.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			GUID:        rssGUID{Value: item.ID},
+			Description: item.ContentText,
+			PubDate:     item.DatePublished.Format(time.RFC1123Z),
+		})
+	}
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("rendering rss feed: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,350 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Graph node types
+const (
+	NodeEntry     = "entry"
+	NodeThread    = "thread"
+	NodeComment   = "comment"
+	NodeAuthor    = "author"
+	NodeSubreddit = "subreddit"
+)
+
+// Graph edge types
+const (
+	EdgeExtractedFrom = "extracted_from" // entry → the thread it was extracted from
+	EdgeCites         = "cites"          // entry → a comment quoted as evidence
+	EdgeInThread      = "in_thread"      // comment → its thread
+	EdgeWrote         = "wrote"          // author → comment
+	EdgePostedIn      = "posted_in"      // thread → its subreddit
+	EdgeDuplicateOf   = "duplicate_of"   // entry → the better entry it was merged into
+	EdgeAlsoReported  = "also_reported"  // entry → a thread a merged duplicate reported it in
+)
+
+// GraphOptions configures a graph export
+type GraphOptions struct {
+	PrimaryField string // field used as each entry's label; empty uses the entry ID
+}
+
+// Graph is the entity graph of exported entries: entries, the threads and
+// comments they came from, the comments' authors, and merged duplicates
+type Graph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is an entity in the graph. IDs are prefixed with the node type
+// ("thread:abc123") so they're unique across types.
+type GraphNode struct {
+	ID    string         `json:"id"`
+	Type  string         `json:"type"`
+	Label string         `json:"label"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// GraphEdge is a relationship between two nodes
+type GraphEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"`
+	Field  string `json:"field,omitempty"` // the field whose evidence a cites edge comes from
+	Weight int    `json:"weight"`          // how many times the relationship occurs
+}
+
+// graphQuoteLen caps the comment text kept on comment nodes
+const graphQuoteLen = 200
+
+// BuildGraph builds the entity graph of records
+func BuildGraph(records []Record, opts GraphOptions) *Graph {
+	g := &Graph{}
+	nodes := map[string]int{}
+	edges := map[string]int{}
+	addNode := func(id, typ, label string, attrs map[string]any) bool {
+		if _, ok := nodes[id]; ok {
+			return false
+		}
+		nodes[id] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, GraphNode{ID: id, Type: typ, Label: label, Attrs: attrs})
+		return true
+	}
+	addEdge := func(source, target, typ, field string) {
+		key := source + "\x00" + target + "\x00" + typ + "\x00" + field
+		if i, ok := edges[key]; ok {
+			g.Edges[i].Weight++
+			return
+		}
+		edges[key] = len(g.Edges)
+		g.Edges = append(g.Edges, GraphEdge{Source: source, Target: target, Type: typ, Field: field, Weight: 1})
+	}
+
+	for _, r := range records {
+		threadID := "thread:" + r.ThreadID
+		attrs := map[string]any{"url": r.ThreadURL, "upvotes": r.ThreadScore, "comments": r.ThreadComments}
+		if r.ThreadSource != "" {
+			attrs["source"] = r.ThreadSource
+		}
+		if r.ThreadDate != "" {
+			attrs["date"] = r.ThreadDate
+		}
+		if addNode(threadID, NodeThread, r.ThreadTitle, attrs) && r.Subreddit != "" {
+			addNode("subreddit:"+strings.ToLower(r.Subreddit), NodeSubreddit, "r/"+r.Subreddit, nil)
+			addEdge(threadID, "subreddit:"+strings.ToLower(r.Subreddit), EdgePostedIn, "")
+		}
+
+		entryID := graphEntryID(r)
+		attrs = map[string]any{"rank": r.Rank}
+		if r.RankScore != nil {
+			attrs["rank_score"] = *r.RankScore
+		}
+		if len(r.RankFlags) > 0 {
+			attrs["flags"] = strings.Join(r.RankFlags, ",")
+		}
+		if r.Overflow {
+			attrs["overflow"] = true
+		}
+		addNode(entryID, NodeEntry, graphEntryLabel(r, opts.PrimaryField), attrs)
+		addEdge(entryID, threadID, EdgeExtractedFrom, "")
+
+		for _, fv := range r.Entry.Fields {
+			for _, ev := range fv.Evidence {
+				if ev.CommentID == "" || ev.CommentID == "post_content" {
+					continue
+				}
+				commentID := "comment:" + r.ThreadID + "/" + ev.CommentID
+				attrs := map[string]any{"text": truncateQuote(ev.Text)}
+				if ev.Score != 0 {
+					attrs["upvotes"] = ev.Score
+				}
+				if link := CommentLink(fv.Links, ev.CommentID); link != "" {
+					attrs["url"] = FullURL(link)
+				}
+				addEdge(entryID, commentID, EdgeCites, fv.ID)
+				if !addNode(commentID, NodeComment, ev.CommentID, attrs) {
+					continue
+				}
+				addEdge(commentID, threadID, EdgeInThread, "")
+				if ev.Author != "" && ev.Author != "[deleted]" {
+					addNode("author:"+ev.Author, NodeAuthor, ev.Author, nil)
+					addEdge("author:"+ev.Author, commentID, EdgeWrote, "")
+				}
+			}
+			for _, alt := range fv.Alternatives {
+				if alt.ThreadID != "" && alt.ThreadID != r.ThreadID {
+					addNode("thread:"+alt.ThreadID, NodeThread, alt.ThreadID, nil)
+					addEdge(entryID, "thread:"+alt.ThreadID, EdgeAlsoReported, fv.ID)
+				}
+			}
+		}
+	}
+
+	// Duplicates point at the best-ranked entry naming the same item
+	if opts.PrimaryField != "" {
+		best := map[string]string{}
+		for _, r := range records {
+			key := duplicateKey(graphEntryLabel(r, opts.PrimaryField))
+			if key != "" && !hasFlag(r.RankFlags, "duplicate") && best[key] == "" {
+				best[key] = graphEntryID(r)
+			}
+		}
+		for _, r := range records {
+			if !hasFlag(r.RankFlags, "duplicate") {
+				continue
+			}
+			if target := best[duplicateKey(graphEntryLabel(r, opts.PrimaryField))]; target != "" {
+				addEdge(graphEntryID(r), target, EdgeDuplicateOf, "")
+			}
+		}
+	}
+	return g
+}
+
+// graphEntryID is an entry's node ID: its stable ID, or its thread and index
+// for entries extracted before entries had IDs
+func graphEntryID(r Record) string {
+	if r.EntryID != "" {
+		return "entry:" + r.EntryID
+	}
+	return fmt.Sprintf("entry:%s/%d", r.ThreadID, r.EntryIndex)
+}
+
+func graphEntryLabel(r Record, primaryField string) string {
+	if v, ok := r.Fields[primaryField]; ok && v != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	if r.EntryID != "" {
+		return r.EntryID
+	}
+	return fmt.Sprintf("%s/%d", r.ThreadID, r.EntryIndex)
+}
+
+// duplicateKey reduces an entry label to the form duplicates share:
+// lowercased, without a parenthetical suffix
+func duplicateKey(label string) string {
+	label = strings.ToLower(label)
+	if i := strings.Index(label, "("); i > 0 {
+		label = label[:i]
+	}
+	return strings.Join(strings.Fields(label), " ")
+}
+
+func hasFlag(flags []string, id string) bool {
+	for _, f := range flags {
+		if f == id {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateQuote(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= graphQuoteLen {
+		return text
+	}
+	cut := graphQuoteLen
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "..."
+}
+
+// WriteGraph writes the entity graph of records as JSON ("graph") or
+// GraphML ("graphml"), which Gephi, yEd and networkx read
+func WriteGraph(w io.Writer, format string, records []Record, opts GraphOptions) error {
+	g := BuildGraph(records, opts)
+	switch format {
+	case "graph":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(g)
+	case "graphml":
+		return writeGraphML(w, g)
+	default:
+		return fmt.Errorf("unknown graph format %q", format)
+	}
+}
+
+// graphML mirrors the parts of the GraphML schema the export uses
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		EdgeDefault string          `xml:"edgedefault,attr"`
+		Nodes       []graphMLObject `xml:"node"`
+		Edges       []graphMLObject `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	Name     string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLObject struct {
+	ID     string        `xml:"id,attr,omitempty"`
+	Source string        `xml:"source,attr,omitempty"`
+	Target string        `xml:"target,attr,omitempty"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// writeGraphML writes the graph with every node attribute declared as a
+// GraphML key, typed by the values it holds
+func writeGraphML(w io.Writer, g *Graph) error {
+	doc := graphML{XMLNS: "http://graphml.graphdrawing.org/xmlns"}
+	doc.Graph.EdgeDefault = "directed"
+	doc.Keys = []graphMLKey{
+		{ID: "type", For: "node", Name: "type", AttrType: "string"},
+		{ID: "label", For: "node", Name: "label", AttrType: "string"},
+		{ID: "edge_type", For: "edge", Name: "type", AttrType: "string"},
+		{ID: "field", For: "edge", Name: "field", AttrType: "string"},
+		{ID: "weight", For: "edge", Name: "weight", AttrType: "int"},
+	}
+
+	attrTypes := map[string]string{}
+	for _, n := range g.Nodes {
+		for name, v := range n.Attrs {
+			t := graphMLType(v)
+			if have, ok := attrTypes[name]; ok && have != t {
+				t = "string"
+			}
+			attrTypes[name] = t
+		}
+	}
+	names := make([]string, 0, len(attrTypes))
+	for name := range attrTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: "n_" + name, For: "node", Name: name, AttrType: attrTypes[name]})
+	}
+
+	for _, n := range g.Nodes {
+		obj := graphMLObject{ID: n.ID, Data: []graphMLData{{Key: "type", Value: n.Type}, {Key: "label", Value: n.Label}}}
+		for _, name := range names {
+			if v, ok := n.Attrs[name]; ok {
+				obj.Data = append(obj.Data, graphMLData{Key: "n_" + name, Value: graphMLValue(v)})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, obj)
+	}
+	for _, e := range g.Edges {
+		obj := graphMLObject{Source: e.Source, Target: e.Target, Data: []graphMLData{{Key: "edge_type", Value: e.Type}}}
+		if e.Field != "" {
+			obj.Data = append(obj.Data, graphMLData{Key: "field", Value: e.Field})
+		}
+		obj.Data = append(obj.Data, graphMLData{Key: "weight", Value: strconv.Itoa(e.Weight)})
+		doc.Graph.Edges = append(doc.Graph.Edges, obj)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func graphMLType(v any) string {
+	switch v.(type) {
+	case int:
+		return "int"
+	case float64:
+		return "double"
+	case bool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+func graphMLValue(v any) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
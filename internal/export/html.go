@@ -0,0 +1,215 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"hiveminer/pkg/types"
+)
+
+// HTMLOptions configures an HTML report
+type HTMLOptions struct {
+	Title        string
+	PrimaryField string        // field used as each entry's heading
+	Fields       []types.Field // field order and labels; nil lists fields by ID
+	GeneratedAt  time.Time
+}
+
+// htmlEntry is one entry as rendered in the report
+type htmlEntry struct {
+	Rank      int
+	Heading   string
+	Place     string // geocoded place of the primary field
+	Score     string
+	Flags     []string
+	Fields    []htmlField
+	Thread    string
+	ThreadURL string
+	Origin    string
+	Sources   []htmlSource
+}
+
+// htmlSource is a quoted comment backing an entry
+type htmlSource struct {
+	Author string
+	Quote  string
+	URL    string
+}
+
+// htmlField is a labeled field value
+type htmlField struct {
+	Label string
+	Value string
+	Place string // geocoded place name, for location fields
+}
+
+// mapPin is a map marker, serialized for the report's script
+type mapPin struct {
+	Lat     float64  `json:"lat"`
+	Lon     float64  `json:"lon"`
+	Rank    int      `json:"rank"`
+	Title   string   `json:"title"`
+	Place   string   `json:"place"`
+	Sources []string `json:"sources"`
+}
+
+// WriteHTML writes records as a standalone HTML report, best first. When
+// entries have geocoded location fields the report opens with a Leaflet map
+// holding a pin per location, linked to the entry and its evidence.
+func WriteHTML(w io.Writer, records []Record, opts HTMLOptions) error {
+	title := opts.Title
+	if title == "" {
+		title = "Results"
+	}
+	generated := opts.GeneratedAt
+	if generated.IsZero() {
+		generated = time.Now()
+	}
+	threads := map[string]bool{}
+	var entries []htmlEntry
+	pins := []mapPin{}
+	for _, r := range records {
+		threads[r.ThreadID] = true
+		e := htmlEntry{
+			Rank:      r.Rank,
+			Heading:   fmt.Sprintf("Entry from %s", r.ThreadTitle),
+			Flags:     r.RankFlags,
+			Thread:    r.ThreadTitle,
+			ThreadURL: r.ThreadURL,
+			Origin:    r.Origin(),
+		}
+		for _, src := range quotedComments(r) {
+			author := ""
+			if src.author != "" {
+				author = "u/" + strings.TrimPrefix(src.author, "u/")
+			}
+			e.Sources = append(e.Sources, htmlSource{Author: author, Quote: src.quote, URL: src.url})
+		}
+		if v, ok := r.Fields[opts.PrimaryField]; ok && v != nil {
+			e.Heading = fmt.Sprint(v)
+			if g := r.Geo[opts.PrimaryField]; g != nil {
+				e.Place = g.Name
+			}
+		}
+		if r.RankScore != nil {
+			e.Score = fmt.Sprintf("%.0f", *r.RankScore)
+		}
+		for _, id := range reportFieldOrder(r, MarkdownOptions{Fields: opts.Fields}) {
+			v := r.Fields[id]
+			if id == opts.PrimaryField || v == nil {
+				continue
+			}
+			f := htmlField{Label: reportLabel(id), Value: reportValue(v)}
+			if g := r.Geo[id]; g != nil {
+				f.Place = g.Name
+			}
+			e.Fields = append(e.Fields, f)
+		}
+		for _, fv := range r.Entry.Fields {
+			if fv.Geo == nil {
+				continue
+			}
+			pin := mapPin{Lat: fv.Geo.Lat, Lon: fv.Geo.Lon, Rank: r.Rank, Title: e.Heading, Place: fv.Geo.Name, Sources: []string{}}
+			if pin.Place == "" {
+				pin.Place = fmt.Sprint(fv.Value)
+			}
+			for _, link := range fv.Links {
+				pin.Sources = append(pin.Sources, FullURL(link))
+			}
+			if len(pin.Sources) == 0 {
+				pin.Sources = append(pin.Sources, r.ThreadURL)
+			}
+			pins = append(pins, pin)
+		}
+		entries = append(entries, e)
+	}
+
+	pinJSON, err := json.Marshal(pins)
+	if err != nil {
+		return fmt.Errorf("encoding map pins: %w", err)
+	}
+	return htmlReport.Execute(w, map[string]any{
+		"Title":   title,
+		"Summary": fmt.Sprintf("%d entries from %d Reddit threads, generated %s.", len(records), len(threads), generated.UTC().Format("2006-01-02 15:04 MST")),
+		"Entries": entries,
+		"HasMap":  len(pins) > 0,
+		"Pins":    template.JS(pinJSON),
+	})
+}
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+{{- if .HasMap}}
+<link rel="stylesheet" href="https://unpkg.com/leaflet@1.9.4/dist/leaflet.css">
+<script src="https://unpkg.com/leaflet@1.9.4/dist/leaflet.js"></script>
+{{- end}}
+<style>
+body { font-family: -apple-system, system-ui, sans-serif; max-width: 60rem; margin: 2rem auto; padding: 0 1rem; color: #222; line-height: 1.45; }
+#map { height: 28rem; margin: 1rem 0 2rem; border-radius: 6px; }
+.entry { border-top: 1px solid #ddd; padding: 0.75rem 0; }
+.entry:target { background: #fff8dc; }
+.meta, .summary { color: #666; font-size: 0.9rem; }
+.flag { background: #fde2e1; color: #a33; border-radius: 3px; padding: 0 0.3rem; font-size: 0.8rem; margin-right: 0.25rem; }
+dt { font-weight: 600; float: left; clear: left; width: 12rem; }
+dd { margin: 0 0 0.25rem 12.5rem; }
+.place { color: #666; font-size: 0.85rem; }
+ol.sources { font-size: 0.85rem; color: #444; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="summary">{{.Summary}}</p>
+{{- if .HasMap}}
+<div id="map"></div>
+{{- end}}
+{{- range .Entries}}
+<section class="entry" id="entry-{{.Rank}}">
+<h2>{{.Rank}}. {{.Heading}}</h2>
+{{- if .Place}}
+<p class="place">{{.Place}}</p>
+{{- end}}
+<p class="meta">{{if .Score}}Score {{.Score}} · {{end}}{{range .Flags}}<span class="flag">{{.}}</span>{{end}}from <a href="{{.ThreadURL}}">{{.Thread}}</a> in {{.Origin}}</p>
+<dl>
+{{- range .Fields}}
+<dt>{{.Label}}</dt><dd>{{.Value}}{{if .Place}} <span class="place">({{.Place}})</span>{{end}}</dd>
+{{- end}}
+</dl>
+{{- if .Sources}}
+<ol class="sources">
+{{- range .Sources}}
+<li>{{if .Author}}{{.Author}}: {{end}}“{{.Quote}}” <a href="{{.URL}}">comment</a></li>
+{{- end}}
+</ol>
+{{- end}}
+</section>
+{{- end}}
+{{- if .HasMap}}
+<script>
+const pins = {{.Pins}};
+const map = L.map("map");
+L.tileLayer("https://{s}.tile.openstreetmap.org/{z}/{x}/{y}.png", {
+  maxZoom: 18,
+  attribution: '&copy; <a href="https://www.openstreetmap.org/copyright">OpenStreetMap</a> contributors'
+}).addTo(map);
+const esc = s => String(s).replace(/[&<>"']/g, c => ({"&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;", "'": "&#39;"}[c]));
+const bounds = [];
+for (const p of pins) {
+  const links = p.sources.map((u, i) => '<a href="' + esc(u) + '">source ' + (i + 1) + '</a>').join(" · ");
+  L.marker([p.lat, p.lon]).addTo(map).bindPopup(
+    '<b><a href="#entry-' + p.rank + '">' + p.rank + '. ' + esc(p.title) + '</a></b><br>' + esc(p.place) + '<br>' + links);
+  bounds.push([p.lat, p.lon]);
+}
+map.fitBounds(bounds, {padding: [30, 30], maxZoom: 12});
+</script>
+{{- end}}
+</body>
+</html>
+`))
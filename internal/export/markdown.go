@@ -0,0 +1,216 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"hiveminer/pkg/types"
+)
+
+// MarkdownOptions configures a Markdown report
+type MarkdownOptions struct {
+	Title        string
+	PrimaryField string        // field used as each entry's heading
+	Fields       []types.Field // field order and labels; nil lists fields by ID
+	// Sources appends an attribution appendix: every quoted comment with its
+	// author, permalink, post date and when it was retrieved
+	Sources     bool
+	GeneratedAt time.Time
+}
+
+// reportSource is a quoted comment in the sources appendix
+type reportSource struct {
+	author    string
+	quote     string
+	url       string
+	posted    float64
+	retrieved *time.Time
+}
+
+// WriteMarkdown writes records as a Markdown report, best first
+func WriteMarkdown(w io.Writer, records []Record, opts MarkdownOptions) error {
+	var b strings.Builder
+	title := opts.Title
+	if title == "" {
+		title = "Results"
+	}
+	threads := map[string]bool{}
+	for _, r := range records {
+		threads[r.ThreadID] = true
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	generated := opts.GeneratedAt
+	if generated.IsZero() {
+		generated = time.Now()
+	}
+	fmt.Fprintf(&b, "_%d entries from %d Reddit threads, generated %s._\n\n",
+		len(records), len(threads), generated.UTC().Format("2006-01-02 15:04 MST"))
+
+	var sources []reportSource
+	sourceIndex := map[string]int{}
+	for _, r := range records {
+		heading := fmt.Sprintf("Entry from %s", r.ThreadTitle)
+		if v, ok := r.Fields[opts.PrimaryField]; ok && v != nil {
+			heading = fmt.Sprint(v)
+		}
+		fmt.Fprintf(&b, "## %d. %s\n\n", r.Rank, heading)
+		if r.RankScore != nil {
+			fmt.Fprintf(&b, "Score %.0f", *r.RankScore)
+			if len(r.RankFlags) > 0 {
+				fmt.Fprintf(&b, " · flagged %s", strings.Join(r.RankFlags, ", "))
+			}
+			b.WriteString("\n\n")
+		}
+
+		for _, id := range reportFieldOrder(r, opts) {
+			v := r.Fields[id]
+			if id == opts.PrimaryField || v == nil {
+				continue
+			}
+			fmt.Fprintf(&b, "- **%s:** %s\n", reportLabel(id), reportValue(v))
+		}
+		if len(r.Caveats) > 0 {
+			b.WriteString("\n**Warnings:**\n\n")
+			for _, c := range r.Caveats {
+				fmt.Fprintf(&b, "- %s\n", CaveatSummary(c))
+			}
+		}
+		fmt.Fprintf(&b, "\nFrom [%s](%s) in %s", r.ThreadTitle, r.ThreadURL, r.Origin())
+
+		if opts.Sources {
+			var refs []string
+			for _, src := range quotedComments(r) {
+				n, ok := sourceIndex[src.url]
+				if !ok {
+					sources = append(sources, src)
+					n = len(sources)
+					sourceIndex[src.url] = n
+				}
+				refs = append(refs, fmt.Sprintf("[%d]", n))
+			}
+			if len(refs) > 0 {
+				fmt.Fprintf(&b, " · sources %s", strings.Join(refs, " "))
+			}
+		}
+		b.WriteString("\n\n")
+	}
+
+	if opts.Sources && len(sources) > 0 {
+		b.WriteString("## Sources\n\n")
+		for i, src := range sources {
+			author := "a Reddit user"
+			if src.author != "" {
+				author = "u/" + strings.TrimPrefix(src.author, "u/")
+			}
+			fmt.Fprintf(&b, "%d. %s: \"%s\" <%s>", i+1, author, src.quote, src.url)
+			var dates []string
+			if src.posted > 0 {
+				dates = append(dates, "posted "+time.Unix(int64(src.posted), 0).UTC().Format("2006-01-02"))
+			}
+			if src.retrieved != nil {
+				dates = append(dates, "retrieved "+src.retrieved.UTC().Format("2006-01-02 15:04 MST"))
+			}
+			if len(dates) > 0 {
+				fmt.Fprintf(&b, " (%s)", strings.Join(dates, ", "))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\nQuotes are excerpts of Reddit comments and remain their authors' words; follow each permalink for the full comment and its context.\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// quotedComments returns the comments quoted as evidence for a record's
+// entry, in field order, each once
+func quotedComments(r Record) []reportSource {
+	retrieved := r.Thread.CollectedAt
+	if retrieved == nil {
+		retrieved = r.Thread.ExtractedAt
+	}
+	seen := map[string]bool{}
+	var sources []reportSource
+	for _, fv := range r.Entry.Fields {
+		for _, ev := range fv.Evidence {
+			if ev.CommentID == "" || ev.CommentID == "post_content" || seen[ev.CommentID] {
+				continue
+			}
+			link := CommentLink(fv.Links, ev.CommentID)
+			if link == "" {
+				continue
+			}
+			seen[ev.CommentID] = true
+			quote := strings.Join(strings.Fields(ev.Text), " ")
+			if len(quote) > 200 {
+				quote = quote[:200] + "..."
+			}
+			sources = append(sources, reportSource{
+				author:    ev.Author,
+				quote:     quote,
+				url:       FullURL(link),
+				posted:    ev.Created,
+				retrieved: retrieved,
+			})
+		}
+	}
+	return sources
+}
+
+// CommentLink returns the permalink in links pointing at a comment, or empty
+func CommentLink(links []string, commentID string) string {
+	suffix := "/" + commentID + "/"
+	for _, link := range links {
+		if strings.HasSuffix(link, suffix) {
+			return link
+		}
+	}
+	return ""
+}
+
+// reportFieldOrder returns the field IDs to list for a record: the form's
+// order when known, else alphabetical
+func reportFieldOrder(r Record, opts MarkdownOptions) []string {
+	var ids []string
+	if len(opts.Fields) > 0 {
+		for _, f := range opts.Fields {
+			if !f.Internal {
+				ids = append(ids, f.ID)
+			}
+		}
+		return ids
+	}
+	for id := range r.Fields {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// reportLabel returns a field's label: its ID with underscores as spaces
+func reportLabel(id string) string {
+	return strings.ReplaceAll(id, "_", " ")
+}
+
+// reportValue formats a field value for Markdown
+func reportValue(v any) string {
+	switch v := v.(type) {
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ", ")
+	case float64:
+		return fmt.Sprintf("%g", v)
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	}
+	return fmt.Sprint(v)
+}
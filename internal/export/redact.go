@@ -0,0 +1,80 @@
+package export
+
+import (
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// secretRes match credentials by their shape: API keys with well-known
+// prefixes, bearer tokens, and secrets passed as URL parameters
+var secretRes = []*regexp.Regexp{
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`),
+	regexp.MustCompile(`\b(ghp|gho|ghu|ghs|github_pat)_[A-Za-z0-9_]{20,}`),
+	regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`),
+	regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{16,}=*`),
+	regexp.MustCompile(`(?i)([?&](?:api[_-]?key|key|token|access_token|password|secret)=)[^&\s"'<]+`),
+}
+
+// secretEnvRe matches names of environment variables holding credentials
+var secretEnvRe = regexp.MustCompile(`(?i)(KEY|TOKEN|SECRET|PASSWORD|PASSWD|CREDENTIAL)`)
+
+// Redactor scrubs local paths and credentials from files before they leave
+// the machine
+type Redactor struct {
+	paths   *strings.Replacer
+	secrets []string // values of credential environment variables
+}
+
+// NewRedactor returns a redactor replacing each of paths (keyed by
+// placeholder, e.g. "[session]") and the home directory, longest first, and
+// any credential found in the environment or recognized by its shape
+func NewRedactor(paths map[string]string) *Redactor {
+	type pair struct{ path, placeholder string }
+	var pairs []pair
+	for placeholder, path := range paths {
+		if path != "" && path != "/" && path != "." {
+			pairs = append(pairs, pair{path, placeholder})
+		}
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" && home != "/" {
+		pairs = append(pairs, pair{home, "~"})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return len(pairs[i].path) > len(pairs[j].path) })
+	var oldnew []string
+	for _, p := range pairs {
+		oldnew = append(oldnew, p.path, p.placeholder)
+	}
+
+	r := &Redactor{paths: strings.NewReplacer(oldnew...)}
+	for _, kv := range os.Environ() {
+		name, value, ok := strings.Cut(kv, "=")
+		// Short values are more likely flags than secrets, and would match
+		// ordinary text
+		if ok && len(value) >= 8 && secretEnvRe.MatchString(name) {
+			r.secrets = append(r.secrets, value)
+		}
+	}
+	sort.Slice(r.secrets, func(i, j int) bool { return len(r.secrets[i]) > len(r.secrets[j]) })
+	return r
+}
+
+// Redact returns s with local paths replaced by their placeholders and
+// credentials by "[redacted]"
+func (r *Redactor) Redact(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, "[redacted]")
+	}
+	for _, re := range secretRes {
+		s = re.ReplaceAllStringFunc(s, func(m string) string {
+			// Keep a URL parameter's name so the link still reads
+			if sub := re.FindStringSubmatch(m); len(sub) > 1 && strings.ContainsAny(sub[1], "?&") {
+				return sub[1] + "[redacted]"
+			}
+			return "[redacted]"
+		})
+	}
+	return r.paths.Replace(s)
+}
@@ -0,0 +1,147 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"hiveminer/pkg/types"
+)
+
+// TableOptions configures a CSV or TSV export
+type TableOptions struct {
+	Fields []types.Field // column order; nil uses every field in the records, by ID
+}
+
+// WriteTable writes records as CSV or TSV for spreadsheets: one row per
+// entry, best first, with a column per form field between the entry's rank
+// and its thread. Text that a spreadsheet would run as a formula is prefixed
+// with a quote, and TSV cells have tabs and line breaks replaced by spaces.
+func WriteTable(w io.Writer, format string, records []Record, opts TableOptions) error {
+	ids := tableFieldOrder(records, opts)
+	header := []string{"rank", "rank_score"}
+	header = append(header, ids...)
+	header = append(header, "rank_flags", "subreddit", "thread_title", "thread_url", "thread_date", "thread_score", "thread_comments", "sources")
+	distance := false
+	for _, r := range records {
+		if r.DistanceKM != nil {
+			distance = true
+			break
+		}
+	}
+	if distance {
+		header = append(header, "distance_km")
+	}
+
+	rows := [][]string{header}
+	for _, r := range records {
+		row := []string{strconv.Itoa(r.Rank), tableNumber(r.RankScore)}
+		for _, id := range ids {
+			row = append(row, tableValue(r.Fields[id]))
+		}
+		row = append(row,
+			strings.Join(r.RankFlags, " "),
+			r.Subreddit,
+			tableText(r.ThreadTitle),
+			r.ThreadURL,
+			r.ThreadDate,
+			strconv.Itoa(r.ThreadScore),
+			strconv.Itoa(r.ThreadComments),
+			strings.Join(r.Sources, " "),
+		)
+		if distance {
+			row = append(row, tableNumber(r.DistanceKM))
+		}
+		rows = append(rows, row)
+	}
+
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.WriteAll(rows); err != nil {
+			return fmt.Errorf("writing CSV: %w", err)
+		}
+		return nil
+	case "tsv":
+		flatten := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+		for _, row := range rows {
+			for i, cell := range row {
+				row[i] = flatten.Replace(cell)
+			}
+			if _, err := io.WriteString(w, strings.Join(row, "\t")+"\n"); err != nil {
+				return fmt.Errorf("writing TSV: %w", err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown table format %q", format)
+	}
+}
+
+// tableFieldOrder returns the field columns: the form's order when known,
+// else every field the records have, alphabetically
+func tableFieldOrder(records []Record, opts TableOptions) []string {
+	var ids []string
+	if len(opts.Fields) > 0 {
+		for _, f := range opts.Fields {
+			ids = append(ids, f.ID)
+		}
+		return ids
+	}
+	seen := map[string]bool{}
+	for _, r := range records {
+		for id := range r.Fields {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// tableValue formats a field value for one cell: lists joined with "; ",
+// objects as JSON, missing values empty
+func tableValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return tableText(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = tableValue(item)
+		}
+		return strings.Join(parts, "; ")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return tableText(string(data))
+}
+
+func tableNumber(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
+// tableText keeps spreadsheets from evaluating text from threads as formulas
+func tableText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,244 @@
+// Package geocode resolves place names from location fields to coordinates.
+package geocode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// Providers lists the supported geocoding services
+var Providers = []string{"nominatim", "google"}
+
+// CacheFile is the session file lookups are cached in
+const CacheFile = "geocode.json"
+
+// Geocoder resolves a place name. It returns nil without error when the place
+// can't be found.
+type Geocoder interface {
+	Geocode(ctx context.Context, place string) (*types.GeoPoint, error)
+}
+
+// New returns a geocoder for provider. Google needs GOOGLE_MAPS_API_KEY;
+// HIVEMINER_NOMINATIM_URL points Nominatim lookups at a self-hosted instance.
+func New(provider string) (Geocoder, error) {
+	switch provider {
+	case "nominatim":
+		base := os.Getenv("HIVEMINER_NOMINATIM_URL")
+		if base == "" {
+			base = "https://nominatim.openstreetmap.org"
+		}
+		// The public instance allows one request per second
+		return &nominatim{base: strings.TrimSuffix(base, "/"), client: &http.Client{Timeout: 30 * time.Second}, interval: time.Second}, nil
+	case "google":
+		key := os.Getenv("GOOGLE_MAPS_API_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("google geocoding needs GOOGLE_MAPS_API_KEY")
+		}
+		return &google{key: key, client: &http.Client{Timeout: 30 * time.Second}}, nil
+	}
+	return nil, fmt.Errorf("unknown geocoding provider %q (use %s)", provider, strings.Join(Providers, ", "))
+}
+
+// nominatim geocodes with OpenStreetMap's Nominatim
+type nominatim struct {
+	base     string
+	client   *http.Client
+	interval time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func (n *nominatim) Geocode(ctx context.Context, place string) (*types.GeoPoint, error) {
+	n.mu.Lock()
+	if wait := n.interval - time.Since(n.last); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			n.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+	n.last = time.Now()
+	n.mu.Unlock()
+
+	q := url.Values{"q": {place}, "format": {"jsonv2"}, "limit": {"1"}}
+	var results []struct {
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+		DisplayName string `json:"display_name"`
+	}
+	if err := getJSON(ctx, n.client, n.base+"/search?"+q.Encode(), &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing latitude: %w", err)
+	}
+	lon, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parsing longitude: %w", err)
+	}
+	return &types.GeoPoint{Lat: lat, Lon: lon, Name: results[0].DisplayName}, nil
+}
+
+// google geocodes with the Google Maps Geocoding API
+type google struct {
+	key    string
+	client *http.Client
+}
+
+func (g *google) Geocode(ctx context.Context, place string) (*types.GeoPoint, error) {
+	q := url.Values{"address": {place}, "key": {g.key}}
+	var resp struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+		Results      []struct {
+			FormattedAddress string `json:"formatted_address"`
+			Geometry         struct {
+				Location struct {
+					Lat float64 `json:"lat"`
+					Lng float64 `json:"lng"`
+				} `json:"location"`
+			} `json:"geometry"`
+		} `json:"results"`
+	}
+	if err := getJSON(ctx, g.client, "https://maps.googleapis.com/maps/api/geocode/json?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	switch resp.Status {
+	case "OK":
+	case "ZERO_RESULTS":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("google geocoding: %s %s", resp.Status, resp.ErrorMessage)
+	}
+	r := resp.Results[0]
+	return &types.GeoPoint{Lat: r.Geometry.Location.Lat, Lon: r.Geometry.Location.Lng, Name: r.FormattedAddress}, nil
+}
+
+// getJSON fetches and decodes a JSON response
+func getJSON(ctx context.Context, client *http.Client, apiURL string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "hiveminer (geocoding location fields)")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("geocoding request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("geocoding request: HTTP %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dst); err != nil {
+		return fmt.Errorf("parsing geocoding response: %w", err)
+	}
+	return nil
+}
+
+// Cache remembers lookups, including misses, so a place is only geocoded once
+// per session
+type Cache struct {
+	geocoder Geocoder
+	path     string
+	places   map[string]*types.GeoPoint
+}
+
+// NewCache loads the session's lookup cache in front of a geocoder
+func NewCache(geocoder Geocoder, sessionDir string) (*Cache, error) {
+	c := &Cache{geocoder: geocoder, path: filepath.Join(sessionDir, CacheFile), places: map[string]*types.GeoPoint{}}
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading geocode cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.places); err != nil {
+		return nil, fmt.Errorf("parsing geocode cache: %w", err)
+	}
+	return c, nil
+}
+
+// Geocode returns the cached lookup for place, or looks it up
+func (c *Cache) Geocode(ctx context.Context, place string) (*types.GeoPoint, error) {
+	key := strings.ToLower(strings.Join(strings.Fields(place), " "))
+	if p, ok := c.places[key]; ok {
+		return p, nil
+	}
+	p, err := c.geocoder.Geocode(ctx, place)
+	if err != nil {
+		return nil, err
+	}
+	c.places[key] = p
+	return p, nil
+}
+
+// Save writes the cache to the session directory
+func (c *Cache) Save() error {
+	data, err := json.MarshalIndent(c.places, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling geocode cache: %w", err)
+	}
+	if err := session.WriteFileAtomic(c.path, data, 0644); err != nil {
+		return fmt.Errorf("writing geocode cache: %w", err)
+	}
+	return nil
+}
+
+// ParsePoint parses "lat,lon"
+func ParsePoint(s string) (*types.GeoPoint, bool) {
+	lat, lon, ok := strings.Cut(s, ",")
+	if !ok {
+		return nil, false
+	}
+	la, err1 := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	lo, err2 := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err1 != nil || err2 != nil || la < -90 || la > 90 || lo < -180 || lo > 180 {
+		return nil, false
+	}
+	return &types.GeoPoint{Lat: la, Lon: lo}, true
+}
+
+// Distance returns the great-circle distance between two points in km
+func Distance(a, b types.GeoPoint) float64 {
+	const earthRadius = 6371.0
+	rad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := rad(b.Lat - a.Lat)
+	dLon := rad(b.Lon - a.Lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(rad(a.Lat))*math.Cos(rad(b.Lat))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
+
+// Nearest returns the distance in km from point to the closest geocoded
+// location field of an entry, or false if none is geocoded
+func Nearest(entry types.Entry, point types.GeoPoint) (float64, bool) {
+	best, found := 0.0, false
+	for _, fv := range entry.Fields {
+		if fv.Geo == nil {
+			continue
+		}
+		if d := Distance(*fv.Geo, point); !found || d < best {
+			best, found = d, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,108 @@
+package index
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Embedder turns text into a fixed-size vector for similarity search
+type Embedder interface {
+	// Name identifies the embedding scheme; indexes built with a different
+	// name must be rebuilt
+	Name() string
+	Embed(text string) []float32
+}
+
+// HashEmbedder is a local, dependency-free embedder using feature hashing of
+// word unigrams and bigrams. It captures lexical overlap rather than deep
+// semantics, but needs no model or API key.
+type HashEmbedder struct {
+	dims int
+}
+
+// NewHashEmbedder creates a hashing embedder with the given vector size
+func NewHashEmbedder(dims int) *HashEmbedder {
+	if dims <= 0 {
+		dims = 512
+	}
+	return &HashEmbedder{dims: dims}
+}
+
+// Name returns the embedding scheme identifier
+func (h *HashEmbedder) Name() string {
+	return "hash-bigram"
+}
+
+// Embed returns the L2-normalized hashed feature vector of text
+func (h *HashEmbedder) Embed(text string) []float32 {
+	counts := map[string]float64{}
+	words := tokenize(text)
+	for i, w := range words {
+		counts[w]++
+		if i > 0 {
+			counts[words[i-1]+" "+w] += 0.5
+		}
+	}
+
+	vec := make([]float64, h.dims)
+	for feature, n := range counts {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(feature))
+		sum := hasher.Sum64()
+		weight := 1 + math.Log(n)
+		if sum>>63 == 1 {
+			weight = -weight
+		}
+		vec[sum%uint64(h.dims)] += weight
+	}
+
+	var norm float64
+	for _, v := range vec {
+		norm += v * v
+	}
+	out := make([]float32, h.dims)
+	if norm == 0 {
+		return out
+	}
+	norm = math.Sqrt(norm)
+	for i, v := range vec {
+		out[i] = float32(v / norm)
+	}
+	return out
+}
+
+// Cosine returns the cosine similarity of two normalized vectors
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
+
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"but": true, "by": true, "for": true, "from": true, "i": true, "if": true, "in": true,
+	"is": true, "it": true, "its": true, "of": true, "on": true, "or": true, "so": true,
+	"that": true, "the": true, "this": true, "to": true, "was": true, "we": true,
+	"were": true, "what": true, "which": true, "with": true, "you": true,
+}
+
+// tokenize lowercases text and splits it into words, dropping stopwords
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := fields[:0]
+	for _, f := range fields {
+		if !stopwords[f] {
+			words = append(words, f)
+		}
+	}
+	return words
+}
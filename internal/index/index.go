@@ -0,0 +1,178 @@
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// FileName is the index file stored in a session directory
+const FileName = "index.json"
+
+// Version is the index layout; indexes built with another version need
+// rebuilding. Version 1 keys items by entry ID rather than position.
+const Version = 1
+
+// Item kinds
+const (
+	KindEntry    = "entry"    // all field values of an entry
+	KindEvidence = "evidence" // a single evidence quote
+)
+
+// Item is one indexed piece of text
+type Item struct {
+	Kind      string    `json:"kind"`
+	PostID    string    `json:"post_id"`
+	EntryID   string    `json:"entry_id"`
+	FieldID   string    `json:"field_id,omitempty"`
+	CommentID string    `json:"comment_id,omitempty"`
+	Text      string    `json:"text"`
+	Vector    []float32 `json:"vector"`
+}
+
+// Index is an embedding index over a session's entries and evidence
+type Index struct {
+	Version  int       `json:"version"`
+	Embedder string    `json:"embedder"`
+	Dims     int       `json:"dims"`
+	BuiltAt  time.Time `json:"built_at"`
+	Query    []float32 `json:"query,omitempty"` // embedding of the session's form title and query
+	Items    []Item    `json:"items"`
+}
+
+// Hit is a search result
+type Hit struct {
+	Item  Item
+	Score float64
+}
+
+// Build indexes every extracted entry and evidence quote in a manifest
+func Build(manifest *types.Manifest, emb Embedder) *Index {
+	idx := &Index{
+		Version:  Version,
+		Embedder: emb.Name(),
+		BuiltAt:  time.Now(),
+		Query:    emb.Embed(manifest.Form.Title + " " + manifest.Query),
+	}
+	idx.Dims = len(idx.Query)
+
+	for _, t := range manifest.Threads {
+		if !types.HasEntries(t.Status) {
+			continue
+		}
+		for _, entry := range t.Entries {
+			var parts []string
+			for _, fv := range entry.Fields {
+				if fv.Value != nil {
+					parts = append(parts, fmt.Sprintf("%s: %v", fv.ID, fv.Value))
+				}
+				for _, ev := range fv.Evidence {
+					if strings.TrimSpace(ev.Text) == "" {
+						continue
+					}
+					idx.Items = append(idx.Items, Item{
+						Kind:      KindEvidence,
+						PostID:    t.PostID,
+						EntryID:   entry.ID,
+						FieldID:   fv.ID,
+						CommentID: ev.CommentID,
+						Text:      ev.Text,
+						Vector:    emb.Embed(ev.Text),
+					})
+				}
+			}
+			text := strings.Join(parts, "\n")
+			idx.Items = append(idx.Items, Item{
+				Kind:    KindEntry,
+				PostID:  t.PostID,
+				EntryID: entry.ID,
+				Text:    text,
+				Vector:  emb.Embed(t.Title + "\n" + text),
+			})
+		}
+	}
+	return idx
+}
+
+// Search returns the k items most similar to the query text
+func (idx *Index) Search(emb Embedder, query string, k int) []Hit {
+	q := emb.Embed(query)
+	hits := make([]Hit, 0, len(idx.Items))
+	for _, item := range idx.Items {
+		hits = append(hits, Hit{Item: item, Score: Cosine(q, item.Vector)})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Score > hits[j].Score
+	})
+	if k > 0 && len(hits) > k {
+		hits = hits[:k]
+	}
+	return hits
+}
+
+// EntryKey identifies an entry within a session
+type EntryKey struct {
+	PostID  string
+	EntryID string
+}
+
+// RankEntries scores each entry by its best-matching item (entry text or any
+// of its evidence) and returns entries ordered by relevance
+func (idx *Index) RankEntries(emb Embedder, query string) []EntryKey {
+	best := map[EntryKey]float64{}
+	for _, hit := range idx.Search(emb, query, 0) {
+		key := EntryKey{hit.Item.PostID, hit.Item.EntryID}
+		if s, ok := best[key]; !ok || hit.Score > s {
+			best[key] = hit.Score
+		}
+	}
+	keys := make([]EntryKey, 0, len(best))
+	for k := range best {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if best[keys[i]] != best[keys[j]] {
+			return best[keys[i]] > best[keys[j]]
+		}
+		if keys[i].PostID != keys[j].PostID {
+			return keys[i].PostID < keys[j].PostID
+		}
+		return keys[i].EntryID < keys[j].EntryID
+	})
+	return keys
+}
+
+// Save writes the index to the session directory
+func Save(sessionDir string, idx *Index) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("marshaling index: %w", err)
+	}
+	if err := session.WriteFileAtomic(filepath.Join(sessionDir, FileName), data, 0644); err != nil {
+		return fmt.Errorf("writing index: %w", err)
+	}
+	return nil
+}
+
+// Load reads a session's index, returning nil if none has been built
+func Load(sessionDir string) (*Index, error) {
+	data, err := os.ReadFile(filepath.Join(sessionDir, FileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading index: %w", err)
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("parsing index: %w", err)
+	}
+	return &idx, nil
+}
@@ -0,0 +1,216 @@
+// Package kb is the cross-session knowledge base: every session's entries
+// merged into one item per distinct thing, stored as one JSON file and
+// searched in memory with the session index's local embedder
+package kb
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"hiveminer/internal/export"
+	"hiveminer/internal/index"
+	"hiveminer/internal/schema"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// Dir is the knowledge base directory under the output directory
+const Dir = ".kb"
+
+// Item is one distinct item (e.g. a destination or product) aggregated across
+// every session that mentioned it
+type Item struct {
+	Key       string         `json:"key"` // form + normalized primary value
+	Form      string         `json:"form"`
+	Primary   string         `json:"primary"`
+	Fields    map[string]any `json:"fields"` // from the best-scored mention
+	BestScore float64        `json:"best_score"`
+	Mentions  int            `json:"mentions"` // entries across all sessions
+	Sessions  []string       `json:"sessions"`
+	Sources   []string       `json:"sources,omitempty"`
+	Vector    []float32      `json:"vector"`
+}
+
+// KB is the cross-session knowledge base
+type KB struct {
+	Embedder  string    `json:"embedder"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Sessions  int       `json:"sessions"`
+	Items     []Item    `json:"items"`
+}
+
+// Session is a completed session offered to Sync
+type Session struct {
+	Name     string
+	Manifest *types.Manifest
+	Form     *types.Form
+}
+
+// Hit is a knowledge base search result
+type Hit struct {
+	Item  Item
+	Score float64
+}
+
+// Load reads the knowledge base, returning nil if it hasn't been built
+func Load(outputDir string) (*KB, error) {
+	data, err := os.ReadFile(filepath.Join(outputDir, Dir, "kb.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading knowledge base: %w", err)
+	}
+	var k KB
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("parsing knowledge base: %w", err)
+	}
+	return &k, nil
+}
+
+// Save writes the knowledge base to outputDir/.kb/kb.json
+func Save(outputDir string, k *KB) error {
+	dir := filepath.Join(outputDir, Dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating knowledge base dir: %w", err)
+	}
+	k.UpdatedAt = time.Now()
+	data, err := json.Marshal(k)
+	if err != nil {
+		return fmt.Errorf("marshaling knowledge base: %w", err)
+	}
+	if err := session.WriteFileAtomic(filepath.Join(dir, "kb.json"), data, 0644); err != nil {
+		return fmt.Errorf("writing knowledge base: %w", err)
+	}
+	return nil
+}
+
+// Build aggregates the entries of all sessions, deduplicating items by form
+// and primary value. Rebuilding from scratch keeps refreshed sessions from
+// being counted twice.
+func Build(sessions []Session, emb index.Embedder) *KB {
+	k := &KB{Embedder: emb.Name(), Sessions: len(sessions)}
+	byKey := map[string]int{}
+	for _, s := range sessions {
+		primaryID := schema.PrimaryField(s.Form)
+		for _, r := range export.Records(s.Manifest) {
+			primary := fmt.Sprintf("%v", r.Fields[primaryID])
+			if r.Fields[primaryID] == nil || strings.TrimSpace(primary) == "" {
+				continue
+			}
+			key := normalizeKey(s.Form.Title) + "/" + normalizeKey(primary)
+			score := 0.0
+			if r.RankScore != nil {
+				score = *r.RankScore
+			}
+
+			i, ok := byKey[key]
+			if !ok {
+				i = len(k.Items)
+				byKey[key] = i
+				k.Items = append(k.Items, Item{Key: key, Form: s.Form.Title, Primary: primary, BestScore: -1})
+			}
+			item := &k.Items[i]
+			item.Mentions++
+			if !contains(item.Sessions, s.Name) {
+				item.Sessions = append(item.Sessions, s.Name)
+			}
+			for _, src := range r.Sources {
+				if !contains(item.Sources, src) {
+					item.Sources = append(item.Sources, src)
+				}
+			}
+			if score > item.BestScore {
+				item.BestScore = score
+				item.Primary = primary
+				item.Fields = r.Fields
+				item.Vector = emb.Embed(itemText(item.Form, r.Entry))
+			}
+		}
+	}
+	return k
+}
+
+// HasSession reports whether any item was aggregated from the named session
+func (k *KB) HasSession(name string) bool {
+	for _, item := range k.Items {
+		if contains(item.Sessions, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// RenameSession updates the items a renamed session mentioned. Reports
+// whether any item mentioned it.
+func (k *KB) RenameSession(name, newName string) bool {
+	found := false
+	for i := range k.Items {
+		for j, s := range k.Items[i].Sessions {
+			if s == name {
+				k.Items[i].Sessions[j] = newName
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// Search returns the k items most similar to the query, optionally limited to a form
+func (k *KB) Search(emb index.Embedder, query, form string, limit int) []Hit {
+	q := emb.Embed(query)
+	var hits []Hit
+	for _, item := range k.Items {
+		if form != "" && !strings.EqualFold(item.Form, form) {
+			continue
+		}
+		hits = append(hits, Hit{Item: item, Score: index.Cosine(q, item.Vector)})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		return hits[i].Score > hits[j].Score
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// itemText is the text embedded for an item: its field values and evidence quotes
+func itemText(form string, entry types.Entry) string {
+	parts := []string{form}
+	for _, fv := range entry.Fields {
+		if fv.Value != nil {
+			parts = append(parts, fmt.Sprintf("%s: %v", fv.ID, fv.Value))
+		}
+		for _, ev := range fv.Evidence {
+			parts = append(parts, ev.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+var nonAlphaNum = regexp.MustCompile(`[^a-z0-9]+`)
+
+// normalizeKey lowercases s and collapses punctuation, dropping parentheticals
+func normalizeKey(s string) string {
+	s = strings.ToLower(s)
+	if i := strings.Index(s, "("); i > 0 {
+		s = s[:i]
+	}
+	return strings.Trim(nonAlphaNum.ReplaceAllString(s, "-"), "-")
+}
+
+func contains(slice []string, s string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+// Package meter keeps a running tally of the tokens and estimated cost of a
+// run's agent calls, per agent and model, so runaway runs can be stopped
+// before the bill arrives
+package meter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"belaykit"
+)
+
+// Pricing returns the token pricing for a backend's model, or nil when the
+// backend's usage can't be priced
+type Pricing func(backend, model string) *belaykit.ModelPricing
+
+// Usage is the tally of a set of agent calls. Tokens are estimated from the
+// text sent and received; Cost is what the backend reported, or else the
+// estimate priced by the model's rates.
+type Usage struct {
+	Calls        int
+	InputTokens  int
+	OutputTokens int
+	CostUSD      float64
+	Unpriced     bool // some calls had neither a reported cost nor pricing
+}
+
+func (u *Usage) add(o Usage) {
+	u.Calls += o.Calls
+	u.InputTokens += o.InputTokens
+	u.OutputTokens += o.OutputTokens
+	u.CostUSD += o.CostUSD
+	u.Unpriced = u.Unpriced || o.Unpriced
+}
+
+// String formats the usage as "~412k in / 38k out tokens, ~$1.23"
+func (u Usage) String() string {
+	s := fmt.Sprintf("~%s in / %s out tokens, ~$%.2f", formatTokens(u.InputTokens), formatTokens(u.OutputTokens), u.CostUSD)
+	if u.Unpriced {
+		s += " (some calls unpriced)"
+	}
+	return s
+}
+
+// key identifies a tally line: the agent making the calls and its model
+type key struct {
+	agent string
+	model string
+}
+
+// Meter tallies agent calls as they complete
+type Meter struct {
+	pricing Pricing
+
+	mu      sync.Mutex
+	byKey   map[key]*Usage
+	changed bool
+}
+
+// New creates a meter pricing calls with pricing
+func New(pricing Pricing) *Meter {
+	return &Meter{pricing: pricing, byKey: map[key]*Usage{}}
+}
+
+// Runner wraps an agent runner so every call it makes is tallied under the
+// agent's name. Calls without a model are tallied as "default".
+func (m *Meter) Runner(base Runner, agent, backend string) Runner {
+	return meteredRunner{base: base, meter: m, agent: agent, backend: backend}
+}
+
+// Total returns the usage of every call so far
+func (m *Meter) Total() Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var total Usage
+	for _, u := range m.byKey {
+		total.add(*u)
+	}
+	return total
+}
+
+// Lines returns one line per agent and model, costliest first
+func (m *Meter) Lines() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]key, 0, len(m.byKey))
+	for k := range m.byKey {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := m.byKey[keys[i]], m.byKey[keys[j]]
+		if a.CostUSD != b.CostUSD {
+			return a.CostUSD > b.CostUSD
+		}
+		return keys[i].agent+keys[i].model < keys[j].agent+keys[j].model
+	})
+	var lines []string
+	for _, k := range keys {
+		u := m.byKey[k]
+		lines = append(lines, fmt.Sprintf("%s (%s): %d calls, %s", k.agent, k.model, u.Calls, u))
+	}
+	return lines
+}
+
+// Ticker prints the running total to w every interval while usage changes,
+// with the costliest agents alongside, until ctx is done
+func (m *Meter) Ticker(ctx context.Context, w io.Writer, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.mu.Lock()
+			changed := m.changed
+			m.changed = false
+			m.mu.Unlock()
+			if !changed {
+				continue
+			}
+			line := "  [usage] " + m.Total().String() + " so far"
+			if top := m.Lines(); len(top) > 0 {
+				if len(top) > 3 {
+					top = top[:3]
+				}
+				line += " — " + strings.Join(top, "; ")
+			}
+			fmt.Fprintln(w, line)
+		}
+	}
+}
+
+// record tallies one call
+func (m *Meter) record(agent, backend, model string, in, out int, reported float64) {
+	u := Usage{Calls: 1, InputTokens: in, OutputTokens: out, CostUSD: reported}
+	if reported == 0 {
+		if p := m.pricing(backend, model); p != nil {
+			u.CostUSD = p.Cost(in, out)
+		} else {
+			u.Unpriced = true
+		}
+	}
+	if model == "" {
+		model = "default"
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	k := key{agent, model}
+	if m.byKey[k] == nil {
+		m.byKey[k] = &Usage{}
+	}
+	m.byKey[k].add(u)
+	m.changed = true
+}
+
+func formatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%dk", n/1_000)
+	}
+	return fmt.Sprintf("%d", n)
+}
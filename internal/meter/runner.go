@@ -0,0 +1,57 @@
+package meter
+
+import (
+	"context"
+	"sync"
+
+	"belaykit"
+)
+
+// Runner is the agent runner interface metered runners wrap
+type Runner interface {
+	Run(ctx context.Context, prompt string, opts ...belaykit.RunOption) (belaykit.Result, error)
+}
+
+type meteredRunner struct {
+	base    Runner
+	meter   *Meter
+	agent   string
+	backend string
+}
+
+// Run passes the call through, counting the prompt and response plus the
+// tool traffic and reported cost seen in its events
+func (r meteredRunner) Run(ctx context.Context, prompt string, opts ...belaykit.RunOption) (belaykit.Result, error) {
+	cfg := belaykit.NewRunConfig(opts...)
+	in := belaykit.EstimateTokens(prompt) + belaykit.EstimateTokens(cfg.SystemPrompt)
+	var (
+		mu         sync.Mutex
+		tools      int // tool output fed back to the model
+		toolTokens int // tool names and inputs the model wrote
+		reported   float64
+	)
+	next := cfg.EventHandler
+	opts = append(opts, belaykit.WithEventHandler(func(e belaykit.Event) {
+		mu.Lock()
+		switch e.Type {
+		case belaykit.EventToolResult:
+			tools += belaykit.EstimateTokens(e.Text)
+		case belaykit.EventToolUse:
+			toolTokens += belaykit.EstimateTokens(e.ToolName) + belaykit.EstimateTokens(string(e.ToolInput))
+		case belaykit.EventResult, belaykit.EventResultError:
+			reported += e.CostUSD
+		}
+		mu.Unlock()
+		if next != nil {
+			next(e)
+		}
+	}))
+
+	result, err := r.base.Run(ctx, prompt, opts...)
+
+	mu.Lock()
+	defer mu.Unlock()
+	out := belaykit.EstimateTokens(result.Text) + toolTokens
+	r.meter.record(r.agent, r.backend, cfg.Model, in+tools, out, reported)
+	return result, err
+}
@@ -0,0 +1,209 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"hiveminer/internal/export"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// snapshotFile records the previous digest's top entries in the session directory
+const snapshotFile = "digest.json"
+
+// Snapshot is the top-N list a digest was built from, used to report changes
+// in the next digest
+type Snapshot struct {
+	BuiltAt time.Time       `json:"built_at"`
+	Top     []SnapshotEntry `json:"top"`
+}
+
+// SnapshotEntry is one top entry in a snapshot
+type SnapshotEntry struct {
+	Key   string  `json:"key"`
+	Title string  `json:"title"`
+	Score float64 `json:"score"`
+}
+
+// DigestEntry is a top entry with its change since the previous digest
+type DigestEntry struct {
+	Rank    int
+	Title   string
+	Score   float64
+	Fields  []string // "field: value" lines, excluding the primary field
+	Source  string
+	IsNew   bool // not in the previous digest's top list
+	PrevPos int  // previous rank, 0 when new
+}
+
+// Digest summarizes a session's top entries and what changed since last time
+type Digest struct {
+	FormTitle string
+	Query     string
+	Top       []DigestEntry
+	Dropped   []SnapshotEntry // in the previous top list but not this one
+	Previous  *time.Time
+}
+
+// BuildDigest builds a digest of the top n entries, comparing against the
+// previous snapshot when there is one
+func BuildDigest(manifest *types.Manifest, primaryID string, prev *Snapshot, n int) (*Digest, *Snapshot) {
+	records := export.Records(manifest)
+	if n > 0 && len(records) > n {
+		records = records[:n]
+	}
+
+	d := &Digest{FormTitle: manifest.Form.Title, Query: manifest.Query}
+	snap := &Snapshot{BuiltAt: time.Now()}
+
+	prevPos := map[string]int{}
+	if prev != nil {
+		d.Previous = &prev.BuiltAt
+		for i, e := range prev.Top {
+			prevPos[e.Key] = i + 1
+		}
+	}
+
+	current := map[string]bool{}
+	for _, r := range records {
+		title := fmt.Sprintf("%v", r.Fields[primaryID])
+		if r.Fields[primaryID] == nil {
+			title = r.ThreadTitle
+		}
+		key := r.ThreadID + "/" + strings.ToLower(title)
+		current[key] = true
+
+		score := 0.0
+		if r.RankScore != nil {
+			score = *r.RankScore
+		}
+
+		var fields []string
+		ids := make([]string, 0, len(r.Fields))
+		for id := range r.Fields {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			if id != primaryID && r.Fields[id] != nil {
+				fields = append(fields, fmt.Sprintf("%s: %v", id, r.Fields[id]))
+			}
+		}
+
+		source := r.ThreadURL
+		if len(r.Sources) > 0 {
+			source = r.Sources[0]
+		}
+
+		d.Top = append(d.Top, DigestEntry{
+			Rank:    r.Rank,
+			Title:   title,
+			Score:   score,
+			Fields:  fields,
+			Source:  source,
+			IsNew:   prev != nil && prevPos[key] == 0,
+			PrevPos: prevPos[key],
+		})
+		snap.Top = append(snap.Top, SnapshotEntry{Key: key, Title: title, Score: score})
+	}
+
+	if prev != nil {
+		for _, e := range prev.Top {
+			if !current[e.Key] {
+				d.Dropped = append(d.Dropped, e)
+			}
+		}
+	}
+	return d, snap
+}
+
+// Subject returns the email subject line for a digest. Line breaks in the
+// form title become spaces so it can't add header lines.
+func (d *Digest) Subject() string {
+	title := strings.NewReplacer("\r", " ", "\n", " ").Replace(d.FormTitle)
+	newCount := 0
+	for _, e := range d.Top {
+		if e.IsNew {
+			newCount++
+		}
+	}
+	if newCount > 0 {
+		return fmt.Sprintf("hiveminer: %s — %d new in the top %d", title, newCount, len(d.Top))
+	}
+	return fmt.Sprintf("hiveminer: %s — top %d", title, len(d.Top))
+}
+
+// Text renders the digest as plain text
+func (d *Digest) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", d.FormTitle)
+	if d.Query != "" {
+		fmt.Fprintf(&b, "Query: %s\n", d.Query)
+	}
+	if d.Previous != nil {
+		fmt.Fprintf(&b, "Changes since %s\n", d.Previous.Format("Jan 02 15:04"))
+	}
+	b.WriteString("\n")
+
+	for i, e := range d.Top {
+		change := ""
+		switch {
+		case e.IsNew:
+			change = "  [NEW]"
+		case e.PrevPos > i+1:
+			change = fmt.Sprintf("  [up from #%d]", e.PrevPos)
+		case e.PrevPos > 0 && e.PrevPos < i+1:
+			change = fmt.Sprintf("  [down from #%d]", e.PrevPos)
+		}
+		fmt.Fprintf(&b, "#%d  %s  (%.0f pts)%s\n", i+1, e.Title, e.Score, change)
+		for _, f := range e.Fields {
+			fmt.Fprintf(&b, "    %s\n", f)
+		}
+		if e.Source != "" {
+			fmt.Fprintf(&b, "    %s\n", e.Source)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(d.Dropped) > 0 {
+		b.WriteString("Dropped out of the top list:\n")
+		for _, e := range d.Dropped {
+			fmt.Fprintf(&b, "    %s (%.0f pts)\n", e.Title, e.Score)
+		}
+	}
+	return b.String()
+}
+
+// LoadSnapshot reads a session's previous digest snapshot, or nil if none
+func LoadSnapshot(sessionDir string) (*Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(sessionDir, snapshotFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading digest snapshot: %w", err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("parsing digest snapshot: %w", err)
+	}
+	return &snap, nil
+}
+
+// SaveSnapshot writes a digest snapshot to the session directory
+func SaveSnapshot(sessionDir string, snap *Snapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling digest snapshot: %w", err)
+	}
+	if err := session.WriteFileAtomic(filepath.Join(sessionDir, snapshotFile), data, 0644); err != nil {
+		return fmt.Errorf("writing digest snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// Notifier delivers a digest to its recipients
+type Notifier interface {
+	Send(d *Digest) error
+}
+
+// SMTPConfig holds SMTP server settings
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// SMTPConfigFromEnv reads SMTP settings from HIVEMINER_SMTP_* environment
+// variables. To is taken from the argument.
+func SMTPConfigFromEnv(to []string) (SMTPConfig, error) {
+	cfg := SMTPConfig{
+		Host:     os.Getenv("HIVEMINER_SMTP_HOST"),
+		Port:     os.Getenv("HIVEMINER_SMTP_PORT"),
+		Username: os.Getenv("HIVEMINER_SMTP_USER"),
+		Password: os.Getenv("HIVEMINER_SMTP_PASSWORD"),
+		From:     os.Getenv("HIVEMINER_SMTP_FROM"),
+		To:       to,
+	}
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+	if cfg.From == "" {
+		cfg.From = cfg.Username
+	}
+	if cfg.Host == "" || cfg.From == "" {
+		return cfg, fmt.Errorf("HIVEMINER_SMTP_HOST and HIVEMINER_SMTP_FROM (or HIVEMINER_SMTP_USER) must be set")
+	}
+	if len(cfg.To) == 0 {
+		return cfg, fmt.Errorf("no recipients")
+	}
+	return cfg, nil
+}
+
+// SMTPNotifier sends digests as plain-text email
+type SMTPNotifier struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPNotifier creates a notifier for the given SMTP settings
+func NewSMTPNotifier(cfg SMTPConfig) *SMTPNotifier {
+	return &SMTPNotifier{cfg: cfg}
+}
+
+// Send emails the digest
+func (n *SMTPNotifier) Send(d *Digest) error {
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.cfg.To, ", "))
+	// Headers are ASCII; the subject may not be
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", d.Subject()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(d.Text(), "\n", "\r\n"))
+
+	addr := net.JoinHostPort(n.cfg.Host, n.cfg.Port)
+	if err := smtp.SendMail(addr, auth, n.cfg.From, n.cfg.To, []byte(msg.String())); err != nil {
+		return fmt.Errorf("sending digest email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// CheckpointDecision is the answer to a checkpoint: carry on, optionally
+// with a new thread limit, or abort the run
+type CheckpointDecision struct {
+	Abort bool
+	Limit int // new thread limit, 0 keeps the current one
+}
+
+// checkpoint pauses the pipeline and asks config.OnCheckpoint whether to go
+// on, given the threads extracted so far. Aborting cancels the run, which is
+// saved as interrupted and can be resumed.
+func checkpoint(config RunConfig, store *session.Store, limit *atomic.Int64) {
+	if config.OnCheckpoint == nil || config.Control == nil {
+		return
+	}
+	paused := config.Control.Pause()
+	fmt.Printf("\n=== Checkpoint: %d threads extracted ===\n", config.Checkpoint)
+	fmt.Println("  New threads wait here; threads already in progress finish in the background")
+
+	var preview []types.ThreadState
+	store.View(func(m *types.Manifest) {
+		for _, t := range m.Threads {
+			if types.HasEntries(t.Status) {
+				preview = append(preview, t)
+			}
+		}
+	})
+
+	decision := config.OnCheckpoint(preview, int(limit.Load()))
+	if decision.Abort {
+		fmt.Println("Aborted at checkpoint, saving progress...")
+		config.Control.Cancel()
+		return
+	}
+	if decision.Limit > 0 && int64(decision.Limit) != limit.Load() {
+		fmt.Printf("Thread limit changed from %d to %d\n", limit.Load(), decision.Limit)
+		limit.Store(int64(decision.Limit))
+	}
+	if paused {
+		config.Control.Resume()
+	}
+	fmt.Println("Continuing")
+}
@@ -0,0 +1,29 @@
+package orchestrator
+
+import (
+	"hiveminer/internal/search"
+	"hiveminer/pkg/types"
+)
+
+// partialCoverage is the share of a thread's comments below which its
+// extraction is reported as a partial view of the discussion
+const partialCoverage = 0.5
+
+// contextUsage records how much of a thread reached extraction: fetched is
+// its comment count before truncation, sent the thread extraction was given
+func contextUsage(ts types.ThreadState, fetched int, sent *types.Thread, result *types.ExtractionResult) *types.ContextUsage {
+	comments := max(ts.NumComments, sent.Post.NumComments, fetched)
+	return &types.ContextUsage{
+		Comments:     comments,
+		Fetched:      fetched,
+		Sent:         max(search.CountComments(sent.Comments)-result.Summarized, 0),
+		Summarized:   result.Summarized,
+		PromptTokens: result.PromptTokens,
+	}
+}
+
+// PartialView reports whether a thread's last extraction saw under half of
+// its comments, so its entries rest on a partial view of the discussion
+func PartialView(ts types.ThreadState) bool {
+	return ts.Context != nil && ts.Context.Coverage() < partialCoverage
+}
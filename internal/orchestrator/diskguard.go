@@ -0,0 +1,238 @@
+package orchestrator
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// errSessionFull is returned when the session has hit its size limit and no
+// new thread payloads may be written
+var errSessionFull = errors.New("session size limit reached")
+
+// diskGuard enforces the session and per-thread payload size limits. A nil
+// guard enforces nothing.
+type diskGuard struct {
+	dir        string
+	maxSession int64
+	maxThread  int64
+
+	mu   sync.Mutex
+	full bool
+}
+
+// newDiskGuard returns a guard for the config's limits, or nil if unlimited
+func newDiskGuard(config RunConfig, sessionDir string) *diskGuard {
+	if config.MaxSessionBytes <= 0 && config.MaxThreadBytes <= 0 {
+		return nil
+	}
+	return &diskGuard{dir: sessionDir, maxSession: config.MaxSessionBytes, maxThread: config.MaxThreadBytes}
+}
+
+// Full reports whether the session has stopped collecting new payloads
+func (g *diskGuard) Full() bool {
+	if g == nil {
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.full
+}
+
+// writeThread writes a thread payload, trimming it to the per-thread limit.
+// Returns errSessionFull without writing once the session limit is reached.
+func (g *diskGuard) writeThread(path string, thread *types.Thread) error {
+	if g.Full() {
+		return errSessionFull
+	}
+	data, err := json.MarshalIndent(thread, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling thread: %w", err)
+	}
+	if g != nil && g.maxThread > 0 && int64(len(data)) > g.maxThread {
+		data, err = g.trimThread(thread)
+		if err != nil {
+			return err
+		}
+	}
+	return session.WriteFileAtomic(path, data, 0644)
+}
+
+// enforceThreadLimit trims a payload written by someone else (the evaluation
+// agent) if it exceeds the per-thread limit
+func (g *diskGuard) enforceThreadLimit(path string) {
+	if g == nil || g.maxThread <= 0 {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Size() <= g.maxThread {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	thread, err := parseThreadJSON(data)
+	if err != nil {
+		return
+	}
+	if trimmed, err := g.trimThread(thread); err == nil {
+		session.WriteFileAtomic(path, trimmed, 0644)
+	}
+}
+
+// trimThread keeps the highest-scored top-level comment trees that fit within
+// the per-thread limit
+func (g *diskGuard) trimThread(thread *types.Thread) ([]byte, error) {
+	comments := append([]*types.Comment(nil), thread.Comments...)
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].Score > comments[j].Score
+	})
+
+	marshal := func(n int) ([]byte, error) {
+		t := *thread
+		t.Comments = comments[:n]
+		return json.MarshalIndent(&t, "", "  ")
+	}
+
+	// Binary search for the most trees that fit
+	lo, hi := 0, len(comments)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		data, err := marshal(mid)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling thread: %w", err)
+		}
+		if int64(len(data)) <= g.maxThread {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	fmt.Printf("  Warning: thread %s payload exceeds %s, keeping the top %d of %d comment trees\n",
+		thread.Post.ID, session.FormatBytes(g.maxThread), lo, len(comments))
+	return marshal(lo)
+}
+
+// check measures the session and, if it's over the limit, gzips the payloads
+// of threads that are no longer needed for extraction. If that isn't enough,
+// the session stops collecting new payloads.
+func (g *diskGuard) check(store *session.Store) {
+	if g == nil || g.maxSession <= 0 || g.Full() {
+		return
+	}
+	size := session.DirSize(g.dir)
+	if size <= g.maxSession {
+		return
+	}
+
+	done := map[string]bool{}
+	store.View(func(m *types.Manifest) {
+		for _, t := range m.Threads {
+			switch t.Status {
+			case types.StatusExtracted, types.StatusRanked, types.StatusSkipped, types.StatusFailed, types.StatusQuarantined:
+				if !t.Pinned {
+					done[t.PostID] = true
+				}
+			}
+		}
+	})
+
+	compressed := 0
+	matches, _ := filepath.Glob(filepath.Join(g.dir, "thread_*.json"))
+	for _, path := range matches {
+		id := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), "thread_"), ".json")
+		if !done[id] {
+			continue
+		}
+		if err := gzipFile(path); err == nil {
+			compressed++
+		}
+	}
+
+	size = session.DirSize(g.dir)
+	if compressed > 0 {
+		fmt.Printf("  Session over %s: compressed %d finished thread payloads (now %s)\n",
+			session.FormatBytes(g.maxSession), compressed, session.FormatBytes(size))
+	}
+	if size > g.maxSession {
+		g.mu.Lock()
+		g.full = true
+		g.mu.Unlock()
+		fmt.Printf("\n  WARNING: session is %s, over its %s limit (--max-session-mb).\n", session.FormatBytes(size), session.FormatBytes(g.maxSession))
+		fmt.Println("  No new threads will be collected; threads already collected will still be extracted.")
+		fmt.Println("  Free space or raise the limit, then resume with --session.")
+	}
+}
+
+// ThreadPayload reads the saved payload of a session's thread, gzipped or not
+func ThreadPayload(sessionDir, postID string) ([]byte, error) {
+	return readThreadPayload(filepath.Join(sessionDir, fmt.Sprintf("thread_%s.json", postID)))
+}
+
+// readThreadPayload reads a thread payload, falling back to its gzipped form
+func readThreadPayload(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil || !os.IsNotExist(err) {
+		return data, err
+	}
+	f, gzErr := os.Open(path + ".gz")
+	if gzErr != nil {
+		return nil, err
+	}
+	defer f.Close()
+	zr, gzErr := gzip.NewReader(f)
+	if gzErr != nil {
+		return nil, gzErr
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// gzipFile replaces path with path.gz
+func gzipFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".gz.tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(f)
+	if _, err := zw.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path+".gz"); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
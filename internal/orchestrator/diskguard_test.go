@@ -0,0 +1,111 @@
+package orchestrator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// tentThread is a thread with n comments of increasing score
+func tentThread(postID string, n int) *types.Thread {
+	thread := &types.Thread{Post: types.Post{ID: postID, Title: "Best backpacking tent?"}}
+	for i := 0; i < n; i++ {
+		thread.Comments = append(thread.Comments, &types.Comment{
+			ID:    fmt.Sprintf("c%d", i),
+			Body:  strings.Repeat("The Duplex has held up for years. ", 20),
+			Score: i,
+		})
+	}
+	return thread
+}
+
+func TestDiskGuardCompressesFinishedThreads(t *testing.T) {
+	dir := t.TempDir()
+	g := newDiskGuard(RunConfig{MaxSessionBytes: 1}, dir)
+	payloads := map[string][]byte{}
+	for _, id := range []string{"a1", "b2", "c3"} {
+		path := filepath.Join(dir, fmt.Sprintf("thread_%s.json", id))
+		if err := g.writeThread(path, tentThread(id, 5)); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		payloads[id] = data
+	}
+	store := session.NewStore(dir, &types.Manifest{Threads: []types.ThreadState{
+		{PostID: "a1", Status: types.StatusExtracted},
+		{PostID: "b2", Status: types.StatusCollected},
+		{PostID: "c3", Status: types.StatusRanked, Pinned: true},
+	}})
+
+	g.check(store)
+
+	if _, err := os.Stat(filepath.Join(dir, "thread_a1.json.gz")); err != nil {
+		t.Errorf("extracted thread's payload not compressed: %v", err)
+	}
+	for _, id := range []string{"b2", "c3"} {
+		if _, err := os.Stat(filepath.Join(dir, fmt.Sprintf("thread_%s.json", id))); err != nil {
+			t.Errorf("thread %s: payload compressed, want it left for extraction or pinned (%v)", id, err)
+		}
+	}
+	for id, want := range payloads {
+		got, err := ThreadPayload(dir, id)
+		if err != nil {
+			t.Fatalf("reading %s back: %v", id, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("thread %s read back differs from what was written", id)
+		}
+	}
+
+	// Still over a one-byte limit, so no more payloads are collected
+	if !g.Full() {
+		t.Fatal("guard not full after compression left the session over its limit")
+	}
+	if err := g.writeThread(filepath.Join(dir, "thread_d4.json"), tentThread("d4", 1)); err != errSessionFull {
+		t.Errorf("writing to a full session: %v, want errSessionFull", err)
+	}
+}
+
+func TestDiskGuardTrimsToTopComments(t *testing.T) {
+	dir := t.TempDir()
+	full, err := json.MarshalIndent(tentThread("a1", 10), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit := int64(len(full) / 2)
+	g := newDiskGuard(RunConfig{MaxThreadBytes: limit}, dir)
+	path := filepath.Join(dir, "thread_a1.json")
+	if err := g.writeThread(path, tentThread("a1", 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(data)) > limit {
+		t.Errorf("payload is %d bytes, over the %d limit", len(data), limit)
+	}
+	var trimmed types.Thread
+	if err := json.Unmarshal(data, &trimmed); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(trimmed.Comments); n == 0 || n >= 10 {
+		t.Fatalf("kept %d of 10 comments", n)
+	}
+	for i, c := range trimmed.Comments {
+		if want := 9 - i; c.Score != want {
+			t.Errorf("comment %d scored %d, want %d: the highest-scored should be kept", i, c.Score, want)
+		}
+	}
+}
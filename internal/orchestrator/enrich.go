@@ -0,0 +1,82 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"hiveminer/internal/enrich"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// SetResolvers sets the resolvers the enrich phase runs over each entry;
+// without any the phase is skipped
+func (o *DefaultOrchestrator) SetResolvers(rs []enrich.Resolver) {
+	o.resolvers = rs
+}
+
+// enrichEntries passes every extracted entry through each resolver. Entries
+// of threads already ranked are refreshed too, so price history accumulates
+// across runs, but their scores only change when they're ranked again.
+// Returns how many entries changed and how many lookups failed.
+func (o *DefaultOrchestrator) enrichEntries(ctx context.Context, config RunConfig, store *session.Store) (int, int, error) {
+	// Resolvers work on copies so lookups run without holding the store
+	type pendingEntry struct {
+		postID, title string
+		entry         types.Entry
+	}
+	var pending []pendingEntry
+	store.View(func(m *types.Manifest) {
+		for _, t := range m.Threads {
+			if !types.HasEntries(t.Status) {
+				continue
+			}
+			for _, entry := range t.Entries {
+				if entry.Overflow {
+					continue
+				}
+				entry.Fields = slices.Clone(entry.Fields)
+				entry.Links = slices.Clone(entry.Links)
+				pending = append(pending, pendingEntry{postID: t.PostID, title: t.Title, entry: entry})
+			}
+		}
+	})
+
+	enriched, failed := 0, 0
+	for _, p := range pending {
+		entry := p.entry
+		changed := false
+		for _, r := range o.resolvers {
+			ok, err := r.Enrich(ctx, config.Form, &entry)
+			if err != nil {
+				if ctx.Err() != nil {
+					return enriched, failed, ctx.Err()
+				}
+				failed++
+				fmt.Printf("  Warning: %s: %v\n", r.Name(), err)
+				continue
+			}
+			changed = changed || ok
+		}
+		if !changed {
+			continue
+		}
+		err := store.UpdateThread(p.postID, func(t *types.ThreadState) error {
+			for j := range t.Entries {
+				if t.Entries[j].ID == entry.ID {
+					t.Entries[j] = entry
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return enriched, failed, err
+		}
+		enriched++
+		if a := entry.Availability; a != nil && a.Status == types.AvailabilityDiscontinued {
+			fmt.Printf("  %s: discontinued (%s)\n", truncate(p.title, 50), a.Source)
+		}
+	}
+	return enriched, failed, nil
+}
@@ -0,0 +1,30 @@
+package orchestrator
+
+import (
+	"slices"
+
+	"hiveminer/internal/schema"
+	"hiveminer/pkg/types"
+)
+
+// excludeEntries drops entries falling under one of the form's exclusions or
+// outside its audience's budget, returning the rest and why entries were
+// dropped
+func excludeEntries(form *types.Form, entries []types.Entry) ([]types.Entry, []string) {
+	var kept []types.Entry
+	var reasons []string
+	for _, entry := range entries {
+		reason := schema.CheckAudience(form, entry)
+		if ex := schema.MatchExclusion(form, entry); ex != nil {
+			reason = ex.Description
+		}
+		if reason == "" {
+			kept = append(kept, entry)
+			continue
+		}
+		if !slices.Contains(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+	return kept, reasons
+}
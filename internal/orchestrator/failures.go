@@ -0,0 +1,157 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"belaykit"
+
+	"hiveminer/internal/search"
+	"hiveminer/pkg/types"
+)
+
+// Pipeline stages a thread can fail in, used as error code prefixes
+const (
+	stageFetch   = "fetch"
+	stageEval    = "eval"
+	stageExtract = "extract"
+	stageWrite   = "write" // write_failed
+)
+
+// contextOverflowHints are phrases model APIs use when a prompt is too long
+var contextOverflowHints = []string{
+	"context length", "context window", "prompt is too long", "too many tokens", "maximum context",
+}
+
+// classifyFailure maps a thread failure in a pipeline stage to an error code.
+// Errors from agents arrive as text, so besides typed errors it matches the
+// messages Reddit and model APIs are known to produce.
+func classifyFailure(stage string, err error) string {
+	if err == nil {
+		return types.ErrUnknown
+	}
+	msg := strings.ToLower(err.Error())
+
+	if errors.Is(err, search.ErrBudgetExhausted) || strings.Contains(msg, "budget exhausted") {
+		return types.ErrFetchBudget
+	}
+	switch code := search.HTTPStatus(err); {
+	case code == 429:
+		return types.ErrFetch429
+	case code == 403:
+		return types.ErrFetch403
+	case code == 404:
+		return types.ErrFetch404
+	case code >= 500:
+		return types.ErrFetch5xx
+	}
+	switch {
+	case strings.Contains(msg, "http 429"), strings.Contains(msg, "too many requests"):
+		return types.ErrFetch429
+	case strings.Contains(msg, "http 403"):
+		return types.ErrFetch403
+	case strings.Contains(msg, "http 404"):
+		return types.ErrFetch404
+	}
+	for _, hint := range contextOverflowHints {
+		if strings.Contains(msg, hint) {
+			return types.ErrContextOverflow
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) || strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out") {
+		return stage + "_timeout"
+	}
+	if errors.Is(err, belaykit.ErrNoJSON) || strings.Contains(msg, "parsing") || strings.Contains(msg, "unmarshal") {
+		return types.ErrParseFailure
+	}
+	return stage + "_failed"
+}
+
+// FormatFailures formats failure counts by code, most frequent first, e.g.
+// "fetch_429 3, parse_failure 1"
+func FormatFailures(counts map[string]int) string {
+	codes := make([]string, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if counts[codes[i]] != counts[codes[j]] {
+			return counts[codes[i]] > counts[codes[j]]
+		}
+		return codes[i] < codes[j]
+	})
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		parts[i] = fmt.Sprintf("%s %d", code, counts[code])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// maxErrorHistory is how many recent failures a thread keeps
+const maxErrorHistory = 5
+
+// recordFailure marks a thread failed, counting the attempt and keeping its
+// error history. At maxAttempts it is quarantined instead; returns whether it was.
+func recordFailure(t *types.ThreadState, code string, err error, maxAttempts int) bool {
+	t.Status = types.StatusFailed
+	t.ErrorCode = code
+	if err != nil {
+		t.Error = err.Error()
+	}
+	t.Attempts++
+	t.ErrorHistory = append(t.ErrorHistory, code+": "+t.Error)
+	if len(t.ErrorHistory) > maxErrorHistory {
+		t.ErrorHistory = t.ErrorHistory[len(t.ErrorHistory)-maxErrorHistory:]
+	}
+	if maxAttempts > 0 && t.Attempts >= maxAttempts {
+		t.Status = types.StatusQuarantined
+		return true
+	}
+	return false
+}
+
+// retryFailed resets a resumed session's failed threads so this run tries
+// them again. Quarantined threads stay put until released with runs retry.
+func retryFailed(manifest *types.Manifest, sessionDir string, maxAttempts int) {
+	retried := 0
+	for i := range manifest.Threads {
+		t := &manifest.Threads[i]
+		if t.Status != types.StatusFailed {
+			continue
+		}
+		// Failures recorded before attempts were counted
+		if t.Attempts == 0 {
+			t.Attempts = 1
+		}
+		if maxAttempts > 0 && t.Attempts >= maxAttempts {
+			t.Status = types.StatusQuarantined
+			continue
+		}
+		ResetFailedThread(t, sessionDir)
+		retried++
+	}
+	if retried > 0 {
+		fmt.Printf("Retrying %d failed threads\n", retried)
+	}
+}
+
+// ResetFailedThread returns a failed or quarantined thread to the pipeline:
+// threads whose payload was saved go straight back to extraction, the rest
+// are fetched (and evaluated) again. Attempts and error history are kept.
+func ResetFailedThread(t *types.ThreadState, sessionDir string) {
+	t.Status = types.StatusPending
+	path := filepath.Join(sessionDir, fmt.Sprintf("thread_%s.json", t.PostID))
+	for _, p := range []string{path, path + ".gz"} {
+		if _, err := os.Stat(p); err == nil {
+			t.Status = types.StatusCollected
+			break
+		}
+	}
+	t.Error = ""
+	t.ErrorCode = ""
+}
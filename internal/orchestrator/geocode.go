@@ -0,0 +1,92 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"hiveminer/internal/geocode"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// SetGeocoder sets the geocoder the geocode phase resolves location fields
+// with; without one the phase is skipped
+func (o *DefaultOrchestrator) SetGeocoder(g geocode.Geocoder) {
+	o.geocoder = g
+}
+
+// geocodeEntries attaches coordinates and normalized place names to the
+// location fields of every extracted entry not yet geocoded. Lookups are
+// cached in the session, so each place is resolved once. Returns how many
+// values were located and how many couldn't be found.
+func (o *DefaultOrchestrator) geocodeEntries(ctx context.Context, config RunConfig, store *session.Store, sessionDir string) (int, int, error) {
+	locationFields := map[string]bool{}
+	for _, f := range config.Form.Fields {
+		if f.Type == types.FieldTypeLocation {
+			locationFields[f.ID] = true
+		}
+	}
+	if len(locationFields) == 0 {
+		return 0, 0, nil
+	}
+
+	// A place to look up: the value of one entry's location field
+	type lookup struct {
+		postID, entryID string
+		field           int
+		place           string
+	}
+	var pending []lookup
+	store.View(func(m *types.Manifest) {
+		for _, t := range m.Threads {
+			if !types.HasEntries(t.Status) {
+				continue
+			}
+			for _, entry := range t.Entries {
+				for k, fv := range entry.Fields {
+					place, ok := fv.Value.(string)
+					if !locationFields[fv.ID] || !ok || strings.TrimSpace(place) == "" || fv.Geo != nil {
+						continue
+					}
+					pending = append(pending, lookup{postID: t.PostID, entryID: entry.ID, field: k, place: place})
+				}
+			}
+		}
+	})
+	if len(pending) == 0 {
+		return 0, 0, nil
+	}
+
+	cache, err := geocode.NewCache(o.geocoder, sessionDir)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cache.Save()
+
+	located, missed := 0, 0
+	for _, l := range pending {
+		point, err := cache.Geocode(ctx, l.place)
+		if err != nil {
+			return located, missed, fmt.Errorf("geocoding %q: %w", l.place, err)
+		}
+		if point == nil {
+			missed++
+			continue
+		}
+		err = store.UpdateThread(l.postID, func(t *types.ThreadState) error {
+			for j := range t.Entries {
+				if t.Entries[j].ID != l.entryID || l.field >= len(t.Entries[j].Fields) {
+					continue
+				}
+				t.Entries[j].Fields[l.field].Geo = point
+			}
+			return nil
+		})
+		if err != nil {
+			return located, missed, err
+		}
+		located++
+	}
+	return located, missed, nil
+}
@@ -0,0 +1,56 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"hiveminer/pkg/types"
+)
+
+// checkIntegrity repairs what a crash can leave in a resumed session: temp
+// files from interrupted atomic writes are removed, and thread payloads that
+// were cut short (agents save them with their own tools) are deleted so the
+// thread is fetched again. A collected thread's extraction refetches its
+// missing payload; threads further along don't need theirs.
+func checkIntegrity(sessionDir string, manifest *types.Manifest) {
+	var temps int
+	filepath.WalkDir(sessionDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), ".tmp") && os.Remove(path) == nil {
+			temps++
+		}
+		return nil
+	})
+
+	var truncated []string
+	for _, t := range manifest.Threads {
+		if t.Status != types.StatusCollected {
+			continue
+		}
+		path := filepath.Join(sessionDir, fmt.Sprintf("thread_%s.json", t.PostID))
+		data, err := readThreadPayload(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err == nil {
+			_, err = parseThreadJSON(data)
+		}
+		if err != nil {
+			os.Remove(path)
+			os.Remove(path + ".gz")
+			truncated = append(truncated, t.PostID)
+		}
+	}
+
+	if temps > 0 {
+		fmt.Printf("Integrity check: removed %d temp files left by an interrupted write\n", temps)
+	}
+	if len(truncated) > 0 {
+		fmt.Printf("Integrity check: %d thread payloads were truncated or unreadable and will be fetched again (%s)\n",
+			len(truncated), strings.Join(truncated, ", "))
+	}
+}
@@ -3,24 +3,69 @@ package orchestrator
 import (
 	"context"
 
+	"hiveminer/internal/control"
 	"hiveminer/pkg/types"
 )
 
 // RunConfig holds configuration for an extraction run
 type RunConfig struct {
-	FormPath       string
-	Form           *types.Form
-	Query          string
-	Subreddits     []string
-	Limit          int
-	Sort           string
-	OutputDir      string
-	Workers        int    // concurrent extraction workers (default 10)
-	DiscoveryModel string // model for phases 0+1 (default "opus")
-	EvalModel      string // model for phase 2 (default "opus")
-	ExtractModel   string // model for phase 3 (default "haiku")
-	RankModel      string // model for phase 4 (default "haiku")
-	OnPhaseStart   func(phaseName string)
+	FormPath        string
+	Form            *types.Form
+	Query           string
+	Subreddits      []string
+	User            string // mine this user's post/comment history instead of discovering threads
+	Limit           int
+	TargetEntries   int // stop once this many entries are extracted instead of at Limit threads (0 = use Limit)
+	Sort            string
+	OutputDir       string
+	SessionDir      string                   // resume/refresh this existing session instead of creating a new one
+	Name            string                   // directory name for a new session instead of one generated from the query
+	Workers         int                      // concurrent extraction workers (default 10)
+	DiscoveryModel  string                   // model for phases 0+1 (default "opus")
+	EvalModel       string                   // model for phase 2 (default "opus")
+	ExtractModel    string                   // model for phase 3 (default "haiku")
+	RankModel       string                   // model for phase 4 (default "haiku")
+	MineWiki        bool                     // also extract from subreddit wiki pages and sidebars
+	Reviews         []string                 // product review pages to extract alongside threads: URLs or site:id, see reviews.ParseRef
+	Transcripts     []string                 // podcast/video URLs whose transcripts are extracted alongside threads
+	WebPages        []string                 // article URLs whose readable content is extracted alongside threads
+	IgnoreSkipList  bool                     // rediscover threads rejected for this form in earlier sessions
+	CommentBudget   int                      // max comments fetched per megathread (default 1000)
+	ChunkSize       int                      // max comments per extraction call before chunking (default 200)
+	ContextBudget   int                      // max comment characters per extraction call before summarizing (default 120000)
+	CommentSort     string                   // comment sort threads are fetched with (default: the form's comment_sort, else Reddit's)
+	Truncation      types.TruncationStrategy // which comments reach extraction (zero value: flat, keep all fetched)
+	MaxSessionBytes int64                    // stop collecting new threads once the session dir exceeds this (0 = unlimited)
+	MaxThreadBytes  int64                    // trim thread payloads larger than this (0 = unlimited)
+	NormalizeScores string                   // rescale rank scores session-wide: none, percentile, zscore (default none)
+	TournamentSize  int                      // reorder the top N entries by head-to-head comparison (0 = off)
+	MaxEntries      int                      // per-thread entry cap, overriding the form's (0 = form's or default)
+	Cost            func() float64           // agent spend so far in USD, nil if the backend doesn't report it
+	LLMCalls        func() int               // agent calls so far, nil if the backend doesn't report them
+	SourceRequests  func() map[string]int    // non-Reddit requests so far by source, nil if not counted
+	Pipeline        *Pipeline                // phases to run, in order (nil = DefaultPhases)
+	DiscoveryRounds int                      // thread discovery rounds before giving up on the limit (0 = 3)
+	Overprovision   float64                  // threads discovered per thread wanted (0 = adaptive, from 3)
+	MaxAttempts     int                      // failed threads are retried on resume until quarantined after this many failures (0 = retry forever)
+	Settings        map[string]string        // run flags to record in the manifest, nil keeps the recorded ones
+
+	// SubredditReviewFile, when set, receives the discovered subreddits for
+	// editing and the run stops with ErrAwaitingReview; resuming reads it back
+	SubredditReviewFile string
+	// ReviewSubreddits, when set, is asked to confirm or edit the discovered
+	// subreddits before thread discovery
+	ReviewSubreddits func(discovered []string) ([]string, error)
+	OnPhaseStart     func(phaseName string)
+
+	// Checkpoint, when set, pauses the pipeline once this many threads are
+	// extracted and asks OnCheckpoint, given the extracted threads and the
+	// current thread limit, whether to go on. It needs Control.
+	Checkpoint   int
+	OnCheckpoint func(extracted []types.ThreadState, limit int) CheckpointDecision
+
+	// Control, when set, serves pause/resume/cancel requests on a socket in
+	// the session directory (see runs pause/resume/cancel)
+	Control *control.Controller
 }
 
 // Orchestrator defines the interface for running extraction pipelines
@@ -0,0 +1,147 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"hiveminer/internal/search"
+	"hiveminer/pkg/types"
+)
+
+const (
+	// megathreadThreshold is the comment count above which a thread is fetched
+	// with top-sorted pagination instead of a single default-sorted page
+	megathreadThreshold = 500
+
+	defaultCommentBudget = 1000
+	defaultChunkSize     = 200
+)
+
+// isMegathread reports whether a thread is large enough to need paged fetching
+func isMegathread(ts types.ThreadState) bool {
+	return ts.Source == "" && ts.NumComments > megathreadThreshold
+}
+
+func commentBudget(config RunConfig) int {
+	if config.CommentBudget > 0 {
+		return config.CommentBudget
+	}
+	return defaultCommentBudget
+}
+
+func chunkSize(config RunConfig) int {
+	if config.ChunkSize > 0 {
+		return config.ChunkSize
+	}
+	return defaultChunkSize
+}
+
+// fetchMegathread fetches a large thread sorted by top with continued pagination
+// up to the configured comment budget. Falls back to a single page when the
+// searcher cannot paginate.
+func (o *DefaultOrchestrator) fetchMegathread(ctx context.Context, config RunConfig, ts types.ThreadState) (*types.Thread, error) {
+	pf, ok := o.searcher.(search.PagedThreadFetcher)
+	if !ok {
+		return o.searcher.GetThread(ctx, ts.Permalink, threadFetchLimit(config))
+	}
+	return pf.GetThreadPaged(ctx, ts.Permalink, search.ThreadOptions{
+		Sort:          megathreadSort(config),
+		CommentBudget: commentBudget(config),
+	})
+}
+
+// megathreadSort is the run's comment sort, or top: with only the budget's
+// worth of comments fetched, the most upvoted are the ones worth having
+func megathreadSort(config RunConfig) string {
+	if config.CommentSort != "" {
+		return config.CommentSort
+	}
+	return "top"
+}
+
+// threadFetchLimit is the comments requested for a regular thread: a full
+// page when a truncation strategy will prune it, else the flat 100
+func threadFetchLimit(config RunConfig) int {
+	if config.Truncation.Name == "score" {
+		return 500
+	}
+	return 100
+}
+
+// truncateThread applies the run's truncation strategy to a thread's comments
+func truncateThread(config RunConfig, thread *types.Thread) *types.Thread {
+	pruned := *thread
+	pruned.Comments = search.TruncateComments(thread.Comments, config.Truncation)
+	return &pruned
+}
+
+// needsPagedRefetch reports whether a saved megathread payload holds far fewer
+// comments than the budget allows (e.g. the evaluator saved a single page).
+func needsPagedRefetch(config RunConfig, ts types.ThreadState, thread *types.Thread) bool {
+	if !isMegathread(ts) {
+		return false
+	}
+	want := commentBudget(config)
+	if ts.NumComments < want {
+		want = ts.NumComments
+	}
+	return search.CountComments(thread.Comments) < want/2
+}
+
+// extractChunked runs extraction over a large thread in chunks of top-level comment
+// trees so each call stays within the chunk size, then concatenates the entries.
+func (o *DefaultOrchestrator) extractChunked(ctx context.Context, config RunConfig, thread *types.Thread, output io.Writer) (*types.ExtractionResult, error) {
+	chunks := chunkThread(thread, chunkSize(config))
+	if len(chunks) <= 1 {
+		return o.extractWithinBudget(ctx, config, thread, output)
+	}
+
+	merged := &types.ExtractionResult{}
+	var failures int
+	var lastErr error
+	for i, chunk := range chunks {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		result, err := o.extractWithinBudget(ctx, config, chunk, output)
+		if err != nil {
+			failures++
+			lastErr = fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err)
+			continue
+		}
+		merged.Entries = append(merged.Entries, result.Entries...)
+		merged.PromptTokens += result.PromptTokens
+		merged.Summarized += result.Summarized
+	}
+	if failures == len(chunks) {
+		return nil, lastErr
+	}
+	return merged, nil
+}
+
+// chunkThread splits a thread into copies holding consecutive top-level comment
+// trees of at most size comments each (a single oversized tree gets its own chunk).
+func chunkThread(thread *types.Thread, size int) []*types.Thread {
+	if search.CountComments(thread.Comments) <= size {
+		return []*types.Thread{thread}
+	}
+
+	var chunks []*types.Thread
+	current := &types.Thread{Post: thread.Post}
+	count := 0
+	for _, c := range thread.Comments {
+		n := search.CountComments([]*types.Comment{c})
+		if count > 0 && count+n > size {
+			chunks = append(chunks, current)
+			current = &types.Thread{Post: thread.Post}
+			count = 0
+		}
+		current.Comments = append(current.Comments, c)
+		count += n
+	}
+	if len(current.Comments) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
@@ -0,0 +1,111 @@
+package orchestrator
+
+import (
+	"math"
+	"sort"
+
+	"hiveminer/pkg/types"
+)
+
+// ScoreNormalizations lists the supported --normalize-scores methods
+var ScoreNormalizations = []string{"none", "percentile", "zscore"}
+
+// rankedRef points at a scored entry in the manifest
+type rankedRef struct {
+	entry    *types.Entry
+	postID   string
+	index    int
+	raw      float64
+	evidence int
+	latest   float64 // newest evidence creation time (Unix seconds)
+}
+
+// normalizeScores orders every scored entry in the session deterministically
+// and, unless method is "none", rescales their raw ranker scores so entries
+// ranked in different batches or refreshes are comparable. Ties on raw score
+// are broken by evidence count, then by the recency of the newest evidence,
+// then by thread and entry position.
+func normalizeScores(manifest *types.Manifest, method string) {
+	var refs []rankedRef
+	for i := range manifest.Threads {
+		ts := &manifest.Threads[i]
+		for j := range ts.Entries {
+			entry := &ts.Entries[j]
+			if entry.RankScore == nil {
+				continue
+			}
+			// Entries ranked before normalization existed: their score is raw
+			if entry.RawScore == nil {
+				raw := *entry.RankScore
+				entry.RawScore = &raw
+			}
+			ref := rankedRef{entry: entry, postID: ts.PostID, index: j, raw: *entry.RawScore}
+			for _, fv := range entry.Fields {
+				ref.evidence += len(fv.Evidence)
+				for _, ev := range fv.Evidence {
+					ref.latest = math.Max(ref.latest, ev.Created)
+				}
+			}
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) == 0 {
+		return
+	}
+
+	sort.SliceStable(refs, func(i, j int) bool {
+		a, b := refs[i], refs[j]
+		switch {
+		case a.raw != b.raw:
+			return a.raw > b.raw
+		case a.evidence != b.evidence:
+			return a.evidence > b.evidence
+		case a.latest != b.latest:
+			return a.latest > b.latest
+		case a.postID != b.postID:
+			return a.postID < b.postID
+		default:
+			return a.index < b.index
+		}
+	})
+
+	var mean, sd float64
+	if method == "zscore" {
+		for _, r := range refs {
+			mean += r.raw
+		}
+		mean /= float64(len(refs))
+		for _, r := range refs {
+			sd += (r.raw - mean) * (r.raw - mean)
+		}
+		sd = math.Sqrt(sd / float64(len(refs)))
+	}
+
+	for i, r := range refs {
+		r.entry.RankOrder = i + 1
+		r.entry.TournamentRank = 0
+		score := r.raw
+		switch method {
+		case "percentile":
+			score = 100
+			if len(refs) > 1 {
+				// Share of other entries scoring strictly lower; equal raw
+				// scores get equal percentiles
+				below := 0
+				for _, other := range refs {
+					if other.raw < r.raw {
+						below++
+					}
+				}
+				score = 100 * float64(below) / float64(len(refs)-1)
+			}
+		case "zscore":
+			// Map to a 0-100 scale centered on 50, 15 points per standard deviation
+			score = 50
+			if sd > 0 {
+				score = math.Max(0, math.Min(100, 50+15*(r.raw-mean)/sd))
+			}
+		}
+		r.entry.RankScore = &score
+	}
+}
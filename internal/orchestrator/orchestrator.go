@@ -3,18 +3,31 @@ package orchestrator
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"hiveminer/internal/agent"
+	"hiveminer/internal/control"
+	"hiveminer/internal/enrich"
+	"hiveminer/internal/geocode"
+	"hiveminer/internal/postprocess"
+	"hiveminer/internal/ratelimit"
+	"hiveminer/internal/reviews"
 	"hiveminer/internal/schema"
 	"hiveminer/internal/search"
 	"hiveminer/internal/session"
+	"hiveminer/internal/transcripts"
+	"hiveminer/internal/webpage"
 	"hiveminer/pkg/types"
 )
 
@@ -26,6 +39,14 @@ type DefaultOrchestrator struct {
 	threadDiscoverer agent.ThreadDiscoverer
 	threadEvaluator  agent.ThreadEvaluator
 	ranker           agent.Ranker
+	summarizer       agent.Summarizer
+	verifier         agent.Verifier
+	geocoder         geocode.Geocoder
+	reviews          reviews.Fetcher
+	transcripts      transcripts.Fetcher
+	pages            webpage.Fetcher
+	resolvers        []enrich.Resolver
+	guard            *diskGuard
 }
 
 func emitPhase(config RunConfig, phaseName string) {
@@ -41,6 +62,11 @@ func New(searcher search.Searcher) *DefaultOrchestrator {
 	}
 }
 
+// SetSummarizer sets the summarizer used for threads over the context budget
+func (o *DefaultOrchestrator) SetSummarizer(s agent.Summarizer) {
+	o.summarizer = s
+}
+
 // SetExtractor sets the extractor to use
 func (o *DefaultOrchestrator) SetExtractor(e agent.Extractor) {
 	o.extractor = e
@@ -70,10 +96,26 @@ func (o *DefaultOrchestrator) SetRanker(r agent.Ranker) {
 func (o *DefaultOrchestrator) Run(ctx context.Context, config RunConfig) (string, error) {
 	// Create session directory
 	slug := session.GenerateSlugFromQuery(config.Query)
-	if config.Query == "" && len(config.Subreddits) > 0 {
+	if config.User != "" {
+		slug = session.GenerateSlug("u " + config.User)
+	} else if config.Query == "" && len(config.Subreddits) > 0 {
 		slug = session.GenerateSlug(config.Subreddits[0])
 	}
-	sessionDir := filepath.Join(config.OutputDir, slug)
+	if config.Name != "" {
+		slug = config.Name
+	}
+	sessionDir := config.SessionDir
+	if sessionDir == "" {
+		parent, err := session.NewSessionParent(config.OutputDir, config.Form.Title)
+		if err != nil {
+			return "", err
+		}
+		sessionDir = filepath.Join(parent, slug)
+	}
+	o.guard = newDiskGuard(config, sessionDir)
+	if config.CommentSort == "" && config.Form != nil {
+		config.CommentSort = config.Form.CommentSort
+	}
 
 	// Check for existing session or create new
 	manifest, err := session.LoadManifest(sessionDir)
@@ -81,6 +123,7 @@ func (o *DefaultOrchestrator) Run(ctx context.Context, config RunConfig) (string
 		return "", fmt.Errorf("loading manifest: %w", err)
 	}
 
+	resumed := manifest != nil
 	if manifest == nil {
 		// Create new session
 		formHash, err := schema.HashForm(config.Form)
@@ -98,94 +141,393 @@ func (o *DefaultOrchestrator) Run(ctx context.Context, config RunConfig) (string
 		fmt.Printf("Creating new session: %s\n", sessionDir)
 	} else {
 		fmt.Printf("Resuming session: %s\n", sessionDir)
+		if unknown := session.UnknownStatuses(manifest); len(unknown) > 0 {
+			fmt.Printf("Warning: threads with statuses this version doesn't know are left alone: %s\n", session.FormatUnknownStatuses(unknown))
+		}
+		refreshPinned(manifest)
+		session.SettleEntryIDs(manifest, schema.PrimaryField(config.Form))
+		retryFailed(manifest, sessionDir, config.MaxAttempts)
+	}
+
+	if config.Settings != nil {
+		manifest.Settings = config.Settings
 	}
 
 	// Start run log
 	invocationID := fmt.Sprintf("run-%d", time.Now().Unix())
 	session.StartRun(manifest, invocationID)
+	if config.Truncation.Name != "" {
+		manifest.Runs[len(manifest.Runs)-1].Truncation = &config.Truncation
+	}
+
+	// Log Reddit requests for accounting and the request budget. Agents fetch
+	// through hiveminer subprocesses, which inherit the log via the environment.
+	var requestLog string
+	if rl, ok := o.searcher.(requestLogger); ok {
+		requestLog = filepath.Join(sessionDir, "requests", invocationID+".log")
+		if err := os.MkdirAll(filepath.Dir(requestLog), 0755); err != nil {
+			return "", fmt.Errorf("creating request log dir: %w", err)
+		}
+		rl.SetRequestLog(requestLog)
+		os.Setenv("HIVEMINER_REQUEST_LOG", requestLog)
+	}
+
+	// Refuse to run a session another process is already running
+	if config.Control != nil {
+		if err := config.Control.Listen(sessionDir); err != nil {
+			if errors.Is(err, control.ErrRunning) {
+				return "", err
+			}
+			fmt.Printf("Warning: pause/cancel unavailable: %v\n", err)
+		} else {
+			defer config.Control.Close()
+		}
+	}
+
+	// Only the process running the session may clean up after a crash. The
+	// manifest as the last run left it is kept, for runs rollback.
+	if resumed {
+		if err := session.BackupSession(sessionDir); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+		checkIntegrity(sessionDir, manifest)
+	}
+
+	// From here the manifest is saved through the store. Phases with
+	// concurrent workers (the pipeline and ranking) also read and change it
+	// through the store; phases that run alone use it directly in between.
+	store := session.NewStore(sessionDir, manifest)
 
 	// Save initial manifest
-	if err := session.SaveManifest(sessionDir, manifest); err != nil {
+	if err := store.Save(); err != nil {
 		return "", fmt.Errorf("saving manifest: %w", err)
 	}
+	defer func() {
+		if err := session.RefreshFormIndex(sessionDir); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}()
 
 	runStart := time.Now()
+	clock := newPhaseClock(config, manifest)
 
-	// Phase 0: Subreddit Discovery
-	if config.Query != "" && len(config.Subreddits) == 0 {
-		if manifest.DiscoveredSubreddits && len(manifest.Subreddits) > 0 {
-			fmt.Printf("Reusing %d previously discovered subreddits\n", len(manifest.Subreddits))
-			config.Subreddits = manifest.Subreddits
-		} else {
-			emitPhase(config, "subreddit-discovery")
-			fmt.Println("\n=== Phase 0: Subreddit Discovery ===")
-			phase0Start := time.Now()
-			if o.discoverer != nil {
-				discovered, err := o.discoverer.DiscoverSubreddits(ctx, config.Form, config.Query)
-				if err != nil {
-					fmt.Printf("  Warning: subreddit discovery failed: %v\n", err)
-					fmt.Println("  Falling back to searching all of Reddit")
-				} else if len(discovered) > 0 {
-					fmt.Printf("Discovered %d subreddits:\n", len(discovered))
-					for _, name := range discovered {
-						fmt.Printf("  r/%s\n", name)
+	// Run the pipeline's phases in order
+	var totalProcessed int
+	phaseList := config.phases()
+	for i := 0; i < len(phaseList); i++ {
+		if err := config.Control.Wait(ctx); err != nil {
+			session.CompleteRun(manifest, "interrupted", totalProcessed)
+			store.Save()
+			return sessionDir, err
+		}
+
+		switch phaseList[i] {
+		case PhaseUserHistory:
+			// User-history mode: the user's submissions and comments replace discovery
+			if config.User == "" {
+				continue
+			}
+			fmt.Printf("\n=== User History: u/%s ===\n", config.User)
+			userStart := time.Now()
+			added, err := o.mineUser(ctx, config, manifest, sessionDir)
+			if err != nil {
+				return "", fmt.Errorf("mining user history: %w", err)
+			}
+			if err := store.Save(); err != nil {
+				return "", fmt.Errorf("saving manifest: %w", err)
+			}
+			fmt.Printf("  Added %d threads (%s)\n", added, formatDuration(time.Since(userStart)))
+			clock.record(PhaseUserHistory, userStart, added, 0)
+
+		case PhaseSubredditDiscovery:
+			// Phase 0: Subreddit Discovery
+			if config.User != "" || config.Query == "" || len(config.Subreddits) > 0 {
+				continue
+			}
+			if manifest.DiscoveredSubreddits && len(manifest.Subreddits) > 0 {
+				fmt.Printf("Reusing %d previously discovered subreddits\n", len(manifest.Subreddits))
+			} else {
+				emitPhase(config, "subreddit-discovery")
+				fmt.Println("\n=== Phase 0: Subreddit Discovery ===")
+				phase0Start := time.Now()
+				if o.discoverer != nil {
+					if ya, ok := o.discoverer.(yieldAware); ok {
+						if past := pastYield(config, sessionDir); len(past) > 0 {
+							ya.SetYieldNotes(session.YieldNotes(past))
+						}
 					}
-					config.Subreddits = discovered
-					manifest.Subreddits = discovered
-					manifest.DiscoveredSubreddits = true
-					if err := session.SaveManifest(sessionDir, manifest); err != nil {
-						return "", fmt.Errorf("saving manifest: %w", err)
+					discovered, err := o.discoverer.DiscoverSubreddits(ctx, config.Form, config.Query)
+					if err != nil {
+						fmt.Printf("  Warning: subreddit discovery failed: %v\n", err)
+						fmt.Println("  Falling back to searching all of Reddit")
+					} else if len(discovered) > 0 {
+						fmt.Printf("Discovered %d subreddits:\n", len(discovered))
+						for _, name := range discovered {
+							fmt.Printf("  r/%s\n", name)
+						}
+						manifest.Subreddits = discovered
+						manifest.DiscoveredSubreddits = true
+						if err := store.Save(); err != nil {
+							return "", fmt.Errorf("saving manifest: %w", err)
+						}
 					}
 				}
+				fmt.Printf("  Phase 0 completed in %s\n", formatDuration(time.Since(phase0Start)))
+				clock.record(PhaseSubredditDiscovery, phase0Start, 0, 0)
+			}
+			if !manifest.DiscoveredSubreddits || len(manifest.Subreddits) == 0 {
+				continue
 			}
-			fmt.Printf("  Phase 0 completed in %s\n", formatDuration(time.Since(phase0Start)))
-		}
-	}
 
-	// Phases 1+2+3: Streaming pipeline — discover threads and evaluate+extract in parallel
-	pipelineStart := time.Now()
-	totalProcessed, err := o.runPipeline(ctx, config, manifest, sessionDir)
-	if err != nil {
-		if ctx.Err() != nil {
-			session.CompleteRun(manifest, "interrupted", totalProcessed)
-			session.SaveManifest(sessionDir, manifest)
-			return sessionDir, ctx.Err()
-		}
-		return "", err
-	}
+			// Let the user confirm or edit the list before it's searched
+			if err := reviewSubreddits(config, manifest); err != nil {
+				if errors.Is(err, ErrAwaitingReview) {
+					session.CompleteRun(manifest, "interrupted", totalProcessed)
+					store.Save()
+					return sessionDir, err
+				}
+				return "", err
+			}
+			config.Subreddits = manifest.Subreddits
+			if err := store.Save(); err != nil {
+				return "", fmt.Errorf("saving manifest: %w", err)
+			}
 
-	fmt.Printf("  Pipeline completed in %s\n", formatDuration(time.Since(pipelineStart)))
+		case PhaseWiki:
+			// Wiki & sidebar mining: feed dense subreddit reference pages into extraction
+			if !config.MineWiki || len(config.Subreddits) == 0 {
+				continue
+			}
+			fmt.Println("\n=== Wiki & Sidebar Mining ===")
+			wikiStart := time.Now()
+			added, err := o.mineWiki(ctx, config, manifest, sessionDir)
+			if err != nil && ctx.Err() == nil {
+				fmt.Printf("  Warning: wiki mining failed: %v\n", err)
+			}
+			if added > 0 {
+				if err := store.Save(); err != nil {
+					return "", fmt.Errorf("saving manifest: %w", err)
+				}
+			}
+			fmt.Printf("  Added %d wiki/sidebar pages (%s)\n", added, formatDuration(time.Since(wikiStart)))
+			clock.record(PhaseWiki, wikiStart, added, 0)
 
-	if ctx.Err() != nil {
-		session.CompleteRun(manifest, "interrupted", totalProcessed)
-		session.SaveManifest(sessionDir, manifest)
-		return sessionDir, ctx.Err()
-	}
+		case PhaseReviews:
+			// Review ingestion: each product's marketplace reviews become a thread
+			if len(config.Reviews) == 0 || o.reviews == nil {
+				continue
+			}
+			fmt.Println("\n=== Product Reviews ===")
+			reviewsStart := time.Now()
+			added, err := o.mineReviews(ctx, config, manifest, sessionDir)
+			if err != nil && ctx.Err() == nil {
+				fmt.Printf("  Warning: review ingestion failed: %v\n", err)
+			}
+			if added > 0 {
+				if err := store.Save(); err != nil {
+					return "", fmt.Errorf("saving manifest: %w", err)
+				}
+			}
+			fmt.Printf("  Added %d products' reviews (%s)\n", added, formatDuration(time.Since(reviewsStart)))
+			clock.record(PhaseReviews, reviewsStart, added, 0)
+
+		case PhaseTranscripts:
+			// Transcript ingestion: podcasts and videos become threads of timestamped segments
+			if len(config.Transcripts) == 0 || o.transcripts == nil {
+				continue
+			}
+			fmt.Println("\n=== Transcripts ===")
+			transcriptsStart := time.Now()
+			added, err := o.mineTranscripts(ctx, config, manifest, sessionDir)
+			if err != nil && ctx.Err() == nil {
+				fmt.Printf("  Warning: transcript ingestion failed: %v\n", err)
+			}
+			if added > 0 {
+				if err := store.Save(); err != nil {
+					return "", fmt.Errorf("saving manifest: %w", err)
+				}
+			}
+			fmt.Printf("  Added %d transcripts (%s)\n", added, formatDuration(time.Since(transcriptsStart)))
+			clock.record(PhaseTranscripts, transcriptsStart, added, 0)
+
+		case PhaseWeb:
+			// Web pages: articles and listicles become comment-less threads
+			if len(config.WebPages) == 0 || o.pages == nil {
+				continue
+			}
+			fmt.Println("\n=== Web Pages ===")
+			webStart := time.Now()
+			added, err := o.mineWebPages(ctx, config, manifest, sessionDir)
+			if err != nil && ctx.Err() == nil {
+				fmt.Printf("  Warning: web page ingestion failed: %v\n", err)
+			}
+			if added > 0 {
+				if err := store.Save(); err != nil {
+					return "", fmt.Errorf("saving manifest: %w", err)
+				}
+			}
+			fmt.Printf("  Added %d web pages (%s)\n", added, formatDuration(time.Since(webStart)))
+			clock.record(PhaseWeb, webStart, added, 0)
+
+		case PhaseThreadDiscovery, PhaseEvaluate, PhaseExtract:
+			// Phases 1+2+3: Streaming pipeline — discover threads and
+			// evaluate+extract in parallel. The pipeline's other collection
+			// phases run in this same stage.
+			for i+1 < len(phaseList) && slices.Contains(collectPhases, phaseList[i+1]) {
+				i++
+			}
+			pipelineStart := time.Now()
+			processed, err := o.runPipeline(ctx, config, store, sessionDir)
+			totalProcessed += processed
+			if err != nil {
+				if ctx.Err() != nil {
+					clock.record("collect", pipelineStart, processed, 0)
+					session.CompleteRun(manifest, "interrupted", totalProcessed)
+					store.Save()
+					return sessionDir, ctx.Err()
+				}
+				return "", err
+			}
+
+			fmt.Printf("  Pipeline completed in %s\n", formatDuration(time.Since(pipelineStart)))
+			clock.record("collect", pipelineStart, processed, 0)
 
-	// Phase 4: Rank all extracted entries
-	if o.ranker != nil {
-		emitPhase(config, "ranking")
-		fmt.Println("\n=== Phase 4: Ranking ===")
-		phase4Start := time.Now()
-		ranked, err := o.rankEntries(ctx, config, manifest, sessionDir)
-		if err != nil {
 			if ctx.Err() != nil {
 				session.CompleteRun(manifest, "interrupted", totalProcessed)
-				session.SaveManifest(sessionDir, manifest)
+				store.Save()
 				return sessionDir, ctx.Err()
 			}
-			fmt.Printf("  Warning: ranking failed: %v\n", err)
-			fmt.Println("  Continuing without ranking")
-		} else {
-			fmt.Printf("  Ranked %d entries (%s)\n", ranked, formatDuration(time.Since(phase4Start)))
+
+		case PhaseVerify:
+			// Check entries against their threads before they're ranked
+			if o.verifier == nil {
+				continue
+			}
+			emitPhase(config, PhaseVerify)
+			fmt.Println("\n=== Verification ===")
+			verifyStart := time.Now()
+			verified, unsupported, err := o.verifyEntries(ctx, config, store, sessionDir)
+			if err != nil && ctx.Err() != nil {
+				session.CompleteRun(manifest, "interrupted", totalProcessed)
+				store.Save()
+				return sessionDir, ctx.Err()
+			}
+			if err := store.Save(); err != nil {
+				return "", fmt.Errorf("saving manifest: %w", err)
+			}
+			fmt.Printf("  Verified %d entries, %d unsupported or not meant seriously (%s)\n", verified, unsupported, formatDuration(time.Since(verifyStart)))
+			clock.record(PhaseVerify, verifyStart, 0, verified)
+
+		case PhaseGeocode:
+			// Resolve location fields to coordinates
+			if o.geocoder == nil {
+				continue
+			}
+			emitPhase(config, PhaseGeocode)
+			fmt.Println("\n=== Geocoding ===")
+			geocodeStart := time.Now()
+			located, missed, err := o.geocodeEntries(ctx, config, store, sessionDir)
+			if err != nil {
+				if ctx.Err() != nil {
+					session.CompleteRun(manifest, "interrupted", totalProcessed)
+					store.Save()
+					return sessionDir, ctx.Err()
+				}
+				fmt.Printf("  Warning: geocoding stopped: %v\n", err)
+			}
+			if err := store.Save(); err != nil {
+				return "", fmt.Errorf("saving manifest: %w", err)
+			}
+			fmt.Printf("  Located %d places, %d not found (%s)\n", located, missed, formatDuration(time.Since(geocodeStart)))
+			clock.record(PhaseGeocode, geocodeStart, 0, 0)
+
+		case PhaseEnrich:
+			// Check entries against outside sources (retailer prices and stock)
+			if len(o.resolvers) == 0 {
+				continue
+			}
+			emitPhase(config, PhaseEnrich)
+			fmt.Println("\n=== Enrichment ===")
+			enrichStart := time.Now()
+			enriched, failed, err := o.enrichEntries(ctx, config, store)
+			if err != nil && ctx.Err() != nil {
+				session.CompleteRun(manifest, "interrupted", totalProcessed)
+				store.Save()
+				return sessionDir, ctx.Err()
+			}
+			if err := store.Save(); err != nil {
+				return "", fmt.Errorf("saving manifest: %w", err)
+			}
+			fmt.Printf("  Enriched %d entries, %d lookups failed (%s)\n", enriched, failed, formatDuration(time.Since(enrichStart)))
+			clock.record(PhaseEnrich, enrichStart, 0, 0)
+
+		case PhaseRank:
+			// Phase 4: Rank all extracted entries
+			if o.ranker == nil {
+				continue
+			}
+			emitPhase(config, "ranking")
+			fmt.Println("\n=== Phase 4: Ranking ===")
+			phase4Start := time.Now()
+			ranked, err := o.rankEntries(ctx, config, store)
+			if err != nil {
+				if ctx.Err() != nil {
+					session.CompleteRun(manifest, "interrupted", totalProcessed)
+					store.Save()
+					return sessionDir, ctx.Err()
+				}
+				fmt.Printf("  Warning: ranking failed: %v\n", err)
+				fmt.Println("  Continuing without ranking")
+			} else {
+				fmt.Printf("  Ranked %d entries (%s)\n", ranked, formatDuration(time.Since(phase4Start)))
+			}
+			clock.record(PhaseRank, phase4Start, 0, ranked)
+
+		default:
+			// Custom phase run by a plugin executable
+			phase := config.Pipeline.Phase(phaseList[i])
+			if phase == nil || phase.Plugin == "" {
+				continue
+			}
+			emitPhase(config, phase.Name)
+			fmt.Printf("\n=== Plugin: %s ===\n", phase.Name)
+			pluginStart := time.Now()
+			updated, err := o.runPlugin(ctx, config, phase, store, sessionDir)
+			if err != nil {
+				if ctx.Err() != nil {
+					session.CompleteRun(manifest, "interrupted", totalProcessed)
+					store.Save()
+					return sessionDir, ctx.Err()
+				}
+				fmt.Printf("  Warning: %v\n", err)
+				fmt.Println("  Continuing with entries unchanged")
+			} else {
+				if err := store.Save(); err != nil {
+					return "", fmt.Errorf("saving manifest: %w", err)
+				}
+				fmt.Printf("  Updated %d threads (%s)\n", updated, formatDuration(time.Since(pluginStart)))
+			}
+			clock.record(phase.Name, pluginStart, updated, 0)
 		}
 	}
 
 	// Complete run
+	recordYield(config, manifest, sessionDir)
 	session.CompleteRun(manifest, "completed", totalProcessed)
-	if err := session.SaveManifest(sessionDir, manifest); err != nil {
+	var requests search.RequestStats
+	if requestLog != "" {
+		requests, _ = search.ReadRequestLog(requestLog)
+		manifest.Runs[len(manifest.Runs)-1].Requests = requests
+	}
+	bySource := sourceRequests(config, requests)
+	manifest.Runs[len(manifest.Runs)-1].SourceRequests = bySource
+	if err := store.Save(); err != nil {
 		return "", fmt.Errorf("saving final manifest: %w", err)
 	}
+	summary := buildSummary(config, manifest, sessionDir)
+	if err := writeSummary(sessionDir, summary); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
 
 	// Print summary
 	totalDuration := time.Since(runStart)
@@ -193,15 +535,88 @@ func (o *DefaultOrchestrator) Run(ctx context.Context, config RunConfig) (string
 	fmt.Printf("\n=== Complete (%s) ===\n", formatDuration(totalDuration))
 	fmt.Printf("Session: %s\n", sessionDir)
 	fmt.Printf("Threads: %d total\n", len(manifest.Threads))
-	fmt.Printf("  - Ranked: %d\n", counts["ranked"])
-	fmt.Printf("  - Extracted: %d\n", counts["extracted"])
-	fmt.Printf("  - Collected: %d\n", counts["collected"])
-	fmt.Printf("  - Skipped: %d\n", counts["skipped"])
-	fmt.Printf("  - Failed: %d\n", counts["failed"])
+	fmt.Printf("  - Ranked: %d\n", counts[types.StatusRanked])
+	fmt.Printf("  - Extracted: %d\n", counts[types.StatusExtracted])
+	fmt.Printf("  - Collected: %d\n", counts[types.StatusCollected])
+	fmt.Printf("  - Skipped: %d\n", counts[types.StatusSkipped])
+	for _, line := range session.FormatSkipReasons(summary.SkipReasons, summarySkipReasons) {
+		fmt.Printf("      %s\n", line)
+	}
+	if failures := session.CountFailures(manifest); len(failures) > 0 {
+		fmt.Printf("  - Failed: %d (%s)\n", counts[types.StatusFailed], FormatFailures(failures))
+		fmt.Println("    Retry with: hiveminer runs retry <run-id> [--code <code>]")
+	} else {
+		fmt.Printf("  - Failed: %d\n", counts[types.StatusFailed])
+	}
+	if counts[types.StatusQuarantined] > 0 {
+		fmt.Printf("  - Quarantined: %d (failed %d times; release with runs retry --quarantined)\n", counts[types.StatusQuarantined], config.MaxAttempts)
+	}
+	if unknown := session.UnknownStatuses(manifest); len(unknown) > 0 {
+		fmt.Printf("  - Unknown status: %s\n", session.FormatUnknownStatuses(unknown))
+	}
+	if requests != nil {
+		fmt.Printf("Reddit requests: %s\n", requests)
+	}
+	if len(bySource) > 1 {
+		fmt.Printf("Requests by source: %s\n", ratelimit.FormatCounts(bySource))
+	}
+	if len(manifest.Runs) > 0 && manifest.Runs[len(manifest.Runs)-1].Ranking != nil {
+		for _, line := range RankingSummaryLines(manifest.Runs[len(manifest.Runs)-1].Ranking) {
+			fmt.Println(line)
+		}
+	}
 
 	return sessionDir, nil
 }
 
+// sourceRequests merges the run's Reddit requests with those counted for
+// other sources
+func sourceRequests(config RunConfig, reddit search.RequestStats) map[string]int {
+	counts := map[string]int{}
+	if config.SourceRequests != nil {
+		for source, n := range config.SourceRequests() {
+			if n > 0 {
+				counts[source] = n
+			}
+		}
+	}
+	if total := reddit.Total(); total > 0 {
+		counts[ratelimit.SourceReddit] = total
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+	return counts
+}
+
+// requestLogger is an optional interface for searchers that can log their requests
+type requestLogger interface {
+	SetRequestLog(path string)
+}
+
+// assessmentAuditor is an optional interface for rankers that report
+// assessments they rejected
+type assessmentAuditor interface {
+	MalformedAssessments() []string
+}
+
+// batchReporter is an optional interface for rankers that assess entries in
+// batches and report how each was scored
+type batchReporter interface {
+	RankBatches() []types.RankBatch
+}
+
+// budgetedSearcher is an optional interface for searchers with a request budget
+type budgetedSearcher interface {
+	BudgetExhausted() bool
+}
+
+// budgetExhausted reports whether the searcher's request budget is spent
+func (o *DefaultOrchestrator) budgetExhausted() bool {
+	bs, ok := o.searcher.(budgetedSearcher)
+	return ok && bs.BudgetExhausted()
+}
+
 // outputExtractor is an optional interface for extractors that support directing output to a writer
 type outputExtractor interface {
 	ExtractFieldsWithOutput(ctx context.Context, thread *types.Thread, form *types.Form, output io.Writer) (*types.ExtractionResult, error)
@@ -236,7 +651,7 @@ type workItem struct {
 // runPipeline executes the streaming discovery + evaluate + extract pipeline.
 // Workers run continuously while discovery feeds them threads across multiple rounds.
 // Manifest saves are batched via a periodic saver instead of per-update.
-func (o *DefaultOrchestrator) runPipeline(ctx context.Context, config RunConfig, manifest *types.Manifest, sessionDir string) (int, error) {
+func (o *DefaultOrchestrator) runPipeline(ctx context.Context, config RunConfig, store *session.Store, sessionDir string) (int, error) {
 	if o.extractor == nil {
 		return 0, fmt.Errorf("no extractor configured")
 	}
@@ -258,17 +673,50 @@ func (o *DefaultOrchestrator) runPipeline(ctx context.Context, config RunConfig,
 	defer logFile.Close()
 	logWriter := &syncWriter{w: logFile}
 
+	var runID string
+	store.View(func(m *types.Manifest) {
+		if len(m.Runs) > 0 {
+			runID = m.Runs[len(m.Runs)-1].InvocationID
+		}
+	})
+
+	// Per-form skip list: threads rejected in earlier sessions aren't
+	// rediscovered. It is only changed inside store updates, which serialize it.
+	var skipList *types.SkipList
+	if !config.IgnoreSkipList {
+		skipList, err = session.LoadSkipList(config.OutputDir, config.Form.Title)
+		if err != nil {
+			fmt.Printf("  Warning: %v\n", err)
+		}
+	}
+	var skipsAdded int
+
+	// Subreddit yield in the form's earlier sessions steers discovery
+	past := pastYield(config, sessionDir)
+
 	var (
-		mu        sync.Mutex // protects manifest and processed
 		wg        sync.WaitGroup
-		processed int
+		processed atomic.Int64
 		extracted atomic.Int64
 		done      atomic.Int64
 		totalFed  atomic.Int64
+		limit     atomic.Int64 // thread limit, which a checkpoint may change
 	)
+	limit.Store(int64(config.Limit))
+
+	// enough reports whether the run has what it came for: the target's
+	// worth of entries when one is set, else the limit's worth of threads
+	enough := func() bool {
+		if config.TargetEntries > 0 {
+			var outlook EntryOutlook
+			store.View(func(m *types.Manifest) { outlook = Outlook(m) })
+			return outlook.Extracted >= config.TargetEntries
+		}
+		counts := store.CountByStatus()
+		return counts[types.StatusExtracted]+counts[types.StatusRanked] >= int(limit.Load())
+	}
 
 	// Periodic manifest saver — batches disk writes instead of saving on every update
-	dirty := &atomic.Bool{}
 	saveCtx, saveCancel := context.WithCancel(context.Background())
 	saveDone := make(chan struct{})
 	go func() {
@@ -278,160 +726,242 @@ func (o *DefaultOrchestrator) runPipeline(ctx context.Context, config RunConfig,
 		for {
 			select {
 			case <-ticker.C:
-				if dirty.CompareAndSwap(true, false) {
-					mu.Lock()
-					session.SaveManifest(sessionDir, manifest)
-					mu.Unlock()
-				}
+				o.guard.check(store)
+				store.SaveIfDirty()
 			case <-saveCtx.Done():
-				mu.Lock()
-				session.SaveManifest(sessionDir, manifest)
-				mu.Unlock()
+				store.Save()
 				return
 			}
 		}
 	}()
-	markDirty := func() { dirty.Store(true) }
 
 	// Work channel — buffered so discovery can feed without blocking
 	workCh := make(chan workItem, 200)
 
+	evaluate := runsPhase(config, PhaseEvaluate)
+	extract := runsPhase(config, PhaseExtract)
+	entryCap := schema.EntryCap(config.Form)
+	if config.MaxEntries > 0 {
+		entryCap.Max = config.MaxEntries
+	}
+	hook := postprocess.New(config.Form)
+
 	// Start worker pool — workers persist across discovery rounds
 	wg.Add(workers)
 	for w := 0; w < workers; w++ {
 		go func() {
 			defer wg.Done()
 			for item := range workCh {
-				if ctx.Err() != nil {
+				// Paused runs finish in-flight threads, then wait here
+				if config.Control.Wait(ctx) != nil {
 					return
 				}
 
-				// Early stop: enough threads extracted
-				mu.Lock()
-				counts := session.CountByStatus(manifest)
-				enough := counts["extracted"]+counts["ranked"] >= config.Limit
-				mu.Unlock()
-				if enough {
-					return
+				// Early stop: enough extracted
+				if enough() && !item.state.Pinned {
+					continue
 				}
 
 				ts := item.state
 				n := done.Add(1)
 				total := totalFed.Load()
-				markThreadFailed := func(err error) {
-					idx := session.FindThreadIndex(manifest, ts.PostID)
-					if idx >= 0 {
-						manifest.Threads[idx].Status = "failed"
-						if err != nil {
-							manifest.Threads[idx].Error = err.Error()
+
+				// Over the session size limit: leave uncollected threads pending
+				if item.needsEval && o.guard.Full() {
+					fmt.Printf("  [%d/%d] %s → deferred: session size limit reached\n", n, total, truncate(ts.Title, 50))
+					continue
+				}
+				if item.needsEval && o.budgetExhausted() {
+					fmt.Printf("  [%d/%d] %s → deferred: request budget exhausted\n", n, total, truncate(ts.Title, 50))
+					continue
+				}
+				markThreadFailed := func(stage string, err error) {
+					store.UpdateThread(ts.PostID, func(t *types.ThreadState) error {
+						if recordFailure(t, classifyFailure(stage, err), err, config.MaxAttempts) {
+							fmt.Printf("  %s → quarantined after %d failed attempts\n", truncate(ts.Title, 50), t.Attempts)
 						}
-					}
+						return nil
+					})
 				}
 
 				// Step 1: Evaluate if needed
 				if item.needsEval {
-					if o.threadEvaluator != nil {
+					// Pinned threads bypass evaluation: they are never skipped
+					if o.threadEvaluator != nil && evaluate && !ts.Pinned {
 						evalResult, err := o.threadEvaluator.EvaluateThread(ctx, config.Form, ts, sessionDir)
 						if err != nil {
-							mu.Lock()
-							markThreadFailed(fmt.Errorf("evaluation failed: %w", err))
-							mu.Unlock()
-							markDirty()
+							markThreadFailed(stageEval, fmt.Errorf("evaluation failed: %w", err))
 							fmt.Printf("  [%d/%d] %s → eval failed: %v\n", n, total, truncate(ts.Title, 50), err)
 							continue
 						}
 
 						if evalResult.Verdict != "keep" {
-							mu.Lock()
-							session.UpdateThreadStatus(manifest, ts.PostID, "skipped")
-							mu.Unlock()
-							markDirty()
+							store.Update(func(m *types.Manifest) error {
+								session.UpdateThreadStatus(m, ts.PostID, types.StatusSkipped)
+								if t := session.FindThread(m, ts.PostID); t != nil {
+									t.SkipReason = evalResult.Reason
+								}
+								if skipList != nil {
+									session.AddSkip(skipList, ts, evalResult.Reason, "evaluation")
+									skipsAdded++
+								}
+								return nil
+							})
 							fmt.Printf("  [%d/%d] %s → SKIP: %s\n", n, total, truncate(ts.Title, 50), evalResult.Reason)
 							continue
 						}
 
+						o.guard.enforceThreadLimit(filepath.Join(sessionDir, fmt.Sprintf("thread_%s.json", ts.PostID)))
+
 						// Mark as collected
-						mu.Lock()
-						now := time.Now()
-						idx := session.FindThreadIndex(manifest, ts.PostID)
-						if idx >= 0 {
-							manifest.Threads[idx].Status = "collected"
-							manifest.Threads[idx].CollectedAt = &now
-						}
-						mu.Unlock()
-						markDirty()
+						store.UpdateThread(ts.PostID, func(t *types.ThreadState) error {
+							now := time.Now()
+							t.Status = types.StatusCollected
+							t.CollectedAt = &now
+							t.EstimatedEntries = evalResult.EstimatedEntries
+							return nil
+						})
 					} else {
-						// No evaluator: fetch thread directly
-						thread, err := o.searcher.GetThread(ctx, ts.Permalink, 100)
+						// No evaluator (or pinned thread): fetch thread directly
+						thread, err := o.fetchThread(ctx, config, ts)
 						if err != nil {
-							mu.Lock()
-							markThreadFailed(fmt.Errorf("thread fetch failed: %w", err))
-							mu.Unlock()
-							markDirty()
+							markThreadFailed(stageFetch, fmt.Errorf("thread fetch failed: %w", err))
 							fmt.Printf("  [%d/%d] %s → fetch failed: %v\n", n, total, truncate(ts.Title, 50), err)
 							continue
 						}
 
 						// Write thread JSON OUTSIDE the lock
 						threadPath := filepath.Join(sessionDir, fmt.Sprintf("thread_%s.json", ts.PostID))
-						threadData, err := json.MarshalIndent(thread, "", "  ")
-						if err != nil {
-							mu.Lock()
-							markThreadFailed(fmt.Errorf("thread marshal failed: %w", err))
-							mu.Unlock()
-							markDirty()
-							continue
-						}
-						if err := os.WriteFile(threadPath, threadData, 0644); err != nil {
-							mu.Lock()
-							markThreadFailed(fmt.Errorf("thread write failed: %w", err))
-							mu.Unlock()
-							markDirty()
+						if err := o.guard.writeThread(threadPath, thread); err != nil {
+							if errors.Is(err, errSessionFull) {
+								fmt.Printf("  [%d/%d] %s → deferred: session size limit reached\n", n, total, truncate(ts.Title, 50))
+								continue
+							}
+							markThreadFailed(stageWrite, fmt.Errorf("thread write failed: %w", err))
 							continue
 						}
 
-						mu.Lock()
-						now := time.Now()
-						idx := session.FindThreadIndex(manifest, ts.PostID)
-						if idx >= 0 {
-							manifest.Threads[idx].Status = "collected"
-							manifest.Threads[idx].CollectedAt = &now
-						}
-						mu.Unlock()
-						markDirty()
+						store.UpdateThread(ts.PostID, func(t *types.ThreadState) error {
+							now := time.Now()
+							t.Status = types.StatusCollected
+							t.CollectedAt = &now
+							if thread.Post.Title != "" {
+								t.Title = thread.Post.Title
+								t.Subreddit = thread.Post.Subreddit
+								t.Score = thread.Post.Score
+								t.NumComments = thread.Post.NumComments
+								t.Created = thread.Post.Created
+							}
+							return nil
+						})
 					}
 				}
 
-				// Step 2: Extract fields from thread JSON
-				thread, err := o.loadThreadForExtraction(ctx, ts, sessionDir)
+				// Step 2: Extract fields from thread JSON. Without extraction in
+				// the pipeline, collected threads wait for a later run.
+				if !extract {
+					continue
+				}
+				thread, err := o.loadThreadForExtraction(ctx, config, ts, sessionDir)
 				if err != nil {
-					mu.Lock()
-					markThreadFailed(err)
-					mu.Unlock()
-					markDirty()
+					markThreadFailed(stageFetch, err)
 					fmt.Printf("  [%d/%d] %s → thread load failed: %v\n", n, total, truncate(ts.Title, 50), err)
 					continue
 				}
 
-				result, err := o.extractSingle(ctx, thread, config.Form, logWriter)
+				// Threads collected before post dates were recorded
+				if ts.Created == 0 && thread.Post.Created > 0 {
+					store.UpdateThread(ts.PostID, func(t *types.ThreadState) error {
+						t.Created = thread.Post.Created
+						return nil
+					})
+				}
+
+				fetched := search.CountComments(thread.Comments)
+				if ts.Source == "" {
+					thread = truncateThread(config, thread)
+				}
+
+				result, err := o.extractChunked(ctx, config, thread, logWriter)
 				if err != nil {
-					mu.Lock()
-					markThreadFailed(fmt.Errorf("extraction failed: %w", err))
-					mu.Unlock()
-					markDirty()
+					markThreadFailed(stageExtract, fmt.Errorf("extraction failed: %w", err))
 					fmt.Printf("  [%d/%d] %s → extract failed: %v\n", n, total, truncate(ts.Title, 50), err)
 					continue
 				}
+				usage := contextUsage(ts, fetched, thread, result)
+				if usage.Coverage() < partialCoverage {
+					fmt.Printf("  [%d/%d] %s → partial view: extraction saw %d of %d comments (%d truncated)\n",
+						n, total, truncate(ts.Title, 50), usage.Sent+usage.Summarized, usage.Comments, usage.Truncated())
+				}
 
-				e := extracted.Add(1)
+				if len(config.Form.Exclusions) > 0 || config.Form.Audience != nil {
+					before := len(result.Entries)
+					kept, reasons := excludeEntries(config.Form, result.Entries)
+					if len(kept) < before {
+						fmt.Printf("  [%d/%d] %s → dropped %d excluded entries (%s)\n",
+							n, total, truncate(ts.Title, 50), before-len(kept), strings.Join(reasons, "; "))
+					}
+					result.Entries = kept
+				}
 
-				mu.Lock()
-				session.UpdateThreadEntries(manifest, ts.PostID, result.Entries)
-				processed++
-				mu.Unlock()
-				markDirty()
+				if hook != nil {
+					before := len(result.Entries)
+					kept, rejected, errs := hook.Apply(ctx, ts, result.Entries)
+					for _, err := range errs {
+						fmt.Printf("  [%d/%d] %s → post-processor: %v\n", n, total, truncate(ts.Title, 50), err)
+					}
+					if len(rejected) > 0 {
+						var reasons []string
+						for _, reason := range rejected {
+							if reason != "" {
+								reasons = append(reasons, reason)
+							}
+						}
+						note := ""
+						if len(reasons) > 0 {
+							note = " (" + strings.Join(reasons, "; ") + ")"
+						}
+						fmt.Printf("  [%d/%d] %s → post-processor rejected %d of %d entries%s\n",
+							n, total, truncate(ts.Title, 50), len(rejected), before, note)
+					}
+					result.Entries = kept
+				}
 
-				fmt.Printf("  [%d extracted] %s (%d entries)\n", e, truncate(ts.Title, 50), len(result.Entries))
+				if config.Form.Entity != nil {
+					before := len(result.Entries)
+					kept, merged, dropped := agent.EnforceEntity(config.Form, ts.PostID, result.Entries)
+					if merged > 0 || dropped > 0 {
+						fmt.Printf("  [%d/%d] %s → %d entries held to the entity definition: %d merged, %d without a key value dropped\n",
+							n, total, truncate(ts.Title, 50), before, merged, dropped)
+					}
+					result.Entries = kept
+				}
+
+				entries, merged, overflow := agent.CapEntries(config.Form, entryCap, ts.PostID, result.Entries)
+				if merged > 0 || overflow > 0 {
+					fmt.Printf("  [%d/%d] %s → %d entries over the cap of %d: %d merged, %d flagged overflow\n",
+						n, total, truncate(ts.Title, 50), len(result.Entries)-entryCap.Max, entryCap.Max, merged, overflow)
+				}
+				result.Entries = entries
+
+				e := extracted.Add(1)
+				stampProvenance(result.Entries, runID)
+				session.AssignEntryIDs(ts.PostID, schema.PrimaryField(config.Form), result.Entries)
+
+				store.Update(func(m *types.Manifest) error {
+					session.UpdateThreadEntries(m, ts.PostID, result.Entries)
+					return nil
+				})
+				store.UpdateThread(ts.PostID, func(t *types.ThreadState) error {
+					t.Context = usage
+					return nil
+				})
+				processed.Add(1)
+
+				fmt.Printf("  [%d extracted] %s (%d entries, ~%d prompt tokens)\n", e, truncate(ts.Title, 50), len(result.Entries), usage.PromptTokens)
+				if config.Checkpoint > 0 && e == int64(config.Checkpoint) {
+					checkpoint(config, store, &limit)
+				}
 			}
 		}()
 	}
@@ -439,36 +969,77 @@ func (o *DefaultOrchestrator) runPipeline(ctx context.Context, config RunConfig,
 	// Track which threads have been fed to the work channel
 	fed := make(map[string]bool)
 
-	// Feed already-collected threads (resume case)
-	mu.Lock()
-	collected := session.GetCollectedThreads(manifest)
-	mu.Unlock()
+	// Feed already-collected threads (resume case), those evaluation expects
+	// the most entries from first
+	var collected []types.ThreadState
+	store.View(func(m *types.Manifest) { collected = session.GetCollectedThreads(m) })
+	sort.SliceStable(collected, func(i, j int) bool {
+		return collected[i].EstimatedEntries > collected[j].EstimatedEntries
+	})
 	for _, ts := range collected {
 		fed[ts.PostID] = true
 		totalFed.Add(1)
 		workCh <- workItem{ts, false}
 	}
 
+	// Feed pinned threads ahead of discovery — they are processed regardless of the limit
+	var pinnedItems []workItem
+	store.View(func(m *types.Manifest) {
+		for _, ts := range session.GetPinnedThreads(m) {
+			if ts.Status == types.StatusPending && !fed[ts.PostID] {
+				pinnedItems = append(pinnedItems, workItem{ts, true})
+				fed[ts.PostID] = true
+			}
+		}
+	})
+	if len(pinnedItems) > 0 {
+		fmt.Printf("Feeding %d pinned threads\n", len(pinnedItems))
+	}
+	for _, item := range pinnedItems {
+		totalFed.Add(1)
+		workCh <- item
+	}
+
 	// Discovery + feed loop — runs discovery and feeds workers across multiple rounds
-	const maxRounds = 3
+	maxRounds := config.DiscoveryRounds
+	if maxRounds <= 0 {
+		maxRounds = 3
+	}
+	discover := runsPhase(config, PhaseThreadDiscovery)
 	for round := 0; round < maxRounds; round++ {
-		if ctx.Err() != nil {
+		if config.Control.Wait(ctx) != nil {
+			break
+		}
+		if o.guard.Full() {
+			fmt.Println("Session size limit reached, stopping discovery")
+			break
+		}
+		if o.budgetExhausted() {
+			fmt.Println("Request budget exhausted, stopping discovery")
 			break
 		}
 
-		// Check if we already have enough extracted threads
-		mu.Lock()
-		counts := session.CountByStatus(manifest)
-		haveEnough := counts["extracted"]+counts["ranked"] >= config.Limit
-		mu.Unlock()
-		if haveEnough {
-			fmt.Printf("Already have %d extracted threads (target: %d)\n", counts["extracted"]+counts["ranked"], config.Limit)
+		// Check if we already have enough extracted
+		counts := store.CountByStatus()
+		var outlook EntryOutlook
+		store.View(func(m *types.Manifest) { outlook = Outlook(m) })
+		if enough() {
+			if config.TargetEntries > 0 {
+				fmt.Printf("Already have %d entries (target: %d)\n", outlook.Extracted, config.TargetEntries)
+			} else {
+				fmt.Printf("Already have %d extracted threads (target: %d)\n", counts[types.StatusExtracted]+counts[types.StatusRanked], limit.Load())
+			}
 			break
 		}
 
 		if round > 0 {
-			fmt.Printf("\n=== Retry round %d: need more threads (have %d extracted, need %d) ===\n",
-				round+1, counts["extracted"]+counts["ranked"], config.Limit)
+			if config.TargetEntries > 0 {
+				fmt.Printf("\n=== Retry round %d: need more entries (have %d, need %d) ===\n",
+					round+1, outlook.Extracted, config.TargetEntries)
+			} else {
+				fmt.Printf("\n=== Retry round %d: need more threads (have %d extracted, need %d) ===\n",
+					round+1, counts[types.StatusExtracted]+counts[types.StatusRanked], limit.Load())
+			}
 		}
 
 		// Phase 1: Discover threads
@@ -476,17 +1047,50 @@ func (o *DefaultOrchestrator) runPipeline(ctx context.Context, config RunConfig,
 		fmt.Println("\n=== Phase 1: Thread Discovery ===")
 		discoveryStart := time.Now()
 
-		mu.Lock()
-		counts = session.CountByStatus(manifest)
-		actionable := counts["pending"] + counts["collected"] + counts["extracted"] + counts["ranked"]
-		mu.Unlock()
-		overprovisionTarget := config.Limit * 3
+		counts = store.CountByStatus()
+		actionable := counts[types.StatusPending] + counts[types.StatusCollected] + counts[types.StatusExtracted] + counts[types.StatusRanked]
+		factor := overprovisionFactor(config, counts)
+		overprovisionTarget := int(math.Ceil(float64(limit.Load()) * factor))
 		remaining := overprovisionTarget - actionable
+		if config.TargetEntries > 0 {
+			// Discover enough threads for the entries still missing, going by
+			// what kept threads yield, up to the limit's worth
+			store.View(func(m *types.Manifest) { outlook = Outlook(m) })
+			missing := math.Max(float64(config.TargetEntries-outlook.Projected()), 0)
+			wanted := math.Min(math.Ceil(missing/outlook.PerThread()), float64(limit.Load()))
+			overprovisionTarget = int(math.Ceil(wanted * factor))
+			remaining = overprovisionTarget - counts[types.StatusPending]
+		}
 
-		if remaining <= 0 {
+		if config.User != "" {
+			fmt.Printf("User-history mode: skipping discovery for u/%s\n", config.User)
+		} else if !discover {
+			fmt.Println("Thread discovery is not in the pipeline, processing known threads only")
+		} else if remaining <= 0 && config.TargetEntries > 0 {
+			expected := outlook.Projected() + int(math.Round(float64(counts[types.StatusPending])*outlook.PerThread()))
+			fmt.Printf("Known threads are expected to yield ~%d entries (target: %d), skipping discovery\n", expected, config.TargetEntries)
+		} else if remaining <= 0 {
 			fmt.Printf("Already have %d actionable threads (target: %d), skipping discovery\n", actionable, overprovisionTarget)
 		} else {
-			posts, err := o.findThreads(ctx, config, remaining, sessionDir)
+			// Favor the subreddits that have yielded entries, and drop
+			// discovered ones whose threads keep getting skipped
+			var yields map[string]types.SubredditYield
+			var discovered bool
+			store.View(func(m *types.Manifest) {
+				yields = session.MergeYields(past, session.ComputeYield(m))
+				discovered = m.DiscoveredSubreddits
+			})
+			roundConfig := config
+			var dropped []string
+			roundConfig.Subreddits, dropped = session.OrderSubreddits(config.Subreddits, yields, discovered)
+			for _, sub := range dropped {
+				fmt.Printf("Deprioritizing r/%s: %s\n", sub, session.FormatYield(yields[strings.ToLower(sub)]))
+			}
+			if ya, ok := o.threadDiscoverer.(yieldAware); ok {
+				ya.SetYieldNotes(session.YieldNotes(yields))
+			}
+
+			posts, searches, err := o.findThreads(ctx, roundConfig, remaining, sessionDir)
 			if err != nil {
 				if ctx.Err() != nil {
 					break
@@ -502,44 +1106,60 @@ func (o *DefaultOrchestrator) runPipeline(ctx context.Context, config RunConfig,
 				break
 			}
 
-			// Add discovered posts to manifest under lock
-			mu.Lock()
+			// Add discovered posts to the manifest in one update
 			added := 0
-			for _, post := range posts {
-				if added >= remaining {
-					break
-				}
-				if session.FindThread(manifest, post.ID) != nil {
-					continue
+			knownSkips := 0
+			store.Update(func(m *types.Manifest) error {
+				for _, search := range searches {
+					search.Run = runID
+					search.Round = round + 1
+					m.DiscoveryLog = append(m.DiscoveryLog, search)
 				}
-				thread := types.ThreadState{
-					PostID:      post.ID,
-					Permalink:   post.Permalink,
-					Title:       post.Title,
-					Subreddit:   post.Subreddit,
-					Score:       post.Score,
-					NumComments: post.NumComments,
-					Status:      "pending",
+				for _, post := range posts {
+					if added >= remaining {
+						break
+					}
+					if session.FindThread(m, post.ID) != nil {
+						continue
+					}
+					if session.IsSkipped(skipList, post.ID) {
+						knownSkips++
+						continue
+					}
+					thread := types.ThreadState{
+						PostID:      post.ID,
+						Permalink:   post.Permalink,
+						Title:       post.Title,
+						Subreddit:   post.Subreddit,
+						Score:       post.Score,
+						NumComments: post.NumComments,
+						Created:     post.Created,
+						Status:      types.StatusPending,
+
+						DiscoveryReason: post.DiscoveryReason,
+					}
+					session.AddThread(m, thread)
+					added++
 				}
-				session.AddThread(manifest, thread)
-				added++
-			}
-			mu.Unlock()
-			markDirty()
+				return nil
+			})
 			fmt.Printf("Added %d new threads to session\n", added)
+			if knownSkips > 0 {
+				fmt.Printf("  Ignored %d threads on the form's skip list\n", knownSkips)
+			}
 		}
 		fmt.Printf("  Discovery completed in %s\n", formatDuration(time.Since(discoveryStart)))
 
 		// Feed newly pending threads to workers
-		mu.Lock()
 		var newItems []workItem
-		for _, ts := range manifest.Threads {
-			if ts.Status == "pending" && !fed[ts.PostID] {
-				newItems = append(newItems, workItem{ts, true})
-				fed[ts.PostID] = true
+		store.View(func(m *types.Manifest) {
+			for _, ts := range m.Threads {
+				if ts.Status == types.StatusPending && !fed[ts.PostID] {
+					newItems = append(newItems, workItem{ts, true})
+					fed[ts.PostID] = true
+				}
 			}
-		}
-		mu.Unlock()
+		})
 
 		if len(newItems) == 0 && round > 0 {
 			fmt.Println("No new threads to process, stopping")
@@ -567,30 +1187,26 @@ func (o *DefaultOrchestrator) runPipeline(ctx context.Context, config RunConfig,
 			if done.Load() >= roundTarget {
 				break
 			}
-			mu.Lock()
-			counts = session.CountByStatus(manifest)
-			haveEnough = counts["extracted"]+counts["ranked"] >= config.Limit
-			mu.Unlock()
-			if haveEnough {
+			if enough() {
 				break
 			}
 			time.Sleep(500 * time.Millisecond)
 		}
 		fmt.Printf("  Evaluate & Extract completed in %s (%d extracted)\n",
 			formatDuration(time.Since(evalExtractStart)), extracted.Load())
-		mu.Lock()
-		counts = session.CountByStatus(manifest)
-		mu.Unlock()
+		counts = store.CountByStatus()
 		fmt.Printf("  Round status: %d extracted, %d skipped, %d failed, %d pending\n",
-			counts["extracted"], counts["skipped"], counts["failed"], counts["pending"])
+			counts[types.StatusExtracted], counts[types.StatusSkipped], counts[types.StatusFailed], counts[types.StatusPending])
+		store.View(func(m *types.Manifest) { outlook = Outlook(m) })
+		if outlook.Awaiting > 0 || config.TargetEntries > 0 {
+			fmt.Printf("  Entry outlook: %s\n", outlook.Line(config.TargetEntries))
+		}
 
 		// Circuit breaker: if first round produced zero extractions and everything failed, abort
 		if extracted.Load() == 0 && round == 0 {
-			mu.Lock()
-			counts = session.CountByStatus(manifest)
-			failCount := counts["failed"] + counts["skipped"]
-			total := failCount + counts["extracted"]
-			mu.Unlock()
+			counts = store.CountByStatus()
+			failCount := counts[types.StatusFailed] + counts[types.StatusSkipped]
+			total := failCount + counts[types.StatusExtracted]
 			if total > 0 && failCount == total {
 				fmt.Printf("\n=== Circuit breaker: all %d threads failed or were skipped with 0 extracted. Aborting. ===\n", failCount)
 				break
@@ -601,41 +1217,63 @@ func (o *DefaultOrchestrator) runPipeline(ctx context.Context, config RunConfig,
 	close(workCh)
 	wg.Wait()
 
+	if skipList != nil && skipsAdded > 0 {
+		if err := session.SaveSkipList(config.OutputDir, skipList); err != nil {
+			fmt.Printf("  Warning: saving skip list: %v\n", err)
+		}
+	}
+	store.Update(func(m *types.Manifest) error {
+		recordYield(config, m, sessionDir)
+		return nil
+	})
+
 	// Final manifest save
 	saveCancel()
 	<-saveDone
 
 	fmt.Printf("Extraction log: %s\n", logPath)
-	return processed, nil
+	return int(processed.Load()), nil
 }
 
-func (o *DefaultOrchestrator) loadThreadForExtraction(ctx context.Context, ts types.ThreadState, sessionDir string) (*types.Thread, error) {
+func (o *DefaultOrchestrator) loadThreadForExtraction(ctx context.Context, config RunConfig, ts types.ThreadState, sessionDir string) (*types.Thread, error) {
 	threadPath := filepath.Join(sessionDir, fmt.Sprintf("thread_%s.json", ts.PostID))
-	threadData, readErr := os.ReadFile(threadPath)
+	threadData, readErr := readThreadPayload(threadPath)
+	var partial *types.Thread
 	if readErr == nil {
 		thread, parseErr := parseThreadJSON(threadData)
-		if parseErr == nil {
+		if parseErr == nil && !needsPagedRefetch(config, ts, thread) {
 			return thread, nil
 		}
-		fmt.Printf("  [%s] thread payload invalid (%v), refetching canonical JSON\n", ts.PostID, parseErr)
+		if parseErr == nil {
+			partial = thread
+			fmt.Printf("  [%s] megathread payload holds %d of %d comments, refetching with pagination\n",
+				ts.PostID, search.CountComments(thread.Comments), ts.NumComments)
+		} else {
+			fmt.Printf("  [%s] thread payload invalid (%v), refetching canonical JSON\n", ts.PostID, parseErr)
+		}
 	} else if !os.IsNotExist(readErr) {
 		fmt.Printf("  [%s] thread payload unreadable (%v), refetching canonical JSON\n", ts.PostID, readErr)
 	}
 
-	thread, err := o.searcher.GetThread(ctx, ts.Permalink, 100)
+	thread, err := o.fetchThread(ctx, config, ts)
 	if err != nil {
+		if partial != nil {
+			fmt.Printf("  [%s] paged refetch failed (%v), using saved payload\n", ts.PostID, err)
+			return partial, nil
+		}
 		if readErr != nil && !os.IsNotExist(readErr) {
 			return nil, fmt.Errorf("refetch failed after read error (%v): %w", readErr, err)
 		}
 		return nil, fmt.Errorf("refetch failed: %w", err)
 	}
 
-	canonical, err := json.MarshalIndent(thread, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("marshaling canonical thread JSON: %w", err)
-	}
-	if err := os.WriteFile(threadPath, canonical, 0644); err != nil {
-		return nil, fmt.Errorf("writing canonical thread JSON: %w", err)
+	if err := o.guard.writeThread(threadPath, thread); err != nil {
+		if !errors.Is(err, errSessionFull) {
+			return nil, fmt.Errorf("writing canonical thread JSON: %w", err)
+		}
+		// Over the size limit: extract from the refetch without saving it
+		fmt.Printf("  [%s] refetched thread (not saved: session size limit reached)\n", ts.PostID)
+		return thread, nil
 	}
 	fmt.Printf("  [%s] refetched thread and wrote canonical payload\n", ts.PostID)
 
@@ -653,14 +1291,15 @@ func parseThreadJSON(data []byte) (*types.Thread, error) {
 	return &thread, nil
 }
 
-// findThreads discovers threads using the agentic discoverer or direct search.
-// Returns posts without modifying the manifest — the caller handles that under lock.
-func (o *DefaultOrchestrator) findThreads(ctx context.Context, config RunConfig, remaining int, sessionDir string) ([]types.Post, error) {
+// findThreads discovers threads using the agentic discoverer or direct search,
+// returning the posts and the searches made to find them. The manifest is
+// left alone — the caller handles that under lock.
+func (o *DefaultOrchestrator) findThreads(ctx context.Context, config RunConfig, remaining int, sessionDir string) ([]types.Post, []types.DiscoverySearch, error) {
 	if o.threadDiscoverer != nil {
 		fmt.Printf("Agent discovering %d threads across %v\n", remaining, config.Subreddits)
 
 		if err := os.MkdirAll(sessionDir, 0755); err != nil {
-			return nil, fmt.Errorf("creating session dir: %w", err)
+			return nil, nil, fmt.Errorf("creating session dir: %w", err)
 		}
 
 		posts, err := o.threadDiscoverer.DiscoverThreads(ctx, config.Form, config.Query, config.Subreddits, remaining, sessionDir)
@@ -669,30 +1308,43 @@ func (o *DefaultOrchestrator) findThreads(ctx context.Context, config RunConfig,
 			fmt.Println("  Falling back to direct search")
 			return o.searchDirect(ctx, config, remaining)
 		}
-		return posts, nil
+		var searches []types.DiscoverySearch
+		if l, ok := o.threadDiscoverer.(searchLogger); ok {
+			searches = l.SearchLog()
+		}
+		return posts, searches, nil
 	}
 
 	return o.searchDirect(ctx, config, remaining)
 }
 
+// searchLogger is an optional interface for thread discoverers that report
+// the searches they made
+type searchLogger interface {
+	SearchLog() []types.DiscoverySearch
+}
+
 // searchDirect performs parallel API searches across subreddits
-func (o *DefaultOrchestrator) searchDirect(ctx context.Context, config RunConfig, remaining int) ([]types.Post, error) {
+func (o *DefaultOrchestrator) searchDirect(ctx context.Context, config RunConfig, remaining int) ([]types.Post, []types.DiscoverySearch, error) {
 	if config.Query != "" {
 		if len(config.Subreddits) == 0 {
 			fmt.Printf("Searching all of Reddit for: %s\n", config.Query)
 			posts, err := o.searcher.Search(ctx, config.Query, "all", remaining)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			fmt.Printf("  Found %d posts\n", len(posts))
-			return posts, nil
+			searches := []types.DiscoverySearch{{Method: "search", Query: config.Query, Results: len(posts), At: time.Now()}}
+			return posts, searches, nil
 		}
 
 		// Parallel search across subreddits
 		var (
-			posts []types.Post
-			mu    sync.Mutex
-			wg    sync.WaitGroup
+			posts    []types.Post
+			searches []types.DiscoverySearch
+			failures []error
+			mu       sync.Mutex
+			wg       sync.WaitGroup
 		)
 		for _, sub := range config.Subreddits {
 			wg.Add(1)
@@ -705,23 +1357,29 @@ func (o *DefaultOrchestrator) searchDirect(ctx context.Context, config RunConfig
 				subPosts, err := o.searcher.Search(ctx, config.Query, sub, remaining)
 				if err != nil {
 					fmt.Printf("  Warning: search failed for r/%s: %v\n", sub, err)
+					mu.Lock()
+					failures = append(failures, fmt.Errorf("r/%s: %w", sub, err))
+					mu.Unlock()
 					return
 				}
 				mu.Lock()
 				posts = append(posts, subPosts...)
+				searches = append(searches, types.DiscoverySearch{Method: "search", Query: config.Query, Subreddit: sub, Results: len(subPosts), At: time.Now()})
 				mu.Unlock()
 				fmt.Printf("  Found %d posts in r/%s\n", len(subPosts), sub)
 			}(sub)
 		}
 		wg.Wait()
-		return posts, nil
+		return posts, searches, subredditFailures(failures, len(config.Subreddits))
 	}
 
 	// List mode — parallel across subreddits
 	var (
-		posts []types.Post
-		mu    sync.Mutex
-		wg    sync.WaitGroup
+		posts    []types.Post
+		searches []types.DiscoverySearch
+		failures []error
+		mu       sync.Mutex
+		wg       sync.WaitGroup
 	)
 	for _, sub := range config.Subreddits {
 		wg.Add(1)
@@ -734,43 +1392,81 @@ func (o *DefaultOrchestrator) searchDirect(ctx context.Context, config RunConfig
 			subPosts, err := o.searcher.ListSubreddit(ctx, sub, config.Sort, remaining)
 			if err != nil {
 				fmt.Printf("  Warning: list failed for r/%s: %v\n", sub, err)
+				mu.Lock()
+				failures = append(failures, fmt.Errorf("r/%s: %w", sub, err))
+				mu.Unlock()
 				return
 			}
 			mu.Lock()
 			posts = append(posts, subPosts...)
+			searches = append(searches, types.DiscoverySearch{Method: "listing", Query: config.Sort, Subreddit: sub, Results: len(subPosts), At: time.Now()})
 			mu.Unlock()
 			fmt.Printf("  Found %d posts in r/%s\n", len(subPosts), sub)
 		}(sub)
 	}
 	wg.Wait()
-	return posts, nil
+	return posts, searches, subredditFailures(failures, len(config.Subreddits))
 }
 
-// rankEntries collects all extracted entries and runs them through the ranker
-func (o *DefaultOrchestrator) rankEntries(ctx context.Context, config RunConfig, manifest *types.Manifest, sessionDir string) (int, error) {
+// subredditFailures turns per-subreddit search failures into an error when
+// none of the subreddits could be searched, so a round that only failed isn't
+// mistaken for one that found nothing new
+func subredditFailures(failures []error, subreddits int) error {
+	if len(failures) == 0 || len(failures) < subreddits {
+		return nil
+	}
+	return fmt.Errorf("all %d subreddit searches failed: %w", subreddits, failures[0])
+}
+
+// rankedEntry finds the entry a ranking input or output refers to: by its
+// stable ID, or by position for entries without one
+func rankedEntry(thread *types.ThreadState, id string, index int) *types.Entry {
+	if id != "" {
+		if i := session.FindEntry(thread, id); i >= 0 {
+			return &thread.Entries[i]
+		}
+		return nil
+	}
+	if index < 0 || index >= len(thread.Entries) {
+		return nil
+	}
+	return &thread.Entries[index]
+}
+
+// rankEntries collects all extracted entries and runs them through the ranker.
+// The ranker works on copies; its scores are written back in one store update.
+func (o *DefaultOrchestrator) rankEntries(ctx context.Context, config RunConfig, store *session.Store) (int, error) {
 	// Collect entries from all extracted threads
 	var inputs []agent.RankInput
-	for _, ts := range manifest.Threads {
-		if ts.Status != "extracted" || len(ts.Entries) == 0 {
-			continue
-		}
-		for j, entry := range ts.Entries {
-			inputs = append(inputs, agent.RankInput{
-				ThreadPostID: ts.PostID,
-				EntryIndex:   j,
-				Entry:        entry,
-				ThreadScore:  ts.Score,
-				NumComments:  ts.NumComments,
-			})
+	var threads int
+	store.View(func(m *types.Manifest) {
+		for _, ts := range m.Threads {
+			if ts.Status != types.StatusExtracted || len(ts.Entries) == 0 {
+				continue
+			}
+			for j, entry := range ts.Entries {
+				if entry.Overflow {
+					continue
+				}
+				inputs = append(inputs, agent.RankInput{
+					ThreadPostID: ts.PostID,
+					EntryIndex:   j,
+					Entry:        entry,
+					ThreadScore:  ts.Score,
+					NumComments:  ts.NumComments,
+					Source:       ts.Source,
+				})
+			}
 		}
-	}
+		threads = len(session.GetExtractedThreads(m))
+	})
 
 	if len(inputs) == 0 {
 		fmt.Println("  No entries to rank")
 		return 0, nil
 	}
 
-	fmt.Printf("  Ranking %d entries from %d threads\n", len(inputs), len(session.GetExtractedThreads(manifest)))
+	fmt.Printf("  Ranking %d entries from %d threads\n", len(inputs), threads)
 
 	outputs, err := o.ranker.RankEntries(ctx, config.Form, inputs)
 	if err != nil {
@@ -778,39 +1474,95 @@ func (o *DefaultOrchestrator) rankEntries(ctx context.Context, config RunConfig,
 	}
 
 	// Write scores back to entries in the manifest
-	for _, out := range outputs {
-		idx := session.FindThreadIndex(manifest, out.ThreadPostID)
-		if idx < 0 {
-			continue
+	store.Update(func(m *types.Manifest) error {
+		if a, ok := o.ranker.(assessmentAuditor); ok && len(m.Runs) > 0 {
+			m.Runs[len(m.Runs)-1].MalformedAssessments = a.MalformedAssessments()
 		}
-		thread := &manifest.Threads[idx]
-		if out.EntryIndex < 0 || out.EntryIndex >= len(thread.Entries) {
-			continue
-		}
-		score := out.FinalScore
-		thread.Entries[out.EntryIndex].RankScore = &score
-		if len(out.Flags) > 0 {
-			thread.Entries[out.EntryIndex].RankFlags = out.Flags
+		for _, out := range outputs {
+			idx := session.FindThreadIndex(m, out.ThreadPostID)
+			if idx < 0 {
+				continue
+			}
+			entry := rankedEntry(&m.Threads[idx], out.EntryID, out.EntryIndex)
+			if entry == nil {
+				continue
+			}
+			score, raw := out.FinalScore, out.FinalScore
+			entry.RankScore = &score
+			entry.RawScore = &raw
+			if len(out.Flags) > 0 {
+				entry.RankFlags = out.Flags
+			}
+			if out.Reason != "" {
+				entry.RankReason = out.Reason
+			}
+			if out.MergedFields != nil {
+				entry.Fields = out.MergedFields
+				entry.Caveats = out.MergedCaveats
+				for _, fv := range entry.Fields {
+					for _, link := range fv.Links {
+						if !slices.Contains(entry.Links, link) {
+							entry.Links = append(entry.Links, link)
+						}
+					}
+				}
+			}
 		}
-		if out.Reason != "" {
-			thread.Entries[out.EntryIndex].RankReason = out.Reason
+
+		// Rescale and order scores across the whole session, including
+		// entries ranked by earlier runs
+		normalizeScores(m, config.NormalizeScores)
+		return nil
+	})
+
+	if config.TournamentSize > 1 {
+		if err := o.rankHead(ctx, config, store, config.TournamentSize); err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			fmt.Printf("  Warning: tournament failed: %v\n", err)
+			fmt.Println("  Keeping the scored order")
 		}
 	}
 
-	// Update thread statuses to "ranked"
-	for _, ts := range manifest.Threads {
-		if ts.Status == "extracted" && len(ts.Entries) > 0 {
-			session.UpdateThreadRanked(manifest, ts.PostID)
+	store.Update(func(m *types.Manifest) error {
+		summary := summarizeRanking(config.Form, outputs, m)
+		if b, ok := o.ranker.(batchReporter); ok {
+			summary.Batches = b.RankBatches()
 		}
-	}
+		if len(m.Runs) > 0 {
+			m.Runs[len(m.Runs)-1].Ranking = summary
+		}
+
+		// Update thread statuses to "ranked"
+		for _, ts := range m.Threads {
+			if ts.Status == types.StatusExtracted && len(ts.Entries) > 0 {
+				session.UpdateThreadRanked(m, ts.PostID)
+			}
+		}
+		return nil
+	})
 
-	if err := session.SaveManifest(sessionDir, manifest); err != nil {
+	if err := store.Save(); err != nil {
 		return 0, fmt.Errorf("saving manifest after ranking: %w", err)
 	}
 
 	return len(outputs), nil
 }
 
+// stampProvenance records the run and extraction time on each entry, keeping any
+// model/prompt details the extractor already filled in
+func stampProvenance(entries []types.Entry, runID string) {
+	now := time.Now()
+	for i := range entries {
+		if entries[i].Provenance == nil {
+			entries[i].Provenance = &types.Provenance{}
+		}
+		entries[i].Provenance.RunID = runID
+		entries[i].Provenance.ExtractedAt = now
+	}
+}
+
 func truncate(s string, n int) string {
 	if len(s) <= n {
 		return s
@@ -0,0 +1,99 @@
+package orchestrator
+
+import (
+	"fmt"
+	"math"
+
+	"hiveminer/pkg/types"
+)
+
+// Evaluation's entry estimates are scaled by how they have compared with
+// extraction once calibrationSample estimated threads are extracted, within
+// [minCalibration, maxCalibration]. Without estimates or extracted threads,
+// a kept thread is assumed to yield defaultEntriesPerThread entries.
+const (
+	calibrationSample       = 3
+	minCalibration          = 0.25
+	maxCalibration          = 4.0
+	defaultEntriesPerThread = 3.0
+)
+
+// EntryOutlook weighs the entries a session has against those evaluation
+// expects from the threads it kept that are still awaiting extraction
+type EntryOutlook struct {
+	Extracted        int // entries extracted so far
+	ExtractedThreads int // threads they came from
+	Awaiting         int // kept threads awaiting extraction
+	AwaitingEstimate int // entries evaluation expects from them
+	Estimated        int // estimates of the threads since extracted
+	EstimatedActual  int // entries those threads yielded
+	EstimatedThreads int // extracted threads with an estimate
+}
+
+// Outlook tallies a manifest's entries and evaluation estimates
+func Outlook(m *types.Manifest) EntryOutlook {
+	var o EntryOutlook
+	for _, t := range m.Threads {
+		switch {
+		case types.HasEntries(t.Status):
+			o.Extracted += len(t.Entries)
+			o.ExtractedThreads++
+			if t.EstimatedEntries > 0 {
+				o.Estimated += t.EstimatedEntries
+				o.EstimatedActual += len(t.Entries)
+				o.EstimatedThreads++
+			}
+		case t.Status == types.StatusCollected:
+			o.Awaiting++
+			o.AwaitingEstimate += t.EstimatedEntries
+		}
+	}
+	return o
+}
+
+// Calibration is how many entries extraction has found per entry estimated,
+// 1 until enough estimated threads are extracted
+func (o EntryOutlook) Calibration() float64 {
+	if o.EstimatedThreads < calibrationSample || o.Estimated == 0 {
+		return 1
+	}
+	return math.Min(maxCalibration, math.Max(minCalibration, float64(o.EstimatedActual)/float64(o.Estimated)))
+}
+
+// Projected is the entries the session should have once its kept threads
+// are extracted
+func (o EntryOutlook) Projected() int {
+	return o.Extracted + int(math.Round(float64(o.AwaitingEstimate)*o.Calibration()))
+}
+
+// PerThread is the entries a newly kept thread is expected to yield: what
+// extracted threads have averaged, else what evaluation estimated
+func (o EntryOutlook) PerThread() float64 {
+	switch {
+	case o.ExtractedThreads >= calibrationSample:
+		return math.Max(float64(o.Extracted)/float64(o.ExtractedThreads), 0.5)
+	case o.Awaiting > 0 && o.AwaitingEstimate > 0:
+		return float64(o.AwaitingEstimate) / float64(o.Awaiting)
+	}
+	return defaultEntriesPerThread
+}
+
+// Line summarizes the outlook against an entry target (0 for none)
+func (o EntryOutlook) Line(target int) string {
+	line := fmt.Sprintf("%d entries extracted", o.Extracted)
+	if o.Awaiting > 0 {
+		line += fmt.Sprintf(", ~%d expected from %d kept threads awaiting extraction", int(math.Round(float64(o.AwaitingEstimate)*o.Calibration())), o.Awaiting)
+	}
+	if c := o.Calibration(); c != 1 {
+		line += fmt.Sprintf(" (extraction finds %.1fx the estimates)", c)
+	}
+	if target > 0 {
+		projected := o.Projected()
+		if projected >= target {
+			line += fmt.Sprintf("; on track for the target of %d", target)
+		} else {
+			line += fmt.Sprintf("; projected ~%d, %d short of the target of %d", projected, target-projected, target)
+		}
+	}
+	return line
+}
@@ -0,0 +1,26 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"hiveminer/pkg/types"
+)
+
+// refreshPinned resets pinned threads to pending when a session is resumed so
+// the watch list is re-fetched and re-extracted on every refresh.
+func refreshPinned(manifest *types.Manifest) {
+	refreshed := 0
+	for i := range manifest.Threads {
+		t := &manifest.Threads[i]
+		if !t.Pinned || t.Status == types.StatusPending {
+			continue
+		}
+		t.Status = types.StatusPending
+		t.Error = ""
+		t.ErrorCode = ""
+		refreshed++
+	}
+	if refreshed > 0 {
+		fmt.Printf("Refreshing %d pinned threads\n", refreshed)
+	}
+}
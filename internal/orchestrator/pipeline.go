@@ -0,0 +1,224 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Pipeline phases. Thread discovery, evaluation and extraction stream into
+// each other and run together as one stage.
+const (
+	PhaseUserHistory        = "user-history"
+	PhaseSubredditDiscovery = "subreddit-discovery"
+	PhaseWiki               = "wiki"
+	PhaseReviews            = "reviews"
+	PhaseTranscripts        = "transcripts"
+	PhaseWeb                = "web"
+	PhaseThreadDiscovery    = "thread-discovery"
+	PhaseEvaluate           = "evaluate"
+	PhaseExtract            = "extract"
+	PhaseVerify             = "verify"
+	PhaseGeocode            = "geocode"
+	PhaseEnrich             = "enrich"
+	PhaseRank               = "rank"
+)
+
+// DefaultPhases is the pipeline run when no pipeline file is given. Phases
+// whose inputs are missing (user-history without --user, wiki without
+// --wiki, reviews without --reviews, transcripts without --transcripts, web
+// without --web, verify without --verify, geocode without --geocode, enrich
+// without --enrich) do nothing.
+var DefaultPhases = []string{
+	PhaseUserHistory,
+	PhaseSubredditDiscovery,
+	PhaseWiki,
+	PhaseReviews,
+	PhaseTranscripts,
+	PhaseWeb,
+	PhaseThreadDiscovery,
+	PhaseEvaluate,
+	PhaseExtract,
+	PhaseVerify,
+	PhaseGeocode,
+	PhaseEnrich,
+	PhaseRank,
+}
+
+// collectPhases make up the streaming collection stage
+var collectPhases = []string{PhaseThreadDiscovery, PhaseEvaluate, PhaseExtract}
+
+// phaseParams lists the parameters each phase accepts and their kind
+var phaseParams = map[string]map[string]string{
+	PhaseUserHistory:        {},
+	PhaseSubredditDiscovery: {},
+	PhaseWiki:               {},
+	PhaseReviews:            {},
+	PhaseTranscripts:        {},
+	PhaseWeb:                {},
+	PhaseThreadDiscovery:    {"limit": "int", "target_entries": "int", "rounds": "int", "overprovision": "float", "sort": "string"},
+	PhaseEvaluate:           {"workers": "int", "mode": "string"},
+	PhaseExtract:            {"workers": "int", "chunk_size": "int", "context_budget": "int", "truncation": "string", "max_entries": "int", "decompose": "int", "extractor": "string"},
+	PhaseVerify:             {},
+	PhaseGeocode:            {"provider": "string"},
+	PhaseEnrich:             {"config": "string"},
+	PhaseRank:               {"tournament": "int", "normalize": "string", "batch_size": "int", "workers": "int", "fallback": "string", "ranker": "string"},
+}
+
+// Pipeline describes which phases a run executes, in order, and how each is
+// configured
+type Pipeline struct {
+	Phases []PhaseConfig `yaml:"phases"`
+}
+
+// PhaseConfig configures one phase. Backend and model apply to the phase's
+// agent; empty values fall back to the run's flags. A phase with a plugin is
+// a custom phase run by that executable (see plugin.go).
+type PhaseConfig struct {
+	Name    string         `yaml:"name"`
+	Backend string         `yaml:"backend,omitempty"` // claude or codex
+	Model   string         `yaml:"model,omitempty"`
+	Params  map[string]any `yaml:"params,omitempty"`
+
+	Plugin  string   `yaml:"plugin,omitempty"`  // executable; relative paths are resolved against the pipeline file
+	Args    []string `yaml:"args,omitempty"`    // arguments passed to the plugin
+	Timeout string   `yaml:"timeout,omitempty"` // e.g. 30s (default 10m)
+}
+
+// LoadPipeline reads and validates a pipeline file
+func LoadPipeline(path string) (*Pipeline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading pipeline: %w", err)
+	}
+	var p Pipeline
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing pipeline: %w", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid pipeline %s: %w", path, err)
+	}
+	for i := range p.Phases {
+		plugin := p.Phases[i].Plugin
+		if strings.Contains(plugin, "/") && !filepath.IsAbs(plugin) {
+			p.Phases[i].Plugin = filepath.Join(filepath.Dir(path), plugin)
+		}
+	}
+	return &p, nil
+}
+
+// Validate checks phase names, backends and parameters, and that the
+// collection phases are listed together
+func (p *Pipeline) Validate() error {
+	if len(p.Phases) == 0 {
+		return fmt.Errorf("no phases")
+	}
+	seen := map[string]bool{}
+	lastCollect := -1
+	for i, phase := range p.Phases {
+		params, builtin := phaseParams[phase.Name]
+		if phase.Plugin != "" {
+			if err := validatePlugin(phase, builtin); err != nil {
+				return err
+			}
+		} else if !builtin {
+			return fmt.Errorf("phase %d: unknown phase %q (use %s, or set plugin for a custom phase)", i+1, phase.Name, strings.Join(DefaultPhases, ", "))
+		}
+		if phase.Name == "" {
+			return fmt.Errorf("phase %d: name is required", i+1)
+		}
+		if seen[phase.Name] {
+			return fmt.Errorf("phase %s: listed twice", phase.Name)
+		}
+		seen[phase.Name] = true
+		if phase.Backend != "" && phase.Backend != "claude" && phase.Backend != "codex" {
+			return fmt.Errorf("phase %s: invalid backend %q (use claude or codex)", phase.Name, phase.Backend)
+		}
+		for key, value := range phase.Params {
+			if phase.Plugin != "" {
+				break // plugins get their params as is
+			}
+			kind, ok := params[key]
+			if !ok {
+				return fmt.Errorf("phase %s: unknown parameter %q", phase.Name, key)
+			}
+			if !paramHasKind(value, kind) {
+				return fmt.Errorf("phase %s: parameter %s must be of type %s", phase.Name, key, kind)
+			}
+		}
+		if slices.Contains(collectPhases, phase.Name) {
+			if lastCollect >= 0 && lastCollect != i-1 {
+				return fmt.Errorf("phase %s: %s must be listed together", phase.Name, strings.Join(collectPhases, ", "))
+			}
+			lastCollect = i
+		}
+	}
+	return nil
+}
+
+// Names returns the pipeline's phase names in order
+func (p *Pipeline) Names() []string {
+	names := make([]string, len(p.Phases))
+	for i, phase := range p.Phases {
+		names[i] = phase.Name
+	}
+	return names
+}
+
+// Phase returns the configuration of a phase, or nil if the pipeline skips it
+func (p *Pipeline) Phase(name string) *PhaseConfig {
+	if p == nil {
+		return nil
+	}
+	for i := range p.Phases {
+		if p.Phases[i].Name == name {
+			return &p.Phases[i]
+		}
+	}
+	return nil
+}
+
+// Int returns an integer parameter
+func (c *PhaseConfig) Int(key string) (int, bool) {
+	if c == nil {
+		return 0, false
+	}
+	n, ok := c.Params[key].(int)
+	return n, ok
+}
+
+// paramHasKind reports whether a decoded YAML value is of kind
+func paramHasKind(value any, kind string) bool {
+	switch kind {
+	case "int":
+		_, ok := value.(int)
+		return ok
+	case "float":
+		switch value.(type) {
+		case int, float64:
+			return true
+		}
+		return false
+	case "string":
+		_, ok := value.(string)
+		return ok
+	}
+	return false
+}
+
+// runsPhase reports whether the run's pipeline includes a phase
+func runsPhase(config RunConfig, name string) bool {
+	return slices.Contains(config.phases(), name)
+}
+
+// phases returns the run's phases, or the default pipeline
+func (c RunConfig) phases() []string {
+	if c.Pipeline != nil {
+		return c.Pipeline.Names()
+	}
+	return DefaultPhases
+}
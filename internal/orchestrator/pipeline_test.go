@@ -0,0 +1,244 @@
+package orchestrator_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"hiveminer/internal/orchestrator"
+	"hiveminer/internal/search"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/harness"
+	"hiveminer/pkg/types"
+)
+
+var testForm = &types.Form{
+	Title:       "Tents",
+	Description: "Tents campers recommend",
+	Fields: []types.Field{
+		{ID: "name", Type: "string", Question: "Which tent is recommended?", Required: true},
+	},
+}
+
+// newReddit serves three camping threads, a1, b2 and c3, each titled
+// "Tent thread <id>" with a few nested comments
+func newReddit(t *testing.T) *harness.Reddit {
+	t.Helper()
+	reddit := harness.NewReddit()
+	t.Cleanup(reddit.Close)
+	for i, id := range []string{"a1", "b2", "c3"} {
+		var comments []*types.Comment
+		for j := 0; j < 3; j++ {
+			comments = append(comments, &types.Comment{
+				ID:     fmt.Sprintf("%s_c%d", id, j),
+				Author: fmt.Sprintf("camper%d", j),
+				Body:   "The Duplex has held up for years",
+				Score:  10 - j,
+				Replies: []*types.Comment{
+					{ID: fmt.Sprintf("%s_r%d", id, j), Author: "hiker", Body: "Seconded", Score: 2},
+				},
+			})
+		}
+		reddit.AddThread(&types.Thread{
+			Post: types.Post{
+				ID:        id,
+				Subreddit: "camping",
+				Title:     "Tent thread " + id,
+				Score:     100 - i,
+				Created:   float64(1700000000 + i),
+			},
+			Comments: comments,
+		})
+	}
+	return reddit
+}
+
+// newRunner keeps every thread and extracts one tent from each
+func newRunner() *harness.ScriptedRunner {
+	runner := harness.NewScriptedRunner().Default("[]")
+	runner.On(harness.EvaluatePrompt).Reply(harness.Keep(1))
+	runner.On(harness.ExtractPrompt).Reply(harness.Extraction(map[string]any{"name": "Duplex"}))
+	return runner
+}
+
+// run runs the pipeline on the fake and returns the session's directory and
+// manifest; setup can add agents to the orchestrator first
+func run(t *testing.T, reddit *harness.Reddit, runner *harness.ScriptedRunner, config orchestrator.RunConfig, setup ...func(*harness.Orchestrator)) (string, *types.Manifest) {
+	t.Helper()
+	orch, err := harness.NewOrchestrator(reddit, runner, os.DirFS("../../prompts"), harness.Agents{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fn := range setup {
+		fn(orch)
+	}
+	config.Form = testForm
+	config.Query = "tent"
+	config.Subreddits = []string{"camping"}
+	config.Workers = 2
+	if config.OutputDir == "" {
+		config.OutputDir = t.TempDir()
+	}
+	dir, err := orch.Run(context.Background(), config)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	m, err := session.LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, m
+}
+
+func statuses(m *types.Manifest) map[string]string {
+	out := map[string]string{}
+	for _, th := range m.Threads {
+		out[th.PostID] = th.Status
+	}
+	return out
+}
+
+func TestPipelineExtractsAndRanks(t *testing.T) {
+	reddit := newReddit(t)
+	_, m := run(t, reddit, newRunner(), orchestrator.RunConfig{Limit: 3})
+
+	if len(m.Threads) != 3 {
+		t.Fatalf("got %d threads, want 3", len(m.Threads))
+	}
+	for _, th := range m.Threads {
+		if th.Status != types.StatusRanked {
+			t.Errorf("thread %s: status %q, want ranked", th.PostID, th.Status)
+		}
+		if len(th.Entries) != 1 {
+			t.Errorf("thread %s: %d entries, want 1", th.PostID, len(th.Entries))
+		}
+	}
+	if n := reddit.Count("/search.json"); n != 1 {
+		t.Errorf("searched %d times, want one round", n)
+	}
+}
+
+func TestPipelineRetriesRateLimitedFetch(t *testing.T) {
+	reddit := newReddit(t)
+	reddit.Fail("/comments/b2", 429, 1)
+	_, m := run(t, reddit, newRunner(), orchestrator.RunConfig{Limit: 3})
+
+	if got := statuses(m)["b2"]; got != types.StatusRanked {
+		t.Errorf("b2: status %q after a retried 429, want ranked", got)
+	}
+	if n := reddit.Count("/comments/b2"); n < 2 {
+		t.Errorf("b2 fetched %d times, want a retry", n)
+	}
+}
+
+func TestPipelineSkipsAndRunsAnotherRound(t *testing.T) {
+	reddit := newReddit(t)
+	runner := harness.NewScriptedRunner().Default("[]")
+	runner.On(harness.EvaluatePrompt, "Tent thread b2").Reply(harness.Skip("a question with no answers"))
+	runner.On(harness.EvaluatePrompt).Reply(harness.Keep(1))
+	runner.On(harness.ExtractPrompt).Reply(harness.Extraction(map[string]any{"name": "Duplex"}))
+	_, m := run(t, reddit, runner, orchestrator.RunConfig{Limit: 3})
+
+	got := statuses(m)
+	if got["b2"] != types.StatusSkipped || got["a1"] != types.StatusRanked || got["c3"] != types.StatusRanked {
+		t.Errorf("statuses %v, want b2 skipped and the rest ranked", got)
+	}
+	if n := reddit.Count("/search.json"); n != 2 {
+		t.Errorf("searched %d times, want a second round for the skipped thread", n)
+	}
+}
+
+func TestPipelineResumesFailedThreads(t *testing.T) {
+	reddit := newReddit(t)
+	output := t.TempDir()
+
+	failing := harness.NewScriptedRunner().Default("[]")
+	failing.On(harness.EvaluatePrompt).Reply(harness.Keep(1))
+	failing.On(harness.ExtractPrompt, "Tent thread b2").Reply("not JSON")
+	failing.On(harness.ExtractPrompt).Reply(harness.Extraction(map[string]any{"name": "Duplex"}))
+	dir, m := run(t, reddit, failing, orchestrator.RunConfig{Limit: 3, OutputDir: output})
+	if got := statuses(m)["b2"]; got != types.StatusFailed {
+		t.Fatalf("b2: status %q, want failed", got)
+	}
+
+	resumed := newRunner()
+	_, m = run(t, reddit, resumed, orchestrator.RunConfig{Limit: 3, OutputDir: output, SessionDir: dir})
+	for id, status := range statuses(m) {
+		if status != types.StatusRanked {
+			t.Errorf("thread %s: status %q after resume, want ranked", id, status)
+		}
+	}
+	if n := resumed.Count(harness.ExtractPrompt); n != 1 {
+		t.Errorf("resume ran %d extractions, want only the failed thread's", n)
+	}
+}
+
+func TestPipelineCircuitBreaker(t *testing.T) {
+	reddit := newReddit(t)
+	runner := harness.NewScriptedRunner().Default("[]")
+	runner.On(harness.EvaluatePrompt).Reply(harness.Keep(1))
+	runner.On(harness.ExtractPrompt).Reply("not JSON")
+	_, m := run(t, reddit, runner, orchestrator.RunConfig{Limit: 3})
+
+	for id, status := range statuses(m) {
+		if status != types.StatusFailed {
+			t.Errorf("thread %s: status %q, want failed", id, status)
+		}
+	}
+	if n := reddit.Count("/search.json"); n != 1 {
+		t.Errorf("searched %d times, want the breaker to stop after the first round", n)
+	}
+}
+
+// chunkVerifier supports entries only from the chunk holding the given
+// comment, recording how many comments each call saw
+type chunkVerifier struct {
+	support string
+
+	mu    sync.Mutex
+	sizes []int
+}
+
+func (v *chunkVerifier) VerifyEntries(ctx context.Context, form *types.Form, thread *types.Thread, entries []types.Entry) ([]*types.Verification, error) {
+	v.mu.Lock()
+	v.sizes = append(v.sizes, search.CountComments(thread.Comments))
+	v.mu.Unlock()
+	verdict := types.VerdictUnsupported
+	for _, c := range thread.Comments {
+		if strings.HasSuffix(c.ID, v.support) {
+			verdict = types.VerdictSupported
+		}
+	}
+	out := make([]*types.Verification, len(entries))
+	for i := range entries {
+		out[i] = &types.Verification{Verdict: verdict}
+	}
+	return out, nil
+}
+
+func TestPipelineVerifiesLongThreadsInChunks(t *testing.T) {
+	reddit := newReddit(t)
+	verifier := &chunkVerifier{support: "_c2"}
+	_, m := run(t, reddit, newRunner(), orchestrator.RunConfig{Limit: 3, ChunkSize: 2}, func(o *harness.Orchestrator) {
+		o.SetVerifier(verifier)
+	})
+
+	for _, n := range verifier.sizes {
+		if n > 2 {
+			t.Errorf("verifier saw %d comments at once, over the chunk size of 2", n)
+		}
+	}
+	if len(verifier.sizes) != 3*len(m.Threads) {
+		t.Errorf("%d verification calls, want one per comment tree of each thread", len(verifier.sizes))
+	}
+	for _, th := range m.Threads {
+		for _, e := range th.Entries {
+			if e.Verification == nil || e.Verification.Verdict != types.VerdictSupported {
+				t.Errorf("thread %s: entry %s verification %+v, want supported by the last chunk", th.PostID, e.ID, e.Verification)
+			}
+		}
+	}
+}
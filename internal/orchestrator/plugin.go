@@ -0,0 +1,164 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"hiveminer/internal/schema"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// PluginProtocol is the version of the plugin request/response format
+const PluginProtocol = 1
+
+// defaultPluginTimeout bounds a plugin run unless its phase sets a timeout
+const defaultPluginTimeout = 10 * time.Minute
+
+// PluginRequest is written as JSON to a plugin's stdin. Threads are those
+// extracted and not yet ranked, the entries the next ranking pass will score.
+type PluginRequest struct {
+	Protocol   int            `json:"protocol"`
+	Phase      string         `json:"phase"`
+	SessionDir string         `json:"session_dir"`
+	Form       *types.Form    `json:"form"`
+	Model      string         `json:"model,omitempty"`
+	Params     map[string]any `json:"params,omitempty"`
+	Threads    []PluginThread `json:"threads"`
+}
+
+// PluginThread is a thread and its entries as sent to a plugin
+type PluginThread struct {
+	PostID      string        `json:"post_id"`
+	Permalink   string        `json:"permalink"`
+	Title       string        `json:"title"`
+	Subreddit   string        `json:"subreddit"`
+	Score       int           `json:"score"`
+	NumComments int           `json:"num_comments"`
+	Entries     []types.Entry `json:"entries"`
+}
+
+// PluginResponse is read as JSON from a plugin's stdout. Each listed
+// thread's entries replace the thread's entries; unlisted threads are left
+// as they were.
+type PluginResponse struct {
+	Threads []PluginThreadUpdate `json:"threads"`
+}
+
+// PluginThreadUpdate carries a thread's new entries
+type PluginThreadUpdate struct {
+	PostID  string        `json:"post_id"`
+	Entries []types.Entry `json:"entries"`
+}
+
+// validatePlugin checks a custom phase's plugin settings
+func validatePlugin(phase PhaseConfig, builtin bool) error {
+	if builtin {
+		return fmt.Errorf("phase %s: built-in phases can't be replaced by a plugin; give the plugin phase its own name", phase.Name)
+	}
+	if phase.Timeout != "" {
+		if d, err := time.ParseDuration(phase.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("phase %s: invalid timeout %q", phase.Name, phase.Timeout)
+		}
+	}
+	return nil
+}
+
+// runPlugin runs a custom phase: it sends the extracted, unranked threads to
+// the plugin and applies the entries it returns. It returns how many threads
+// the plugin updated.
+func (o *DefaultOrchestrator) runPlugin(ctx context.Context, config RunConfig, phase *PhaseConfig, store *session.Store, sessionDir string) (int, error) {
+	req := PluginRequest{
+		Protocol:   PluginProtocol,
+		Phase:      phase.Name,
+		SessionDir: sessionDir,
+		Form:       config.Form,
+		Model:      phase.Model,
+		Params:     phase.Params,
+		Threads:    []PluginThread{},
+	}
+	store.View(func(m *types.Manifest) {
+		for _, ts := range m.Threads {
+			if ts.Status != types.StatusExtracted || len(ts.Entries) == 0 {
+				continue
+			}
+			req.Threads = append(req.Threads, PluginThread{
+				PostID:      ts.PostID,
+				Permalink:   ts.Permalink,
+				Title:       ts.Title,
+				Subreddit:   ts.Subreddit,
+				Score:       ts.Score,
+				NumComments: ts.NumComments,
+				Entries:     ts.Entries,
+			})
+		}
+	})
+	if len(req.Threads) == 0 {
+		fmt.Println("  No extracted threads to send")
+		return 0, nil
+	}
+	input, err := json.Marshal(req)
+	if err != nil {
+		return 0, fmt.Errorf("encoding plugin request: %w", err)
+	}
+
+	timeout := defaultPluginTimeout
+	if phase.Timeout != "" {
+		timeout, _ = time.ParseDuration(phase.Timeout)
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(runCtx, phase.Plugin, phase.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "HIVEMINER_SESSION_DIR="+sessionDir, "HIVEMINER_PHASE="+phase.Name)
+	if err := cmd.Run(); err != nil {
+		if runCtx.Err() == context.DeadlineExceeded {
+			return 0, fmt.Errorf("plugin %s timed out after %s", phase.Plugin, timeout)
+		}
+		return 0, fmt.Errorf("plugin %s: %w", phase.Plugin, err)
+	}
+
+	var resp PluginResponse
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &resp); err != nil {
+		return 0, fmt.Errorf("parsing plugin response: %w", err)
+	}
+
+	// Check the whole response before touching the manifest
+	sent := map[string]bool{}
+	for _, t := range req.Threads {
+		sent[t.PostID] = true
+	}
+	var unknown []string
+	for _, u := range resp.Threads {
+		if !sent[u.PostID] {
+			unknown = append(unknown, u.PostID)
+		}
+	}
+	if len(unknown) > 0 {
+		return 0, fmt.Errorf("plugin returned threads it wasn't sent: %s", strings.Join(unknown, ", "))
+	}
+
+	err = store.Update(func(m *types.Manifest) error {
+		for _, u := range resp.Threads {
+			session.AssignEntryIDs(u.PostID, schema.PrimaryField(config.Form), u.Entries)
+			t := &m.Threads[session.FindThreadIndex(m, u.PostID)]
+			t.Entries = u.Entries
+			t.ProvisionalEntryIDs = false
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(resp.Threads), nil
+}
@@ -0,0 +1,41 @@
+package orchestrator
+
+import (
+	"fmt"
+	"math"
+
+	"hiveminer/pkg/types"
+)
+
+// Adaptive over-provisioning: discovery starts out requesting
+// defaultOverprovision threads per thread wanted, and once enough threads
+// have been evaluated, requests just enough to cover the observed keep rate
+// plus a margin
+const (
+	defaultOverprovision = 3.0
+	minOverprovision     = 1.2
+	maxOverprovision     = 6.0
+	keepRateMargin       = 1.25
+	keepRateSample       = 5 // evaluated threads needed to trust the keep rate
+)
+
+// overprovisionFactor returns how many threads to discover per thread
+// wanted: the configured factor, or one adapted to the share of evaluated
+// threads kept so far
+func overprovisionFactor(config RunConfig, counts map[string]int) float64 {
+	if config.Overprovision > 0 {
+		return config.Overprovision
+	}
+	kept := counts[types.StatusCollected] + counts[types.StatusExtracted] + counts[types.StatusRanked]
+	evaluated := kept + counts[types.StatusSkipped]
+	if evaluated < keepRateSample {
+		return defaultOverprovision
+	}
+	if kept == 0 {
+		return maxOverprovision
+	}
+	keepRate := float64(kept) / float64(evaluated)
+	factor := math.Min(maxOverprovision, math.Max(minOverprovision, keepRateMargin/keepRate))
+	fmt.Printf("Keep rate so far %.0f%% (%d of %d evaluated): discovering %.1fx the limit\n", 100*keepRate, kept, evaluated, factor)
+	return factor
+}
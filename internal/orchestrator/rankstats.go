@@ -0,0 +1,142 @@
+package orchestrator
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"hiveminer/internal/agent"
+	"hiveminer/internal/schema"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// clusteredSpread is the interquartile range, in points, below which the
+// summary warns that ranking barely separated the entries
+const clusteredSpread = 5
+
+// summarizeRanking rolls up the penalties and final scores of a ranking pass
+func summarizeRanking(form *types.Form, outputs []agent.RankOutput, manifest *types.Manifest) *types.RankingSummary {
+	quality := map[string]bool{}
+	for _, f := range schema.QualityFlags(form) {
+		quality[f.ID] = true
+	}
+
+	s := &types.RankingSummary{Entries: len(outputs), Flags: map[string]int{}}
+	var scores []float64
+	for _, out := range outputs {
+		if saturated(out) {
+			s.Saturated++
+		}
+		flagged := false
+		for _, f := range out.Flags {
+			s.Flags[f]++
+			if f == schema.DuplicateFlag.ID {
+				s.Duplicates++
+			}
+			if quality[f] {
+				flagged = true
+			}
+		}
+		if flagged {
+			s.Flagged++
+		}
+
+		// Final scores come from the manifest, after normalization
+		score := out.FinalScore
+		if idx := session.FindThreadIndex(manifest, out.ThreadPostID); idx >= 0 {
+			if entry := rankedEntry(&manifest.Threads[idx], out.EntryID, out.EntryIndex); entry != nil && entry.RankScore != nil {
+				score = *entry.RankScore
+			}
+		}
+		scores = append(scores, score)
+	}
+
+	if len(scores) > 0 {
+		sort.Float64s(scores)
+		s.Min, s.Max = scores[0], scores[len(scores)-1]
+		s.Q1 = quantile(scores, 0.25)
+		s.Median = quantile(scores, 0.5)
+		s.Q3 = quantile(scores, 0.75)
+	}
+	return s
+}
+
+// saturated reports whether the thread-saturation penalty lowered an
+// entry's final score, rather than being outweighed by the other penalties
+func saturated(out agent.RankOutput) bool {
+	return out.SaturationPenalty < 0 && out.FinalScore < math.Max(0, out.AlgoScore+out.Penalty-out.SaturationPenalty)
+}
+
+// RankingSummaryLines formats a ranking summary for the run summary and
+// runs show
+func RankingSummaryLines(s *types.RankingSummary) []string {
+	var flags []string
+	ids := make([]string, 0, len(s.Flags))
+	for id := range s.Flags {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if id != schema.DuplicateFlag.ID {
+			flags = append(flags, fmt.Sprintf("%s %d", id, s.Flags[id]))
+		}
+	}
+	penalties := fmt.Sprintf("Ranking: %d entries — %d duplicates, %d thread-saturated, %d flagged",
+		s.Entries, s.Duplicates, s.Saturated, s.Flagged)
+	if len(flags) > 0 {
+		penalties += " (" + strings.Join(flags, ", ") + ")"
+	}
+
+	lines := []string{
+		penalties,
+		fmt.Sprintf("Scores: min %.0f · q1 %.0f · median %.0f · q3 %.0f · max %.0f", s.Min, s.Q1, s.Median, s.Q3, s.Max),
+	}
+	if s.Entries > 1 && s.Q3-s.Q1 < clusteredSpread {
+		lines = append(lines, "Scores are tightly clustered: ranking barely differentiated these entries")
+	}
+	if line := batchLine(s.Batches); line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// batchLine summarizes the models assessment batches were scored by, when
+// any batch needed a retry or fallback; "" when every batch went smoothly
+func batchLine(batches []types.RankBatch) string {
+	retried := false
+	counts := map[string]int{}
+	var models []string
+	for _, b := range batches {
+		if len(b.Failures) > 0 {
+			retried = true
+		}
+		model := b.Model
+		if model == "" {
+			model = "algorithmic only"
+		}
+		if counts[model] == 0 {
+			models = append(models, model)
+		}
+		counts[model]++
+	}
+	if !retried {
+		return ""
+	}
+	parts := make([]string, len(models))
+	for i, m := range models {
+		parts[i] = fmt.Sprintf("%d %s", counts[m], m)
+	}
+	return fmt.Sprintf("Assessment: %d batches — %s", len(batches), strings.Join(parts, ", "))
+}
+
+// quantile returns the q-quantile of sorted values by linear interpolation
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo+1 >= len(sorted) {
+		return sorted[lo]
+	}
+	return sorted[lo] + (pos-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
@@ -0,0 +1,61 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"belaykit"
+
+	"hiveminer/internal/agent"
+	"hiveminer/pkg/types"
+)
+
+// replyRunner answers every prompt with the same text
+type replyRunner string
+
+func (r replyRunner) Run(ctx context.Context, prompt string, opts ...belaykit.RunOption) (belaykit.Result, error) {
+	return belaykit.Result{Text: string(r)}, nil
+}
+
+func TestRankingSummaryCountsAppliedSaturation(t *testing.T) {
+	form := &types.Form{
+		Title:       "Tents",
+		Description: "Tents campers recommend",
+		Fields: []types.Field{
+			{ID: "name", Type: "string", Question: "Which tent is recommended?", Required: true},
+		},
+		Saturation: &types.SaturationPolicy{Step: 20, Cap: 40},
+	}
+	var inputs []agent.RankInput
+	add := func(postID, name string, confidence float64, anti bool) {
+		entry := types.Entry{ID: postID + "-" + name, Fields: []types.FieldValue{{ID: "name", Value: name, Confidence: confidence}}}
+		if anti {
+			entry.AntiRecommendation = &types.AntiRecommendation{Source: types.AntiSourceVerify, Reason: "tore in the wind"}
+		}
+		inputs = append(inputs, agent.RankInput{ThreadPostID: postID, EntryIndex: len(inputs), Entry: entry, ThreadScore: 100, NumComments: 50})
+	}
+	add("a1", "Duplex", 0.9, false)
+	add("a1", "Stratospire", 0.8, false) // saturated and assessed
+	add("a1", "Lunar Solo", 0.7, true)   // saturated, but scored 0 as an anti-recommendation
+	add("b2", "Copper Spur", 0.9, false) // assessed only
+	ranker := agent.NewClaudeRanker(replyRunner(`[
+		{"index": 1, "flags": ["spam"], "penalty": -10, "reason": "affiliate link"},
+		{"index": 3, "flags": ["joke"], "penalty": -15, "reason": "sarcastic"}
+	]`), os.DirFS("../../prompts"), "", nil, "claude")
+	outputs, err := ranker.RankEntries(context.Background(), form, inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := summarizeRanking(form, outputs, &types.Manifest{})
+	if s.Saturated != 1 {
+		t.Errorf("%d thread-saturated, want 1: the anti-recommendation's score didn't depend on it", s.Saturated)
+	}
+	if s.Flagged != 2 || s.Flags["spam"] != 1 || s.Flags["joke"] != 1 {
+		t.Errorf("%d flagged (%v), want spam and joke", s.Flagged, s.Flags)
+	}
+	if got := outputs[1]; got.FinalScore != got.AlgoScore-20-10 {
+		t.Errorf("Stratospire scored %g from %g, want both its saturation and assessment penalties", got.FinalScore, got.AlgoScore)
+	}
+}
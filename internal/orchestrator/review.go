@@ -0,0 +1,99 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// ErrAwaitingReview stops a run after its discovered subreddits were written
+// to the review file; resuming the session with the same file continues
+// with the edited list
+var ErrAwaitingReview = errors.New("waiting for subreddit review")
+
+// Subreddit review states recorded in the manifest
+const (
+	reviewPending = "pending"
+	reviewDone    = "done"
+)
+
+// reviewSubreddits lets the user confirm or edit the discovered subreddits
+// before thread discovery spends the budget on them: through the review file
+// (written, then read back on resume) or the interactive callback. A session
+// is reviewed once.
+func reviewSubreddits(config RunConfig, manifest *types.Manifest) error {
+	if manifest.SubredditReview == reviewDone {
+		return nil
+	}
+	var reviewed []string
+	switch {
+	case config.SubredditReviewFile != "" && manifest.SubredditReview == reviewPending:
+		data, err := os.ReadFile(config.SubredditReviewFile)
+		if err != nil {
+			return fmt.Errorf("reading subreddit review: %w", err)
+		}
+		reviewed = ParseSubredditList(string(data))
+	case config.SubredditReviewFile != "":
+		var b strings.Builder
+		b.WriteString("# Subreddits discovered for: " + manifest.Query + "\n")
+		b.WriteString("# One per line. Delete or comment out (#) any that don't fit, add missing\n")
+		b.WriteString("# ones, then resume the session with the same --subreddit-review file.\n")
+		for _, name := range manifest.Subreddits {
+			b.WriteString(name + "\n")
+		}
+		if err := session.WriteFileAtomic(config.SubredditReviewFile, []byte(b.String()), 0644); err != nil {
+			return fmt.Errorf("writing subreddit review: %w", err)
+		}
+		manifest.SubredditReview = reviewPending
+		return ErrAwaitingReview
+	case config.ReviewSubreddits != nil:
+		var err error
+		if reviewed, err = config.ReviewSubreddits(manifest.Subreddits); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	if len(reviewed) == 0 {
+		return fmt.Errorf("subreddit review left no subreddits")
+	}
+	for _, name := range reviewed {
+		if !slices.Contains(manifest.Subreddits, name) {
+			fmt.Printf("  + r/%s\n", name)
+		}
+	}
+	for _, name := range manifest.Subreddits {
+		if !slices.Contains(reviewed, name) {
+			fmt.Printf("  - r/%s\n", name)
+		}
+	}
+	manifest.Subreddits = reviewed
+	manifest.SubredditReview = reviewDone
+	fmt.Printf("Using %d reviewed subreddits\n", len(reviewed))
+	return nil
+}
+
+// ParseSubredditList reads subreddit names one per line (or comma-separated),
+// ignoring blank lines, # comments and r/ prefixes
+func ParseSubredditList(text string) []string {
+	var names []string
+	for _, line := range strings.Split(text, "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		for _, name := range strings.Split(line, ",") {
+			name = strings.TrimSpace(name)
+			name = strings.TrimPrefix(strings.TrimPrefix(name, "/"), "r/")
+			if name != "" && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
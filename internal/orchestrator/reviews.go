@@ -0,0 +1,89 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"hiveminer/internal/reviews"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// SetReviewFetcher sets the fetcher the reviews phase ingests product review
+// pages with; without one the phase is skipped
+func (o *DefaultOrchestrator) SetReviewFetcher(f reviews.Fetcher) {
+	o.reviews = f
+}
+
+// mineReviews fetches the configured products' reviews and registers each
+// product as a collected thread, so workers extract them like any other
+// thread. Products already in the session are left alone. Returns the
+// number of products added to the manifest.
+func (o *DefaultOrchestrator) mineReviews(ctx context.Context, config RunConfig, manifest *types.Manifest, sessionDir string) (int, error) {
+	added := 0
+	for _, s := range config.Reviews {
+		if ctx.Err() != nil {
+			return added, ctx.Err()
+		}
+		ref, err := reviews.ParseRef(s)
+		if err != nil {
+			fmt.Printf("  Warning: %v\n", err)
+			continue
+		}
+		if session.FindThread(manifest, ref.PostID()) != nil {
+			continue
+		}
+		thread, err := o.reviews.Fetch(ctx, ref)
+		if err != nil {
+			fmt.Printf("  Warning: %v\n", err)
+			continue
+		}
+		if err := o.addCollected(manifest, sessionDir, thread); err != nil {
+			if errors.Is(err, errSessionFull) {
+				fmt.Println("  Session size limit reached, not collecting more reviews")
+				return added, nil
+			}
+			return added, fmt.Errorf("writing reviews: %w", err)
+		}
+		added++
+		fmt.Printf("  + %s (%d reviews)\n", thread.Post.Title, len(thread.Comments))
+	}
+	return added, nil
+}
+
+// addCollected writes a thread fetched outside discovery to the session and
+// registers it as collected, ready for extraction
+func (o *DefaultOrchestrator) addCollected(manifest *types.Manifest, sessionDir string, thread *types.Thread) error {
+	threadPath := filepath.Join(sessionDir, fmt.Sprintf("thread_%s.json", thread.Post.ID))
+	if err := o.guard.writeThread(threadPath, thread); err != nil {
+		return err
+	}
+	now := time.Now()
+	session.AddThread(manifest, types.ThreadState{
+		PostID:      thread.Post.ID,
+		Permalink:   thread.Post.Permalink,
+		Title:       thread.Post.Title,
+		Subreddit:   thread.Post.Subreddit,
+		Source:      thread.Post.Source,
+		NumComments: thread.Post.NumComments,
+		Created:     thread.Post.Created,
+		Status:      types.StatusCollected,
+		CollectedAt: &now,
+	})
+	return nil
+}
+
+// fetchReviews refetches a review thread from its product permalink
+func (o *DefaultOrchestrator) fetchReviews(ctx context.Context, ts types.ThreadState) (*types.Thread, error) {
+	if o.reviews == nil {
+		return nil, fmt.Errorf("no review fetcher configured")
+	}
+	ref, err := reviews.ParseRef(ts.Permalink)
+	if err != nil {
+		return nil, err
+	}
+	return o.reviews.Fetch(ctx, ref)
+}
@@ -0,0 +1,199 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"hiveminer/internal/search"
+	"hiveminer/pkg/types"
+)
+
+const (
+	defaultContextBudget = 120000
+
+	// summaryIDPrefix marks the synthetic comments holding block summaries
+	summaryIDPrefix = "summary_"
+
+	// maxVerbatimComments caps the top comments kept word for word next to the summaries
+	maxVerbatimComments = 30
+)
+
+func contextBudget(config RunConfig) int {
+	if config.ContextBudget > 0 {
+		return config.ContextBudget
+	}
+	return defaultContextBudget
+}
+
+// commentChars is the comment text length of a comment tree
+func commentChars(comments []*types.Comment) int {
+	n := 0
+	for _, c := range comments {
+		n += len(c.Body) + commentChars(c.Replies)
+	}
+	return n
+}
+
+// extractWithinBudget extracts from a thread, first condensing it with
+// map-reduce summarization when its comment text exceeds the context budget.
+// Entries that rest only on summaries are marked SummaryDerived.
+func (o *DefaultOrchestrator) extractWithinBudget(ctx context.Context, config RunConfig, thread *types.Thread, output io.Writer) (*types.ExtractionResult, error) {
+	budget := contextBudget(config)
+	if o.summarizer == nil || commentChars(thread.Comments) <= budget {
+		return o.extractSingle(ctx, thread, config.Form, output)
+	}
+
+	condensed, summarized, err := o.condenseThread(ctx, config, thread, budget)
+	if err != nil {
+		fmt.Fprintf(output, "[%s] summarization failed (%v), extracting from the full thread\n", thread.Post.ID, err)
+		return o.extractSingle(ctx, thread, config.Form, output)
+	}
+	result, err := o.extractSingle(ctx, condensed, config.Form, output)
+	if err != nil {
+		return nil, err
+	}
+	markSummaryDerived(result.Entries, condensed)
+	result.Summarized = summarized
+	return result, nil
+}
+
+// condenseThread replaces a thread's comments with its top comments verbatim
+// (up to a third of the budget) plus one summary per block of the rest, also
+// returning how many comments the summaries cover
+func (o *DefaultOrchestrator) condenseThread(ctx context.Context, config RunConfig, thread *types.Thread, budget int) (*types.Thread, int, error) {
+	// The highest-scored comments stay word for word
+	flat := flattenTree(thread.Comments)
+	sort.SliceStable(flat, func(i, j int) bool { return flat[i].Score > flat[j].Score })
+	verbatim := map[string]bool{}
+	condensed := &types.Thread{Post: thread.Post}
+	used := 0
+	for _, c := range flat {
+		if len(condensed.Comments) >= maxVerbatimComments || used+len(c.Body) > budget/3 {
+			break
+		}
+		cp := *c
+		cp.Replies = nil
+		condensed.Comments = append(condensed.Comments, &cp)
+		verbatim[c.ID] = true
+		used += len(c.Body)
+	}
+
+	// Map: summarize the remaining comments in blocks of top-level trees; the
+	// extraction over summaries plus verbatim comments is the reduce step
+	blocks := blockThread(thread, verbatim, budget/2)
+	var summaries, covered int
+	for i, block := range blocks {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		summary, err := o.summarizer.SummarizeComments(ctx, config.Form, block)
+		if err != nil {
+			fmt.Printf("  [%s] summary of block %d/%d failed: %v\n", thread.Post.ID, i+1, len(blocks), err)
+			continue
+		}
+		summaries++
+		covered += search.CountComments(block.Comments)
+		condensed.Comments = append(condensed.Comments, &types.Comment{
+			ID:     fmt.Sprintf("%s%d", summaryIDPrefix, i+1),
+			Author: "[summary]",
+			Body:   fmt.Sprintf("Summary of %d comments:\n%s", search.CountComments(block.Comments), summary),
+			Score:  blockScore(block.Comments),
+		})
+	}
+	if len(blocks) > 0 && summaries == 0 {
+		return nil, 0, fmt.Errorf("all %d block summaries failed", len(blocks))
+	}
+	fmt.Printf("  [%s] %d chars of comments over the %d budget: summarized %d blocks, kept %d comments verbatim\n",
+		thread.Post.ID, commentChars(thread.Comments), budget, summaries, len(verbatim))
+	return condensed, covered, nil
+}
+
+// blockThread groups a thread's top-level comment trees, minus the verbatim
+// comments, into threads of at most size comment characters
+func blockThread(thread *types.Thread, verbatim map[string]bool, size int) []*types.Thread {
+	var blocks []*types.Thread
+	current := &types.Thread{Post: thread.Post}
+	chars := 0
+	for _, c := range thread.Comments {
+		tree := withoutComments(c, verbatim)
+		if tree == nil {
+			continue
+		}
+		n := commentChars([]*types.Comment{tree})
+		if chars > 0 && chars+n > size {
+			blocks = append(blocks, current)
+			current = &types.Thread{Post: thread.Post}
+			chars = 0
+		}
+		current.Comments = append(current.Comments, tree)
+		chars += n
+	}
+	if len(current.Comments) > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
+
+// withoutComments copies a comment tree, blanking the excluded comments'
+// bodies (their replies stay, for context) and dropping empty subtrees
+func withoutComments(c *types.Comment, excluded map[string]bool) *types.Comment {
+	cp := *c
+	cp.Replies = nil
+	for _, r := range c.Replies {
+		if kept := withoutComments(r, excluded); kept != nil {
+			cp.Replies = append(cp.Replies, kept)
+		}
+	}
+	if excluded[c.ID] {
+		if len(cp.Replies) == 0 {
+			return nil
+		}
+		cp.Body = "[quoted verbatim elsewhere]"
+	}
+	return &cp
+}
+
+func flattenTree(comments []*types.Comment) []*types.Comment {
+	var flat []*types.Comment
+	for _, c := range comments {
+		flat = append(flat, c)
+		flat = append(flat, flattenTree(c.Replies)...)
+	}
+	return flat
+}
+
+func blockScore(comments []*types.Comment) int {
+	score := 0
+	for _, c := range flattenTree(comments) {
+		if c.Score > 0 {
+			score += c.Score
+		}
+	}
+	return score
+}
+
+// markSummaryDerived flags entries with no evidence from the condensed
+// thread's verbatim comments or post, i.e. resting only on summaries (or on
+// comments the summaries cite)
+func markSummaryDerived(entries []types.Entry, condensed *types.Thread) {
+	verbatim := map[string]bool{"post_content": true}
+	for _, c := range condensed.Comments {
+		if !strings.HasPrefix(c.ID, summaryIDPrefix) {
+			verbatim[c.ID] = true
+		}
+	}
+	for i := range entries {
+		direct := false
+		for _, fv := range entries[i].Fields {
+			for _, ev := range fv.Evidence {
+				if verbatim[ev.CommentID] {
+					direct = true
+				}
+			}
+		}
+		entries[i].SummaryDerived = !direct
+	}
+}
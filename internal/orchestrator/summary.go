@@ -0,0 +1,119 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"hiveminer/internal/export"
+	"hiveminer/internal/schema"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// SummaryFile is the machine-readable run summary written to the session
+// directory when a run completes
+const SummaryFile = "summary.json"
+
+// summaryTopEntries is how many of the best entries the summary lists
+const summaryTopEntries = 10
+
+// summarySkipReasons is how many groups of skip reasons the printed summary
+// lists before folding the rest into one line
+const summarySkipReasons = 5
+
+// RunSummary is the outcome of a run, for wrappers and CI that shouldn't
+// parse stdout or the manifest
+type RunSummary struct {
+	Session         string                  `json:"session"`
+	RunID           string                  `json:"run_id"`
+	Form            string                  `json:"form"`
+	Status          string                  `json:"status"`
+	StartedAt       time.Time               `json:"started_at"`
+	CompletedAt     time.Time               `json:"completed_at"`
+	DurationSeconds float64                 `json:"duration_seconds"`
+	Threads         int                     `json:"threads"`
+	Counts          map[string]int          `json:"counts"`                 // threads by status
+	Failures        map[string]int          `json:"failures,omitempty"`     // failed threads by error code
+	SkipReasons     []types.SkipReasonGroup `json:"skip_reasons,omitempty"` // skipped threads by similar reason
+	Phases          []types.PhaseTiming     `json:"phases"`
+	CostUSD         *float64                `json:"cost_usd,omitempty"`        // agent spend, when the backend reports it
+	Requests        map[string]int          `json:"requests,omitempty"`        // Reddit requests by kind
+	SourceRequests  map[string]int          `json:"source_requests,omitempty"` // requests by site
+	Ranking         *types.RankingSummary   `json:"ranking,omitempty"`
+	TopEntries      []SummaryEntry          `json:"top_entries"`
+}
+
+// SummaryEntry is one of the run's best entries
+type SummaryEntry struct {
+	Rank      int      `json:"rank"`
+	Title     string   `json:"title"` // primary field value
+	Score     *float64 `json:"score,omitempty"`
+	ThreadID  string   `json:"thread_id"`
+	ThreadURL string   `json:"thread_url"`
+}
+
+// buildSummary rolls up a finished run
+func buildSummary(config RunConfig, manifest *types.Manifest, sessionDir string) RunSummary {
+	s := RunSummary{
+		Session:  sessionDir,
+		Form:     manifest.Form.Title,
+		Threads:  len(manifest.Threads),
+		Counts:   session.CountByStatus(manifest),
+		Failures: session.CountFailures(manifest),
+	}
+	if len(manifest.Runs) > 0 {
+		run := manifest.Runs[len(manifest.Runs)-1]
+		s.RunID = run.InvocationID
+		s.Status = run.Status
+		s.StartedAt = run.StartedAt
+		s.CompletedAt = run.CompletedAt
+		s.DurationSeconds = run.CompletedAt.Sub(run.StartedAt).Seconds()
+		s.Requests = run.Requests
+		s.SourceRequests = run.SourceRequests
+		s.Ranking = run.Ranking
+		s.Phases = run.Phases
+	}
+	skipList, _ := session.LoadSkipList(config.OutputDir, config.Form.Title)
+	s.SkipReasons = session.SkipReasons(manifest, skipList)
+	if config.Cost != nil {
+		cost := config.Cost()
+		s.CostUSD = &cost
+	}
+
+	primaryID := schema.PrimaryField(config.Form)
+	s.TopEntries = []SummaryEntry{}
+	for _, r := range export.Records(manifest) {
+		if r.Overflow {
+			continue
+		}
+		if len(s.TopEntries) == summaryTopEntries {
+			break
+		}
+		title := r.ThreadTitle
+		if v, ok := r.Fields[primaryID]; ok && v != nil {
+			title = fmt.Sprint(v)
+		}
+		s.TopEntries = append(s.TopEntries, SummaryEntry{
+			Rank:      r.Rank,
+			Title:     title,
+			Score:     r.RankScore,
+			ThreadID:  r.ThreadID,
+			ThreadURL: r.ThreadURL,
+		})
+	}
+	return s
+}
+
+// writeSummary writes the run summary to the session directory
+func writeSummary(sessionDir string, s RunSummary) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling summary: %w", err)
+	}
+	if err := session.WriteFileAtomic(filepath.Join(sessionDir, SummaryFile), data, 0644); err != nil {
+		return fmt.Errorf("writing summary: %w", err)
+	}
+	return nil
+}
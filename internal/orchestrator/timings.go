@@ -0,0 +1,100 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hiveminer/pkg/types"
+)
+
+// phaseClock records each finished phase in the current run's log. Phases
+// run one after another, so the agent calls made since the previous phase
+// finished are the phase's own.
+type phaseClock struct {
+	config     RunConfig
+	manifest   *types.Manifest
+	startCalls int // agent calls before the run started
+	lastCalls  int
+}
+
+func newPhaseClock(config RunConfig, manifest *types.Manifest) *phaseClock {
+	calls := llmCalls(config)
+	return &phaseClock{config: config, manifest: manifest, startCalls: calls, lastCalls: calls}
+}
+
+// record adds a phase that began at start, with the threads it added or
+// processed and the entries it ranked
+func (c *phaseClock) record(name string, start time.Time, threads, entries int) {
+	if len(c.manifest.Runs) == 0 {
+		return
+	}
+	calls := llmCalls(c.config)
+	run := &c.manifest.Runs[len(c.manifest.Runs)-1]
+	run.Phases = append(run.Phases, types.PhaseTiming{
+		Name:     name,
+		Seconds:  time.Since(start).Seconds(),
+		Threads:  threads,
+		Entries:  entries,
+		LLMCalls: calls - c.lastCalls,
+	})
+	run.LLMCalls = calls - c.startCalls
+	c.lastCalls = calls
+}
+
+// llmCalls is the backend's agent call count so far, 0 if it doesn't report one
+func llmCalls(config RunConfig) int {
+	if config.LLMCalls == nil {
+		return 0
+	}
+	return config.LLMCalls()
+}
+
+// PhaseTimingLines describes a run's phase timings, one line per phase
+// ("collect      9m12s  48 threads (5.2/min)  310 LLM calls")
+func PhaseTimingLines(run types.RunLog) []string {
+	lines := make([]string, 0, len(run.Phases))
+	for _, p := range run.Phases {
+		line := fmt.Sprintf("%-20s %8s", p.Name, formatDuration(time.Duration(p.Seconds*float64(time.Second))))
+		if p.Threads > 0 {
+			line += "  " + formatThroughput(p.Threads, "threads", p.Seconds)
+		}
+		if p.Entries > 0 {
+			line += "  " + formatThroughput(p.Entries, "entries", p.Seconds)
+		}
+		if p.LLMCalls > 0 {
+			line += fmt.Sprintf("  %d LLM calls", p.LLMCalls)
+		}
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	return lines
+}
+
+// PhaseTimingSummary sums up a run's timings on one line ("12m04s: collect
+// 9m12s, rank 2m01s · 5.2 threads/min · 340 LLM calls")
+func PhaseTimingSummary(run types.RunLog) string {
+	var total float64
+	var threads int
+	parts := make([]string, 0, len(run.Phases))
+	for _, p := range run.Phases {
+		total += p.Seconds
+		threads += p.Threads
+		parts = append(parts, p.Name+" "+formatDuration(time.Duration(p.Seconds*float64(time.Second))))
+	}
+	s := formatDuration(time.Duration(total*float64(time.Second))) + ": " + strings.Join(parts, ", ")
+	if threads > 0 && total > 0 {
+		s += fmt.Sprintf(" · %.1f threads/min", float64(threads)/total*60)
+	}
+	if run.LLMCalls > 0 {
+		s += fmt.Sprintf(" · %d LLM calls", run.LLMCalls)
+	}
+	return s
+}
+
+// formatThroughput renders a count with its per-minute rate ("48 threads (5.2/min)")
+func formatThroughput(n int, unit string, seconds float64) string {
+	if seconds <= 0 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %s (%.1f/min)", n, unit, float64(n)/seconds*60)
+}
@@ -0,0 +1,93 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"hiveminer/internal/agent"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// headRanker is an optional interface for rankers that can reorder the top
+// of the ranking by comparing entries against each other
+type headRanker interface {
+	RankHead(ctx context.Context, form *types.Form, head []agent.RankInput) ([]int, error)
+}
+
+// rankHead runs the tournament stage over the session's top size entries.
+// The head keeps its set of scores, reassigned in tournament order, so
+// entries below the head are unaffected.
+func (o *DefaultOrchestrator) rankHead(ctx context.Context, config RunConfig, store *session.Store, size int) error {
+	hr, ok := o.ranker.(headRanker)
+	if !ok {
+		return nil
+	}
+
+	var head []agent.RankInput
+	store.View(func(m *types.Manifest) {
+		for _, ts := range m.Threads {
+			for j, entry := range ts.Entries {
+				if entry.RankOrder > 0 {
+					head = append(head, agent.RankInput{
+						ThreadPostID: ts.PostID,
+						EntryIndex:   j,
+						Entry:        entry,
+						ThreadScore:  ts.Score,
+						NumComments:  ts.NumComments,
+						Source:       ts.Source,
+					})
+				}
+			}
+		}
+	})
+	sort.Slice(head, func(i, j int) bool { return head[i].Entry.RankOrder < head[j].Entry.RankOrder })
+	if len(head) > size {
+		head = head[:size]
+	}
+	if len(head) < 2 {
+		return nil
+	}
+
+	fmt.Printf("  Tournament: comparing the top %d entries\n", len(head))
+	order, err := hr.RankHead(ctx, config.Form, head)
+	if err != nil {
+		return err
+	}
+	if len(order) != len(head) {
+		return fmt.Errorf("tournament returned %d entries, want %d", len(order), len(head))
+	}
+
+	scores := make([]float64, len(head))
+	orders := make([]int, len(head))
+	for i, in := range head {
+		scores[i] = *in.Entry.RankScore
+		orders[i] = in.Entry.RankOrder
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(scores)))
+	moved := 0
+	store.Update(func(m *types.Manifest) error {
+		for pos, idx := range order {
+			in := head[idx]
+			if idx != pos {
+				moved++
+			}
+			t := session.FindThread(m, in.ThreadPostID)
+			if t == nil {
+				continue
+			}
+			entry := rankedEntry(t, in.Entry.ID, in.EntryIndex)
+			if entry == nil {
+				continue
+			}
+			score := scores[pos]
+			entry.RankScore = &score
+			entry.RankOrder = orders[pos]
+			entry.TournamentRank = pos + 1
+		}
+		return nil
+	})
+	fmt.Printf("  Tournament moved %d of the top %d entries\n", moved, len(head))
+	return nil
+}
@@ -0,0 +1,49 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hiveminer/internal/session"
+	"hiveminer/internal/transcripts"
+	"hiveminer/pkg/types"
+)
+
+// SetTranscriptFetcher sets the fetcher the transcripts phase reads podcasts
+// and videos with; without one the phase is skipped
+func (o *DefaultOrchestrator) SetTranscriptFetcher(f transcripts.Fetcher) {
+	o.transcripts = f
+}
+
+// mineTranscripts fetches or transcribes the configured media and registers
+// each transcript as a collected thread, so workers extract it like any other
+// thread. Media already in the session is left alone. Returns the number of
+// transcripts added to the manifest.
+func (o *DefaultOrchestrator) mineTranscripts(ctx context.Context, config RunConfig, manifest *types.Manifest, sessionDir string) (int, error) {
+	added := 0
+	for _, mediaURL := range config.Transcripts {
+		if ctx.Err() != nil {
+			return added, ctx.Err()
+		}
+		if session.FindThread(manifest, transcripts.PostID(mediaURL)) != nil {
+			continue
+		}
+		fmt.Printf("  Fetching %s\n", mediaURL)
+		thread, err := o.transcripts.Fetch(ctx, mediaURL)
+		if err != nil {
+			fmt.Printf("  Warning: %v\n", err)
+			continue
+		}
+		if err := o.addCollected(manifest, sessionDir, thread); err != nil {
+			if errors.Is(err, errSessionFull) {
+				fmt.Println("  Session size limit reached, not collecting more transcripts")
+				return added, nil
+			}
+			return added, fmt.Errorf("writing transcript: %w", err)
+		}
+		added++
+		fmt.Printf("  + %s (%d segments)\n", thread.Post.Title, len(thread.Comments))
+	}
+	return added, nil
+}
@@ -0,0 +1,108 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"hiveminer/internal/search"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+const (
+	// userHistoryLimit is how many submissions and comments are listed for a user
+	userHistoryLimit = 100
+
+	// userCommentsPerThread is how many history comments go into one pseudo-thread
+	userCommentsPerThread = 50
+)
+
+// mineUser registers a user's submissions as pending threads and pages of their
+// comment history as collected pseudo-threads. This replaces subreddit and thread
+// discovery in user-history mode. Returns the number of threads added.
+func (o *DefaultOrchestrator) mineUser(ctx context.Context, config RunConfig, manifest *types.Manifest, sessionDir string) (int, error) {
+	uf, ok := o.searcher.(search.UserFetcher)
+	if !ok {
+		return 0, fmt.Errorf("searcher does not support user history")
+	}
+
+	added := 0
+	posts, err := uf.ListUserSubmissions(ctx, config.User, "top", userHistoryLimit)
+	if err != nil {
+		fmt.Printf("  Warning: listing submissions for u/%s failed: %v\n", config.User, err)
+	}
+	for _, post := range posts {
+		if session.FindThread(manifest, post.ID) != nil {
+			continue
+		}
+		session.AddThread(manifest, types.ThreadState{
+			PostID:      post.ID,
+			Permalink:   post.Permalink,
+			Title:       post.Title,
+			Subreddit:   post.Subreddit,
+			Score:       post.Score,
+			NumComments: post.NumComments,
+			Created:     post.Created,
+			Status:      types.StatusPending,
+		})
+		added++
+	}
+	fmt.Printf("  %d submissions by u/%s\n", len(posts), config.User)
+
+	comments, err := uf.ListUserComments(ctx, config.User, "top", userHistoryLimit)
+	if err != nil {
+		fmt.Printf("  Warning: listing comments for u/%s failed: %v\n", config.User, err)
+	}
+	for _, thread := range search.UserCommentThreads(config.User, comments, userCommentsPerThread) {
+		if session.FindThread(manifest, thread.Post.ID) != nil {
+			continue
+		}
+		threadPath := filepath.Join(sessionDir, fmt.Sprintf("thread_%s.json", thread.Post.ID))
+		if err := o.guard.writeThread(threadPath, thread); err != nil {
+			if errors.Is(err, errSessionFull) {
+				fmt.Println("  Session size limit reached, not collecting more user commentss")
+				return added, nil
+			}
+			return added, fmt.Errorf("writing user comments: %w", err)
+		}
+
+		now := time.Now()
+		session.AddThread(manifest, types.ThreadState{
+			PostID:      thread.Post.ID,
+			Permalink:   thread.Post.Permalink,
+			Title:       thread.Post.Title,
+			Source:      types.SourceUser,
+			NumComments: len(thread.Comments),
+			Status:      types.StatusCollected,
+			CollectedAt: &now,
+		})
+		added++
+	}
+	fmt.Printf("  %d comments by u/%s\n", len(comments), config.User)
+
+	return added, nil
+}
+
+// fetchUserComments rebuilds a page of a user's comment history
+func (o *DefaultOrchestrator) fetchUserComments(ctx context.Context, config RunConfig, ts types.ThreadState) (*types.Thread, error) {
+	uf, ok := o.searcher.(search.UserFetcher)
+	if !ok {
+		return nil, fmt.Errorf("searcher does not support user history")
+	}
+	page, ok := search.ParseUserCommentsPostID(ts.PostID)
+	if !ok {
+		return nil, fmt.Errorf("invalid user comments id: %s", ts.PostID)
+	}
+	comments, err := uf.ListUserComments(ctx, config.User, "top", userHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	threads := search.UserCommentThreads(config.User, comments, userCommentsPerThread)
+	if page < 1 || page > len(threads) {
+		return nil, fmt.Errorf("user comments page %d no longer exists", page)
+	}
+	return threads[page-1], nil
+}
@@ -0,0 +1,235 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"hiveminer/internal/agent"
+	"hiveminer/internal/search"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// unsupportedConfidence scales the confidence of values the verifier found
+// no support for in the thread
+const unsupportedConfidence = 0.5
+
+// SetVerifier sets the verifier the verify phase checks entries with;
+// without one the phase is skipped
+func (o *DefaultOrchestrator) SetVerifier(v agent.Verifier) {
+	o.verifier = v
+}
+
+// verifyEntries has the verifier check every unranked thread's entries that
+// haven't been verified against the thread they came from. Values it finds
+// unsupported lose confidence; the ranker penalizes unsupported entries and
+// jokes. Returns how many entries were verified and how many were found
+// unsupported.
+func (o *DefaultOrchestrator) verifyEntries(ctx context.Context, config RunConfig, store *session.Store, sessionDir string) (int, int, error) {
+	var pending []types.ThreadState
+	store.View(func(m *types.Manifest) {
+		for _, ts := range m.Threads {
+			if ts.Status != types.StatusExtracted {
+				continue
+			}
+			for _, entry := range ts.Entries {
+				if !entry.Overflow && entry.Verification == nil {
+					pending = append(pending, ts)
+					break
+				}
+			}
+		}
+	})
+	if len(pending) == 0 {
+		fmt.Println("  No entries to verify")
+		return 0, 0, nil
+	}
+	fmt.Printf("  Verifying entries of %d threads\n", len(pending))
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	var (
+		mu                    sync.Mutex
+		verified, unsupported int
+		wg                    sync.WaitGroup
+	)
+	jobs := make(chan types.ThreadState)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ts := range jobs {
+				v, u, err := o.verifyThread(ctx, config, store, ts, sessionDir)
+				if err != nil {
+					if ctx.Err() == nil {
+						fmt.Printf("  Warning: [%s] verification failed: %v\n", ts.PostID, err)
+					}
+					continue
+				}
+				mu.Lock()
+				verified += v
+				unsupported += u
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, ts := range pending {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- ts
+	}
+	close(jobs)
+	wg.Wait()
+	return verified, unsupported, ctx.Err()
+}
+
+// verifyThread verifies one thread's unverified entries against the thread as
+// extraction saw it and stores the verdicts
+func (o *DefaultOrchestrator) verifyThread(ctx context.Context, config RunConfig, store *session.Store, ts types.ThreadState, sessionDir string) (int, int, error) {
+	thread, err := o.loadThreadForExtraction(ctx, config, ts, sessionDir)
+	if err != nil {
+		return 0, 0, fmt.Errorf("loading thread: %w", err)
+	}
+	var indexes []int
+	var entries []types.Entry
+	for j, entry := range ts.Entries {
+		if !entry.Overflow && entry.Verification == nil {
+			indexes = append(indexes, j)
+			entries = append(entries, entry)
+		}
+	}
+	if ts.Source == "" {
+		thread = truncateThread(config, thread)
+	}
+	verdicts, err := o.verifyChunked(ctx, config, thread, entries)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	verified, unsupported := 0, 0
+	err = store.UpdateThread(ts.PostID, func(t *types.ThreadState) error {
+		for k, v := range verdicts {
+			j := indexes[k]
+			if v == nil || j >= len(t.Entries) || t.Entries[j].ID != entries[k].ID {
+				continue
+			}
+			applyVerification(&t.Entries[j], v)
+			verified++
+			if v.Verdict == types.VerdictUnsupported || len(v.Issues) > 0 {
+				unsupported++
+				fmt.Printf("  [%s] %s: %s%s\n", ts.PostID, v.Verdict, formatIssues(v.Issues), truncate(v.Reason, 80))
+			}
+		}
+		return nil
+	})
+	return verified, unsupported, err
+}
+
+// verdictStrength orders verdicts from none to fully supported
+var verdictStrength = map[string]int{
+	types.VerdictUnsupported: 1,
+	types.VerdictPartial:     2,
+	types.VerdictSupported:   3,
+}
+
+// verifyChunked verifies entries against a thread in chunks of top-level
+// comment trees that stay within the chunk size and context budget, as
+// extraction's calls do. An entry a chunk fully supports is settled there;
+// the rest are checked against the next chunk, and keep the strongest
+// verdict any chunk gave.
+func (o *DefaultOrchestrator) verifyChunked(ctx context.Context, config RunConfig, thread *types.Thread, entries []types.Entry) ([]*types.Verification, error) {
+	verdicts := make([]*types.Verification, len(entries))
+	pending := make([]int, len(entries))
+	for i := range pending {
+		pending[i] = i
+	}
+	for _, chunk := range verifyChunks(config, thread) {
+		if len(pending) == 0 {
+			break
+		}
+		batch := make([]types.Entry, len(pending))
+		for k, i := range pending {
+			batch[k] = entries[i]
+		}
+		found, err := o.verifier.VerifyEntries(ctx, config.Form, chunk, batch)
+		if err != nil {
+			return nil, err
+		}
+		var next []int
+		for k, i := range pending {
+			var v *types.Verification
+			if k < len(found) {
+				v = found[k]
+			}
+			if v != nil && (verdicts[i] == nil || verdictStrength[v.Verdict] > verdictStrength[verdicts[i].Verdict]) {
+				verdicts[i] = v
+			}
+			if v == nil || v.Verdict != types.VerdictSupported || len(v.Issues) > 0 {
+				next = append(next, i)
+			}
+		}
+		pending = next
+	}
+	return verdicts, nil
+}
+
+// verifyChunks splits a thread into top-level comment trees grouped within
+// the run's chunk size and context budget; a single tree over either is a
+// chunk of its own
+func verifyChunks(config RunConfig, thread *types.Thread) []*types.Thread {
+	size, budget := chunkSize(config), contextBudget(config)
+	if search.CountComments(thread.Comments) <= size && commentChars(thread.Comments) <= budget {
+		return []*types.Thread{thread}
+	}
+	var chunks []*types.Thread
+	current := &types.Thread{Post: thread.Post}
+	count, chars := 0, 0
+	for _, c := range thread.Comments {
+		tree := []*types.Comment{c}
+		n, m := search.CountComments(tree), commentChars(tree)
+		if count > 0 && (count+n > size || chars+m > budget) {
+			chunks = append(chunks, current)
+			current = &types.Thread{Post: thread.Post}
+			count, chars = 0, 0
+		}
+		current.Comments = append(current.Comments, c)
+		count += n
+		chars += m
+	}
+	if len(current.Comments) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// applyVerification records a verdict on an entry, scaling down the
+// confidence of the values it found unsupported. The verdict marks entries
+// whose evidence advises against the item, and clears the heuristic's mark
+// from entries it finds fully supported.
+func applyVerification(entry *types.Entry, v *types.Verification) {
+	entry.Verification = v
+	switch {
+	case v.AntiRecommendation && entry.AntiRecommendation == nil:
+		entry.AntiRecommendation = &types.AntiRecommendation{Source: types.AntiSourceVerify, Reason: v.Reason}
+	case !v.AntiRecommendation && v.Verdict == types.VerdictSupported && entry.AntiRecommendation != nil &&
+		entry.AntiRecommendation.Source == types.AntiSourceHeuristic:
+		entry.AntiRecommendation = nil
+	}
+	for i := range entry.Fields {
+		if _, ok := v.Fields[entry.Fields[i].ID]; ok {
+			entry.Fields[i].Confidence *= unsupportedConfidence
+		}
+	}
+}
+
+func formatIssues(issues []string) string {
+	if len(issues) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(issues, ", ") + ") "
+}
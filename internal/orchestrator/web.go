@@ -0,0 +1,48 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hiveminer/internal/session"
+	"hiveminer/internal/webpage"
+	"hiveminer/pkg/types"
+)
+
+// SetPageFetcher sets the fetcher the web phase reads article URLs with;
+// without one the phase is skipped
+func (o *DefaultOrchestrator) SetPageFetcher(f webpage.Fetcher) {
+	o.pages = f
+}
+
+// mineWebPages fetches the configured article URLs and registers each page's
+// readable content as a collected thread, so workers extract it like any
+// other thread. Pages already in the session are left alone. Returns the
+// number of pages added to the manifest.
+func (o *DefaultOrchestrator) mineWebPages(ctx context.Context, config RunConfig, manifest *types.Manifest, sessionDir string) (int, error) {
+	added := 0
+	for _, pageURL := range config.WebPages {
+		if ctx.Err() != nil {
+			return added, ctx.Err()
+		}
+		if session.FindThread(manifest, webpage.PostID(pageURL)) != nil {
+			continue
+		}
+		thread, err := o.pages.Fetch(ctx, pageURL)
+		if err != nil {
+			fmt.Printf("  Warning: %v\n", err)
+			continue
+		}
+		if err := o.addCollected(manifest, sessionDir, thread); err != nil {
+			if errors.Is(err, errSessionFull) {
+				fmt.Println("  Session size limit reached, not collecting more web pages")
+				return added, nil
+			}
+			return added, fmt.Errorf("writing web page: %w", err)
+		}
+		added++
+		fmt.Printf("  + %s (%s)\n", thread.Post.Title, thread.Post.Domain)
+	}
+	return added, nil
+}
@@ -0,0 +1,159 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"hiveminer/internal/search"
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// wikiPageKeywords are page-name fragments that usually indicate dense recommendation content
+var wikiPageKeywords = []string{"faq", "recommend", "guide", "index", "buying", "best", "resources", "beginner", "list"}
+
+// maxWikiPagesPerSubreddit caps how many wiki pages are mined from a single subreddit
+const maxWikiPagesPerSubreddit = 3
+
+// mineWiki fetches relevant wiki pages and sidebars for the target subreddits and
+// registers them as collected threads so workers extract them like any other thread.
+// Returns the number of pages added to the manifest.
+func (o *DefaultOrchestrator) mineWiki(ctx context.Context, config RunConfig, manifest *types.Manifest, sessionDir string) (int, error) {
+	wf, ok := o.searcher.(search.WikiFetcher)
+	if !ok {
+		return 0, fmt.Errorf("searcher does not support wiki pages")
+	}
+
+	added := 0
+	for _, sub := range config.Subreddits {
+		if ctx.Err() != nil {
+			return added, ctx.Err()
+		}
+
+		var threads []*types.Thread
+		if session.FindThread(manifest, search.SidebarPostID(sub)) == nil {
+			if sidebar, err := wf.GetSidebar(ctx, sub); err == nil {
+				threads = append(threads, sidebar)
+			}
+		}
+
+		pages, err := wf.ListWikiPages(ctx, sub)
+		if err != nil {
+			fmt.Printf("  r/%s: no wiki (%v)\n", sub, err)
+		}
+		for _, page := range selectWikiPages(pages, config.Form) {
+			if session.FindThread(manifest, search.WikiPostID(sub, page)) != nil {
+				continue
+			}
+			thread, err := wf.GetWikiPage(ctx, sub, page)
+			if err != nil {
+				fmt.Printf("  Warning: wiki page r/%s/wiki/%s failed: %v\n", sub, page, err)
+				continue
+			}
+			threads = append(threads, thread)
+		}
+
+		for _, thread := range threads {
+			threadPath := filepath.Join(sessionDir, fmt.Sprintf("thread_%s.json", thread.Post.ID))
+			if err := o.guard.writeThread(threadPath, thread); err != nil {
+				if errors.Is(err, errSessionFull) {
+					fmt.Println("  Session size limit reached, not collecting more wiki pages")
+					return added, nil
+				}
+				return added, fmt.Errorf("writing wiki page: %w", err)
+			}
+
+			now := time.Now()
+			session.AddThread(manifest, types.ThreadState{
+				PostID:      thread.Post.ID,
+				Permalink:   thread.Post.Permalink,
+				Title:       thread.Post.Title,
+				Subreddit:   thread.Post.Subreddit,
+				Source:      thread.Post.Source,
+				Created:     thread.Post.Created,
+				Status:      types.StatusCollected,
+				CollectedAt: &now,
+			})
+			added++
+			fmt.Printf("  + %s\n", thread.Post.Title)
+		}
+	}
+
+	return added, nil
+}
+
+// selectWikiPages picks the wiki pages most likely to hold recommendations,
+// matching page names against generic keywords and the form's search hints.
+func selectWikiPages(pages []string, form *types.Form) []string {
+	keywords := append([]string{}, wikiPageKeywords...)
+	for _, hint := range form.SearchHints {
+		for _, word := range strings.Fields(strings.ToLower(hint)) {
+			if len(word) >= 4 {
+				keywords = append(keywords, word)
+			}
+		}
+	}
+
+	var selected []string
+	for _, page := range pages {
+		if len(selected) >= maxWikiPagesPerSubreddit {
+			break
+		}
+		name := strings.ToLower(page)
+		if strings.HasPrefix(name, "config/") || strings.HasPrefix(name, "automoderator") {
+			continue
+		}
+		for _, kw := range keywords {
+			if strings.Contains(name, kw) {
+				selected = append(selected, page)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// fetchThread fetches thread content for a thread state, dispatching on its source type
+func (o *DefaultOrchestrator) fetchThread(ctx context.Context, config RunConfig, ts types.ThreadState) (*types.Thread, error) {
+	switch ts.Source {
+	case types.SourceUser:
+		return o.fetchUserComments(ctx, config, ts)
+	case types.SourceReviews:
+		return o.fetchReviews(ctx, ts)
+	case types.SourceTranscript:
+		if o.transcripts == nil {
+			return nil, fmt.Errorf("no transcript fetcher configured")
+		}
+		return o.transcripts.Fetch(ctx, ts.Permalink)
+	case types.SourceWeb:
+		if o.pages == nil {
+			return nil, fmt.Errorf("no web page fetcher configured")
+		}
+		return o.pages.Fetch(ctx, ts.Permalink)
+	case types.SourceWiki, types.SourceSidebar:
+		wf, ok := o.searcher.(search.WikiFetcher)
+		if !ok {
+			return nil, fmt.Errorf("searcher does not support wiki pages")
+		}
+		if ts.Source == types.SourceSidebar {
+			return wf.GetSidebar(ctx, ts.Subreddit)
+		}
+		sub, page, ok := search.ParseWikiPermalink(ts.Permalink)
+		if !ok {
+			return nil, fmt.Errorf("invalid wiki permalink: %s", ts.Permalink)
+		}
+		return wf.GetWikiPage(ctx, sub, page)
+	default:
+		if isMegathread(ts) {
+			return o.fetchMegathread(ctx, config, ts)
+		}
+		if pf, ok := o.searcher.(search.PagedThreadFetcher); ok && config.CommentSort != "" {
+			return pf.GetThreadPaged(ctx, ts.Permalink, search.ThreadOptions{Sort: config.CommentSort, Limit: threadFetchLimit(config)})
+		}
+		return o.searcher.GetThread(ctx, ts.Permalink, threadFetchLimit(config))
+	}
+}
@@ -0,0 +1,43 @@
+package orchestrator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"hiveminer/internal/session"
+	"hiveminer/pkg/types"
+)
+
+// yieldAware is an optional interface for discoverers that take the
+// subreddits' yield into account
+type yieldAware interface {
+	SetYieldNotes(notes []string)
+}
+
+// pastYield returns the subreddits' yield for the form in other sessions
+func pastYield(config RunConfig, sessionDir string) map[string]types.SubredditYield {
+	history, err := session.LoadYieldHistory(config.OutputDir, config.Form.Title)
+	if err != nil {
+		fmt.Printf("  Warning: %v\n", err)
+		return nil
+	}
+	return session.PastYield(history, filepath.Base(sessionDir))
+}
+
+// recordYield tallies the session's subreddit yield into the manifest and
+// the form's yield history, for later rounds and sessions to learn from
+func recordYield(config RunConfig, manifest *types.Manifest, sessionDir string) {
+	manifest.SubredditYield = session.ComputeYield(manifest)
+	if len(manifest.SubredditYield) == 0 {
+		return
+	}
+	history, err := session.LoadYieldHistory(config.OutputDir, config.Form.Title)
+	if err != nil {
+		fmt.Printf("  Warning: %v\n", err)
+		return
+	}
+	history.Sessions[filepath.Base(sessionDir)] = manifest.SubredditYield
+	if err := session.SaveYieldHistory(config.OutputDir, history); err != nil {
+		fmt.Printf("  Warning: %v\n", err)
+	}
+}
@@ -0,0 +1,175 @@
+// Package postprocess runs a form's post-processor hook over extracted
+// entries before they reach the manifest.
+package postprocess
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"hiveminer/pkg/types"
+)
+
+// DefaultTimeout bounds one entry's trip through the hook
+const DefaultTimeout = 30 * time.Second
+
+// Request is the JSON a hook receives for each entry
+type Request struct {
+	Form   string        `json:"form"`
+	Thread RequestThread `json:"thread"`
+	Entry  types.Entry   `json:"entry"`
+}
+
+// RequestThread is the thread an entry was extracted from
+type RequestThread struct {
+	PostID    string `json:"post_id"`
+	Permalink string `json:"permalink"`
+	Title     string `json:"title"`
+	Subreddit string `json:"subreddit"`
+}
+
+// Response is the JSON a hook answers with. A nil Entry keeps the entry as
+// sent; Reject drops it.
+type Response struct {
+	Entry  *types.Entry `json:"entry,omitempty"`
+	Reject bool         `json:"reject,omitempty"`
+	Reason string       `json:"reason,omitempty"`
+}
+
+// Hook passes entries through a form's post-processor
+type Hook struct {
+	config  types.PostProcessHook
+	form    string
+	timeout time.Duration
+	client  *http.Client
+}
+
+// New returns the form's hook, or nil if it has none
+func New(form *types.Form) *Hook {
+	if form == nil || form.PostProcess == nil {
+		return nil
+	}
+	h := &Hook{config: *form.PostProcess, form: form.Title, timeout: DefaultTimeout}
+	if d, err := time.ParseDuration(h.config.Timeout); err == nil && d > 0 {
+		h.timeout = d
+	}
+	if h.config.URL != "" {
+		h.client = &http.Client{Timeout: h.timeout}
+	}
+	return h
+}
+
+// Apply sends each entry through the hook and returns the entries to keep
+// and the reasons given for each rejected one (empty when the hook gave
+// none). A failed call keeps or rejects the entry per the hook's on_error;
+// its error is returned in errs either way.
+func (h *Hook) Apply(ctx context.Context, thread types.ThreadState, entries []types.Entry) (kept []types.Entry, rejected []string, errs []error) {
+	meta := RequestThread{
+		PostID:    thread.PostID,
+		Permalink: thread.Permalink,
+		Title:     thread.Title,
+		Subreddit: thread.Subreddit,
+	}
+	for i, entry := range entries {
+		resp, err := h.call(ctx, Request{Form: h.form, Thread: meta, Entry: entry})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entry %d: %w", i+1, err))
+			if h.config.OnError == "reject" {
+				rejected = append(rejected, "hook failed")
+			} else {
+				kept = append(kept, entry)
+			}
+			continue
+		}
+		if resp.Reject {
+			rejected = append(rejected, resp.Reason)
+			continue
+		}
+		if resp.Entry != nil {
+			// The hook may rewrite values but not the extraction's bookkeeping
+			resp.Entry.Provenance = entry.Provenance
+			resp.Entry.SummaryDerived = entry.SummaryDerived
+			entry = *resp.Entry
+		}
+		kept = append(kept, entry)
+	}
+	return kept, rejected, errs
+}
+
+// call sends one entry to the hook
+func (h *Hook) call(ctx context.Context, req Request) (*Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encoding request: %w", err)
+	}
+	var out []byte
+	if h.config.URL != "" {
+		out, err = h.post(ctx, body)
+	} else {
+		out, err = h.run(ctx, body)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var resp Response
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return &resp, nil // no answer keeps the entry unchanged
+	}
+	if err := json.Unmarshal(out, &resp); err != nil {
+		return nil, fmt.Errorf("parsing hook response: %w", err)
+	}
+	if resp.Entry != nil && len(resp.Entry.Fields) == 0 {
+		return nil, fmt.Errorf("hook returned an entry without fields")
+	}
+	return &resp, nil
+}
+
+// run pipes a request through the hook command
+func (h *Hook) run(ctx context.Context, body []byte) ([]byte, error) {
+	runCtx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(runCtx, h.config.Command[0], h.config.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if runCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("hook timed out after %s", h.timeout)
+		}
+		return nil, fmt.Errorf("running hook: %w", err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// post sends a request to the hook webhook
+func (h *Hook) post(ctx context.Context, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", h.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("reading webhook response: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("webhook returned %s: %s", resp.Status, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
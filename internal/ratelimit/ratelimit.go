@@ -0,0 +1,288 @@
+// Package ratelimit keeps a run polite to every site it reads: each source
+// (Reddit, Amazon, Google, web pages, transcripts, ...) gets its own minimum
+// delay between requests, retry backoff and User-Agent, and its requests are
+// counted separately so a run's footprint on each site can be audited.
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sources that requests are accounted to
+const (
+	SourceReddit      = "reddit"
+	SourceAmazon      = "amazon"
+	SourceGoogle      = "google"
+	SourceWeb         = "web"
+	SourceTranscripts = "transcripts"
+	SourceOpenAI      = "openai"
+)
+
+// Policy is how politely one source is fetched
+type Policy struct {
+	Delay             Duration `json:"delay,omitempty"`               // minimum time between requests
+	RequestsPerMinute int      `json:"requests_per_minute,omitempty"` // cap on requests, spread evenly over the minute (0 = none)
+	MaxRetries        int      `json:"max_retries,omitempty"`         // retries of rate-limited (429) and unavailable (5xx) responses
+	Backoff           Duration `json:"backoff,omitempty"`             // wait before the first retry, doubling after each with jitter; Retry-After wins
+	UserAgent         string   `json:"user_agent,omitempty"`          // overrides the source's default User-Agent
+}
+
+// Interval returns the minimum time between requests: the delay, or the
+// spacing the per-minute cap needs if that's longer
+func (p Policy) Interval() time.Duration {
+	return Interval(time.Duration(p.Delay), p.RequestsPerMinute)
+}
+
+// Interval returns the longer of delay and the spacing that keeps requests
+// under perMinute a minute (0 = no cap)
+func Interval(delay time.Duration, perMinute int) time.Duration {
+	if perMinute > 0 {
+		if spacing := time.Minute / time.Duration(perMinute); spacing > delay {
+			return spacing
+		}
+	}
+	return delay
+}
+
+// Jitter returns a retry wait of d plus up to half again at random, so
+// requests that failed together don't all retry at the same moment
+func Jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d + time.Duration(rand.Int63n(int64(d)/2+1))
+}
+
+// Defaults are the policies of sources the config doesn't mention
+var Defaults = map[string]Policy{
+	SourceReddit:      {RequestsPerMinute: 60, MaxRetries: 3, Backoff: Duration(2 * time.Second)},
+	SourceAmazon:      {Delay: Duration(time.Second), MaxRetries: 2, Backoff: Duration(5 * time.Second)},
+	SourceGoogle:      {MaxRetries: 2, Backoff: Duration(time.Second)},
+	SourceWeb:         {Delay: Duration(500 * time.Millisecond), MaxRetries: 1, Backoff: Duration(2 * time.Second)},
+	SourceTranscripts: {Delay: Duration(time.Second), MaxRetries: 2, Backoff: Duration(2 * time.Second)},
+	SourceOpenAI:      {MaxRetries: 2, Backoff: Duration(5 * time.Second)},
+}
+
+// Config holds per-source policies, keyed by source name
+type Config struct {
+	Sources map[string]Policy `json:"sources"`
+}
+
+// LoadConfig reads a politeness config file:
+//
+//	{"sources": {"reddit": {"requests_per_minute": 30, "max_retries": 3, "backoff": "10s"},
+//	             "web": {"delay": "1s", "user_agent": "my-bot/1.0"}}}
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading politeness config: %w", err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing politeness config %s: %w", path, err)
+	}
+	for name, p := range cfg.Sources {
+		if p.Delay < 0 || p.Backoff < 0 || p.MaxRetries < 0 || p.RequestsPerMinute < 0 {
+			return nil, fmt.Errorf("politeness config %s: negative value for %s", path, name)
+		}
+	}
+	return &cfg, nil
+}
+
+// ConfigFromEnv loads the config named by HIVEMINER_POLITENESS, which run
+// and the debug commands set from --politeness so hiveminer subprocesses
+// started by agents follow it too. Returns nil if it isn't set.
+func ConfigFromEnv() (*Config, error) {
+	path := os.Getenv("HIVEMINER_POLITENESS")
+	if path == "" {
+		return nil, nil
+	}
+	return LoadConfig(path)
+}
+
+// Policy returns a source's policy: the config's, else the default
+func (c *Config) Policy(source string) Policy {
+	if c != nil {
+		if p, ok := c.Sources[source]; ok {
+			return p
+		}
+	}
+	return Defaults[source]
+}
+
+// Duration is a time.Duration written as a string like "1.5s" in JSON
+type Duration time.Duration
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON reads a duration string such as "500ms" or "2s"
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"2s\"")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Limiter spaces out and counts requests per source. One limiter is shared
+// by everything a run fetches with.
+type Limiter struct {
+	config *Config
+
+	mu     sync.Mutex
+	next   map[string]time.Time // earliest time of each source's next request
+	counts map[string]int
+}
+
+// NewLimiter returns a limiter applying config's policies; config may be nil
+// for the defaults
+func NewLimiter(config *Config) *Limiter {
+	return &Limiter{config: config, next: map[string]time.Time{}, counts: map[string]int{}}
+}
+
+// Policy returns the policy the limiter applies to a source
+func (l *Limiter) Policy(source string) Policy {
+	return l.config.Policy(source)
+}
+
+// Wait blocks until the source's interval since its previous request has
+// passed, then counts a request. Callers making requests outside Client, such
+// as through a subprocess, call it once per request.
+func (l *Limiter) Wait(ctx context.Context, source string) error {
+	delay := l.Policy(source).Interval()
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next[source]
+	if at.Before(now) {
+		at = now
+	}
+	l.next[source] = at.Add(delay)
+	l.counts[source]++
+	l.mu.Unlock()
+
+	if wait := time.Until(at); wait > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+	return nil
+}
+
+// Counts returns the requests made so far, by source
+func (l *Limiter) Counts() map[string]int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	counts := make(map[string]int, len(l.counts))
+	for k, v := range l.counts {
+		counts[k] = v
+	}
+	return counts
+}
+
+// Client returns an HTTP client whose requests follow the source's policy
+func (l *Limiter) Client(source string, timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: &transport{limiter: l, source: source, base: http.DefaultTransport}}
+}
+
+// transport applies a source's policy to each request: the delay, the
+// User-Agent, and retries with backoff
+type transport struct {
+	limiter *Limiter
+	source  string
+	base    http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	policy := t.limiter.Policy(t.source)
+	if policy.UserAgent != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", policy.UserAgent)
+	}
+	backoff := time.Duration(policy.Backoff)
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("cannot retry request to %s: body can't be replayed", req.URL.Host)
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		if err := t.limiter.Wait(req.Context(), t.source); err != nil {
+			return nil, err
+		}
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || attempt >= policy.MaxRetries || !retryable(resp.StatusCode) {
+			return resp, err
+		}
+		wait := retryAfter(resp.Header.Get("Retry-After"), Jitter(backoff))
+		resp.Body.Close()
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// retryable reports whether a response status means trying again later may
+// succeed
+func retryable(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusBadGateway ||
+		code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// retryAfter reads a Retry-After header given in seconds, falling back to
+// the backoff
+func retryAfter(header string, backoff time.Duration) time.Duration {
+	if secs, err := strconv.Atoi(strings.TrimSpace(header)); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return backoff
+}
+
+// FormatCounts formats requests by source as "reddit 120, amazon 6", busiest
+// first
+func FormatCounts(counts map[string]int) string {
+	sources := make([]string, 0, len(counts))
+	for s, n := range counts {
+		if n > 0 {
+			sources = append(sources, s)
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		if counts[sources[i]] != counts[sources[j]] {
+			return counts[sources[i]] > counts[sources[j]]
+		}
+		return sources[i] < sources[j]
+	})
+	parts := make([]string, len(sources))
+	for i, s := range sources {
+		parts[i] = fmt.Sprintf("%s %d", s, counts[s])
+	}
+	return strings.Join(parts, ", ")
+}
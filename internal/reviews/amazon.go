@@ -0,0 +1,222 @@
+package reviews
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"hiveminer/pkg/types"
+)
+
+// amazon scrapes a product's review pages, most helpful reviews first. Its
+// client spaces the pages out.
+type amazon struct {
+	client   *http.Client
+	maxPages int
+}
+
+var (
+	amazonReviewTag   = regexp.MustCompile(`<div[^>]*data-hook="review"[^>]*>`)
+	amazonIDAttr      = regexp.MustCompile(`\bid="([A-Za-z0-9]+)"`)
+	amazonStarTag     = regexp.MustCompile(`<i[^>]*review-star-rating[^>]*>`)
+	amazonStarClass   = regexp.MustCompile(`a-star-(\d)(?:-(\d))?`)
+	amazonProfileName = regexp.MustCompile(`<span class="a-profile-name">([^<]*)</span>`)
+	amazonProductLink = regexp.MustCompile(`data-hook="product-link"[^>]*>([^<]+)<`)
+	amazonPageTitle   = regexp.MustCompile(`<title>(?:[^<:]*:\s*Customer reviews:\s*)?([^<]*)</title>`)
+	amazonUSDate      = regexp.MustCompile(`[A-Z][a-z]+ \d{1,2}, \d{4}`)
+	amazonCount       = regexp.MustCompile(`[\d,.]+`)
+	amazonHookEnd     = regexp.MustCompile(`</a>|</span>|</div>|data-hook=`)
+	amazonDecoration  = regexp.MustCompile(`(?s)<i\b[^>]*>.*?</i>|<span[^>]*>\s*</span>`)
+	htmlBreak         = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlTag           = regexp.MustCompile(`<[^>]+>`)
+)
+
+func (a *amazon) fetch(ctx context.Context, ref Ref) (*types.Thread, error) {
+	thread := &types.Thread{Post: types.Post{Domain: strings.TrimPrefix(ref.Host, "www."), URL: ref.Permalink()}}
+	seen := map[string]bool{}
+	for page := 1; page <= a.maxPages; page++ {
+		pageURL := fmt.Sprintf("https://%s/product-reviews/%s/?pageNumber=%d&sortBy=helpful&reviewerType=all_reviews", ref.Host, ref.ID, page)
+		doc, err := a.get(ctx, pageURL)
+		if err != nil {
+			if page > 1 && len(thread.Comments) > 0 {
+				break // keep the pages already fetched
+			}
+			return nil, err
+		}
+		if page == 1 {
+			thread.Post.Title, thread.Post.Selftext = amazonProduct(doc)
+		}
+		added := 0
+		for _, c := range parseAmazonReviews(doc, ref.Host) {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+			thread.Comments = append(thread.Comments, c)
+			added++
+		}
+		if added == 0 {
+			break
+		}
+	}
+	if len(thread.Comments) == 0 {
+		return nil, fmt.Errorf("no reviews found")
+	}
+	return thread, nil
+}
+
+// get fetches a review page, recognizing the captcha and sign-in pages Amazon
+// serves instead when it suspects automation
+func (a *amazon) get(ctx context.Context, pageURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("review page request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("review page request: HTTP %d", resp.StatusCode)
+	}
+	if strings.Contains(resp.Request.URL.Path, "/ap/signin") {
+		return "", fmt.Errorf("amazon requires signing in to see review pages")
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading review page: %w", err)
+	}
+	doc := string(data)
+	if strings.Contains(doc, "/errors/validateCaptcha") {
+		return "", fmt.Errorf("amazon served a captcha instead of reviews; try again later")
+	}
+	return doc, nil
+}
+
+// amazonProduct reads the product name and rating summary from a review page
+func amazonProduct(doc string) (title, summary string) {
+	name := ""
+	if m := amazonProductLink.FindStringSubmatch(doc); m != nil {
+		name = cleanText(m[1])
+	} else if m := amazonPageTitle.FindStringSubmatch(doc); m != nil {
+		name = cleanText(m[1])
+	}
+	title = "Amazon reviews: " + name
+	var parts []string
+	if avg := hookText(doc, "rating-out-of-text"); avg != "" {
+		parts = append(parts, "Average rating "+avg+".")
+	}
+	if total := hookText(doc, "total-review-count"); total != "" {
+		parts = append(parts, total+".")
+	}
+	summary = strings.TrimSpace("Amazon customer reviews of " + name + ", most helpful first. " + strings.Join(parts, " "))
+	return title, summary
+}
+
+// parseAmazonReviews reads the reviews on a review page as comments
+func parseAmazonReviews(doc, host string) []*types.Comment {
+	tags := amazonReviewTag.FindAllStringIndex(doc, -1)
+	var comments []*types.Comment
+	for i, loc := range tags {
+		end := len(doc)
+		if i+1 < len(tags) {
+			end = tags[i+1][0]
+		}
+		block := doc[loc[0]:end]
+		m := amazonIDAttr.FindStringSubmatch(doc[loc[0]:loc[1]])
+		if m == nil {
+			continue
+		}
+		id := m[1]
+
+		rating := 0.0
+		if tag := amazonStarTag.FindString(block); tag != "" {
+			if s := amazonStarClass.FindStringSubmatch(tag); s != nil {
+				rating, _ = strconv.ParseFloat(s[1], 64)
+				if s[2] != "" {
+					rating += 0.5
+				}
+			}
+		}
+		author := ""
+		if p := amazonProfileName.FindStringSubmatch(block); p != nil {
+			author = cleanText(p[1])
+		}
+		var created float64
+		if d := amazonUSDate.FindString(hookText(block, "review-date")); d != "" {
+			if t, err := time.Parse("January 2, 2006", d); err == nil {
+				created = float64(t.Unix())
+			}
+		}
+		body := reviewBody(rating, hookText(block, "review-title"), strings.TrimSuffix(hookText(block, "review-body"), "Read more"))
+		if body == "" {
+			continue
+		}
+		comments = append(comments, &types.Comment{
+			ID:        id,
+			Body:      body,
+			Author:    author,
+			Score:     helpfulVotes(hookText(block, "helpful-vote-statement")),
+			Created:   created,
+			Permalink: fmt.Sprintf("https://%s/gp/customer-reviews/%s", host, id),
+		})
+	}
+	return comments
+}
+
+// hookText returns the text of the element marked with a data-hook, up to
+// where the element or its link ends
+func hookText(doc, hook string) string {
+	marker := `data-hook="` + hook + `"`
+	i := strings.Index(doc, marker)
+	if i < 0 {
+		return ""
+	}
+	rest := doc[i+len(marker):]
+	start := strings.Index(rest, ">")
+	if start < 0 {
+		return ""
+	}
+	// Titles lead with the star rating icon, which is read separately, and
+	// a spacer
+	rest = amazonDecoration.ReplaceAllString(rest[start+1:], "")
+	if loc := amazonHookEnd.FindStringIndex(rest); loc != nil {
+		rest = rest[:loc[0]]
+	}
+	return cleanText(rest)
+}
+
+// helpfulVotes reads "12 people found this helpful" or "One person found
+// this helpful"
+func helpfulVotes(statement string) int {
+	if statement == "" {
+		return 0
+	}
+	m := amazonCount.FindString(statement)
+	if m == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(strings.NewReplacer(",", "", ".", "").Replace(m))
+	return n
+}
+
+// cleanText turns an HTML fragment into plain text, keeping line breaks
+func cleanText(s string) string {
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = html.UnescapeString(htmlTag.ReplaceAllString(s, ""))
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,105 @@
+package reviews
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"hiveminer/pkg/types"
+)
+
+// google fetches a place's reviews from the Places API. The API returns a
+// place's five most relevant reviews.
+type google struct {
+	client *http.Client
+	key    string
+}
+
+// placeResponse is the part of a Places API place the fetcher asks for
+type placeResponse struct {
+	DisplayName struct {
+		Text string `json:"text"`
+	} `json:"displayName"`
+	FormattedAddress string  `json:"formattedAddress"`
+	GoogleMapsURI    string  `json:"googleMapsUri"`
+	Rating           float64 `json:"rating"`
+	UserRatingCount  int     `json:"userRatingCount"`
+	Reviews          []struct {
+		Name   string  `json:"name"` // places/<place>/reviews/<review>
+		Rating float64 `json:"rating"`
+		Text   struct {
+			Text string `json:"text"`
+		} `json:"text"`
+		AuthorAttribution struct {
+			DisplayName string `json:"displayName"`
+		} `json:"authorAttribution"`
+		PublishTime   string `json:"publishTime"`
+		GoogleMapsURI string `json:"googleMapsUri"`
+	} `json:"reviews"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (g *google) fetch(ctx context.Context, ref Ref) (*types.Thread, error) {
+	if g.key == "" {
+		return nil, fmt.Errorf("GOOGLE_MAPS_API_KEY is not set")
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://places.googleapis.com/v1/places/"+url.PathEscape(ref.ID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("X-Goog-Api-Key", g.key)
+	req.Header.Set("X-Goog-FieldMask", "displayName,formattedAddress,googleMapsUri,rating,userRatingCount,reviews")
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("places request: %w", err)
+	}
+	defer resp.Body.Close()
+	var place placeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&place); err != nil {
+		return nil, fmt.Errorf("parsing places response: %w", err)
+	}
+	if place.Error != nil {
+		return nil, fmt.Errorf("places API: %s", place.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("places request: HTTP %d", resp.StatusCode)
+	}
+
+	thread := &types.Thread{Post: types.Post{
+		Title:    "Google reviews: " + place.DisplayName.Text,
+		Selftext: fmt.Sprintf("Google reviews of %s (%s). Average rating %g out of 5 from %d ratings.", place.DisplayName.Text, place.FormattedAddress, place.Rating, place.UserRatingCount),
+		Domain:   "google.com",
+		URL:      place.GoogleMapsURI,
+	}}
+	for _, r := range place.Reviews {
+		body := reviewBody(r.Rating, "", r.Text.Text)
+		if body == "" {
+			continue
+		}
+		link := r.GoogleMapsURI
+		if link == "" {
+			link = place.GoogleMapsURI
+		}
+		var created float64
+		if t, err := time.Parse(time.RFC3339, r.PublishTime); err == nil {
+			created = float64(t.Unix())
+		}
+		thread.Comments = append(thread.Comments, &types.Comment{
+			ID:        r.Name[strings.LastIndex(r.Name, "/")+1:],
+			Body:      body,
+			Author:    r.AuthorAttribution.DisplayName,
+			Created:   created,
+			Permalink: link,
+		})
+	}
+	if len(thread.Comments) == 0 {
+		return nil, fmt.Errorf("no reviews found")
+	}
+	return thread, nil
+}
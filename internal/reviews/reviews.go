@@ -0,0 +1,179 @@
+// Package reviews fetches marketplace product reviews — Amazon product review
+// pages and Google place reviews — as threads, one per product with a comment
+// per review, so forms can mine them alongside Reddit threads.
+package reviews
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"hiveminer/internal/ratelimit"
+	"hiveminer/pkg/types"
+)
+
+// Sites that reviews can be fetched from
+const (
+	SiteAmazon = "amazon"
+	SiteGoogle = "google"
+)
+
+// Ref names a product on a review site
+type Ref struct {
+	Site string
+	ID   string // Amazon ASIN or Google place ID
+	Host string // Amazon marketplace host, e.g. www.amazon.co.uk
+}
+
+var (
+	asinPattern    = regexp.MustCompile(`^[A-Z0-9]{10}$`)
+	asinInPath     = regexp.MustCompile(`/(?:dp|gp/product|product-reviews|gp/aw/d)/([A-Z0-9]{10})(?:[/?]|$)`)
+	nonPostIDChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+)
+
+// ParseRef reads a product reference: an Amazon product or review page URL,
+// a Google Maps URL naming a place ID, or site:id ("amazon:B0C1234567",
+// "google:ChIJ...")
+func ParseRef(s string) (Ref, error) {
+	s = strings.TrimSpace(s)
+	if site, id, ok := strings.Cut(s, ":"); ok && !strings.HasPrefix(id, "//") {
+		switch strings.ToLower(site) {
+		case SiteAmazon:
+			id = strings.ToUpper(id)
+			if !asinPattern.MatchString(id) {
+				return Ref{}, fmt.Errorf("not an Amazon ASIN: %q", id)
+			}
+			return Ref{Site: SiteAmazon, ID: id, Host: "www.amazon.com"}, nil
+		case SiteGoogle:
+			if id == "" {
+				return Ref{}, fmt.Errorf("missing Google place ID")
+			}
+			return Ref{Site: SiteGoogle, ID: id}, nil
+		}
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return Ref{}, fmt.Errorf("not a review page URL or site:id reference: %q", s)
+	}
+	host := strings.ToLower(u.Host)
+	switch {
+	case strings.Contains(host, "amazon."):
+		m := asinInPath.FindStringSubmatch(u.Path)
+		if m == nil {
+			return Ref{}, fmt.Errorf("no ASIN in Amazon URL: %s", s)
+		}
+		return Ref{Site: SiteAmazon, ID: m[1], Host: host}, nil
+	case strings.Contains(host, "google."):
+		// https://www.google.com/maps/place/?q=place_id:ChIJ...
+		if id, ok := strings.CutPrefix(u.Query().Get("q"), "place_id:"); ok && id != "" {
+			return Ref{Site: SiteGoogle, ID: id}, nil
+		}
+		if id := u.Query().Get("query_place_id"); id != "" {
+			return Ref{Site: SiteGoogle, ID: id}, nil
+		}
+		return Ref{}, fmt.Errorf("no place ID in Google URL (use google:<place_id>): %s", s)
+	}
+	return Ref{}, fmt.Errorf("unsupported review site: %s", host)
+}
+
+// String returns the reference in site:id form
+func (r Ref) String() string {
+	return r.Site + ":" + r.ID
+}
+
+// PostID returns a stable, filename-safe post ID for a product's reviews
+func (r Ref) PostID() string {
+	return "reviews_" + r.Site + "_" + nonPostIDChars.ReplaceAllString(r.ID, "_")
+}
+
+// Permalink returns the product's page, which ParseRef reads back
+func (r Ref) Permalink() string {
+	switch r.Site {
+	case SiteAmazon:
+		return fmt.Sprintf("https://%s/dp/%s", r.Host, r.ID)
+	case SiteGoogle:
+		return "https://www.google.com/maps/place/?q=place_id:" + url.QueryEscape(r.ID)
+	}
+	return ""
+}
+
+// Fetcher fetches a product's reviews as a thread with a comment per review
+type Fetcher interface {
+	Fetch(ctx context.Context, ref Ref) (*types.Thread, error)
+}
+
+// Client fetches reviews from every supported site
+type Client struct {
+	amazon *amazon
+	google *google
+}
+
+// NewClient returns a client for all sites. Amazon review pages are scraped,
+// up to maxPages pages of ten reviews each (0 = 5); Google reviews come from
+// the Places API and need GOOGLE_MAPS_API_KEY.
+func NewClient(maxPages int) *Client {
+	if maxPages <= 0 {
+		maxPages = 5
+	}
+	c := &Client{
+		amazon: &amazon{maxPages: maxPages},
+		google: &google{key: os.Getenv("GOOGLE_MAPS_API_KEY")},
+	}
+	c.SetLimiter(ratelimit.NewLimiter(nil))
+	return c
+}
+
+// SetLimiter paces and counts the client's requests with a run's limiter
+func (c *Client) SetLimiter(l *ratelimit.Limiter) {
+	c.amazon.client = l.Client(ratelimit.SourceAmazon, 30*time.Second)
+	c.google.client = l.Client(ratelimit.SourceGoogle, 30*time.Second)
+}
+
+// Fetch fetches a product's reviews from its site
+func (c *Client) Fetch(ctx context.Context, ref Ref) (*types.Thread, error) {
+	var thread *types.Thread
+	var err error
+	switch ref.Site {
+	case SiteAmazon:
+		thread, err = c.amazon.fetch(ctx, ref)
+	case SiteGoogle:
+		thread, err = c.google.fetch(ctx, ref)
+	default:
+		return nil, fmt.Errorf("unsupported review site: %s", ref.Site)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s reviews for %s: %w", ref.Site, ref.ID, err)
+	}
+	thread.Post.ID = ref.PostID()
+	thread.Post.Permalink = ref.Permalink()
+	thread.Post.Source = types.SourceReviews
+	thread.Post.NumComments = len(thread.Comments)
+	return thread, nil
+}
+
+// reviewBody formats a review as comment text, leading with its rating and
+// title the way a reader scanning reviews would see them
+func reviewBody(rating float64, title, text string) string {
+	var b strings.Builder
+	if rating > 0 {
+		fmt.Fprintf(&b, "Rating: %g/5", rating)
+	}
+	if title = strings.TrimSpace(title); title != "" {
+		if b.Len() > 0 {
+			b.WriteString(" — ")
+		}
+		b.WriteString(title)
+	}
+	if text = strings.TrimSpace(text); text != "" {
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
@@ -1,26 +1,516 @@
 package schema
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
 	"hiveminer/pkg/types"
 )
 
 // FieldType constants for validation
 const (
-	FieldTypeString  = types.FieldTypeString
-	FieldTypeNumber  = types.FieldTypeNumber
-	FieldTypeBoolean = types.FieldTypeBoolean
-	FieldTypeArray   = types.FieldTypeArray
+	FieldTypeString   = types.FieldTypeString
+	FieldTypeNumber   = types.FieldTypeNumber
+	FieldTypeBoolean  = types.FieldTypeBoolean
+	FieldTypeArray    = types.FieldTypeArray
+	FieldTypeLocation = types.FieldTypeLocation
 )
 
 // ValidFieldTypes is the set of valid field types
 var ValidFieldTypes = map[types.FieldType]bool{
-	FieldTypeString:  true,
-	FieldTypeNumber:  true,
-	FieldTypeBoolean: true,
-	FieldTypeArray:   true,
+	FieldTypeString:   true,
+	FieldTypeNumber:   true,
+	FieldTypeBoolean:  true,
+	FieldTypeArray:    true,
+	FieldTypeLocation: true,
 }
 
 // IsValidFieldType checks if a field type is valid
 func IsValidFieldType(t types.FieldType) bool {
 	return ValidFieldTypes[t]
 }
+
+// ValidCommentSorts is the set of Reddit comment sorts a form or run may request
+var ValidCommentSorts = map[string]bool{
+	"top":           true,
+	"best":          true,
+	"new":           true,
+	"controversial": true,
+	"old":           true,
+	"qa":            true,
+}
+
+// IsValidCommentSort checks if a comment sort is valid; empty means Reddit's default
+func IsValidCommentSort(sort string) bool {
+	return sort == "" || ValidCommentSorts[sort]
+}
+
+// DefaultSaturation is the same-thread penalty used when a form sets none
+var DefaultSaturation = types.SaturationPolicy{Free: 1, Step: 5, Cap: 30}
+
+// Saturation returns the form's saturation policy with defaults filled in
+func Saturation(form *types.Form) types.SaturationPolicy {
+	policy := DefaultSaturation
+	if s := form.Saturation; s != nil {
+		policy.Disabled = s.Disabled
+		if s.Free > 0 {
+			policy.Free = s.Free
+		}
+		if s.Step > 0 {
+			policy.Step = s.Step
+		}
+		if s.Cap > 0 {
+			policy.Cap = s.Cap
+		}
+	}
+	return policy
+}
+
+// SourceWeightKeys are the sources a form can weigh: "reddit" covers every
+// Reddit thread, wiki, sidebar and user history page that has no weight of
+// its own
+var SourceWeightKeys = []string{"reddit", types.SourceWiki, types.SourceSidebar, types.SourceUser, types.SourceReviews, types.SourceTranscript, types.SourceWeb}
+
+// SourceWeight returns how much the form trusts entries from threads of a
+// source (a ThreadState.Source value)
+func SourceWeight(form *types.Form, source string) float64 {
+	if w, ok := form.SourceWeights[source]; ok && source != "" {
+		return w
+	}
+	switch source {
+	case "", types.SourceWiki, types.SourceSidebar, types.SourceUser:
+		if w, ok := form.SourceWeights["reddit"]; ok {
+			return w
+		}
+	}
+	return 1
+}
+
+// EntryCapOverflows lists how entries past a thread's cap are handled
+var EntryCapOverflows = []string{"merge", "flag"}
+
+// DefaultEntryCap is the per-thread entry cap used when a form sets none
+var DefaultEntryCap = types.EntryCapPolicy{Max: 25, Overflow: "merge"}
+
+// EntryCap returns the form's entry cap with defaults filled in
+func EntryCap(form *types.Form) types.EntryCapPolicy {
+	policy := DefaultEntryCap
+	if c := form.EntryCap; c != nil {
+		if c.Max > 0 {
+			policy.Max = c.Max
+		}
+		if c.Overflow != "" {
+			policy.Overflow = c.Overflow
+		}
+	}
+	return policy
+}
+
+// PostProcessOnError lists what happens to an entry when its post-processor
+// hook fails
+var PostProcessOnError = []string{"keep", "reject"}
+
+// FlagSeverities lists flag severities from least to most severe
+var FlagSeverities = []string{"minor", "moderate", "severe"}
+
+// DefaultFlags is the quality flag taxonomy used when a form defines none
+var DefaultFlags = []types.FlagDef{
+	{ID: "spam", Description: "Promotional content, affiliate links, or astroturfing", Severity: "severe"},
+	{ID: "joke", Description: "Sarcastic or humorous suggestions not meant as genuine recommendations", Severity: "severe"},
+	{ID: "outdated", Description: "Information that is clearly outdated or no longer relevant", Severity: "moderate"},
+	{ID: "low_effort", Description: "Extremely vague with no supporting detail or evidence", Severity: "minor"},
+	{ID: "off_topic", Description: "Does not match the form's intent at all", Severity: "severe"},
+}
+
+// DuplicateFlag marks entries repeating a better entry's item. The ranker
+// always assigns it, whatever the form's taxonomy.
+var DuplicateFlag = types.FlagDef{ID: "duplicate", Description: "Same item as a better entry", Severity: "moderate"}
+
+// DiscontinuedFlag marks entries whose product a retailer reports as
+// discontinued (see the enrich phase). The ranker penalizes it.
+var DiscontinuedFlag = types.FlagDef{ID: "discontinued", Description: "Product is no longer sold", Severity: "moderate"}
+
+// UnverifiedFlag marks entries the verify phase found unsupported by their
+// thread, or resting on a joke, sarcasm, negation or hypothetical. The
+// ranker penalizes it.
+var UnverifiedFlag = types.FlagDef{ID: "unverified", Description: "Not supported by the thread on a second read", Severity: "moderate"}
+
+// AntiRecommendationFlag marks entries whose evidence advises against the
+// item. The ranker scores them 0 so they stay out of the top results; they
+// are shown as warnings instead.
+var AntiRecommendationFlag = types.FlagDef{ID: "anti_recommendation", Description: "Evidence recommends against the item", Severity: "severe"}
+
+// ReviewFlags are set by consensus checks for review; they carry no penalty
+// or severity
+var ReviewFlags = []types.FlagDef{
+	{ID: "outlier", Description: "Numeric value far from the median of all entries"},
+	{ID: "contradicted", Description: "Merged duplicates disagree on a value"},
+}
+
+// isReservedFlag reports whether id is a flag the ranker assigns on its own
+func isReservedFlag(id string) bool {
+	if id == DuplicateFlag.ID || id == DiscontinuedFlag.ID || id == UnverifiedFlag.ID || id == AntiRecommendationFlag.ID {
+		return true
+	}
+	for _, f := range ReviewFlags {
+		if f.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// QualityFlags returns the form's quality flag taxonomy, or the default
+func QualityFlags(form *types.Form) []types.FlagDef {
+	if len(form.Flags) > 0 {
+		return form.Flags
+	}
+	return DefaultFlags
+}
+
+// RankFlags returns every flag an entry may carry: the quality flags,
+// duplicate, discontinued, unverified, anti_recommendation, and the review
+// flags
+func RankFlags(form *types.Form) []types.FlagDef {
+	flags := append([]types.FlagDef(nil), QualityFlags(form)...)
+	flags = append(flags, DuplicateFlag, DiscontinuedFlag, UnverifiedFlag, AntiRecommendationFlag)
+	return append(flags, ReviewFlags...)
+}
+
+// FindFlag returns the definition of a flag, or nil if the form doesn't know it
+func FindFlag(form *types.Form, id string) *types.FlagDef {
+	for _, f := range RankFlags(form) {
+		if f.ID == id {
+			return &f
+		}
+	}
+	return nil
+}
+
+// SeverityLevel orders severities: 0 for none or unknown, then 1 (minor) to 3 (severe)
+func SeverityLevel(severity string) int {
+	for i, s := range FlagSeverities {
+		if s == severity {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// WorstSeverity returns the most severe severity among flags, or ""
+func WorstSeverity(form *types.Form, flags []string) string {
+	worst := ""
+	for _, id := range flags {
+		if f := FindFlag(form, id); f != nil && SeverityLevel(f.Severity) > SeverityLevel(worst) {
+			worst = f.Severity
+		}
+	}
+	return worst
+}
+
+// PrimaryField returns the ID of the field that identifies an entry's item:
+// the field marked primary, else the first required field, else the first field
+func PrimaryField(form *types.Form) string {
+	for _, f := range form.Fields {
+		if f.Primary {
+			return f.ID
+		}
+	}
+	for _, f := range form.Fields {
+		if f.Required {
+			return f.ID
+		}
+	}
+	if len(form.Fields) > 0 {
+		return form.Fields[0].ID
+	}
+	return ""
+}
+
+// EntityKey returns the fields identifying one of the form's entries: the
+// entity's key, else the primary field
+func EntityKey(form *types.Form) []string {
+	if form.Entity != nil && len(form.Entity.Key) > 0 {
+		return form.Entity.Key
+	}
+	if id := PrimaryField(form); id != "" {
+		return []string{id}
+	}
+	return nil
+}
+
+// MatchExclusion returns the form exclusion an entry falls under, or nil.
+// Terms match whole words in string and array values, case-insensitively.
+func MatchExclusion(form *types.Form, entry types.Entry) *types.Exclusion {
+	for i, ex := range form.Exclusions {
+		terms := ExclusionTerms(ex)
+		for _, fv := range entry.Fields {
+			if len(ex.Fields) > 0 && !slices.Contains(ex.Fields, fv.ID) {
+				continue
+			}
+			for _, value := range exclusionValues(fv.Value) {
+				for _, term := range terms {
+					if containsPhrase(value, term) {
+						return &form.Exclusions[i]
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ExclusionTerms returns the terms an exclusion matches: its own, else its
+// description without a leading "not", "no", "exclude" or "without"
+func ExclusionTerms(ex types.Exclusion) []string {
+	if len(ex.Terms) > 0 {
+		return ex.Terms
+	}
+	desc := strings.TrimSpace(ex.Description)
+	lower := strings.ToLower(desc)
+	for _, prefix := range []string{"not ", "no ", "exclude ", "excluding ", "without "} {
+		if strings.HasPrefix(lower, prefix) {
+			desc = strings.TrimSpace(desc[len(prefix):])
+			break
+		}
+	}
+	return []string{desc}
+}
+
+// exclusionValues returns the text of a field value checked against exclusions
+func exclusionValues(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// containsPhrase reports whether phrase appears in text as whole words,
+// ignoring case and allowing a plural "s"
+func containsPhrase(text, phrase string) bool {
+	words := strings.FieldsFunc(strings.ToLower(phrase), isWordSep)
+	if len(words) == 0 {
+		return false
+	}
+	textWords := strings.FieldsFunc(strings.ToLower(text), isWordSep)
+	for i := 0; i+len(words) <= len(textWords); i++ {
+		match := true
+		for j, w := range words {
+			t := textWords[i+j]
+			if t != w && t != w+"s" && t+"s" != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// isWordSep splits text into words on anything but letters and digits
+func isWordSep(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// AudienceLines describes the form's audience for prompts, one line per
+// constraint ("Budget: 500–1500 USD"); nil when the form has none
+func AudienceLines(form *types.Form) []string {
+	a := form.Audience
+	if a == nil {
+		return nil
+	}
+	var lines []string
+	if b := a.Budget; b != nil {
+		amount := func(v float64) string {
+			return strings.TrimSpace(strconv.FormatFloat(v, 'f', -1, 64) + " " + b.Currency)
+		}
+		switch {
+		case b.Min != nil && b.Max != nil:
+			lines = append(lines, fmt.Sprintf("Budget: %s to %s", amount(*b.Min), amount(*b.Max)))
+		case b.Max != nil:
+			lines = append(lines, fmt.Sprintf("Budget: at most %s", amount(*b.Max)))
+		case b.Min != nil:
+			lines = append(lines, fmt.Sprintf("Budget: at least %s", amount(*b.Min)))
+		}
+	}
+	if a.Region != "" {
+		lines = append(lines, "Region: "+a.Region)
+	}
+	if a.Household != "" {
+		lines = append(lines, "Household: "+a.Household)
+	}
+	if a.Timeframe != "" {
+		lines = append(lines, "Timeframe: "+a.Timeframe)
+	}
+	return lines
+}
+
+// CheckAudience returns why an entry falls outside the form's audience
+// constraints, or "" if it doesn't. Only the budget is checked, and only
+// when the form names its field and the entry has a value for it.
+func CheckAudience(form *types.Form, entry types.Entry) string {
+	if form.Audience == nil || form.Audience.Budget == nil || form.Audience.Budget.Field == "" {
+		return ""
+	}
+	b := form.Audience.Budget
+	for _, fv := range entry.Fields {
+		if fv.ID != b.Field {
+			continue
+		}
+		price, ok := NumberValue(fv.Value)
+		if !ok {
+			return ""
+		}
+		if b.Max != nil && price > *b.Max*(1+b.Tolerance) {
+			return fmt.Sprintf("%s over budget", b.Field)
+		}
+		if b.Min != nil && price < *b.Min*(1-b.Tolerance) {
+			return fmt.Sprintf("%s under budget", b.Field)
+		}
+	}
+	return ""
+}
+
+// NumberValue reads a number field's value, given as a number or a numeric
+// string ("$1,299")
+func NumberValue(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		cleaned := strings.Map(func(r rune) rune {
+			if (r >= '0' && r <= '9') || r == '.' || r == '-' {
+				return r
+			}
+			return -1
+		}, v)
+		f, err := strconv.ParseFloat(cleaned, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// FieldGroups splits the form's fields, other than the primary one, into the
+// groups a decomposed extraction prompts for separately. Fields sharing a
+// group name stay together, in order of first appearance; ungrouped fields
+// are chunked into groups of at most size. Returns a single group when the
+// form is small enough for one prompt.
+func FieldGroups(form *types.Form, size int) [][]types.Field {
+	primary := PrimaryField(form)
+	var named [][]types.Field
+	index := map[string]int{}
+	var ungrouped []types.Field
+	for _, f := range form.Fields {
+		switch {
+		case f.ID == primary:
+		case f.Group == "":
+			ungrouped = append(ungrouped, f)
+		default:
+			i, ok := index[f.Group]
+			if !ok {
+				i = len(named)
+				index[f.Group] = i
+				named = append(named, nil)
+			}
+			named[i] = append(named[i], f)
+		}
+	}
+	if size <= 0 {
+		size = len(ungrouped)
+	}
+	for len(ungrouped) > 0 {
+		n := min(size, len(ungrouped))
+		named = append(named, ungrouped[:n])
+		ungrouped = ungrouped[n:]
+	}
+	return named
+}
+
+// FieldStages orders the form's fields into extraction stages by their
+// depends_on: each field comes one stage after the last of the fields it
+// depends on. Dependencies on fields the form doesn't have are ignored, so
+// the stages of a decomposed prompt's partial form still work. Returns nil
+// when no field depends on another. The form must be free of cycles (see
+// Validate).
+func FieldStages(form *types.Form) [][]types.Field {
+	present := map[string]*types.Field{}
+	for i := range form.Fields {
+		present[form.Fields[i].ID] = &form.Fields[i]
+	}
+	stage := map[string]int{}
+	var stageOf func(id string) int
+	stageOf = func(id string) int {
+		if s, ok := stage[id]; ok {
+			return s
+		}
+		stage[id] = 0 // guards against cycles in unvalidated forms
+		s := 0
+		for _, dep := range present[id].DependsOn {
+			if present[dep] != nil {
+				s = max(s, stageOf(dep)+1)
+			}
+		}
+		stage[id] = s
+		return s
+	}
+	last := 0
+	for _, f := range form.Fields {
+		last = max(last, stageOf(f.ID))
+	}
+	if last == 0 {
+		return nil
+	}
+	stages := make([][]types.Field, last+1)
+	for _, f := range form.Fields {
+		stages[stage[f.ID]] = append(stages[stage[f.ID]], f)
+	}
+	return stages
+}
+
+// dependencyCycle returns the fields of a depends_on cycle starting at id,
+// as "a → b → a", or "" if there is none
+func dependencyCycle(form *types.Form, id string) string {
+	deps := map[string][]string{}
+	for _, f := range form.Fields {
+		deps[f.ID] = f.DependsOn
+	}
+	var path []string
+	onPath, done := map[string]bool{}, map[string]bool{}
+	var visit func(id string) string
+	visit = func(id string) string {
+		if onPath[id] {
+			return strings.Join(append(path, id), " → ")
+		}
+		if done[id] {
+			return ""
+		}
+		onPath[id] = true
+		path = append(path, id)
+		for _, dep := range deps[id] {
+			if cycle := visit(dep); cycle != "" {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		onPath[id] = false
+		done[id] = true
+		return ""
+	}
+	return visit(id)
+}
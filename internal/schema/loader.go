@@ -5,7 +5,12 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"hiveminer/pkg/types"
 )
@@ -26,6 +31,12 @@ func LoadForm(path string) (*types.Form, error) {
 		return nil, fmt.Errorf("validating form: %w", err)
 	}
 
+	if h := form.PostProcess; h != nil && len(h.Command) > 0 {
+		if cmd := h.Command[0]; strings.Contains(cmd, "/") && !filepath.IsAbs(cmd) {
+			h.Command[0] = filepath.Join(filepath.Dir(path), cmd)
+		}
+	}
+
 	return &form, nil
 }
 
@@ -39,7 +50,12 @@ func Validate(form *types.Form) error {
 		return fmt.Errorf("form must have at least one field")
 	}
 
+	if !IsValidCommentSort(form.CommentSort) {
+		return fmt.Errorf("invalid comment_sort %q", form.CommentSort)
+	}
+
 	seen := make(map[string]bool)
+	primary := ""
 	for i, field := range form.Fields {
 		if field.ID == "" {
 			return fmt.Errorf("field %d: id is required", i)
@@ -57,6 +73,130 @@ func Validate(form *types.Form) error {
 		if field.Question == "" {
 			return fmt.Errorf("field %s: question is required", field.ID)
 		}
+
+		if field.Primary {
+			if primary != "" {
+				return fmt.Errorf("field %s: only one field may be primary (%s already is)", field.ID, primary)
+			}
+			primary = field.ID
+		}
+	}
+
+	for _, field := range form.Fields {
+		for _, dep := range field.DependsOn {
+			if dep == field.ID {
+				return fmt.Errorf("field %s: depends on itself", field.ID)
+			}
+			if !seen[dep] {
+				return fmt.Errorf("field %s: depends on unknown field %s", field.ID, dep)
+			}
+		}
+		if cycle := dependencyCycle(form, field.ID); cycle != "" {
+			return fmt.Errorf("field %s: depends_on cycle %s", field.ID, cycle)
+		}
+	}
+
+	if e := form.Entity; e != nil {
+		if strings.TrimSpace(e.Description) == "" {
+			return fmt.Errorf("entity: description is required")
+		}
+		for _, id := range e.Key {
+			if !seen[id] {
+				return fmt.Errorf("entity: key names unknown field %s", id)
+			}
+		}
+	}
+
+	if s := form.Saturation; s != nil && (s.Free < 0 || s.Step < 0 || s.Cap < 0) {
+		return fmt.Errorf("saturation: free, step and cap must not be negative")
+	}
+
+	if c := form.EntryCap; c != nil {
+		if c.Max < 0 {
+			return fmt.Errorf("entry_cap: max must not be negative")
+		}
+		if c.Overflow != "" && !slices.Contains(EntryCapOverflows, c.Overflow) {
+			return fmt.Errorf("entry_cap: invalid overflow %q (use %s)", c.Overflow, strings.Join(EntryCapOverflows, ", "))
+		}
+	}
+
+	for source, w := range form.SourceWeights {
+		if !slices.Contains(SourceWeightKeys, source) {
+			return fmt.Errorf("source_weights: unknown source %q (use %s)", source, strings.Join(SourceWeightKeys, ", "))
+		}
+		if w < 0 {
+			return fmt.Errorf("source_weights: %s must not be negative", source)
+		}
+	}
+
+	if h := form.PostProcess; h != nil {
+		if (len(h.Command) > 0) == (h.URL != "") {
+			return fmt.Errorf("post_process: set exactly one of command and url")
+		}
+		if h.URL != "" {
+			if u, err := url.Parse(h.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("post_process: invalid url %q", h.URL)
+			}
+		}
+		if h.Timeout != "" {
+			if d, err := time.ParseDuration(h.Timeout); err != nil || d <= 0 {
+				return fmt.Errorf("post_process: invalid timeout %q", h.Timeout)
+			}
+		}
+		if h.OnError != "" && !slices.Contains(PostProcessOnError, h.OnError) {
+			return fmt.Errorf("post_process: invalid on_error %q (use %s)", h.OnError, strings.Join(PostProcessOnError, ", "))
+		}
+	}
+
+	seenFlags := make(map[string]bool)
+	for i, flag := range form.Flags {
+		if flag.ID == "" {
+			return fmt.Errorf("flag %d: id is required", i)
+		}
+		if isReservedFlag(flag.ID) {
+			return fmt.Errorf("flag %s: reserved by the ranker", flag.ID)
+		}
+		if seenFlags[flag.ID] {
+			return fmt.Errorf("duplicate flag id: %s", flag.ID)
+		}
+		seenFlags[flag.ID] = true
+		if flag.Description == "" {
+			return fmt.Errorf("flag %s: description is required", flag.ID)
+		}
+		if SeverityLevel(flag.Severity) == 0 {
+			return fmt.Errorf("flag %s: invalid severity %q (use %s)", flag.ID, flag.Severity, strings.Join(FlagSeverities, ", "))
+		}
+	}
+
+	if a := form.Audience; a != nil && a.Budget != nil {
+		b := a.Budget
+		if b.Min == nil && b.Max == nil {
+			return fmt.Errorf("audience: budget needs a min or max")
+		}
+		if (b.Min != nil && *b.Min < 0) || (b.Max != nil && *b.Max < 0) || b.Tolerance < 0 {
+			return fmt.Errorf("audience: budget min, max and tolerance must not be negative")
+		}
+		if b.Min != nil && b.Max != nil && *b.Min > *b.Max {
+			return fmt.Errorf("audience: budget min is over max")
+		}
+		if b.Field != "" {
+			if f := GetField(form, b.Field); f == nil {
+				return fmt.Errorf("audience: unknown budget field %s", b.Field)
+			} else if f.Type != FieldTypeNumber {
+				return fmt.Errorf("audience: budget field %s must be a number field", b.Field)
+			}
+		}
+	}
+
+	for i, ex := range form.Exclusions {
+		if strings.TrimSpace(ex.Description) == "" {
+			return fmt.Errorf("exclusion %d: description is required", i)
+		}
+		for _, id := range ex.Fields {
+			if !seen[id] {
+				return fmt.Errorf("exclusion %q: unknown field %s", ex.Description, id)
+			}
+		}
 	}
 
 	return nil
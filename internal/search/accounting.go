@@ -0,0 +1,196 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"hiveminer/internal/ratelimit"
+)
+
+// ErrBudgetExhausted is returned instead of making a request once the run's
+// request budget is spent
+var ErrBudgetExhausted = errors.New("reddit request budget exhausted")
+
+// Request kinds, in display order
+var RequestKinds = []string{"search", "listing", "thread", "morechildren", "wiki", "user"}
+
+// RequestStats counts Reddit requests by kind
+type RequestStats map[string]int
+
+// Total returns the number of requests of all kinds
+func (s RequestStats) Total() int {
+	total := 0
+	for _, n := range s {
+		total += n
+	}
+	return total
+}
+
+// String formats the stats as "N total (N search, N thread, ...)"
+func (s RequestStats) String() string {
+	var parts []string
+	for _, kind := range RequestKinds {
+		if s[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", s[kind], kind))
+		}
+	}
+	if len(parts) == 0 {
+		return "0 total"
+	}
+	return fmt.Sprintf("%d total (%s)", s.Total(), strings.Join(parts, ", "))
+}
+
+// requestKind classifies a Reddit API path
+func requestKind(path string) string {
+	switch {
+	case strings.Contains(path, "/search.json"):
+		return "search"
+	case strings.Contains(path, "/api/morechildren"):
+		return "morechildren"
+	case strings.Contains(path, "/comments/"):
+		return "thread"
+	case strings.Contains(path, "/wiki/"), strings.Contains(path, "/about.json"):
+		return "wiki"
+	case strings.HasPrefix(path, "/user/"):
+		return "user"
+	default:
+		return "listing"
+	}
+}
+
+// accounting counts requests against the budget, spaces them by the
+// politeness delay and per-minute cap, and appends them to the request log.
+// The log is shared with hiveminer subprocesses started by agents, so the
+// budget covers them too.
+type accounting struct {
+	budget    int
+	delay     time.Duration
+	perMinute int
+	logPath   string
+
+	mu     sync.Mutex
+	stats  RequestStats
+	lastAt time.Time
+}
+
+// SetBudget limits the number of requests; 0 means unlimited
+func (r *RedditSearcher) SetBudget(n int) {
+	r.acct.budget = n
+}
+
+// SetDelay sets the minimum time between requests
+func (r *RedditSearcher) SetDelay(d time.Duration) {
+	r.acct.delay = d
+}
+
+// SetRateLimit caps requests at n a minute, spread evenly so parallel
+// searches queue instead of arriving at once; 0 means no cap
+func (r *RedditSearcher) SetRateLimit(n int) {
+	r.acct.perMinute = n
+}
+
+// SetRetry retries requests that every endpoint rate-limited or found
+// unavailable, up to max times, waiting backoff (doubling each time, with
+// jitter, or the response's Retry-After) in between
+func (r *RedditSearcher) SetRetry(max int, backoff time.Duration) {
+	r.maxRetries = max
+	r.backoff = backoff
+}
+
+// SetRequestLog appends every request to path. The budget then counts all
+// requests in the log, including other processes'.
+func (r *RedditSearcher) SetRequestLog(path string) {
+	r.acct.logPath = path
+}
+
+// Stats returns this process's request counts
+func (r *RedditSearcher) Stats() RequestStats {
+	r.acct.mu.Lock()
+	defer r.acct.mu.Unlock()
+	stats := RequestStats{}
+	for k, v := range r.acct.stats {
+		stats[k] = v
+	}
+	return stats
+}
+
+// BudgetExhausted reports whether the request budget is spent
+func (r *RedditSearcher) BudgetExhausted() bool {
+	r.acct.mu.Lock()
+	defer r.acct.mu.Unlock()
+	return r.acct.budget > 0 && r.acct.used() >= r.acct.budget
+}
+
+// begin waits out the politeness interval and records a request, or returns
+// ErrBudgetExhausted. Requests wait their turn under the lock, so concurrent
+// callers are spaced out too.
+func (a *accounting) begin(ctx context.Context, path string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.budget > 0 && a.used() >= a.budget {
+		return ErrBudgetExhausted
+	}
+	if interval := ratelimit.Interval(a.delay, a.perMinute); interval > 0 {
+		if wait := interval - time.Since(a.lastAt); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+	}
+	a.lastAt = time.Now()
+
+	kind := requestKind(path)
+	if a.stats == nil {
+		a.stats = RequestStats{}
+	}
+	a.stats[kind]++
+	if a.logPath != "" {
+		if f, err := os.OpenFile(a.logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+			fmt.Fprintf(f, "%s %s %s\n", a.lastAt.UTC().Format(time.RFC3339), kind, path)
+			f.Close()
+		}
+	}
+	return nil
+}
+
+// used returns the requests made so far: the log's if there is one
+func (a *accounting) used() int {
+	if a.logPath != "" {
+		if stats, err := ReadRequestLog(a.logPath); err == nil {
+			return stats.Total()
+		}
+	}
+	return a.stats.Total()
+}
+
+// ReadRequestLog counts the requests recorded in a request log
+func ReadRequestLog(path string) (RequestStats, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return RequestStats{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	stats := RequestStats{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 {
+			stats[fields[1]]++
+		}
+	}
+	return stats, scanner.Err()
+}
@@ -17,3 +17,24 @@ type Searcher interface {
 	// GetThread fetches a complete thread with comments
 	GetThread(ctx context.Context, permalink string, commentLimit int) (*types.Thread, error)
 }
+
+// WikiFetcher fetches subreddit wiki pages and sidebars as comment-less threads
+type WikiFetcher interface {
+	// ListWikiPages lists the wiki page names of a subreddit
+	ListWikiPages(ctx context.Context, subreddit string) ([]string, error)
+
+	// GetWikiPage fetches a single wiki page as a Thread with no comments
+	GetWikiPage(ctx context.Context, subreddit, page string) (*types.Thread, error)
+
+	// GetSidebar fetches a subreddit's sidebar as a Thread with no comments
+	GetSidebar(ctx context.Context, subreddit string) (*types.Thread, error)
+}
+
+// UserFetcher lists a Reddit user's post and comment history
+type UserFetcher interface {
+	// ListUserSubmissions lists posts submitted by a user
+	ListUserSubmissions(ctx context.Context, username, sort string, limit int) ([]types.Post, error)
+
+	// ListUserComments lists comments written by a user, each with its own permalink
+	ListUserComments(ctx context.Context, username, sort string, limit int) ([]*types.Comment, error)
+}
@@ -0,0 +1,230 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"hiveminer/pkg/types"
+)
+
+// ThreadOptions controls how much of a large thread is fetched
+type ThreadOptions struct {
+	Sort          string // comment sort: top, best, new, controversial, old, qa (empty = Reddit default)
+	CommentBudget int    // maximum comments to collect across pagination (<= 0 fetches a single page)
+	Limit         int    // comments requested per page (default 500)
+}
+
+// apiCommentSort maps a comment sort to Reddit's API name for it
+func apiCommentSort(sort string) string {
+	if sort == "best" {
+		return "confidence"
+	}
+	return sort
+}
+
+// PagedThreadFetcher fetches threads whose comments exceed a single API page,
+// expanding "more comments" stubs until a comment budget is reached.
+type PagedThreadFetcher interface {
+	GetThreadPaged(ctx context.Context, permalink string, opts ThreadOptions) (*types.Thread, error)
+}
+
+// moreStub is a collapsed "load more comments" placeholder in a comment tree
+type moreStub struct {
+	ParentID string
+	Children []string
+	Depth    int
+}
+
+// morechildrenBatch is the maximum number of comment IDs Reddit expands per call
+const morechildrenBatch = 100
+
+// morechildrenResponse represents the JSON response from /api/morechildren
+type morechildrenResponse struct {
+	JSON struct {
+		Data struct {
+			Things []commentChild `json:"things"`
+		} `json:"data"`
+	} `json:"json"`
+}
+
+// GetThreadPaged fetches a thread and keeps expanding collapsed comments until
+// the comment budget is reached or no more comments remain.
+func (r *RedditSearcher) GetThreadPaged(ctx context.Context, permalink string, opts ThreadOptions) (*types.Thread, error) {
+	permalink = cleanPermalink(permalink)
+
+	pageLimit := 500
+	if opts.Limit > 0 {
+		pageLimit = opts.Limit
+	}
+	if opts.CommentBudget > 0 && opts.CommentBudget < pageLimit {
+		pageLimit = opts.CommentBudget
+	}
+	apiURL := fmt.Sprintf("%s%s.json?limit=%d&raw_json=1&depth=10", baseURL, permalink, pageLimit)
+	if opts.Sort != "" {
+		apiURL += "&sort=" + url.QueryEscape(apiCommentSort(opts.Sort))
+	}
+
+	thread, more, err := r.fetchThreadPage(ctx, apiURL, permalink)
+	if err != nil {
+		return nil, err
+	}
+	if opts.CommentBudget <= 0 || thread.Post.ID == "" {
+		return thread, nil
+	}
+
+	index := map[string]*types.Comment{}
+	indexComments(thread.Comments, index)
+	total := len(index)
+
+	// Replies that arrive before their parent wait for it, by parent ID
+	waiting := map[string][]*types.Comment{}
+	var waitingFor []string
+
+	for len(more) > 0 && total < opts.CommentBudget {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// Take the next batch of collapsed comment IDs, preferring shallow stubs
+		var stub moreStub
+		stub, more = nextStub(more)
+		ids := stub.Children
+		if len(ids) > morechildrenBatch {
+			more = append(more, moreStub{ParentID: stub.ParentID, Children: ids[morechildrenBatch:], Depth: stub.Depth})
+			ids = ids[:morechildrenBatch]
+		}
+
+		things, err := r.fetchMoreChildren(ctx, thread.Post.ID, ids, apiCommentSort(opts.Sort))
+		if err != nil {
+			// Keep what we have; a partial megathread beats none
+			break
+		}
+
+		for _, thing := range things {
+			if total >= opts.CommentBudget {
+				break
+			}
+			parentID := strings.TrimPrefix(thing.Data.ParentID, "t1_")
+			isReply := parentID != thing.Data.ParentID
+			parent := index[parentID]
+			depth := 0
+			switch {
+			case parent != nil:
+				depth = parent.Depth + 1
+			case isReply:
+				depth = thing.Data.Depth
+			}
+			if thing.Kind == "more" {
+				if len(thing.Data.Children) > 0 {
+					more = append(more, moreStub{ParentID: thing.Data.ParentID, Children: thing.Data.Children, Depth: depth})
+				}
+				continue
+			}
+			if thing.Kind != "t1" || index[thing.Data.ID] != nil {
+				continue
+			}
+
+			comment := &types.Comment{
+				ID:        thing.Data.ID,
+				Body:      thing.Data.Body,
+				Author:    thing.Data.Author,
+				Score:     thing.Data.Score,
+				Created:   thing.Data.Created,
+				Permalink: thing.Data.Permalink,
+				Depth:     depth,
+			}
+			switch {
+			case parent != nil:
+				parent.Replies = append(parent.Replies, comment)
+			case isReply:
+				if waiting[parentID] == nil {
+					waitingFor = append(waitingFor, parentID)
+				}
+				waiting[parentID] = append(waiting[parentID], comment)
+			default:
+				thread.Comments = append(thread.Comments, comment)
+			}
+			index[comment.ID] = comment
+			total++
+
+			// Adopt replies that arrived before this comment
+			if replies := waiting[comment.ID]; replies != nil {
+				delete(waiting, comment.ID)
+				for _, reply := range replies {
+					setDepth(reply, comment.Depth+1)
+				}
+				comment.Replies = append(comment.Replies, replies...)
+			}
+		}
+	}
+
+	// Replies whose parent never arrived keep their depth at the top level
+	for _, parentID := range waitingFor {
+		thread.Comments = append(thread.Comments, waiting[parentID]...)
+	}
+
+	if ctx.Err() != nil {
+		return thread, ctx.Err()
+	}
+	return thread, nil
+}
+
+// nextStub removes and returns the shallowest stub, the earliest found among
+// equally deep ones
+func nextStub(more []moreStub) (moreStub, []moreStub) {
+	best := 0
+	for i, stub := range more {
+		if stub.Depth < more[best].Depth {
+			best = i
+		}
+	}
+	stub := more[best]
+	return stub, slices.Delete(more, best, best+1)
+}
+
+// setDepth sets a comment's depth, shifting its replies' to match
+func setDepth(c *types.Comment, depth int) {
+	c.Depth = depth
+	for _, r := range c.Replies {
+		setDepth(r, depth+1)
+	}
+}
+
+// fetchMoreChildren expands a batch of collapsed comment IDs for a post
+func (r *RedditSearcher) fetchMoreChildren(ctx context.Context, postID string, ids []string, sort string) ([]commentChild, error) {
+	params := url.Values{}
+	params.Set("api_type", "json")
+	params.Set("raw_json", "1")
+	params.Set("link_id", "t3_"+postID)
+	params.Set("children", strings.Join(ids, ","))
+	if sort != "" {
+		params.Set("sort", sort)
+	}
+	apiURL := fmt.Sprintf("%s/api/morechildren.json?%s", baseURL, params.Encode())
+
+	var result morechildrenResponse
+	if err := r.getJSON(ctx, apiURL, &result); err != nil {
+		return nil, err
+	}
+	return result.JSON.Data.Things, nil
+}
+
+// indexComments maps comment IDs to comments across a comment tree
+func indexComments(comments []*types.Comment, index map[string]*types.Comment) {
+	for _, c := range comments {
+		index[c.ID] = c
+		indexComments(c.Replies, index)
+	}
+}
+
+// CountComments counts all comments in a comment tree, including nested replies
+func CountComments(comments []*types.Comment) int {
+	n := 0
+	for _, c := range comments {
+		n += 1 + CountComments(c.Replies)
+	}
+	return n
+}
@@ -0,0 +1,127 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// oldRedditURL serves the same JSON API as www.reddit.com and is often still
+// reachable when www is rate-limiting
+const oldRedditURL = "https://old.reddit.com"
+
+// mirrorCooldown is how long an endpoint that refused a request is tried last
+const mirrorCooldown = 2 * time.Minute
+
+// errNotJSON is returned when an endpoint answers with something other than
+// JSON, typically a block page
+var errNotJSON = errors.New("response is not JSON")
+
+// statusError is a non-200 HTTP response
+type statusError struct {
+	code       int
+	status     string
+	retryAfter time.Duration // the response's Retry-After, if any
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.code, e.status)
+}
+
+// HTTPStatus returns the HTTP status code of a failed Reddit request, or 0 if
+// err isn't an HTTP error response
+func HTTPStatus(err error) int {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code
+	}
+	return 0
+}
+
+// retryableStatus reports whether a response status means the same request
+// may succeed after waiting
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusBadGateway ||
+		code == http.StatusServiceUnavailable || code == http.StatusGatewayTimeout
+}
+
+// shouldFallback reports whether a failed request may succeed on a mirror:
+// blocks, rate limits, server errors and network failures, but not requests
+// that are simply wrong (404 etc.)
+func shouldFallback(err error) bool {
+	if errors.Is(err, ErrBudgetExhausted) {
+		return false
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code == http.StatusForbidden || se.code == http.StatusTooManyRequests || se.code >= 500
+	}
+	return true
+}
+
+// mirrorSet is the ordered list of endpoints serving Reddit's JSON API, with
+// endpoints that recently refused requests moved to the back
+type mirrorSet struct {
+	bases []string
+
+	mu           sync.Mutex
+	blockedUntil map[string]time.Time
+}
+
+// newMirrorSet returns www.reddit.com and old.reddit.com, or endpoint in
+// their place when it's set, then the extra mirrors
+func newMirrorSet(endpoint string, extra []string) *mirrorSet {
+	m := &mirrorSet{
+		bases:        []string{baseURL, oldRedditURL},
+		blockedUntil: map[string]time.Time{},
+	}
+	if endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/"); endpoint != "" {
+		m.bases = []string{endpoint}
+	}
+	for _, base := range extra {
+		base = strings.TrimRight(strings.TrimSpace(base), "/")
+		if base != "" && !slices.Contains(m.bases, base) {
+			m.bases = append(m.bases, base)
+		}
+	}
+	return m
+}
+
+// mirrorOrder returns the endpoints to try, available ones first
+func (r *RedditSearcher) mirrorOrder() []string {
+	m := r.mirrors
+	if m == nil {
+		return []string{baseURL}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var ready, blocked []string
+	for _, base := range m.bases {
+		if now.Before(m.blockedUntil[base]) {
+			blocked = append(blocked, base)
+		} else {
+			ready = append(ready, base)
+		}
+	}
+	return append(ready, blocked...)
+}
+
+// markBlocked moves an endpoint to the back of the order for a while
+func (r *RedditSearcher) markBlocked(base string, err error) {
+	m := r.mirrors
+	if m == nil || len(m.bases) < 2 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if now.After(m.blockedUntil[base]) {
+		fmt.Printf("  Warning: %s refused request (%v), falling back to mirrors for %s\n", base, err, mirrorCooldown)
+	}
+	m.blockedUntil[base] = now.Add(mirrorCooldown)
+}
@@ -2,21 +2,27 @@ package search
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	"hiveminer/pkg/types"
 )
 
 // MockSearcher implements Searcher for testing
 type MockSearcher struct {
-	Posts   []types.Post
-	Threads map[string]*types.Thread
-	Err     error
+	Posts     []types.Post
+	Threads   map[string]*types.Thread
+	WikiPages map[string]map[string]*types.Thread // subreddit -> page -> thread
+	Sidebars  map[string]*types.Thread
+	Err       error
 }
 
 // NewMockSearcher creates a new mock searcher
 func NewMockSearcher() *MockSearcher {
 	return &MockSearcher{
-		Threads: make(map[string]*types.Thread),
+		Threads:   make(map[string]*types.Thread),
+		WikiPages: make(map[string]map[string]*types.Thread),
+		Sidebars:  make(map[string]*types.Thread),
 	}
 }
 
@@ -53,3 +59,82 @@ func (m *MockSearcher) GetThread(ctx context.Context, permalink string, commentL
 	return &types.Thread{}, nil
 }
 
+// ListWikiPages returns the mock wiki page names for a subreddit
+func (m *MockSearcher) ListWikiPages(ctx context.Context, subreddit string) ([]string, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var pages []string
+	for page := range m.WikiPages[subreddit] {
+		pages = append(pages, page)
+	}
+	sort.Strings(pages)
+	return pages, nil
+}
+
+// GetWikiPage returns a mock wiki page
+func (m *MockSearcher) GetWikiPage(ctx context.Context, subreddit, page string) (*types.Thread, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if thread, ok := m.WikiPages[subreddit][page]; ok {
+		return thread, nil
+	}
+	return nil, fmt.Errorf("wiki page r/%s/wiki/%s not found", subreddit, page)
+}
+
+// GetSidebar returns a mock sidebar
+func (m *MockSearcher) GetSidebar(ctx context.Context, subreddit string) (*types.Thread, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	if thread, ok := m.Sidebars[subreddit]; ok {
+		return thread, nil
+	}
+	return nil, fmt.Errorf("r/%s has no sidebar", subreddit)
+}
+
+// GetThreadPaged returns a mock thread, ignoring pagination options
+func (m *MockSearcher) GetThreadPaged(ctx context.Context, permalink string, opts ThreadOptions) (*types.Thread, error) {
+	return m.GetThread(ctx, permalink, opts.CommentBudget)
+}
+
+// ListUserSubmissions returns mock posts authored by the user
+func (m *MockSearcher) ListUserSubmissions(ctx context.Context, username, sort string, limit int) ([]types.Post, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var posts []types.Post
+	for _, p := range m.Posts {
+		if p.Author == username && len(posts) < limit {
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+// ListUserComments returns mock comments authored by the user across all mock threads
+func (m *MockSearcher) ListUserComments(ctx context.Context, username, sortBy string, limit int) ([]*types.Comment, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	var comments []*types.Comment
+	var walk func([]*types.Comment)
+	walk = func(cs []*types.Comment) {
+		for _, c := range cs {
+			if c.Author == username && len(comments) < limit {
+				comments = append(comments, c)
+			}
+			walk(c.Replies)
+		}
+	}
+	permalinks := make([]string, 0, len(m.Threads))
+	for permalink := range m.Threads {
+		permalinks = append(permalinks, permalink)
+	}
+	sort.Strings(permalinks)
+	for _, permalink := range permalinks {
+		walk(m.Threads[permalink].Comments)
+	}
+	return comments, nil
+}
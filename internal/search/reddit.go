@@ -1,14 +1,19 @@
 package search
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"hiveminer/internal/ratelimit"
 	"hiveminer/pkg/types"
 )
 
@@ -19,13 +24,20 @@ const (
 
 // RedditSearcher implements Searcher for the Reddit API
 type RedditSearcher struct {
-	client *http.Client
+	client  *http.Client
+	headers http.Header // extra headers set on every request
+	mirrors *mirrorSet  // fallback endpoints when reddit.com refuses requests
+	acct    accounting
+
+	maxRetries int           // retries of requests every endpoint rate-limited
+	backoff    time.Duration // wait before the first retry, doubling after each
 }
 
 // NewRedditSearcher creates a new Reddit API searcher
 func NewRedditSearcher() *RedditSearcher {
 	return &RedditSearcher{
-		client: &http.Client{Timeout: 30 * time.Second},
+		client:  &http.Client{Timeout: 30 * time.Second},
+		mirrors: newMirrorSet("", nil),
 	}
 }
 
@@ -51,6 +63,7 @@ type postData struct {
 	Subreddit   string  `json:"subreddit"`
 	NSFW        bool    `json:"over_18"`
 	Created     float64 `json:"created_utc"`
+	Flair       string  `json:"link_flair_text"`
 }
 
 // commentResponse for thread comments
@@ -74,6 +87,10 @@ type commentData struct {
 	Permalink string  `json:"permalink"`
 	Replies   any     `json:"replies"`
 	Depth     int     `json:"depth"`
+	ParentID  string  `json:"parent_id"`
+	// "more" stub fields (kind == "more")
+	Children []string `json:"children"`
+	Count    int      `json:"count"`
 	// Post fields (for the first element)
 	Title       string `json:"title"`
 	Selftext    string `json:"selftext"`
@@ -82,6 +99,7 @@ type commentData struct {
 	NumComments int    `json:"num_comments"`
 	Domain      string `json:"domain"`
 	NSFW        bool   `json:"over_18"`
+	Flair       string `json:"link_flair_text"`
 }
 
 // Search searches Reddit for posts matching a query
@@ -99,34 +117,29 @@ func (r *RedditSearcher) ListSubreddit(ctx context.Context, subreddit, sort stri
 
 // GetThread fetches a complete thread with comments
 func (r *RedditSearcher) GetThread(ctx context.Context, permalink string, commentLimit int) (*types.Thread, error) {
-	// Clean up permalink
+	permalink = cleanPermalink(permalink)
+	apiURL := fmt.Sprintf("%s%s.json?limit=%d&raw_json=1&depth=10", baseURL, permalink, commentLimit)
+
+	thread, _, err := r.fetchThreadPage(ctx, apiURL, permalink)
+	return thread, err
+}
+
+// cleanPermalink strips the reddit host from a permalink and ensures a leading slash
+func cleanPermalink(permalink string) string {
 	permalink = strings.TrimPrefix(permalink, "https://reddit.com")
 	permalink = strings.TrimPrefix(permalink, "https://www.reddit.com")
 	if !strings.HasPrefix(permalink, "/") {
 		permalink = "/" + permalink
 	}
+	return permalink
+}
 
-	apiURL := fmt.Sprintf("%s%s.json?limit=%d&raw_json=1&depth=10", baseURL, permalink, commentLimit)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("User-Agent", userAgent)
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
-	}
-
+// fetchThreadPage fetches one page of a thread, returning the parsed thread and
+// any "more comments" stubs that can be expanded with morechildren.
+func (r *RedditSearcher) fetchThreadPage(ctx context.Context, apiURL, permalink string) (*types.Thread, []moreStub, error) {
 	var result commentResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
+	if err := r.getJSON(ctx, apiURL, &result); err != nil {
+		return nil, nil, err
 	}
 
 	thread := &types.Thread{}
@@ -147,22 +160,35 @@ func (r *RedditSearcher) GetThread(ctx context.Context, permalink string, commen
 			Permalink:   permalink,
 			NSFW:        postData.NSFW,
 			Created:     postData.Created,
+			Flair:       postData.Flair,
 		}
 	}
 
 	// Second element contains comments
+	var more []moreStub
 	if len(result) > 1 {
-		thread.Comments = r.parseComments(result[1].Data.Children, 0)
+		thread.Comments = r.collectComments(result[1].Data.Children, 0, &more)
 	}
 
-	return thread, nil
+	return thread, more, nil
 }
 
-// parseComments recursively parses comments and their replies
-func (r *RedditSearcher) parseComments(children []commentChild, depth int) []*types.Comment {
+// collectComments recursively parses comments and their replies, recording
+// "more" stubs into more when it is non-nil.
+func (r *RedditSearcher) collectComments(children []commentChild, depth int, more *[]moreStub) []*types.Comment {
 	var comments []*types.Comment
 
 	for _, child := range children {
+		if child.Kind == "more" {
+			if more != nil && len(child.Data.Children) > 0 {
+				*more = append(*more, moreStub{
+					ParentID: child.Data.ParentID,
+					Children: child.Data.Children,
+					Depth:    depth,
+				})
+			}
+			continue
+		}
 		if child.Kind != "t1" { // t1 = comment
 			continue
 		}
@@ -194,7 +220,7 @@ func (r *RedditSearcher) parseComments(children []commentChild, depth int) []*ty
 								}
 							}
 						}
-						comment.Replies = r.parseComments(replyChildren, depth+1)
+						comment.Replies = r.collectComments(replyChildren, depth+1, more)
 					}
 				}
 			}
@@ -208,24 +234,8 @@ func (r *RedditSearcher) parseComments(children []commentChild, depth int) []*ty
 
 // fetchPosts fetches posts from a Reddit API URL
 func (r *RedditSearcher) fetchPosts(ctx context.Context, apiURL string) ([]types.Post, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("User-Agent", userAgent)
-
-	resp, err := r.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
-	}
-
 	var result redditResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := r.getJSON(ctx, apiURL, &result); err != nil {
 		return nil, err
 	}
 
@@ -244,8 +254,98 @@ func (r *RedditSearcher) fetchPosts(ctx context.Context, apiURL string) ([]types
 			Subreddit:   child.Data.Subreddit,
 			NSFW:        child.Data.NSFW,
 			Created:     child.Data.Created,
+			Flair:       child.Data.Flair,
 		})
 	}
 
 	return posts, nil
 }
+
+// getJSON fetches a Reddit API URL and decodes the JSON response into dst.
+// When reddit.com blocks or rate-limits the request, the same path is retried
+// on the fallback mirrors; when every endpoint rate-limits it or is
+// unavailable, the request is retried after the politeness backoff with jitter.
+func (r *RedditSearcher) getJSON(ctx context.Context, apiURL string, dst any) error {
+	backoff := r.backoff
+	for attempt := 0; ; attempt++ {
+		err := r.getJSONOnce(ctx, apiURL, dst)
+		var se *statusError
+		if err == nil || attempt >= r.maxRetries || !errors.As(err, &se) || !retryableStatus(se.code) {
+			return err
+		}
+		wait := ratelimit.Jitter(backoff)
+		if se.retryAfter > 0 {
+			wait = se.retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// getJSONOnce makes one attempt at a request, falling back across mirrors
+func (r *RedditSearcher) getJSONOnce(ctx context.Context, apiURL string, dst any) error {
+	path, ok := strings.CutPrefix(apiURL, baseURL)
+	if !ok {
+		return r.fetchJSON(ctx, apiURL, dst)
+	}
+
+	var firstErr error
+	for _, base := range r.mirrorOrder() {
+		err := r.fetchJSON(ctx, base+path, dst)
+		if err == nil {
+			return nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+		if ctx.Err() != nil || !shouldFallback(err) {
+			return err
+		}
+		r.markBlocked(base, err)
+	}
+	return firstErr
+}
+
+// fetchJSON performs a single GET and decodes the JSON response into dst
+func (r *RedditSearcher) fetchJSON(ctx context.Context, apiURL string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return err
+	}
+	if err := r.acct.begin(ctx, req.URL.RequestURI()); err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	for name, values := range r.headers {
+		req.Header[name] = values
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		se := &statusError{code: resp.StatusCode, status: resp.Status}
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+			se.retryAfter = time.Duration(secs) * time.Second
+		}
+		return se
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	// Block pages and interstitials come back as 200 HTML
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return errNotJSON
+	}
+	return json.Unmarshal(body, dst)
+}
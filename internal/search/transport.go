@@ -0,0 +1,166 @@
+package search
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"hiveminer/internal/ratelimit"
+)
+
+// TransportConfig customizes how RedditSearcher reaches reddit.com, for
+// networks that can't connect directly
+type TransportConfig struct {
+	Proxy    string      // http://, https:// or socks5:// proxy URL; empty uses HTTPS_PROXY/HTTP_PROXY
+	CABundle string      // PEM file of extra CAs to trust (e.g. a corporate TLS-inspecting proxy)
+	Headers  http.Header // set on every request, overriding defaults such as User-Agent
+	Mirrors  []string    // extra endpoints serving Reddit's JSON API, tried after www and old.reddit.com
+	Endpoint string      // serves Reddit's JSON API in place of www and old.reddit.com, e.g. a test server
+
+	Budget            int           // max requests (0 = unlimited)
+	Delay             time.Duration // minimum time between requests
+	RequestsPerMinute int           // cap on requests, spread evenly over the minute (0 = none)
+	RequestLog        string        // append every request here; the budget counts the whole log
+	MaxRetries        int           // retries of rate-limited and unavailable requests (0 = none)
+	Backoff           time.Duration // wait before the first retry, doubling after each with jitter
+}
+
+// TransportConfigFromEnv reads HIVEMINER_PROXY, HIVEMINER_CA_BUNDLE,
+// HIVEMINER_HEADERS (newline-separated "Name: value" lines),
+// HIVEMINER_MIRRORS (comma-separated base URLs), HIVEMINER_REDDIT_URL (an
+// endpoint replacing reddit.com), HIVEMINER_REQUEST_BUDGET,
+// HIVEMINER_REQUEST_DELAY (a duration), HIVEMINER_REQUESTS_PER_MINUTE,
+// HIVEMINER_REQUEST_LOG and the reddit policy of the HIVEMINER_POLITENESS
+// config (or the default policy), which fills in the delay, per-minute cap
+// and User-Agent when they aren't set otherwise and sets the retries
+func TransportConfigFromEnv() (TransportConfig, error) {
+	cfg := TransportConfig{
+		Proxy:      os.Getenv("HIVEMINER_PROXY"),
+		CABundle:   os.Getenv("HIVEMINER_CA_BUNDLE"),
+		Endpoint:   os.Getenv("HIVEMINER_REDDIT_URL"),
+		RequestLog: os.Getenv("HIVEMINER_REQUEST_LOG"),
+	}
+	if raw := os.Getenv("HIVEMINER_REQUEST_BUDGET"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return cfg, fmt.Errorf("HIVEMINER_REQUEST_BUDGET: %w", err)
+		}
+		cfg.Budget = n
+	}
+	if raw := os.Getenv("HIVEMINER_REQUEST_DELAY"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return cfg, fmt.Errorf("HIVEMINER_REQUEST_DELAY: %w", err)
+		}
+		cfg.Delay = d
+	}
+	perMinuteSet := false
+	if raw := os.Getenv("HIVEMINER_REQUESTS_PER_MINUTE"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return cfg, fmt.Errorf("HIVEMINER_REQUESTS_PER_MINUTE: invalid count %q", raw)
+		}
+		cfg.RequestsPerMinute = n
+		perMinuteSet = true
+	}
+	if raw := os.Getenv("HIVEMINER_MIRRORS"); raw != "" {
+		cfg.Mirrors = strings.Split(raw, ",")
+	}
+	if raw := os.Getenv("HIVEMINER_HEADERS"); raw != "" {
+		headers, err := ParseHeaders(strings.Split(raw, "\n"))
+		if err != nil {
+			return cfg, fmt.Errorf("HIVEMINER_HEADERS: %w", err)
+		}
+		cfg.Headers = headers
+	}
+	politeness, err := ratelimit.ConfigFromEnv()
+	if err != nil {
+		return cfg, err
+	}
+	policy := politeness.Policy(ratelimit.SourceReddit)
+	if cfg.Delay == 0 {
+		cfg.Delay = time.Duration(policy.Delay)
+	}
+	if !perMinuteSet {
+		cfg.RequestsPerMinute = policy.RequestsPerMinute
+	}
+	if policy.UserAgent != "" && cfg.Headers.Get("User-Agent") == "" {
+		if cfg.Headers == nil {
+			cfg.Headers = http.Header{}
+		}
+		cfg.Headers.Set("User-Agent", policy.UserAgent)
+	}
+	cfg.MaxRetries = policy.MaxRetries
+	cfg.Backoff = time.Duration(policy.Backoff)
+	return cfg, nil
+}
+
+// ParseHeaders parses "Name: value" lines, skipping blank ones
+func ParseHeaders(lines []string) (http.Header, error) {
+	headers := http.Header{}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid header %q, expected \"Name: value\"", line)
+		}
+		headers.Set(name, strings.TrimSpace(value))
+	}
+	return headers, nil
+}
+
+// NewRedditSearcherWithTransport creates a Reddit searcher that connects
+// through the configured proxy and CA bundle and sends the extra headers
+func NewRedditSearcherWithTransport(cfg TransportConfig) (*RedditSearcher, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", cfg.Proxy)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", proxyURL.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", cfg.CABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	r := &RedditSearcher{
+		client:  &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		headers: cfg.Headers,
+		mirrors: newMirrorSet(cfg.Endpoint, cfg.Mirrors),
+	}
+	r.SetBudget(cfg.Budget)
+	r.SetDelay(cfg.Delay)
+	r.SetRateLimit(cfg.RequestsPerMinute)
+	r.SetRequestLog(cfg.RequestLog)
+	r.SetRetry(cfg.MaxRetries, cfg.Backoff)
+	return r, nil
+}
@@ -0,0 +1,130 @@
+package search_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"hiveminer/internal/search"
+	"hiveminer/pkg/harness"
+	"hiveminer/pkg/types"
+)
+
+const tentPermalink = "/r/camping/comments/a1/"
+
+// tentReddit is a fake Reddit serving one thread
+func tentReddit(t *testing.T) *harness.Reddit {
+	t.Helper()
+	fake := harness.NewReddit()
+	t.Cleanup(fake.Close)
+	fake.AddThread(&types.Thread{
+		Post:     types.Post{ID: "a1", Title: "Best backpacking tent?", Subreddit: "camping"},
+		Comments: []*types.Comment{{ID: "c1", Body: "The Duplex.", Score: 12}},
+	})
+	return fake
+}
+
+// searcherFor reaches endpoint first, then the mirrors, retrying quickly
+func searcherFor(t *testing.T, endpoint string, mirrors ...string) *search.RedditSearcher {
+	t.Helper()
+	s, err := search.NewRedditSearcherWithTransport(search.TransportConfig{Endpoint: endpoint, Mirrors: mirrors})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetRetry(2, 10*time.Millisecond)
+	return s
+}
+
+func TestBlockedEndpointFallsBackToMirror(t *testing.T) {
+	primary, mirror := tentReddit(t), tentReddit(t)
+	primary.Fail("/comments/", http.StatusForbidden, -1)
+	s := searcherFor(t, primary.URL(), mirror.URL())
+
+	for i := 0; i < 2; i++ {
+		thread, err := s.GetThread(context.Background(), tentPermalink, 0)
+		if err != nil {
+			t.Fatalf("fetch %d: %v", i, err)
+		}
+		if thread.Post.ID != "a1" || len(thread.Comments) != 1 {
+			t.Errorf("fetch %d: got post %q with %d comments", i, thread.Post.ID, len(thread.Comments))
+		}
+	}
+	// The blocked endpoint is tried last after refusing once
+	if n := primary.Count("/comments/"); n != 1 {
+		t.Errorf("blocked endpoint asked %d times, want once", n)
+	}
+	if n := mirror.Count("/comments/"); n != 2 {
+		t.Errorf("mirror asked %d times, want 2", n)
+	}
+}
+
+func TestBlockPageFallsBackToMirror(t *testing.T) {
+	blockPage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>Blocked</body></html>"))
+	}))
+	defer blockPage.Close()
+	mirror := tentReddit(t)
+	s := searcherFor(t, blockPage.URL, mirror.URL())
+
+	thread, err := s.GetThread(context.Background(), tentPermalink, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thread.Post.ID != "a1" {
+		t.Errorf("got post %q, want the mirror's a1", thread.Post.ID)
+	}
+}
+
+func TestNotFoundDoesNotFallBack(t *testing.T) {
+	primary, mirror := tentReddit(t), tentReddit(t)
+	s := searcherFor(t, primary.URL(), mirror.URL())
+
+	_, err := s.GetThread(context.Background(), "/r/camping/comments/zz9/", 0)
+	if search.HTTPStatus(err) != http.StatusNotFound {
+		t.Fatalf("got %v, want a 404", err)
+	}
+	if n := len(mirror.Requests()); n != 0 {
+		t.Errorf("mirror asked %d times for a missing thread, want none", n)
+	}
+}
+
+func TestRateLimitWaitsForRetryAfter(t *testing.T) {
+	fake := tentReddit(t)
+	target, err := url.Parse(fake.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	var calls atomic.Int32
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	defer limited.Close()
+	s := searcherFor(t, limited.URL)
+
+	start := time.Now()
+	thread, err := s.GetThread(context.Background(), tentPermalink, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if thread.Post.ID != "a1" {
+		t.Errorf("got post %q after the retry", thread.Post.ID)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want the response's Retry-After of 1s rather than the 10ms backoff", elapsed)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("%d requests, want 2", n)
+	}
+}
@@ -0,0 +1,118 @@
+package search
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"hiveminer/pkg/types"
+)
+
+// minTopLevel is how many top-level comments the score strategy keeps even
+// when few reach the threshold (new or quiet threads)
+const minTopLevel = 10
+
+// DefaultTruncation is the score-aware strategy used unless configured otherwise
+var DefaultTruncation = types.TruncationStrategy{
+	Name:             "score",
+	TopLevelMinScore: 2,
+	HighScore:        20,
+	MinScore:         1,
+	MaxDepth:         3,
+}
+
+// ParseTruncation parses a truncation strategy: "flat", "score", or "score"
+// with overrides, e.g. "score:top=5,high=50,min=2,depth=4"
+func ParseTruncation(s string) (types.TruncationStrategy, error) {
+	name, params, _ := strings.Cut(s, ":")
+	switch name {
+	case "flat":
+		if params != "" {
+			return types.TruncationStrategy{}, fmt.Errorf("flat truncation takes no parameters")
+		}
+		return types.TruncationStrategy{Name: "flat"}, nil
+	case "score", "":
+	default:
+		return types.TruncationStrategy{}, fmt.Errorf("unknown truncation strategy %q (use flat or score)", name)
+	}
+
+	strategy := DefaultTruncation
+	if params == "" {
+		return strategy, nil
+	}
+	for _, kv := range strings.Split(params, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(kv), "=")
+		n, err := strconv.Atoi(value)
+		if !ok || err != nil {
+			return strategy, fmt.Errorf("invalid truncation parameter %q, expected key=number", kv)
+		}
+		switch key {
+		case "top":
+			strategy.TopLevelMinScore = n
+		case "high":
+			strategy.HighScore = n
+		case "min":
+			strategy.MinScore = n
+		case "depth":
+			strategy.MaxDepth = n
+		default:
+			return strategy, fmt.Errorf("unknown truncation parameter %q (use top, high, min, depth)", key)
+		}
+	}
+	return strategy, nil
+}
+
+// TruncateComments prunes a comment tree with the score strategy: top-level
+// comments scoring TopLevelMinScore are kept (or the best minTopLevel when
+// fewer qualify), direct replies to high-score comments are kept, and other
+// replies survive only while they score MinScore within MaxDepth. Dropping a
+// reply drops its subtree. The flat strategy returns the comments unchanged.
+func TruncateComments(comments []*types.Comment, strategy types.TruncationStrategy) []*types.Comment {
+	if strategy.Name != "score" {
+		return comments
+	}
+
+	var kept []*types.Comment
+	for _, c := range comments {
+		if c.Score >= strategy.TopLevelMinScore {
+			kept = append(kept, c)
+		}
+	}
+	if len(kept) < minTopLevel && len(kept) < len(comments) {
+		ranked := append([]*types.Comment(nil), comments...)
+		sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+		keep := map[*types.Comment]bool{}
+		for i := 0; i < len(ranked) && i < minTopLevel; i++ {
+			keep[ranked[i]] = true
+		}
+		kept = kept[:0]
+		for _, c := range comments {
+			if keep[c] || c.Score >= strategy.TopLevelMinScore {
+				kept = append(kept, c)
+			}
+		}
+	}
+
+	result := make([]*types.Comment, len(kept))
+	for i, c := range kept {
+		result[i] = pruneReplies(c, 1, strategy)
+	}
+	return result
+}
+
+// pruneReplies copies a comment with its replies, at the given depth,
+// filtered by the strategy
+func pruneReplies(c *types.Comment, depth int, strategy types.TruncationStrategy) *types.Comment {
+	cp := *c
+	cp.Replies = nil
+	for _, r := range c.Replies {
+		keep := c.Score >= strategy.HighScore ||
+			r.Score >= strategy.HighScore ||
+			(r.Score >= strategy.MinScore && depth <= strategy.MaxDepth)
+		if keep {
+			cp.Replies = append(cp.Replies, pruneReplies(r, depth+1, strategy))
+		}
+	}
+	return &cp
+}
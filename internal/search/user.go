@@ -0,0 +1,99 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"hiveminer/pkg/types"
+)
+
+// userCommentsResponse represents a listing of a user's comments
+type userCommentsResponse struct {
+	Data struct {
+		Children []struct {
+			Data struct {
+				commentData
+				LinkTitle string `json:"link_title"`
+			} `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// ListUserSubmissions lists posts submitted by a user
+func (r *RedditSearcher) ListUserSubmissions(ctx context.Context, username, sort string, limit int) ([]types.Post, error) {
+	username = strings.TrimPrefix(username, "u/")
+	apiURL := fmt.Sprintf("%s/user/%s/submitted.json?sort=%s&limit=%d&raw_json=1", baseURL, username, sort, limit)
+	return r.fetchPosts(ctx, apiURL)
+}
+
+// ListUserComments lists comments written by a user, each with its own permalink
+func (r *RedditSearcher) ListUserComments(ctx context.Context, username, sort string, limit int) ([]*types.Comment, error) {
+	username = strings.TrimPrefix(username, "u/")
+	apiURL := fmt.Sprintf("%s/user/%s/comments.json?sort=%s&limit=%d&raw_json=1", baseURL, username, sort, limit)
+
+	var result userCommentsResponse
+	if err := r.getJSON(ctx, apiURL, &result); err != nil {
+		return nil, err
+	}
+
+	comments := make([]*types.Comment, 0, len(result.Data.Children))
+	for _, child := range result.Data.Children {
+		d := child.Data
+		body := d.Body
+		if d.LinkTitle != "" {
+			body = fmt.Sprintf("(in r/%s thread %q)\n%s", d.Subreddit, d.LinkTitle, d.Body)
+		}
+		comments = append(comments, &types.Comment{
+			ID:        d.ID,
+			Body:      body,
+			Author:    d.Author,
+			Score:     d.Score,
+			Created:   d.Created,
+			Permalink: d.Permalink,
+		})
+	}
+	return comments, nil
+}
+
+// UserCommentThreads groups a user's comments into pseudo-threads of at most
+// perThread comments, so their history can flow through extraction.
+func UserCommentThreads(username string, comments []*types.Comment, perThread int) []*types.Thread {
+	username = strings.TrimPrefix(username, "u/")
+	if perThread <= 0 {
+		perThread = len(comments)
+	}
+
+	var threads []*types.Thread
+	for start, page := 0, 1; start < len(comments); start, page = start+perThread, page+1 {
+		end := min(start+perThread, len(comments))
+		threads = append(threads, &types.Thread{
+			Post: types.Post{
+				ID:        UserCommentsPostID(username, page),
+				Title:     fmt.Sprintf("Comments by u/%s (page %d)", username, page),
+				Author:    username,
+				Permalink: fmt.Sprintf("/user/%s/comments", username),
+				Source:    types.SourceUser,
+			},
+			Comments: comments[start:end],
+		})
+	}
+	return threads
+}
+
+// UserCommentsPostID builds a stable, filename-safe post ID for a page of user comments
+func UserCommentsPostID(username string, page int) string {
+	return fmt.Sprintf("user_%s_comments_%d", idSlug(username), page)
+}
+
+// ParseUserCommentsPostID extracts the page number from a user comments post ID
+func ParseUserCommentsPostID(postID string) (page int, ok bool) {
+	i := strings.LastIndex(postID, "_")
+	if !strings.HasPrefix(postID, "user_") || i < 0 {
+		return 0, false
+	}
+	if _, err := fmt.Sscanf(postID[i+1:], "%d", &page); err != nil {
+		return 0, false
+	}
+	return page, true
+}
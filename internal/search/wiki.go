@@ -0,0 +1,134 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"hiveminer/pkg/types"
+)
+
+var nonIDChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// wikiPageResponse represents the JSON response for a single wiki page
+type wikiPageResponse struct {
+	Data struct {
+		ContentMD    string  `json:"content_md"`
+		RevisionDate float64 `json:"revision_date"`
+		RevisionBy   struct {
+			Data struct {
+				Name string `json:"name"`
+			} `json:"data"`
+		} `json:"revision_by"`
+	} `json:"data"`
+}
+
+// wikiListingResponse represents the JSON response for a wiki page listing
+type wikiListingResponse struct {
+	Data []string `json:"data"`
+}
+
+// aboutResponse represents the JSON response for a subreddit's about page
+type aboutResponse struct {
+	Data struct {
+		DisplayName string  `json:"display_name"`
+		Title       string  `json:"title"`
+		Description string  `json:"description"`
+		Subscribers int     `json:"subscribers"`
+		NSFW        bool    `json:"over18"`
+		Created     float64 `json:"created_utc"`
+	} `json:"data"`
+}
+
+// ListWikiPages lists the wiki page names of a subreddit
+func (r *RedditSearcher) ListWikiPages(ctx context.Context, subreddit string) ([]string, error) {
+	apiURL := fmt.Sprintf("%s/r/%s/wiki/pages.json?raw_json=1", baseURL, subreddit)
+
+	var result wikiListingResponse
+	if err := r.getJSON(ctx, apiURL, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetWikiPage fetches a single wiki page as a Thread with no comments
+func (r *RedditSearcher) GetWikiPage(ctx context.Context, subreddit, page string) (*types.Thread, error) {
+	page = strings.Trim(page, "/")
+	apiURL := fmt.Sprintf("%s/r/%s/wiki/%s.json?raw_json=1", baseURL, subreddit, page)
+
+	var result wikiPageResponse
+	if err := r.getJSON(ctx, apiURL, &result); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(result.Data.ContentMD) == "" {
+		return nil, fmt.Errorf("wiki page r/%s/wiki/%s is empty", subreddit, page)
+	}
+
+	return &types.Thread{
+		Post: types.Post{
+			ID:        WikiPostID(subreddit, page),
+			Title:     fmt.Sprintf("r/%s wiki: %s", subreddit, page),
+			Selftext:  result.Data.ContentMD,
+			Author:    result.Data.RevisionBy.Data.Name,
+			Subreddit: subreddit,
+			Permalink: fmt.Sprintf("/r/%s/wiki/%s", subreddit, page),
+			Created:   result.Data.RevisionDate,
+			Source:    types.SourceWiki,
+		},
+	}, nil
+}
+
+// GetSidebar fetches a subreddit's sidebar as a Thread with no comments
+func (r *RedditSearcher) GetSidebar(ctx context.Context, subreddit string) (*types.Thread, error) {
+	apiURL := fmt.Sprintf("%s/r/%s/about.json?raw_json=1", baseURL, subreddit)
+
+	var result aboutResponse
+	if err := r.getJSON(ctx, apiURL, &result); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(result.Data.Description) == "" {
+		return nil, fmt.Errorf("r/%s has no sidebar", subreddit)
+	}
+
+	name := result.Data.DisplayName
+	if name == "" {
+		name = subreddit
+	}
+
+	return &types.Thread{
+		Post: types.Post{
+			ID:        SidebarPostID(name),
+			Title:     fmt.Sprintf("r/%s sidebar", name),
+			Selftext:  result.Data.Description,
+			Subreddit: name,
+			Permalink: fmt.Sprintf("/r/%s/about/sidebar", name),
+			NSFW:      result.Data.NSFW,
+			Created:   result.Data.Created,
+			Source:    types.SourceSidebar,
+		},
+	}, nil
+}
+
+// WikiPostID builds a stable, filename-safe post ID for a wiki page
+func WikiPostID(subreddit, page string) string {
+	return "wiki_" + idSlug(subreddit) + "_" + idSlug(page)
+}
+
+// SidebarPostID builds a stable, filename-safe post ID for a subreddit sidebar
+func SidebarPostID(subreddit string) string {
+	return "sidebar_" + idSlug(subreddit)
+}
+
+// ParseWikiPermalink splits a wiki permalink like /r/sub/wiki/page into its parts
+func ParseWikiPermalink(permalink string) (subreddit, page string, ok bool) {
+	parts := strings.SplitN(strings.Trim(permalink, "/"), "/", 4)
+	if len(parts) < 4 || parts[0] != "r" || parts[2] != "wiki" {
+		return "", "", false
+	}
+	return parts[1], parts[3], true
+}
+
+func idSlug(s string) string {
+	return strings.Trim(nonIDChars.ReplaceAllString(strings.ToLower(s), "_"), "_")
+}
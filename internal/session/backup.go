@@ -0,0 +1,101 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"hiveminer/pkg/types"
+)
+
+// ManifestBackups is how many earlier manifest versions a session keeps
+const ManifestBackups = 5
+
+// ManifestBackup is an earlier version of a session's manifest
+type ManifestBackup struct {
+	N        int // 1 is the most recent
+	Path     string
+	Manifest *types.Manifest
+}
+
+// backupPath returns the nth most recent backup, manifest.json.n
+func backupPath(dir string, n int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.%d", manifestFile, n))
+}
+
+// BackupManifest saves manifest as the session's newest backup, shifting the
+// others back and dropping the oldest past ManifestBackups. Backups keep the
+// entries inline, since entry files are rewritten in place.
+func BackupManifest(dir string, manifest *types.Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling manifest backup: %w", err)
+	}
+	if err := os.Remove(backupPath(dir, ManifestBackups)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing oldest manifest backup: %w", err)
+	}
+	for n := ManifestBackups - 1; n >= 1; n-- {
+		if err := os.Rename(backupPath(dir, n), backupPath(dir, n+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotating manifest backups: %w", err)
+		}
+	}
+	if err := WriteFileAtomic(backupPath(dir, 1), data, 0644); err != nil {
+		return fmt.Errorf("writing manifest backup: %w", err)
+	}
+	return nil
+}
+
+// BackupSession backs up the manifest as it is on disk. It does nothing for a
+// session without a manifest yet.
+func BackupSession(dir string) error {
+	manifest, err := LoadManifest(dir)
+	if err != nil || manifest == nil {
+		return err
+	}
+	return BackupManifest(dir, manifest)
+}
+
+// ListBackups returns a session's manifest backups, newest first. Backups
+// that can't be read are skipped.
+func ListBackups(dir string) []ManifestBackup {
+	var backups []ManifestBackup
+	for n := 1; n <= ManifestBackups; n++ {
+		manifest, err := LoadBackup(dir, n)
+		if err != nil || manifest == nil {
+			continue
+		}
+		backups = append(backups, ManifestBackup{N: n, Path: backupPath(dir, n), Manifest: manifest})
+	}
+	return backups
+}
+
+// LoadBackup loads a session's nth most recent manifest backup, or nil if
+// there is none
+func LoadBackup(dir string, n int) (*types.Manifest, error) {
+	manifest, err := loadManifestFile(dir, backupPath(dir, n))
+	if err != nil {
+		return nil, fmt.Errorf("backup %d: %w", n, err)
+	}
+	return manifest, nil
+}
+
+// RestoreBackup makes the nth backup the session's manifest. The current
+// manifest is backed up first, becoming backup 1, so a rollback can itself
+// be undone.
+func RestoreBackup(dir string, n int) (*types.Manifest, error) {
+	restored, err := LoadBackup(dir, n)
+	if err != nil {
+		return nil, err
+	}
+	if restored == nil {
+		return nil, fmt.Errorf("no backup %d in %s", n, dir)
+	}
+	if err := BackupSession(dir); err != nil {
+		return nil, fmt.Errorf("backing up current manifest: %w", err)
+	}
+	if err := SaveManifest(dir, restored); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
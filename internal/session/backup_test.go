@@ -0,0 +1,72 @@
+package session
+
+import (
+	"testing"
+
+	"hiveminer/pkg/types"
+)
+
+func TestRestoreBackupCanBeUndone(t *testing.T) {
+	dir := t.TempDir()
+	entries := tentEntries()
+	AssignEntryIDs("a1", "name", entries)
+	m := NewManifest(types.FormRef{Title: "Tents"}, "tent", []string{"camping"})
+	m.Threads = []types.ThreadState{{PostID: "a1", Status: types.StatusExtracted, Entries: entries}}
+	if err := SaveManifest(dir, m); err != nil {
+		t.Fatal(err)
+	}
+	if err := BackupSession(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// A later run fails the thread and drops its entries
+	m.Threads[0].Status = types.StatusFailed
+	m.Threads[0].Entries = nil
+	if err := SaveManifest(dir, m); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := RestoreBackup(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th := restored.Threads[0]; th.Status != types.StatusExtracted || len(th.Entries) != 2 {
+		t.Fatalf("restored thread %s with %d entries, want extracted with 2", th.Status, len(th.Entries))
+	}
+	current, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th := current.Threads[0]; th.Status != types.StatusExtracted || len(th.Entries) != 2 || th.Entries[1].ID != entries[1].ID {
+		t.Errorf("manifest after rollback has thread %s with %d entries, want the backup's", th.Status, len(th.Entries))
+	}
+
+	// The manifest it replaced is now backup 1
+	undone, err := RestoreBackup(dir, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th := undone.Threads[0]; th.Status != types.StatusFailed || len(th.Entries) != 0 {
+		t.Errorf("undoing the rollback gave thread %s with %d entries, want failed with none", th.Status, len(th.Entries))
+	}
+}
+
+func TestBackupsRotate(t *testing.T) {
+	dir := t.TempDir()
+	m := NewManifest(types.FormRef{Title: "Tents"}, "tent", []string{"camping"})
+	for i := 0; i < ManifestBackups+2; i++ {
+		m.Runs = append(m.Runs, types.RunLog{})
+		if err := BackupManifest(dir, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	backups := ListBackups(dir)
+	if len(backups) != ManifestBackups {
+		t.Fatalf("%d backups, want %d", len(backups), ManifestBackups)
+	}
+	for i, b := range backups {
+		if want := ManifestBackups + 2 - i; len(b.Manifest.Runs) != want {
+			t.Errorf("backup %d has %d runs, want %d (newest first)", b.N, len(b.Manifest.Runs), want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"hiveminer/pkg/types"
+)
+
+// entriesDir holds each thread's extracted entries, one file per thread,
+// so the manifest stays small
+const entriesDir = "entries"
+
+// writtenEntries remembers the content of each entry file as last read or
+// written, so periodic saves only rewrite threads whose entries changed
+var writtenEntries sync.Map // path -> [sha256.Size]byte
+
+// entriesPath returns a thread's entry file, relative to the session directory
+func entriesPath(postID string) string {
+	return filepath.Join(entriesDir, postID+".json")
+}
+
+// saveEntries writes changed threads' entries to their entry files and
+// returns the threads as the manifest stores them: entries replaced by the
+// file's path and the entry count. It also returns the entry files of
+// threads whose entries were cleared, for removeEntryFiles once the manifest
+// no longer refers to them.
+func saveEntries(dir string, threads []types.ThreadState) ([]types.ThreadState, []string, error) {
+	stored := make([]types.ThreadState, len(threads))
+	var stale []string
+	for i, t := range threads {
+		stored[i] = t
+		if len(t.Entries) == 0 {
+			path := filepath.Join(dir, entriesPath(t.PostID))
+			if _, written := writtenEntries.Load(path); written || t.EntriesFile != "" {
+				stale = append(stale, path)
+			}
+			stored[i].EntriesFile = ""
+			stored[i].EntryCount = 0
+			continue
+		}
+
+		rel := entriesPath(t.PostID)
+		path := filepath.Join(dir, rel)
+		data, err := json.MarshalIndent(t.Entries, "", "  ")
+		if err != nil {
+			return nil, nil, fmt.Errorf("marshaling entries for thread %s: %w", t.PostID, err)
+		}
+		sum := sha256.Sum256(data)
+		if prev, ok := writtenEntries.Load(path); !ok || prev.([sha256.Size]byte) != sum {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return nil, nil, fmt.Errorf("creating entries directory: %w", err)
+			}
+			if err := WriteFileAtomic(path, data, 0644); err != nil {
+				return nil, nil, fmt.Errorf("writing entries for thread %s: %w", t.PostID, err)
+			}
+			writtenEntries.Store(path, sum)
+		}
+
+		stored[i].Entries = nil
+		stored[i].EntriesFile = rel
+		stored[i].EntryCount = len(t.Entries)
+	}
+	return stored, stale, nil
+}
+
+// removeEntryFiles deletes entry files no thread uses any more and forgets
+// their content, so entries written there again aren't taken as unchanged
+func removeEntryFiles(paths []string) error {
+	for _, path := range paths {
+		writtenEntries.Delete(path)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing entry file: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadEntries reads the entry files the manifest's threads refer to.
+// Manifests from before entry files keep their entries inline and load as
+// they are; their next save moves the entries out.
+func loadEntries(dir string, manifest *types.Manifest) error {
+	for i := range manifest.Threads {
+		t := &manifest.Threads[i]
+		if t.EntriesFile == "" || len(t.Entries) > 0 {
+			continue
+		}
+		path := filepath.Join(dir, t.EntriesFile)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading entries for thread %s: %w", t.PostID, err)
+		}
+		if err := json.Unmarshal(data, &t.Entries); err != nil {
+			return fmt.Errorf("parsing entries for thread %s: %w", t.PostID, err)
+		}
+		writtenEntries.Store(path, sha256.Sum256(data))
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"hiveminer/pkg/types"
+)
+
+func TestSaveMovesInlineEntriesToFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeV1Manifest(t, dir)
+	m, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveManifest(dir, m); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored types.Manifest
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	th := stored.Threads[0]
+	if len(th.Entries) != 0 || th.EntriesFile != entriesPath("a1") || th.EntryCount != 2 {
+		t.Errorf("stored thread has %d inline entries, file %q, count %d; want the entries moved to %s", len(th.Entries), th.EntriesFile, th.EntryCount, entriesPath("a1"))
+	}
+	if stored.Version != manifestVersion {
+		t.Errorf("version %d, want %d", stored.Version, manifestVersion)
+	}
+
+	reloaded, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.Threads[0].Entries; len(got) != 2 || got[1].ID != m.Threads[0].Entries[1].ID {
+		t.Errorf("reloaded entries %+v, want the two saved", got)
+	}
+}
+
+func TestSaveRemovesClearedEntryFiles(t *testing.T) {
+	dir := t.TempDir()
+	entries := tentEntries()
+	AssignEntryIDs("a1", "name", entries)
+	m := NewManifest(types.FormRef{Title: "Tents"}, "tent", []string{"camping"})
+	m.Threads = []types.ThreadState{{PostID: "a1", Status: types.StatusExtracted, Entries: entries}}
+	if err := SaveManifest(dir, m); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, entriesPath("a1"))
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("entry file not written: %v", err)
+	}
+
+	m.Threads[0].Entries = nil
+	if err := SaveManifest(dir, m); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("entry file of a cleared thread still there (%v)", err)
+	}
+
+	// The same entries again must be written, not taken as unchanged
+	m.Threads[0].Entries = entries
+	if err := SaveManifest(dir, m); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatalf("reloading after restoring entries: %v", err)
+	}
+	if n := len(reloaded.Threads[0].Entries); n != 2 {
+		t.Errorf("reloaded %d entries, want 2", n)
+	}
+}
@@ -0,0 +1,95 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"hiveminer/pkg/types"
+)
+
+// EntryID derives an entry's ID from its thread and its primary field value
+// ("abc123-5f2e9c1a"), so re-extracting a thread gives the same item the same
+// ID however the entries are ordered. Without a primary value, the entry's
+// other field values are used.
+func EntryID(postID, primaryID string, entry types.Entry) string {
+	key := ""
+	for _, fv := range entry.Fields {
+		if fv.Value != nil && (fv.ID == primaryID || primaryID == "") {
+			key = normalizeEntryKey(fmt.Sprint(fv.Value))
+			break
+		}
+	}
+	if key == "" {
+		var parts []string
+		for _, fv := range entry.Fields {
+			if fv.Value != nil {
+				parts = append(parts, fv.ID+"="+normalizeEntryKey(fmt.Sprint(fv.Value)))
+			}
+		}
+		key = strings.Join(parts, "|")
+	}
+	sum := sha256.Sum256([]byte(key))
+	return postID + "-" + hex.EncodeToString(sum[:4])
+}
+
+// AssignEntryIDs gives each of a thread's entries without an ID its derived
+// ID. Entries naming the same item get a numbered suffix (-2, -3) in order.
+// With no primaryID, the first field with a value stands in for it.
+func AssignEntryIDs(postID, primaryID string, entries []types.Entry) {
+	used := map[string]bool{}
+	for _, e := range entries {
+		if e.ID != "" {
+			used[e.ID] = true
+		}
+	}
+	for i := range entries {
+		if entries[i].ID != "" {
+			continue
+		}
+		base := EntryID(postID, primaryID, entries[i])
+		id := base
+		for n := 2; used[id]; n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+		entries[i].ID = id
+		used[id] = true
+	}
+}
+
+// SettleEntryIDs rederives the provisional entry IDs given on load from the
+// form's primary field, as extraction would have
+func SettleEntryIDs(manifest *types.Manifest, primaryID string) {
+	for i := range manifest.Threads {
+		t := &manifest.Threads[i]
+		if !t.ProvisionalEntryIDs {
+			continue
+		}
+		for j := range t.Entries {
+			t.Entries[j].ID = ""
+		}
+		AssignEntryIDs(t.PostID, primaryID, t.Entries)
+		t.ProvisionalEntryIDs = false
+	}
+}
+
+// FindEntry returns the index of the entry with the given ID in a thread, or
+// -1 if it has none
+func FindEntry(thread *types.ThreadState, id string) int {
+	for i := range thread.Entries {
+		if thread.Entries[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeEntryKey lowercases a value and reduces it to words, so case and
+// punctuation differences don't change an ID
+func normalizeEntryKey(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
@@ -0,0 +1,95 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"hiveminer/pkg/types"
+)
+
+// tentEntries are entries whose first field isn't the form's primary one
+func tentEntries() []types.Entry {
+	return []types.Entry{
+		{Fields: []types.FieldValue{{ID: "price", Value: "$300"}, {ID: "name", Value: "Duplex"}}},
+		{Fields: []types.FieldValue{{ID: "price", Value: "$300"}, {ID: "name", Value: "Stratospire"}}},
+	}
+}
+
+// writeV1Manifest writes a manifest from before entry files and stable IDs
+func writeV1Manifest(t *testing.T, dir string) {
+	t.Helper()
+	m := types.Manifest{
+		Version: 1,
+		Threads: []types.ThreadState{{PostID: "a1", Status: types.StatusExtracted, Entries: tentEntries()}},
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, manifestFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackfilledEntryIDsSettleToExtractionIDs(t *testing.T) {
+	dir := t.TempDir()
+	writeV1Manifest(t, dir)
+
+	m, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	th := &m.Threads[0]
+	if !th.ProvisionalEntryIDs || th.Entries[0].ID == "" || th.Entries[0].ID == th.Entries[1].ID {
+		t.Fatalf("loaded entries %q and %q (provisional %v), want distinct provisional IDs", th.Entries[0].ID, th.Entries[1].ID, th.ProvisionalEntryIDs)
+	}
+
+	// A save before the form is known keeps the IDs provisional
+	if err := SaveManifest(dir, m); err != nil {
+		t.Fatal(err)
+	}
+	if m, err = LoadManifest(dir); err != nil {
+		t.Fatal(err)
+	}
+	th = &m.Threads[0]
+	if !th.ProvisionalEntryIDs {
+		t.Fatal("provisional mark lost by saving")
+	}
+
+	extracted := tentEntries()
+	AssignEntryIDs("a1", "name", extracted)
+	SettleEntryIDs(m, "name")
+	for i := range extracted {
+		if th.Entries[i].ID != extracted[i].ID {
+			t.Errorf("entry %d: settled ID %q, want extraction's %q", i, th.Entries[i].ID, extracted[i].ID)
+		}
+	}
+	if th.ProvisionalEntryIDs {
+		t.Error("thread still marked provisional after settling")
+	}
+
+	// Settled IDs stay put
+	SettleEntryIDs(m, "price")
+	if th.Entries[0].ID != extracted[0].ID {
+		t.Errorf("settled ID changed to %q", th.Entries[0].ID)
+	}
+}
+
+func TestReextractionClearsProvisionalIDs(t *testing.T) {
+	dir := t.TempDir()
+	writeV1Manifest(t, dir)
+	m, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := tentEntries()[:1]
+	AssignEntryIDs("a1", "name", entries)
+	UpdateThreadEntries(m, "a1", entries)
+	SettleEntryIDs(m, "price")
+	if got := m.Threads[0].Entries[0].ID; got != entries[0].ID {
+		t.Errorf("re-extracted entry's ID changed to %q by settling, want %q", got, entries[0].ID)
+	}
+}
@@ -0,0 +1,216 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"hiveminer/pkg/types"
+)
+
+// Output directory layouts. Layout 1 keeps every session directly in the
+// output directory; layout 2 keeps each form's sessions in a folder named
+// by the form's slug, beside an index of them.
+const (
+	LayoutFlat    = 1
+	LayoutPerForm = 2
+
+	layoutFile    = ".layout.json"
+	formIndexFile = "index.json"
+)
+
+// layoutMarker is the layout file recording an output directory's layout
+type layoutMarker struct {
+	Version int `json:"version"`
+}
+
+// LayoutVersion returns an output directory's layout. Directories without a
+// layout file are flat if they hold any sessions, else new.
+func LayoutVersion(outputDir string) int {
+	data, err := os.ReadFile(filepath.Join(outputDir, layoutFile))
+	if err == nil {
+		var marker layoutMarker
+		if json.Unmarshal(data, &marker) == nil && marker.Version > 0 {
+			return marker.Version
+		}
+	}
+	if len(FlatSessionDirs(outputDir)) > 0 {
+		return LayoutFlat
+	}
+	return LayoutPerForm
+}
+
+// SetLayoutVersion records an output directory's layout
+func SetLayoutVersion(outputDir string, version int) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+	data, err := json.Marshal(layoutMarker{Version: version})
+	if err != nil {
+		return fmt.Errorf("marshaling layout: %w", err)
+	}
+	if err := WriteFileAtomic(filepath.Join(outputDir, layoutFile), data, 0644); err != nil {
+		return fmt.Errorf("writing layout: %w", err)
+	}
+	return nil
+}
+
+// NewSessionParent returns the directory a new session of a form goes in:
+// the form's folder, or the output directory itself in a flat one. A new
+// output directory is recorded as per-form.
+func NewSessionParent(outputDir, formTitle string) (string, error) {
+	if LayoutVersion(outputDir) == LayoutFlat {
+		return outputDir, nil
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, layoutFile)); os.IsNotExist(err) {
+		if err := SetLayoutVersion(outputDir, LayoutPerForm); err != nil {
+			return "", err
+		}
+	}
+	return FormDir(outputDir, formTitle), nil
+}
+
+// FormDir returns a form's folder in a per-form output directory
+func FormDir(outputDir, formTitle string) string {
+	return filepath.Join(outputDir, FormSlug(formTitle))
+}
+
+// SessionDirs returns every session directory in an output directory, in
+// either layout
+func SessionDirs(outputDir string) ([]string, error) {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading output directory: %w", err)
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(outputDir, entry.Name())
+		if isSessionDir(dir) {
+			dirs = append(dirs, dir)
+			continue
+		}
+		dirs = append(dirs, formSessionDirs(dir)...)
+	}
+	return dirs, nil
+}
+
+// FindSession returns the directory of the session with this name in an
+// output directory, or "" if there's none
+func FindSession(outputDir, name string) string {
+	dirs, _ := SessionDirs(outputDir)
+	for _, dir := range dirs {
+		if filepath.Base(dir) == name {
+			return dir
+		}
+	}
+	return ""
+}
+
+// FlatSessionDirs returns the sessions kept directly in an output directory
+// rather than in their forms' folders
+func FlatSessionDirs(outputDir string) []string {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, entry := range entries {
+		dir := filepath.Join(outputDir, entry.Name())
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && isSessionDir(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// formSessionDirs returns the sessions in a form folder
+func formSessionDirs(formDir string) []string {
+	entries, err := os.ReadDir(formDir)
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, entry := range entries {
+		dir := filepath.Join(formDir, entry.Name())
+		if entry.IsDir() && isSessionDir(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+func isSessionDir(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, manifestFile))
+	return err == nil
+}
+
+// RefreshFormIndex rewrites the index of the form folder holding a session,
+// after the session is created, run, renamed or removed. Sessions in a flat
+// output directory have no index.
+func RefreshFormIndex(sessionDir string) error {
+	formDir := filepath.Dir(sessionDir)
+	if _, err := os.Stat(filepath.Join(filepath.Dir(formDir), layoutFile)); err != nil {
+		return nil
+	}
+	return UpdateFormIndex(formDir)
+}
+
+// UpdateFormIndex rewrites a form folder's index from its sessions'
+// manifests, removing the folder once it holds no sessions
+func UpdateFormIndex(formDir string) error {
+	index := types.FormIndex{Sessions: []types.FormIndexEntry{}}
+	for _, dir := range formSessionDirs(formDir) {
+		manifest, err := LoadManifest(dir)
+		if err != nil || manifest == nil {
+			continue
+		}
+		index.Form = manifest.Form.Title
+		entry := types.FormIndexEntry{
+			Name:      filepath.Base(dir),
+			Query:     manifest.Query,
+			Threads:   len(manifest.Threads),
+			CreatedAt: manifest.CreatedAt,
+			UpdatedAt: manifest.UpdatedAt,
+		}
+		if n := len(manifest.Runs); n > 0 {
+			entry.Status = manifest.Runs[n-1].Status
+		}
+		for _, t := range manifest.Threads {
+			if types.HasEntries(t.Status) {
+				entry.Entries += len(t.Entries)
+			}
+		}
+		index.Sessions = append(index.Sessions, entry)
+	}
+
+	path := filepath.Join(formDir, formIndexFile)
+	if len(index.Sessions) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing form index: %w", err)
+		}
+		os.Remove(formDir) // only if nothing else is in it
+		return nil
+	}
+	sort.Slice(index.Sessions, func(i, j int) bool {
+		return index.Sessions[i].CreatedAt.After(index.Sessions[j].CreatedAt)
+	})
+	index.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling form index: %w", err)
+	}
+	if err := WriteFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("writing form index: %w", err)
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"hiveminer/pkg/types"
@@ -12,11 +14,14 @@ import (
 
 const manifestFile = "manifest.json"
 
+// manifestVersion 2 keeps thread entries in entry files rather than inline
+const manifestVersion = 2
+
 // NewManifest creates a new empty manifest
 func NewManifest(formRef types.FormRef, query string, subreddits []string) *types.Manifest {
 	now := time.Now()
 	return &types.Manifest{
-		Version:    1,
+		Version:    manifestVersion,
 		Form:       formRef,
 		Query:      query,
 		Subreddits: subreddits,
@@ -29,7 +34,11 @@ func NewManifest(formRef types.FormRef, query string, subreddits []string) *type
 
 // LoadManifest loads a manifest from a session directory
 func LoadManifest(dir string) (*types.Manifest, error) {
-	path := filepath.Join(dir, manifestFile)
+	return loadManifestFile(dir, filepath.Join(dir, manifestFile))
+}
+
+// loadManifestFile loads a session's manifest, or a backup of it, from path
+func loadManifestFile(dir, path string) (*types.Manifest, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -42,10 +51,39 @@ func LoadManifest(dir string) (*types.Manifest, error) {
 	if err := json.Unmarshal(data, &manifest); err != nil {
 		return nil, fmt.Errorf("parsing manifest: %w", err)
 	}
+	if err := loadEntries(dir, &manifest); err != nil {
+		return nil, err
+	}
+	backfillStatuses(&manifest)
+	// Entries from before stable IDs get provisional ones here, from the
+	// first field with a value, until SettleEntryIDs has the form
+	for i := range manifest.Threads {
+		t := &manifest.Threads[i]
+		if slices.ContainsFunc(t.Entries, func(e types.Entry) bool { return e.ID == "" }) {
+			AssignEntryIDs(t.PostID, "", t.Entries)
+			t.ProvisionalEntryIDs = true
+		}
+	}
 
 	return &manifest, nil
 }
 
+// backfillStatuses restores the ranked status of threads whose ranking was
+// recorded by a version that didn't know it, so they aren't ranked again or
+// miscounted
+func backfillStatuses(manifest *types.Manifest) {
+	for i := range manifest.Threads {
+		t := &manifest.Threads[i]
+		if t.Status != types.StatusExtracted || t.RankedAt == nil {
+			continue
+		}
+		// A thread re-extracted since it was ranked still needs ranking
+		if t.ExtractedAt == nil || !t.RankedAt.Before(*t.ExtractedAt) {
+			t.Status = types.StatusRanked
+		}
+	}
+}
+
 // SaveManifest saves a manifest to a session directory
 func SaveManifest(dir string, manifest *types.Manifest) error {
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -53,24 +91,27 @@ func SaveManifest(dir string, manifest *types.Manifest) error {
 	}
 
 	manifest.UpdatedAt = time.Now()
+	manifest.Version = manifestVersion
 
-	data, err := json.MarshalIndent(manifest, "", "  ")
+	// Entries go to their files first, so the manifest never refers to one
+	// that isn't written yet; files of cleared threads go once it doesn't
+	// refer to them
+	stored := *manifest
+	threads, stale, err := saveEntries(dir, manifest.Threads)
 	if err != nil {
-		return fmt.Errorf("marshaling manifest: %w", err)
+		return err
 	}
+	stored.Threads = threads
 
-	path := filepath.Join(dir, manifestFile)
-	tmpPath := path + ".tmp"
-
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		return fmt.Errorf("writing manifest: %w", err)
+	data, err := json.MarshalIndent(&stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling manifest: %w", err)
 	}
 
-	if err := os.Rename(tmpPath, path); err != nil {
-		return fmt.Errorf("renaming manifest: %w", err)
+	if err := WriteFileAtomic(filepath.Join(dir, manifestFile), data, 0644); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
 	}
-
-	return nil
+	return removeEntryFiles(stale)
 }
 
 // FindThread finds a thread by post ID in the manifest
@@ -99,6 +140,46 @@ func AddThread(manifest *types.Manifest, thread types.ThreadState) {
 	manifest.UpdatedAt = time.Now()
 }
 
+// PinThread adds a thread to the session watch list, registering it as pending if
+// it isn't in the manifest yet. Pinned threads that were skipped or failed are
+// reset to pending so the next run picks them up.
+func PinThread(manifest *types.Manifest, thread types.ThreadState) {
+	if existing := FindThread(manifest, thread.PostID); existing != nil {
+		existing.Pinned = true
+		if existing.Status == types.StatusSkipped || existing.Status == types.StatusFailed {
+			existing.Status = types.StatusPending
+			existing.Error = ""
+			existing.ErrorCode = ""
+		}
+		manifest.UpdatedAt = time.Now()
+		return
+	}
+	thread.Pinned = true
+	thread.Status = types.StatusPending
+	AddThread(manifest, thread)
+}
+
+// UnpinThread removes a thread from the session watch list
+func UnpinThread(manifest *types.Manifest, postID string) bool {
+	if existing := FindThread(manifest, postID); existing != nil && existing.Pinned {
+		existing.Pinned = false
+		manifest.UpdatedAt = time.Now()
+		return true
+	}
+	return false
+}
+
+// GetPinnedThreads returns threads on the session watch list
+func GetPinnedThreads(manifest *types.Manifest) []types.ThreadState {
+	var pinned []types.ThreadState
+	for _, t := range manifest.Threads {
+		if t.Pinned {
+			pinned = append(pinned, t)
+		}
+	}
+	return pinned
+}
+
 // UpdateThreadStatus updates the status of a thread
 func UpdateThreadStatus(manifest *types.Manifest, postID, status string) bool {
 	for i := range manifest.Threads {
@@ -117,7 +198,8 @@ func UpdateThreadEntries(manifest *types.Manifest, postID string, entries []type
 		if manifest.Threads[i].PostID == postID {
 			now := time.Now()
 			manifest.Threads[i].Entries = entries
-			manifest.Threads[i].Status = "extracted"
+			manifest.Threads[i].ProvisionalEntryIDs = false
+			manifest.Threads[i].Status = types.StatusExtracted
 			manifest.Threads[i].ExtractedAt = &now
 			manifest.UpdatedAt = now
 			return true
@@ -126,15 +208,12 @@ func UpdateThreadEntries(manifest *types.Manifest, postID string, entries []type
 	return false
 }
 
-// CountByStatus counts threads by status
+// CountByStatus counts threads by status. Every known status is present;
+// statuses this version doesn't know are counted under their own name.
 func CountByStatus(manifest *types.Manifest) map[string]int {
-	counts := map[string]int{
-		"pending":   0,
-		"collected": 0,
-		"extracted": 0,
-		"ranked":    0,
-		"failed":    0,
-		"skipped":   0,
+	counts := make(map[string]int, len(types.ThreadStatuses))
+	for _, status := range types.ThreadStatuses {
+		counts[status] = 0
 	}
 	for _, t := range manifest.Threads {
 		counts[t.Status]++
@@ -142,11 +221,55 @@ func CountByStatus(manifest *types.Manifest) map[string]int {
 	return counts
 }
 
+// UnknownStatuses counts threads whose status this version doesn't know,
+// such as ones written by a newer version. The pipeline leaves them alone.
+func UnknownStatuses(manifest *types.Manifest) map[string]int {
+	unknown := map[string]int{}
+	for _, t := range manifest.Threads {
+		if !types.KnownStatus(t.Status) {
+			unknown[t.Status]++
+		}
+	}
+	return unknown
+}
+
+// FormatUnknownStatuses lists unknown statuses and their thread counts
+// (`3 "evaluated", 1 ""`), sorted by status
+func FormatUnknownStatuses(unknown map[string]int) string {
+	statuses := make([]string, 0, len(unknown))
+	for status := range unknown {
+		statuses = append(statuses, status)
+	}
+	slices.Sort(statuses)
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = fmt.Sprintf("%d %q", unknown[status], status)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CountFailures counts failed threads by error code; threads that failed
+// before codes were recorded count as unknown
+func CountFailures(manifest *types.Manifest) map[string]int {
+	counts := map[string]int{}
+	for _, t := range manifest.Threads {
+		if t.Status != types.StatusFailed {
+			continue
+		}
+		code := t.ErrorCode
+		if code == "" {
+			code = types.ErrUnknown
+		}
+		counts[code]++
+	}
+	return counts
+}
+
 // GetPendingThreads returns threads that haven't been collected yet
 func GetPendingThreads(manifest *types.Manifest) []types.ThreadState {
 	var pending []types.ThreadState
 	for _, t := range manifest.Threads {
-		if t.Status == "pending" {
+		if t.Status == types.StatusPending {
 			pending = append(pending, t)
 		}
 	}
@@ -157,7 +280,7 @@ func GetPendingThreads(manifest *types.Manifest) []types.ThreadState {
 func GetCollectedThreads(manifest *types.Manifest) []types.ThreadState {
 	var collected []types.ThreadState
 	for _, t := range manifest.Threads {
-		if t.Status == "collected" {
+		if t.Status == types.StatusCollected {
 			collected = append(collected, t)
 		}
 	}
@@ -168,7 +291,7 @@ func GetCollectedThreads(manifest *types.Manifest) []types.ThreadState {
 func GetExtractedThreads(manifest *types.Manifest) []types.ThreadState {
 	var extracted []types.ThreadState
 	for _, t := range manifest.Threads {
-		if t.Status == "extracted" {
+		if t.Status == types.StatusExtracted {
 			extracted = append(extracted, t)
 		}
 	}
@@ -180,7 +303,7 @@ func UpdateThreadRanked(manifest *types.Manifest, postID string) bool {
 	for i := range manifest.Threads {
 		if manifest.Threads[i].PostID == postID {
 			now := time.Now()
-			manifest.Threads[i].Status = "ranked"
+			manifest.Threads[i].Status = types.StatusRanked
 			manifest.Threads[i].RankedAt = &now
 			manifest.UpdatedAt = now
 			return true
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"hiveminer/pkg/types"
+)
+
+const skipListDir = ".skiplists"
+
+// skipListPath returns the per-form skip list path under an output directory
+func skipListPath(outputDir, formTitle string) string {
+	return filepath.Join(outputDir, skipListDir, FormSlug(formTitle)+".json")
+}
+
+// LoadSkipList loads the skip list for a form, returning an empty list if none exists
+func LoadSkipList(outputDir, formTitle string) (*types.SkipList, error) {
+	list := &types.SkipList{Form: formTitle, Threads: map[string]types.SkippedThread{}}
+
+	data, err := os.ReadFile(skipListPath(outputDir, formTitle))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return list, nil
+		}
+		return nil, fmt.Errorf("reading skip list: %w", err)
+	}
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, fmt.Errorf("parsing skip list: %w", err)
+	}
+	if list.Threads == nil {
+		list.Threads = map[string]types.SkippedThread{}
+	}
+	return list, nil
+}
+
+// SaveSkipList saves a form's skip list under an output directory
+func SaveSkipList(outputDir string, list *types.SkipList) error {
+	path := skipListPath(outputDir, list.Form)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating skip list directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling skip list: %w", err)
+	}
+
+	if err := WriteFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("writing skip list: %w", err)
+	}
+	return nil
+}
+
+// AddSkip records a rejected thread in a skip list
+func AddSkip(list *types.SkipList, thread types.ThreadState, reason, by string) {
+	list.Threads[thread.PostID] = types.SkippedThread{
+		PostID:    thread.PostID,
+		Permalink: thread.Permalink,
+		Title:     thread.Title,
+		Reason:    reason,
+		By:        by,
+		SkippedAt: time.Now(),
+	}
+}
+
+// IsSkipped reports whether a post is on a skip list
+func IsSkipped(list *types.SkipList, postID string) bool {
+	if list == nil {
+		return false
+	}
+	_, ok := list.Threads[postID]
+	return ok
+}
@@ -0,0 +1,182 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"hiveminer/pkg/types"
+)
+
+// Reasons whose significant words overlap by at least skipReasonSimilarity
+// (Jaccard) share a group; a group's label is cut to skipReasonChars
+const (
+	skipReasonSimilarity = 0.34
+	skipReasonChars      = 60
+)
+
+// skipReasonStopwords are left out when comparing reasons: evaluators phrase
+// the same verdict with different filler
+var skipReasonStopwords = map[string]bool{
+	"a": true, "about": true, "an": true, "and": true, "any": true, "are": true, "as": true,
+	"at": true, "be": true, "but": true, "by": true, "does": true, "doesn't": true, "for": true,
+	"from": true, "has": true, "have": true, "in": true, "is": true, "it": true, "its": true,
+	"it's": true, "mostly": true, "no": true, "not": true, "of": true, "on": true, "only": true,
+	"or": true, "post": true, "rather": true, "than": true, "that": true, "the": true, "there": true,
+	"this": true, "thread": true, "to": true, "was": true, "which": true, "with": true,
+}
+
+// skipReasonFiller are openings cut from group labels
+var skipReasonFiller = []string{"this thread is ", "the thread is ", "thread is ", "this post is ", "the post is ", "post is ", "this is ", "it is ", "it's "}
+
+// SkipReasons groups the reasons a session's threads were skipped, largest
+// group first. Threads skipped before reasons were kept on them fall back
+// to the form's skip list, which may be nil.
+func SkipReasons(m *types.Manifest, list *types.SkipList) []types.SkipReasonGroup {
+	var reasons []string
+	for _, t := range m.Threads {
+		if t.Status != types.StatusSkipped {
+			continue
+		}
+		reason := t.SkipReason
+		if reason == "" && list != nil {
+			reason = list.Threads[t.PostID].Reason
+		}
+		reasons = append(reasons, reason)
+	}
+	return GroupSkipReasons(reasons)
+}
+
+// GroupSkipReasons clusters similar reasons by their significant words.
+// Empty reasons are counted as "no reason given".
+func GroupSkipReasons(reasons []string) []types.SkipReasonGroup {
+	type cluster struct {
+		seed    map[string]bool
+		members []string
+		words   []map[string]bool
+	}
+	var clusters []*cluster
+	unexplained := 0
+	for _, reason := range reasons {
+		reason = strings.TrimSpace(reason)
+		if reason == "" {
+			unexplained++
+			continue
+		}
+		words := reasonWords(reason)
+		var best *cluster
+		bestSim := skipReasonSimilarity
+		for _, c := range clusters {
+			if sim := jaccard(words, c.seed); sim >= bestSim {
+				best, bestSim = c, sim
+			}
+		}
+		if best == nil {
+			best = &cluster{seed: words}
+			clusters = append(clusters, best)
+		}
+		best.members = append(best.members, reason)
+		best.words = append(best.words, words)
+	}
+
+	groups := make([]types.SkipReasonGroup, 0, len(clusters)+1)
+	for _, c := range clusters {
+		// Label the group with the reason closest to all the others
+		label, bestScore := c.members[0], -1.0
+		for i, words := range c.words {
+			score := 0.0
+			for j, other := range c.words {
+				if i != j {
+					score += jaccard(words, other)
+				}
+			}
+			if score > bestScore || (score == bestScore && len(c.members[i]) < len(label)) {
+				label, bestScore = c.members[i], score
+			}
+		}
+		groups = append(groups, types.SkipReasonGroup{Reason: skipReasonLabel(label), Threads: len(c.members)})
+	}
+	if unexplained > 0 {
+		groups = append(groups, types.SkipReasonGroup{Reason: "no reason given", Threads: unexplained})
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Threads > groups[j].Threads
+	})
+	return groups
+}
+
+// FormatSkipReasons describes the largest groups as "12 skipped: listicle
+// posts", folding the rest into a last line
+func FormatSkipReasons(groups []types.SkipReasonGroup, max int) []string {
+	var lines []string
+	for i, g := range groups {
+		if max > 0 && i == max && len(groups) > max+1 {
+			rest := 0
+			for _, g := range groups[i:] {
+				rest += g.Threads
+			}
+			lines = append(lines, fmt.Sprintf("%d skipped for %d other reasons", rest, len(groups)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%d skipped: %s", g.Threads, g.Reason))
+	}
+	return lines
+}
+
+// reasonWords returns the significant words of a reason, lowercased and
+// without plural endings
+func reasonWords(reason string) map[string]bool {
+	words := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(reason), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}) {
+		w = strings.Trim(w, "'")
+		if len(w) < 2 || skipReasonStopwords[w] {
+			continue
+		}
+		if len(w) > 4 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			w = strings.TrimSuffix(w, "s")
+		}
+		words[w] = true
+	}
+	return words
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// skipReasonLabel keeps a reason's first clause, shortened for one line
+func skipReasonLabel(reason string) string {
+	for _, filler := range skipReasonFiller {
+		if len(reason) > len(filler) && strings.EqualFold(reason[:len(filler)], filler) {
+			reason = reason[len(filler):]
+			break
+		}
+	}
+	if i := strings.IndexAny(reason, ".,;:("); i > 0 {
+		reason = reason[:i]
+	}
+	if i := strings.Index(reason, " — "); i > 0 {
+		reason = reason[:i]
+	}
+	reason = strings.TrimSpace(reason)
+	if r := []rune(reason); len(r) > skipReasonChars {
+		reason = strings.TrimSpace(string(r[:skipReasonChars-1])) + "…"
+	}
+	// Lowercase a capitalized first word, not an acronym
+	if r := []rune(reason); len(r) > 1 && unicode.IsUpper(r[0]) && !unicode.IsUpper(r[1]) {
+		reason = string(unicode.ToLower(r[0])) + string(r[1:])
+	}
+	return reason
+}
@@ -44,3 +44,18 @@ func GenerateSlugFromQuery(query string) string {
 
 	return slug + "-" + timestamp
 }
+
+// NameSlug returns the directory name for a session the user named, without
+// a timestamp, or "" if the name has no letters or digits
+func NameSlug(name string) string {
+	return strings.Trim(nonAlphaNum.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// FormSlug returns a stable, timestamp-free slug for per-form files
+func FormSlug(formTitle string) string {
+	slug := strings.Trim(nonAlphaNum.ReplaceAllString(strings.ToLower(formTitle), "-"), "-")
+	if slug == "" {
+		slug = "untitled"
+	}
+	return slug
+}
@@ -0,0 +1,122 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"hiveminer/pkg/types"
+)
+
+// ErrThreadNotFound is returned when updating a thread the manifest doesn't have
+var ErrThreadNotFound = errors.New("thread not found")
+
+// Store guards a session's manifest for use from several goroutines. Reads
+// go through View and changes through Update or UpdateThread, each holding
+// the store's lock for the whole callback, so no phase or worker sees
+// another's half-made change. Changes mark the store dirty for SaveIfDirty.
+type Store struct {
+	mu       sync.Mutex
+	dir      string
+	manifest *types.Manifest
+	dirty    bool
+}
+
+// NewStore wraps a session's manifest. From then on the manifest should only
+// be used through the store while other goroutines may be using it.
+func NewStore(dir string, manifest *types.Manifest) *Store {
+	return &Store{dir: dir, manifest: manifest}
+}
+
+// View calls fn with the manifest locked. fn must not change the manifest or
+// keep references into it after returning.
+func (s *Store) View(fn func(m *types.Manifest)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.manifest)
+}
+
+// Update calls fn with the manifest locked and marks it changed. If fn
+// returns an error, the manifest's own fields, thread list and run log are
+// put back as they were; changes fn made inside a thread's entries are not.
+func (s *Store) Update(fn func(m *types.Manifest) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	saved := *s.manifest
+	saved.Threads = slices.Clone(s.manifest.Threads)
+	saved.Runs = slices.Clone(s.manifest.Runs)
+	if err := fn(s.manifest); err != nil {
+		*s.manifest = saved
+		return err
+	}
+	s.manifest.UpdatedAt = time.Now()
+	s.dirty = true
+	return nil
+}
+
+// UpdateThread calls fn with a copy of one thread's state and stores the copy
+// if fn returns nil
+func (s *Store) UpdateThread(postID string, fn func(t *types.ThreadState) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	idx := FindThreadIndex(s.manifest, postID)
+	if idx < 0 {
+		return fmt.Errorf("%w: %s", ErrThreadNotFound, postID)
+	}
+	t := s.manifest.Threads[idx]
+	if err := fn(&t); err != nil {
+		return err
+	}
+	s.manifest.Threads[idx] = t
+	s.manifest.UpdatedAt = time.Now()
+	s.dirty = true
+	return nil
+}
+
+// Thread returns a copy of a thread's state
+func (s *Store) Thread(postID string) (types.ThreadState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t := FindThread(s.manifest, postID); t != nil {
+		return *t, true
+	}
+	return types.ThreadState{}, false
+}
+
+// CountByStatus counts the manifest's threads by status
+func (s *Store) CountByStatus() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return CountByStatus(s.manifest)
+}
+
+// Save writes the manifest to the session directory
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save()
+}
+
+// SaveIfDirty writes the manifest if it changed since the last save, so a
+// periodic saver doesn't rewrite an idle session
+func (s *Store) SaveIfDirty() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dirty {
+		return nil
+	}
+	return s.save()
+}
+
+// save writes the manifest with the store locked; a failed save leaves the
+// store dirty so the next attempt retries it
+func (s *Store) save() error {
+	if err := SaveManifest(s.dir, s.manifest); err != nil {
+		s.dirty = true
+		return err
+	}
+	s.dirty = false
+	return nil
+}
@@ -0,0 +1,207 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"hiveminer/pkg/types"
+)
+
+const yieldDir = ".yields"
+
+// Subreddits with at least minYieldThreads evaluated threads are judged on
+// their yield; a subreddit whose threads are mostly skipped is unproductive
+const (
+	minYieldThreads         = 5
+	unproductiveSkipRate    = 0.8
+	unknownEntriesPerThread = 1.0 // assumed for subreddits without enough history
+)
+
+// yieldPath returns the per-form yield history path under an output directory
+func yieldPath(outputDir, formTitle string) string {
+	return filepath.Join(outputDir, yieldDir, FormSlug(formTitle)+".json")
+}
+
+// ComputeYield tallies each subreddit's evaluated threads, skips, entries and
+// rank scores from a manifest. Wiki, sidebar and user-history threads don't
+// count.
+func ComputeYield(manifest *types.Manifest) map[string]types.SubredditYield {
+	yields := map[string]types.SubredditYield{}
+	for _, t := range manifest.Threads {
+		if t.Source != "" || t.Subreddit == "" {
+			continue
+		}
+		switch t.Status {
+		case types.StatusCollected, types.StatusExtracted, types.StatusRanked, types.StatusSkipped:
+		default:
+			continue
+		}
+		key := strings.ToLower(t.Subreddit)
+		y := yields[key]
+		y.Threads++
+		if t.Status == types.StatusSkipped {
+			y.Skipped++
+		}
+		for _, e := range t.Entries {
+			if e.Overflow {
+				continue
+			}
+			y.Entries++
+			if e.RankScore != nil {
+				y.Scored++
+				y.ScoreSum += *e.RankScore
+			}
+		}
+		yields[key] = y
+	}
+	return yields
+}
+
+// MergeYields sums yields across sessions
+func MergeYields(sets ...map[string]types.SubredditYield) map[string]types.SubredditYield {
+	merged := map[string]types.SubredditYield{}
+	for _, set := range sets {
+		for sub, y := range set {
+			m := merged[sub]
+			m.Threads += y.Threads
+			m.Skipped += y.Skipped
+			m.Entries += y.Entries
+			m.Scored += y.Scored
+			m.ScoreSum += y.ScoreSum
+			merged[sub] = m
+		}
+	}
+	return merged
+}
+
+// LoadYieldHistory loads a form's yield history, returning an empty one if
+// none exists
+func LoadYieldHistory(outputDir, formTitle string) (*types.YieldHistory, error) {
+	history := &types.YieldHistory{Form: formTitle, Sessions: map[string]map[string]types.SubredditYield{}}
+
+	data, err := os.ReadFile(yieldPath(outputDir, formTitle))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, fmt.Errorf("reading yield history: %w", err)
+	}
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, fmt.Errorf("parsing yield history: %w", err)
+	}
+	if history.Sessions == nil {
+		history.Sessions = map[string]map[string]types.SubredditYield{}
+	}
+	return history, nil
+}
+
+// SaveYieldHistory saves a form's yield history under an output directory
+func SaveYieldHistory(outputDir string, history *types.YieldHistory) error {
+	path := yieldPath(outputDir, history.Form)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating yield history directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling yield history: %w", err)
+	}
+
+	if err := WriteFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("writing yield history: %w", err)
+	}
+	return nil
+}
+
+// PastYield merges the history of every session but one (the running one,
+// whose yield is counted from its manifest instead)
+func PastYield(history *types.YieldHistory, exceptSession string) map[string]types.SubredditYield {
+	var sets []map[string]types.SubredditYield
+	for name, set := range history.Sessions {
+		if name != exceptSession {
+			sets = append(sets, set)
+		}
+	}
+	return MergeYields(sets...)
+}
+
+// Unproductive reports whether enough of a subreddit's threads were evaluated
+// to judge it and nearly all were skipped
+func Unproductive(y types.SubredditYield) bool {
+	return y.Threads >= minYieldThreads && float64(y.Skipped) >= unproductiveSkipRate*float64(y.Threads)
+}
+
+// entriesPerThread is a subreddit's yield, or a neutral guess without
+// enough history
+func entriesPerThread(y types.SubredditYield) float64 {
+	if y.Threads < minYieldThreads {
+		return unknownEntriesPerThread
+	}
+	return float64(y.Entries) / float64(y.Threads)
+}
+
+// OrderSubreddits sorts subreddits by entries per evaluated thread, best
+// first. With prune, unproductive subreddits are dropped and returned
+// separately, as long as one subreddit remains.
+func OrderSubreddits(subs []string, yields map[string]types.SubredditYield, prune bool) (ordered, dropped []string) {
+	for _, sub := range subs {
+		if prune && Unproductive(yields[strings.ToLower(sub)]) {
+			dropped = append(dropped, sub)
+		} else {
+			ordered = append(ordered, sub)
+		}
+	}
+	if len(ordered) == 0 {
+		ordered, dropped = slices.Clone(subs), nil
+	}
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		ya, yb := entriesPerThread(yields[strings.ToLower(a)]), entriesPerThread(yields[strings.ToLower(b)])
+		switch {
+		case ya > yb:
+			return -1
+		case ya < yb:
+			return 1
+		}
+		return 0
+	})
+	return ordered, dropped
+}
+
+// FormatYield summarizes a subreddit's yield ("12 threads, 75% skipped, 1.3
+// entries per thread, avg score 62")
+func FormatYield(y types.SubredditYield) string {
+	if y.Threads == 0 {
+		return "no threads evaluated"
+	}
+	s := fmt.Sprintf("%d threads, %.0f%% skipped, %.1f entries per thread",
+		y.Threads, 100*float64(y.Skipped)/float64(y.Threads), float64(y.Entries)/float64(y.Threads))
+	if y.Scored > 0 {
+		s += fmt.Sprintf(", avg score %.0f", y.ScoreSum/float64(y.Scored))
+	}
+	return s
+}
+
+// YieldNotes describes yields for discovery prompts, best subreddits first
+func YieldNotes(yields map[string]types.SubredditYield) []string {
+	subs := make([]string, 0, len(yields))
+	for sub, y := range yields {
+		if y.Threads > 0 {
+			subs = append(subs, sub)
+		}
+	}
+	slices.Sort(subs)
+	subs, _ = OrderSubreddits(subs, yields, false)
+	notes := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		note := "r/" + sub + ": " + FormatYield(yields[sub])
+		if Unproductive(yields[sub]) {
+			note += " (unproductive)"
+		}
+		notes = append(notes, note)
+	}
+	return notes
+}
@@ -0,0 +1,205 @@
+// Package transcripts turns podcasts and videos into threads: a media URL's
+// captions, or a Whisper transcript of its audio, are cut into timestamped
+// segments that extraction reads as comments.
+package transcripts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"hiveminer/internal/ratelimit"
+	"hiveminer/pkg/types"
+)
+
+// segmentSeconds is roughly how much of a transcript goes in one comment
+const segmentSeconds = 60
+
+// maxDescription caps how much of a media description becomes post text
+const maxDescription = 4000
+
+// Segment is a stretch of transcript starting at Start seconds
+type Segment struct {
+	Start float64
+	Text  string
+}
+
+// Fetcher fetches a media URL's transcript as a thread
+type Fetcher interface {
+	Fetch(ctx context.Context, mediaURL string) (*types.Thread, error)
+}
+
+// Client fetches transcripts with yt-dlp, which handles YouTube and most
+// video and podcast hosts as well as direct audio links. Media without
+// captions is transcribed when the client has a transcriber.
+type Client struct {
+	transcriber Transcriber
+	client      *http.Client
+	limiter     *ratelimit.Limiter
+}
+
+// NewClient returns a client; transcriber may be nil to use captions only
+func NewClient(transcriber Transcriber) *Client {
+	c := &Client{transcriber: transcriber}
+	c.SetLimiter(ratelimit.NewLimiter(nil))
+	return c
+}
+
+// SetLimiter paces and counts the client's requests with a run's limiter:
+// caption downloads and yt-dlp runs as transcripts, Whisper API uploads as
+// openai
+func (c *Client) SetLimiter(l *ratelimit.Limiter) {
+	c.limiter = l
+	c.client = l.Client(ratelimit.SourceTranscripts, 60*time.Second)
+	if o, ok := c.transcriber.(*openAI); ok {
+		o.client = l.Client(ratelimit.SourceOpenAI, 10*time.Minute)
+	}
+}
+
+// Fetch fetches a media URL's captions, or transcribes its audio, and returns
+// the transcript as a thread with a comment per segment
+func (c *Client) Fetch(ctx context.Context, mediaURL string) (*types.Thread, error) {
+	info, err := c.probe(ctx, mediaURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []Segment
+	if track := info.captionTrack(); track != "" {
+		segments, err = c.captions(ctx, track)
+		if err != nil {
+			return nil, fmt.Errorf("fetching captions for %s: %w", mediaURL, err)
+		}
+	} else {
+		if c.transcriber == nil {
+			return nil, fmt.Errorf("%s has no captions; transcribe its audio with --transcribe", mediaURL)
+		}
+		segments, err = c.transcribe(ctx, mediaURL)
+		if err != nil {
+			return nil, fmt.Errorf("transcribing %s: %w", mediaURL, err)
+		}
+	}
+
+	thread := &types.Thread{Post: types.Post{
+		ID:        PostID(mediaURL),
+		Title:     "Transcript: " + info.Title,
+		Selftext:  truncate(info.Description, maxDescription),
+		Author:    info.Uploader,
+		Domain:    info.Extractor,
+		Permalink: mediaURL,
+		URL:       info.WebpageURL,
+		Created:   info.created(),
+		Source:    types.SourceTranscript,
+	}}
+	for _, seg := range groupSegments(segments, segmentSeconds) {
+		thread.Comments = append(thread.Comments, &types.Comment{
+			ID:        fmt.Sprintf("t%d", int(seg.Start)),
+			Body:      fmt.Sprintf("[%s] %s", Timestamp(seg.Start), seg.Text),
+			Author:    info.Uploader,
+			Created:   thread.Post.Created,
+			Permalink: timestampURL(info.WebpageURL, mediaURL, seg.Start),
+		})
+	}
+	if len(thread.Comments) == 0 {
+		return nil, fmt.Errorf("%s has an empty transcript", mediaURL)
+	}
+	thread.Post.NumComments = len(thread.Comments)
+	return thread, nil
+}
+
+var nonIDChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// PostID returns a stable, filename-safe post ID for a media URL
+func PostID(mediaURL string) string {
+	if id := youtubeID(mediaURL); id != "" {
+		return "transcript_yt_" + nonIDChars.ReplaceAllString(id, "_")
+	}
+	sum := sha256.Sum256([]byte(strings.TrimSpace(mediaURL)))
+	return "transcript_" + hex.EncodeToString(sum[:6])
+}
+
+// youtubeID returns the video ID of a YouTube URL, or ""
+func youtubeID(mediaURL string) string {
+	u, err := url.Parse(mediaURL)
+	if err != nil {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	switch {
+	case host == "youtu.be":
+		return strings.Trim(u.Path, "/")
+	case strings.HasSuffix(host, "youtube.com"):
+		if v := u.Query().Get("v"); v != "" {
+			return v
+		}
+		for _, prefix := range []string{"/shorts/", "/live/", "/embed/"} {
+			if id, ok := strings.CutPrefix(u.Path, prefix); ok {
+				return strings.Trim(id, "/")
+			}
+		}
+	}
+	return ""
+}
+
+// groupSegments merges consecutive segments into stretches of about size
+// seconds, breaking after a sentence ends where possible
+func groupSegments(segments []Segment, size float64) []Segment {
+	var groups []Segment
+	var cur *Segment
+	for _, s := range segments {
+		text := strings.TrimSpace(s.Text)
+		if text == "" {
+			continue
+		}
+		if cur == nil {
+			groups = append(groups, Segment{Start: s.Start, Text: text})
+			cur = &groups[len(groups)-1]
+			continue
+		}
+		cur.Text += " " + text
+		elapsed := s.Start - cur.Start
+		if elapsed >= size*1.5 || (elapsed >= size && strings.ContainsAny(text[len(text)-1:], ".?!")) {
+			cur = nil
+		}
+	}
+	return groups
+}
+
+// Timestamp formats seconds as m:ss or h:mm:ss
+func Timestamp(seconds float64) string {
+	s := int(seconds)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// timestampURL links to a moment in the media: YouTube's t parameter, or a
+// media fragment for other pages
+func timestampURL(pageURL, mediaURL string, seconds float64) string {
+	if pageURL == "" {
+		pageURL = mediaURL
+	}
+	if id := youtubeID(pageURL); id != "" {
+		return fmt.Sprintf("https://www.youtube.com/watch?v=%s&t=%ds", id, int(seconds))
+	}
+	return fmt.Sprintf("%s#t=%d", strings.SplitN(pageURL, "#", 2)[0], int(seconds))
+}
+
+// truncate cuts s to at most n bytes without splitting a character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,148 @@
+package transcripts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Transcriber turns an audio file into timestamped transcript segments
+type Transcriber interface {
+	Transcribe(ctx context.Context, audioPath string) ([]Segment, error)
+}
+
+// Transcribers lists the supported transcription services
+var Transcribers = []string{"openai", "local"}
+
+// openAIMaxBytes is the Whisper API's upload limit
+const openAIMaxBytes = 25 << 20
+
+// New returns a transcriber by name: "openai" uses OpenAI's Whisper API and
+// needs OPENAI_API_KEY; "local" runs the whisper command
+// (HIVEMINER_WHISPER_MODEL picks its model)
+func New(name string) (Transcriber, error) {
+	switch name {
+	case "openai":
+		key := os.Getenv("OPENAI_API_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY is not set")
+		}
+		return &openAI{key: key, client: &http.Client{Timeout: 10 * time.Minute}}, nil
+	case "local":
+		if _, err := exec.LookPath("whisper"); err != nil {
+			return nil, fmt.Errorf("the whisper command is not installed")
+		}
+		return &localWhisper{model: os.Getenv("HIVEMINER_WHISPER_MODEL")}, nil
+	default:
+		return nil, fmt.Errorf("unknown transcriber %q (use %s)", name, strings.Join(Transcribers, ", "))
+	}
+}
+
+// whisperOutput is the segment list both the API's verbose_json format and
+// the whisper command's JSON output have
+type whisperOutput struct {
+	Segments []struct {
+		Start float64 `json:"start"`
+		Text  string  `json:"text"`
+	} `json:"segments"`
+}
+
+func (w whisperOutput) segments() []Segment {
+	segments := make([]Segment, 0, len(w.Segments))
+	for _, s := range w.Segments {
+		segments = append(segments, Segment{Start: s.Start, Text: s.Text})
+	}
+	return segments
+}
+
+// openAI transcribes with OpenAI's Whisper API
+type openAI struct {
+	key    string
+	client *http.Client
+}
+
+func (o *openAI) Transcribe(ctx context.Context, audioPath string) ([]Segment, error) {
+	fi, err := os.Stat(audioPath)
+	if err != nil {
+		return nil, err
+	}
+	if fi.Size() > openAIMaxBytes {
+		return nil, fmt.Errorf("audio is %d MB, over the Whisper API's 25 MB limit; use --transcribe local", fi.Size()>>20)
+	}
+	f, err := os.Open(audioPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("model", "whisper-1")
+	mw.WriteField("response_format", "verbose_json")
+	part, err := mw.CreateFormFile("file", filepath.Base(audioPath))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return nil, fmt.Errorf("reading audio: %w", err)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/audio/transcriptions", &body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+o.key)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("transcription request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("transcription request: HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	var out whisperOutput
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("parsing transcription: %w", err)
+	}
+	return out.segments(), nil
+}
+
+// localWhisper transcribes with the whisper command (openai-whisper)
+type localWhisper struct {
+	model string
+}
+
+func (l *localWhisper) Transcribe(ctx context.Context, audioPath string) ([]Segment, error) {
+	dir := filepath.Dir(audioPath)
+	args := []string{audioPath, "--output_format", "json", "--output_dir", dir, "--verbose", "False"}
+	if l.model != "" {
+		args = append(args, "--model", l.model)
+	}
+	if out, err := exec.CommandContext(ctx, "whisper", args...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("whisper: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	name := strings.TrimSuffix(filepath.Base(audioPath), filepath.Ext(audioPath)) + ".json"
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("reading whisper output: %w", err)
+	}
+	var out whisperOutput
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parsing whisper output: %w", err)
+	}
+	return out.segments(), nil
+}
@@ -0,0 +1,210 @@
+package transcripts
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"hiveminer/internal/ratelimit"
+)
+
+// mediaInfo is the part of yt-dlp's metadata the client uses
+type mediaInfo struct {
+	ID                string                    `json:"id"`
+	Title             string                    `json:"title"`
+	Description       string                    `json:"description"`
+	Uploader          string                    `json:"uploader"`
+	UploadDate        string                    `json:"upload_date"` // YYYYMMDD
+	Timestamp         float64                   `json:"timestamp"`
+	WebpageURL        string                    `json:"webpage_url"`
+	Extractor         string                    `json:"extractor_key"`
+	Subtitles         map[string][]captionTrack `json:"subtitles"`
+	AutomaticCaptions map[string][]captionTrack `json:"automatic_captions"`
+}
+
+type captionTrack struct {
+	Ext string `json:"ext"`
+	URL string `json:"url"`
+}
+
+// probe reads a media URL's metadata with yt-dlp
+func (c *Client) probe(ctx context.Context, mediaURL string) (*mediaInfo, error) {
+	args, err := c.ytdlpArgs(ctx, "--dump-single-json", "--no-playlist", "--no-warnings", mediaURL)
+	if err != nil {
+		return nil, err
+	}
+	out, err := exec.CommandContext(ctx, "yt-dlp", args...).Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("yt-dlp %s: %s", mediaURL, strings.TrimSpace(string(ee.Stderr)))
+		}
+		return nil, fmt.Errorf("reading media needs the yt-dlp command: %w", err)
+	}
+	var info mediaInfo
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, fmt.Errorf("parsing yt-dlp metadata: %w", err)
+	}
+	if info.Title == "" {
+		info.Title = mediaURL
+	}
+	return &info, nil
+}
+
+// created returns when the media was published, in Unix seconds
+func (m *mediaInfo) created() float64 {
+	if m.Timestamp > 0 {
+		return m.Timestamp
+	}
+	if t, err := time.Parse("20060102", m.UploadDate); err == nil {
+		return float64(t.Unix())
+	}
+	return 0
+}
+
+// captionTrack picks the URL of the best WebVTT captions: English subtitles,
+// then English automatic captions, then subtitles or automatic captions in
+// the media's original language. Returns "" if there are none.
+func (m *mediaInfo) captionTrack() string {
+	pick := func(tracks map[string][]captionTrack, match func(lang string) bool) string {
+		langs := make([]string, 0, len(tracks))
+		for lang := range tracks {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+		for _, lang := range langs {
+			if !match(lang) {
+				continue
+			}
+			for _, t := range tracks[lang] {
+				if t.Ext == "vtt" {
+					return t.URL
+				}
+			}
+		}
+		return ""
+	}
+	english := func(lang string) bool { return lang == "en" || strings.HasPrefix(lang, "en-") }
+	if u := pick(m.Subtitles, english); u != "" {
+		return u
+	}
+	if u := pick(m.AutomaticCaptions, func(lang string) bool { return lang == "en-orig" || lang == "en" }); u != "" {
+		return u
+	}
+	if u := pick(m.Subtitles, func(string) bool { return true }); u != "" {
+		return u
+	}
+	return pick(m.AutomaticCaptions, func(lang string) bool { return strings.HasSuffix(lang, "-orig") })
+}
+
+// captions downloads and parses a WebVTT caption track
+func (c *Client) captions(ctx context.Context, trackURL string) ([]Segment, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", trackURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("caption request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("caption request: HTTP %d", resp.StatusCode)
+	}
+	return parseVTT(resp.Body)
+}
+
+var (
+	vttTiming = regexp.MustCompile(`^((?:\d+:)?\d{1,2}:\d{2}[.,]\d{3})\s+-->`)
+	vttTag    = regexp.MustCompile(`<[^>]*>`)
+)
+
+// parseVTT reads WebVTT cues. Automatic captions repeat each line as it
+// scrolls up, so a line equal to the one before it is dropped.
+func parseVTT(r io.Reader) ([]Segment, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	var segments []Segment
+	var start float64
+	inCue := false
+	last := ""
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if m := vttTiming.FindStringSubmatch(line); m != nil {
+			start = vttSeconds(m[1])
+			inCue = true
+			continue
+		}
+		if line == "" {
+			inCue = false
+			continue
+		}
+		if !inCue {
+			continue // header, cue identifiers, NOTE and STYLE blocks
+		}
+		text := strings.Join(strings.Fields(vttTag.ReplaceAllString(line, "")), " ")
+		if text == "" || text == last {
+			continue
+		}
+		last = text
+		segments = append(segments, Segment{Start: start, Text: text})
+	}
+	return segments, sc.Err()
+}
+
+// vttSeconds parses a cue timestamp: hh:mm:ss.ttt or mm:ss.ttt
+func vttSeconds(ts string) float64 {
+	parts := strings.Split(strings.Replace(ts, ",", ".", 1), ":")
+	total := 0.0
+	for _, p := range parts {
+		v, _ := strconv.ParseFloat(p, 64)
+		total = total*60 + v
+	}
+	return total
+}
+
+// transcribe downloads a media URL's audio with yt-dlp and transcribes it
+func (c *Client) transcribe(ctx context.Context, mediaURL string) ([]Segment, error) {
+	dir, err := os.MkdirTemp("", "hiveminer-audio-")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	args, err := c.ytdlpArgs(ctx, "--no-playlist", "--no-warnings", "--quiet",
+		"-f", "bestaudio/best", "-o", filepath.Join(dir, "audio.%(ext)s"), mediaURL)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, "yt-dlp", args...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("downloading audio: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "audio.*"))
+	if len(files) == 0 {
+		return nil, fmt.Errorf("downloading audio: yt-dlp wrote no file")
+	}
+	return c.transcriber.Transcribe(ctx, files[0])
+}
+
+// ytdlpArgs waits for the limiter before a yt-dlp run, which counts as one
+// request, and adds the policy's User-Agent to its arguments
+func (c *Client) ytdlpArgs(ctx context.Context, args ...string) ([]string, error) {
+	if err := c.limiter.Wait(ctx, ratelimit.SourceTranscripts); err != nil {
+		return nil, err
+	}
+	if ua := c.limiter.Policy(ratelimit.SourceTranscripts).UserAgent; ua != "" {
+		args = append([]string{"--user-agent", ua}, args...)
+	}
+	return args, nil
+}
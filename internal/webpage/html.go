@@ -0,0 +1,235 @@
+package webpage
+
+import (
+	"html"
+	"strings"
+)
+
+// node is an element or text node of a parsed page. The parser is forgiving
+// rather than standards-complete: it keeps what readability needs — the
+// element tree, attributes and text — and repairs bad nesting by closing the
+// nearest matching open element.
+type node struct {
+	tag      string // "" for text
+	attrs    map[string]string
+	text     string
+	parent   *node
+	children []*node
+}
+
+// voidTags never have children or end tags
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawTags hold text that isn't markup
+var rawTags = map[string]bool{"script": true, "style": true, "textarea": true, "title": true}
+
+// selfNesting elements are implicitly closed by a sibling of the same kind
+var selfNesting = map[string]bool{"p": true, "li": true, "dt": true, "dd": true, "tr": true, "td": true, "th": true, "option": true}
+
+// closesP are block elements that implicitly close an open paragraph
+var closesP = map[string]bool{
+	"div": true, "ul": true, "ol": true, "dl": true, "table": true, "section": true, "article": true, "blockquote": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "pre": true, "figure": true, "hr": true,
+}
+
+// parseHTML parses a page into a tree under a synthetic root
+func parseHTML(doc string) *node {
+	root := &node{tag: "#root"}
+	cur := root
+	for i := 0; i < len(doc); {
+		lt := strings.IndexByte(doc[i:], '<')
+		if lt < 0 {
+			cur.addText(doc[i:])
+			break
+		}
+		if lt > 0 {
+			cur.addText(doc[i : i+lt])
+		}
+		i += lt
+		switch {
+		case strings.HasPrefix(doc[i:], "<!--"):
+			end := strings.Index(doc[i:], "-->")
+			if end < 0 {
+				return root
+			}
+			i += end + 3
+		case strings.HasPrefix(doc[i:], "</"):
+			end := strings.IndexByte(doc[i:], '>')
+			if end < 0 {
+				return root
+			}
+			name := strings.ToLower(strings.TrimSpace(doc[i+2 : i+end]))
+			i += end + 1
+			for n := cur; n != root; n = n.parent {
+				if n.tag == name {
+					cur = n.parent
+					break
+				}
+			}
+		case strings.HasPrefix(doc[i:], "<!") || strings.HasPrefix(doc[i:], "<?"):
+			end := strings.IndexByte(doc[i:], '>')
+			if end < 0 {
+				return root
+			}
+			i += end + 1
+		default:
+			name, attrs, selfClosing, n := parseTag(doc[i:])
+			if n == 0 {
+				cur.addText("<")
+				i++
+				continue
+			}
+			i += n
+			if cur.tag == name && selfNesting[name] || cur.tag == "p" && closesP[name] {
+				cur = cur.parent // <li>a<li>b, <p>a<div>
+			}
+			el := &node{tag: name, attrs: attrs, parent: cur}
+			cur.children = append(cur.children, el)
+			if rawTags[name] {
+				end := strings.Index(strings.ToLower(doc[i:]), "</"+name)
+				if end < 0 {
+					end = len(doc) - i
+				}
+				el.addText(doc[i : i+end])
+				i += end
+				if gt := strings.IndexByte(doc[i:], '>'); gt >= 0 {
+					i += gt + 1
+				}
+				continue
+			}
+			if !voidTags[name] && !selfClosing {
+				cur = el
+			}
+		}
+	}
+	return root
+}
+
+// parseTag reads a start tag, returning its length, or 0 if s doesn't start
+// with one
+func parseTag(s string) (name string, attrs map[string]string, selfClosing bool, n int) {
+	i := 1
+	for i < len(s) && (isAlnum(s[i]) || s[i] == '-' || s[i] == ':') {
+		i++
+	}
+	if i == 1 {
+		return "", nil, false, 0
+	}
+	name = strings.ToLower(s[1:i])
+	attrs = map[string]string{}
+	for i < len(s) {
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		if s[i] == '>' {
+			return name, attrs, selfClosing, i + 1
+		}
+		if s[i] == '/' {
+			selfClosing = true
+			i++
+			continue
+		}
+		start := i
+		for i < len(s) && !isSpace(s[i]) && s[i] != '=' && s[i] != '>' && s[i] != '/' {
+			i++
+		}
+		key := strings.ToLower(s[start:i])
+		val := ""
+		if i < len(s) && s[i] == '=' {
+			i++
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				q := s[i]
+				end := strings.IndexByte(s[i+1:], q)
+				if end < 0 {
+					return "", nil, false, 0
+				}
+				val = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				start := i
+				for i < len(s) && !isSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				val = s[start:i]
+			}
+		}
+		if key != "" {
+			attrs[key] = html.UnescapeString(val)
+		} else {
+			i++
+		}
+	}
+	return "", nil, false, 0
+}
+
+func (n *node) addText(s string) {
+	if s == "" {
+		return
+	}
+	n.children = append(n.children, &node{text: html.UnescapeString(s), parent: n})
+}
+
+// textContent returns the node's text with whitespace collapsed
+func (n *node) textContent() string {
+	var b strings.Builder
+	var walk func(*node)
+	walk = func(n *node) {
+		if n.tag == "" {
+			b.WriteString(n.text)
+			b.WriteByte(' ')
+			return
+		}
+		if rawTags[n.tag] && n.tag != "title" {
+			return
+		}
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// find returns the elements under n, in document order, that match
+func (n *node) find(match func(*node) bool) []*node {
+	var found []*node
+	var walk func(*node)
+	walk = func(n *node) {
+		for _, c := range n.children {
+			if c.tag == "" {
+				continue
+			}
+			if match(c) {
+				found = append(found, c)
+			}
+			walk(c)
+		}
+	}
+	walk(n)
+	return found
+}
+
+func byTag(tags ...string) func(*node) bool {
+	return func(n *node) bool {
+		for _, t := range tags {
+			if n.tag == t {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
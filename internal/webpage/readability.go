@@ -0,0 +1,263 @@
+package webpage
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Article is the readable part of a page
+type Article struct {
+	Title     string
+	Byline    string
+	SiteName  string
+	Canonical string
+	Published time.Time
+	Text      string // the main content as Markdown-ish text: headings, lists, links
+}
+
+var (
+	unlikelyPattern = regexp.MustCompile(`(?i)comment|footer|sidebar|nav|menu|banner|share|social|related|promo|advert|sponsor|popup|cookie|newsletter|subscribe|widget|breadcrumb|masthead|disqus|pagination`)
+	likelyPattern   = regexp.MustCompile(`(?i)article|body|content|entry|main|post|text|blog|story|review`)
+	blankLines      = regexp.MustCompile(`\n{3,}`)
+)
+
+// dropTags never hold article content
+var dropTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "nav": true, "footer": true, "aside": true, "form": true,
+	"svg": true, "iframe": true, "button": true, "select": true, "header": true, "template": true,
+}
+
+// readable extracts a page's article the way reader modes do: boilerplate is
+// dropped, paragraphs score their ancestors by how much prose they hold, and
+// the best-scoring container, less its link-heavy parts, is the article
+func readable(doc string) *Article {
+	root := parseHTML(doc)
+	a := &Article{}
+	readMeta(root, a)
+
+	body := root
+	if b := root.find(byTag("body")); len(b) > 0 {
+		body = b[0]
+	}
+	prune(body)
+
+	scores := map[*node]float64{}
+	for _, p := range body.find(byTag("p", "pre", "td", "blockquote", "li")) {
+		text := p.textContent()
+		if len(text) < 25 {
+			continue
+		}
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+		if p.parent != nil {
+			addScore(scores, p.parent, score)
+			if p.parent.parent != nil {
+				addScore(scores, p.parent.parent, score/2)
+			}
+		}
+	}
+
+	var top *node
+	best := 0.0
+	for n, s := range scores {
+		s *= 1 - linkDensity(n)
+		scores[n] = s
+		if s > best || s == best && top != nil && len(n.textContent()) > len(top.textContent()) {
+			top, best = n, s
+		}
+	}
+	if top == nil || top.tag == "#root" {
+		top = body
+	}
+
+	// Siblings that score well are part of the article too: listicles often
+	// put each item in its own container
+	parts := []*node{top}
+	if top.parent != nil && top != body {
+		parts = nil
+		for _, sib := range top.parent.children {
+			if sib == top || scores[sib] >= math.Max(10, best*0.2) {
+				parts = append(parts, sib)
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, n := range parts {
+		render(&b, n)
+	}
+	lines := strings.Split(b.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	a.Text = strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+	return a
+}
+
+func addScore(scores map[*node]float64, n *node, score float64) {
+	if _, ok := scores[n]; !ok {
+		scores[n] = baseScore(n)
+	}
+	scores[n] += score
+}
+
+// baseScore weighs a candidate container by its tag and class names
+func baseScore(n *node) float64 {
+	s := 0.0
+	switch n.tag {
+	case "article", "main":
+		s += 10
+	case "div", "section":
+		s += 5
+	case "pre", "td", "blockquote":
+		s += 3
+	case "ol", "ul", "form", "li":
+		s -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		s -= 5
+	}
+	names := n.attrs["class"] + " " + n.attrs["id"]
+	if likelyPattern.MatchString(names) {
+		s += 25
+	}
+	if unlikelyPattern.MatchString(names) {
+		s -= 25
+	}
+	return s
+}
+
+// linkDensity is the share of a node's text that is link text
+func linkDensity(n *node) float64 {
+	total := len(n.textContent())
+	if total == 0 {
+		return 0
+	}
+	links := 0
+	for _, a := range n.find(byTag("a")) {
+		links += len(a.textContent())
+	}
+	return float64(links) / float64(total)
+}
+
+// prune removes boilerplate elements: navigation, scripts, forms, and
+// containers whose class or id mark them as unlikely to be content
+func prune(n *node) {
+	kept := n.children[:0]
+	for _, c := range n.children {
+		if c.tag != "" {
+			if dropTags[c.tag] || c.attrs["aria-hidden"] == "true" || c.attrs["role"] == "navigation" {
+				continue
+			}
+			names := c.attrs["class"] + " " + c.attrs["id"]
+			if c.tag != "body" && c.tag != "article" && c.tag != "main" &&
+				unlikelyPattern.MatchString(names) && !likelyPattern.MatchString(names) {
+				continue
+			}
+			prune(c)
+		}
+		kept = append(kept, c)
+	}
+	n.children = kept
+}
+
+// render writes a node as text, keeping headings, list items, paragraphs and
+// links
+func render(b *strings.Builder, n *node) {
+	if n.tag == "" {
+		b.WriteString(strings.Join(strings.Fields(n.text), " "))
+		if strings.HasSuffix(n.text, " ") || strings.HasSuffix(n.text, "\n") {
+			b.WriteByte(' ')
+		}
+		return
+	}
+	switch n.tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		if text := n.textContent(); text != "" {
+			fmt.Fprintf(b, "\n\n%s %s\n\n", strings.Repeat("#", int(n.tag[1]-'0')), text)
+		}
+		return
+	case "br":
+		b.WriteString("\n")
+		return
+	case "img":
+		return
+	case "a":
+		text := n.textContent()
+		href := n.attrs["href"]
+		if text != "" && strings.HasPrefix(href, "http") {
+			fmt.Fprintf(b, "[%s](%s) ", text, href)
+			return
+		}
+	case "li":
+		b.WriteString("\n- ")
+	case "p", "div", "section", "article", "main", "ul", "ol", "table", "blockquote", "pre", "figure", "dl":
+		b.WriteString("\n\n")
+		defer b.WriteString("\n\n")
+	case "tr":
+		b.WriteString("\n")
+	case "td", "th":
+		defer b.WriteString(" | ")
+	}
+	for _, c := range n.children {
+		render(b, c)
+	}
+}
+
+// readMeta fills in the article's title, byline, site name, canonical URL and
+// publication date from the page's metadata
+func readMeta(root *node, a *Article) {
+	meta := map[string]string{}
+	for _, m := range root.find(byTag("meta")) {
+		key := strings.ToLower(m.attrs["property"])
+		if key == "" {
+			key = strings.ToLower(m.attrs["name"])
+		}
+		if key != "" && meta[key] == "" {
+			meta[key] = strings.TrimSpace(m.attrs["content"])
+		}
+	}
+	a.Title = meta["og:title"]
+	if a.Title == "" {
+		if t := root.find(byTag("title")); len(t) > 0 {
+			a.Title = t[0].textContent()
+		}
+	}
+	if a.Title == "" {
+		if h := root.find(byTag("h1")); len(h) > 0 {
+			a.Title = h[0].textContent()
+		}
+	}
+	a.Byline = firstNonEmpty(meta["author"], meta["article:author"], meta["parsely-author"])
+	a.SiteName = meta["og:site_name"]
+	for _, l := range root.find(byTag("link")) {
+		if strings.EqualFold(l.attrs["rel"], "canonical") {
+			a.Canonical = l.attrs["href"]
+			break
+		}
+	}
+	published := firstNonEmpty(meta["article:published_time"], meta["date"], meta["pubdate"], meta["dc.date"])
+	if published == "" {
+		for _, t := range root.find(byTag("time")) {
+			if published = t.attrs["datetime"]; published != "" {
+				break
+			}
+		}
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, published); err == nil {
+			a.Published = t
+			break
+		}
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+// Package webpage fetches arbitrary article URLs — "best X of the year"
+// listicles, buying guides, blog posts — and turns each page's readable
+// content into a comment-less thread for extraction.
+package webpage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"hiveminer/internal/ratelimit"
+	"hiveminer/pkg/types"
+)
+
+// maxPageBytes caps how much of a page is read
+const maxPageBytes = 10 << 20
+
+// Fetcher fetches a web page as a thread
+type Fetcher interface {
+	Fetch(ctx context.Context, pageURL string) (*types.Thread, error)
+}
+
+// Client fetches pages over HTTP
+type Client struct {
+	client *http.Client
+}
+
+// NewClient returns a client for fetching pages
+func NewClient() *Client {
+	c := &Client{}
+	c.SetLimiter(ratelimit.NewLimiter(nil))
+	return c
+}
+
+// SetLimiter paces and counts the client's requests with a run's limiter
+func (c *Client) SetLimiter(l *ratelimit.Limiter) {
+	c.client = l.Client(ratelimit.SourceWeb, 30*time.Second)
+}
+
+// PostID returns a stable, filename-safe post ID for a page URL
+func PostID(pageURL string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(pageURL)))
+	return "web_" + hex.EncodeToString(sum[:6])
+}
+
+// Fetch fetches a page and returns its readable content as a thread with no
+// comments. The post text opens with where the page came from, so extraction
+// and anyone reading entries can tell an editorial source from a community
+// thread.
+func (c *Client) Fetch(ctx context.Context, pageURL string) (*types.Thread, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("not a web page URL: %s", pageURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; hiveminer)")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.8")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: HTTP %d", pageURL, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPageBytes))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", pageURL, err)
+	}
+
+	var article *Article
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "text/plain":
+		article = &Article{Text: string(data)}
+	case mediaType == "" || strings.Contains(mediaType, "html"):
+		article = readable(string(data))
+	default:
+		return nil, fmt.Errorf("%s is %s, not a web page", pageURL, mediaType)
+	}
+	if strings.TrimSpace(article.Text) == "" {
+		return nil, fmt.Errorf("no readable content on %s", pageURL)
+	}
+
+	host := strings.TrimPrefix(resp.Request.URL.Hostname(), "www.")
+	title := article.Title
+	if title == "" {
+		title = host + resp.Request.URL.Path
+	}
+	site := article.SiteName
+	if site == "" {
+		site = host
+	}
+	link := resp.Request.URL.String()
+	if strings.HasPrefix(article.Canonical, "http") {
+		link = article.Canonical
+	}
+	header := fmt.Sprintf("Web article from %s (%s)", site, link)
+	if article.Byline != "" {
+		header += " by " + article.Byline
+	}
+	var created float64
+	if !article.Published.IsZero() {
+		header += ", published " + article.Published.Format("2006-01-02")
+		created = float64(article.Published.Unix())
+	}
+
+	return &types.Thread{Post: types.Post{
+		ID:        PostID(pageURL),
+		Title:     title,
+		Selftext:  header + ".\n\n" + article.Text,
+		Author:    article.Byline,
+		Domain:    host,
+		Permalink: pageURL,
+		URL:       link,
+		Created:   created,
+		Source:    types.SourceWeb,
+	}}, nil
+}
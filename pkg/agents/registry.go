@@ -0,0 +1,176 @@
+// Package agents lets other packages ship their own extractors, thread
+// evaluators and rankers. A package registers a factory under a name in an
+// init function; a hiveminer binary built with that package imported selects
+// it with run --extractor, --eval-mode or --ranker.
+//
+//	func init() {
+//		agents.RegisterExtractor("recipes", func(cfg agents.Config) (agents.Extractor, error) {
+//			return newRulesExtractor(cfg.Params["rules"])
+//		})
+//	}
+package agents
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+
+	"belaykit"
+
+	"hiveminer/internal/agent"
+)
+
+// Interfaces and types agents implement and exchange, usable outside this
+// module
+type (
+	Extractor       = agent.Extractor
+	ThreadEvaluator = agent.ThreadEvaluator
+	Ranker          = agent.Ranker
+	EvalResult      = agent.EvalResult
+	RankInput       = agent.RankInput
+	RankOutput      = agent.RankOutput
+	Runner          = agent.Runner
+	ThreadFetcher   = agent.ThreadFetcher
+)
+
+// Config is what a factory builds an agent from: the run's settings for the
+// phase the agent serves
+type Config struct {
+	Runner  Runner // model backend for the phase
+	Prompts fs.FS  // prompt templates
+	Model   string
+	Logger  belaykit.EventHandler // may be nil
+	Backend string                // claude or codex
+	Fetcher ThreadFetcher         // fetches threads, for agents that read them themselves
+	Params  map[string]string     // run --agent-params, for the agent's own settings
+}
+
+// Factories build an agent from the run's configuration
+type (
+	ExtractorFactory func(Config) (Extractor, error)
+	EvaluatorFactory func(Config) (ThreadEvaluator, error)
+	RankerFactory    func(Config) (Ranker, error)
+)
+
+// Built-in agent names
+const (
+	Default = "agent" // the Claude or Codex agent
+	Lite    = "lite"  // single-prompt evaluation of a thread preview
+)
+
+var (
+	mu         sync.RWMutex
+	extractors = map[string]ExtractorFactory{}
+	evaluators = map[string]EvaluatorFactory{}
+	rankers    = map[string]RankerFactory{}
+)
+
+func init() {
+	RegisterExtractor(Default, func(cfg Config) (Extractor, error) {
+		return agent.NewClaudeExtractor(cfg.Runner, cfg.Prompts, cfg.Model, cfg.Logger, cfg.Backend), nil
+	})
+	RegisterEvaluator(Default, func(cfg Config) (ThreadEvaluator, error) {
+		return agent.NewClaudeEvaluator(cfg.Runner, cfg.Prompts, cfg.Model, cfg.Logger, cfg.Backend), nil
+	})
+	RegisterEvaluator(Lite, func(cfg Config) (ThreadEvaluator, error) {
+		if cfg.Fetcher == nil {
+			return nil, fmt.Errorf("lite evaluation needs a thread fetcher")
+		}
+		return agent.NewLiteEvaluator(cfg.Runner, cfg.Prompts, cfg.Model, cfg.Logger, cfg.Backend, cfg.Fetcher), nil
+	})
+	RegisterRanker(Default, func(cfg Config) (Ranker, error) {
+		return agent.NewClaudeRanker(cfg.Runner, cfg.Prompts, cfg.Model, cfg.Logger, cfg.Backend), nil
+	})
+}
+
+// RegisterExtractor makes an extractor available by name. It panics if the
+// name is empty or taken, like database/sql.Register.
+func RegisterExtractor(name string, f ExtractorFactory) {
+	register(extractors, "extractor", name, f)
+}
+
+// RegisterEvaluator makes a thread evaluator available by name
+func RegisterEvaluator(name string, f EvaluatorFactory) {
+	register(evaluators, "evaluator", name, f)
+}
+
+// RegisterRanker makes a ranker available by name
+func RegisterRanker(name string, f RankerFactory) {
+	register(rankers, "ranker", name, f)
+}
+
+// NewExtractor builds the extractor registered under name
+func NewExtractor(name string, cfg Config) (Extractor, error) {
+	f, err := lookup(extractors, "extractor", name)
+	if err != nil {
+		return nil, err
+	}
+	return f(cfg)
+}
+
+// NewEvaluator builds the thread evaluator registered under name
+func NewEvaluator(name string, cfg Config) (ThreadEvaluator, error) {
+	f, err := lookup(evaluators, "evaluator", name)
+	if err != nil {
+		return nil, err
+	}
+	return f(cfg)
+}
+
+// NewRanker builds the ranker registered under name
+func NewRanker(name string, cfg Config) (Ranker, error) {
+	f, err := lookup(rankers, "ranker", name)
+	if err != nil {
+		return nil, err
+	}
+	return f(cfg)
+}
+
+// Extractors lists the registered extractor names, sorted
+func Extractors() []string { return names(extractors) }
+
+// Evaluators lists the registered evaluator names, sorted
+func Evaluators() []string { return names(evaluators) }
+
+// Rankers lists the registered ranker names, sorted
+func Rankers() []string { return names(rankers) }
+
+func register[F any](registry map[string]F, kind, name string, f F) {
+	mu.Lock()
+	defer mu.Unlock()
+	if name == "" {
+		panic("agents: " + kind + " registered without a name")
+	}
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("agents: %s %q registered twice", kind, name))
+	}
+	registry[name] = f
+}
+
+func lookup[F any](registry map[string]F, kind, name string) (F, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	f, ok := registry[name]
+	if !ok {
+		var zero F
+		return zero, fmt.Errorf("unknown %s %q (registered: %s)", kind, name, strings.Join(namesLocked(registry), ", "))
+	}
+	return f, nil
+}
+
+func names[F any](registry map[string]F) []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return namesLocked(registry)
+}
+
+func namesLocked[F any](registry map[string]F) []string {
+	out := make([]string, 0, len(registry))
+	for name := range registry {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,88 @@
+// Package harness runs the hiveminer pipeline end to end without the
+// network or a model: Reddit is a fake Reddit API on a local test server,
+// ScriptedRunner answers agent prompts from a script, and NewOrchestrator
+// wires them into an orchestrator whose rounds, retries, resumes and circuit
+// breaker behave deterministically. Extension authors can drive their
+// registered agents through the same pipeline.
+//
+//	reddit := harness.NewReddit()
+//	defer reddit.Close()
+//	reddit.AddThread(&types.Thread{Post: types.Post{ID: "abc", Subreddit: "camping", Title: "Best tents?"}, Comments: comments})
+//
+//	runner := harness.NewScriptedRunner().Default("[]")
+//	runner.On(harness.EvaluatePrompt).Reply(harness.Keep(2))
+//	runner.On(harness.ExtractPrompt, "Best tents?").Reply(harness.Extraction(map[string]any{"name": "Duplex"}))
+//
+//	orch, err := harness.NewOrchestrator(reddit, runner, os.DirFS("prompts"), harness.Agents{})
+//	dir, err := orch.Run(ctx, harness.RunConfig{Form: form, Query: "tent", Subreddits: []string{"camping"}, Limit: 1, OutputDir: t.TempDir()})
+package harness
+
+import (
+	"fmt"
+	"io/fs"
+
+	"hiveminer/internal/orchestrator"
+	"hiveminer/internal/search"
+	"hiveminer/pkg/agents"
+)
+
+// Types the harness hands out, usable outside this module
+type (
+	Orchestrator = orchestrator.DefaultOrchestrator
+	RunConfig    = orchestrator.RunConfig
+	Pipeline     = orchestrator.Pipeline
+	Searcher     = search.RedditSearcher
+)
+
+// Agents names the registered agents an orchestrator uses for each phase;
+// empty names pick lite evaluation and the built-in extractor and ranker,
+// which each answer in a single prompt
+type Agents struct {
+	Evaluator string
+	Extractor string
+	Ranker    string
+	Model     string            // model the agents are run with (default "scripted")
+	Params    map[string]string // passed to the agents as run --agent-params
+}
+
+// NewOrchestrator returns an orchestrator searching the fake Reddit and
+// running every agent on runner
+func NewOrchestrator(reddit *Reddit, runner agents.Runner, prompts fs.FS, names Agents) (*Orchestrator, error) {
+	searcher := reddit.Searcher()
+	cfg := agents.Config{
+		Runner:  runner,
+		Prompts: prompts,
+		Model:   names.Model,
+		Backend: "claude",
+		Fetcher: searcher,
+		Params:  names.Params,
+	}
+	if cfg.Model == "" {
+		cfg.Model = "scripted"
+	}
+
+	orch := orchestrator.New(searcher)
+	evaluator, err := agents.NewEvaluator(or(names.Evaluator, agents.Lite), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("evaluator: %w", err)
+	}
+	orch.SetThreadEvaluator(evaluator)
+	extractor, err := agents.NewExtractor(or(names.Extractor, agents.Default), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("extractor: %w", err)
+	}
+	orch.SetExtractor(extractor)
+	ranker, err := agents.NewRanker(or(names.Ranker, agents.Default), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("ranker: %w", err)
+	}
+	orch.SetRanker(ranker)
+	return orch, nil
+}
+
+func or(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
@@ -0,0 +1,339 @@
+package harness
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"hiveminer/internal/search"
+	"hiveminer/pkg/types"
+)
+
+// Reddit is a fake of Reddit's JSON API on a local test server: subreddit
+// search and listings over the threads it was given, thread pages with
+// nested comments, "more comments" stubs and /api/morechildren to expand
+// them. Anything else is a 404. Requests can be made to fail on purpose to
+// exercise retries, mirror fallback and the circuit breaker.
+type Reddit struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	threads  []*types.Thread // in the order they were added
+	pageSize int             // top-level comments per thread page (0 = all)
+	faults   []*fault
+	requests []string
+}
+
+// fault answers matching requests with an error status
+type fault struct {
+	match  string // substring of the request URI
+	status int
+	left   int // responses still to fail; < 0 fails forever
+}
+
+// NewReddit starts a fake Reddit; Close stops it
+func NewReddit() *Reddit {
+	f := &Reddit{}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	return f
+}
+
+// URL returns the base URL serving the API, for HIVEMINER_REDDIT_URL
+func (f *Reddit) URL() string {
+	return f.server.URL
+}
+
+// Close shuts the server down
+func (f *Reddit) Close() {
+	f.server.Close()
+}
+
+// AddThread adds a thread to search, list and fetch. A post without a
+// permalink gets /r/<subreddit>/comments/<id>/, and its comment count is
+// taken from the comment tree when unset.
+func (f *Reddit) AddThread(thread *types.Thread) {
+	post := &thread.Post
+	if post.Permalink == "" {
+		post.Permalink = fmt.Sprintf("/r/%s/comments/%s/", post.Subreddit, post.ID)
+	}
+	if post.NumComments == 0 {
+		post.NumComments = search.CountComments(thread.Comments)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.threads = append(f.threads, thread)
+}
+
+// SetPageSize limits thread pages to n top-level comments; the rest, with
+// their replies, are collapsed into a "more" stub. 0 serves every comment.
+func (f *Reddit) SetPageSize(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pageSize = n
+}
+
+// Fail answers the next n requests whose URI contains match with status,
+// e.g. Fail("/search.json", 429, 2); n < 0 fails them all
+func (f *Reddit) Fail(match string, status, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = append(f.faults, &fault{match: match, status: status, left: n})
+}
+
+// Requests returns the URIs requested so far, in order
+func (f *Reddit) Requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.requests...)
+}
+
+// Count returns how many requests so far had a URI containing match
+func (f *Reddit) Count(match string) int {
+	n := 0
+	for _, uri := range f.Requests() {
+		if strings.Contains(uri, match) {
+			n++
+		}
+	}
+	return n
+}
+
+// Searcher returns a Reddit searcher reading from the fake, with no rate
+// cap and short retry backoffs so tests run fast
+func (f *Reddit) Searcher() *Searcher {
+	// Only a proxy or CA bundle can make the constructor fail
+	s, _ := search.NewRedditSearcherWithTransport(search.TransportConfig{Endpoint: f.URL()})
+	s.SetRetry(2, 10*time.Millisecond)
+	return s
+}
+
+func (f *Reddit) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	f.requests = append(f.requests, r.URL.RequestURI())
+	status := f.fault(r.URL.RequestURI())
+	f.mu.Unlock()
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, ".json")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	query := r.URL.Query()
+	limit, _ := strconv.Atoi(query.Get("limit"))
+
+	var body any
+	switch comments := slices.Index(parts, "comments"); {
+	case path == "/api/morechildren":
+		body = f.moreChildren(strings.TrimPrefix(query.Get("link_id"), "t3_"), strings.Split(query.Get("children"), ","))
+	case comments >= 0:
+		if comments+1 < len(parts) {
+			if thread := f.thread(parts[comments+1]); thread != nil {
+				body = f.threadPage(thread, limit)
+			}
+		}
+	case len(parts) == 3 && parts[0] == "r" && parts[2] == "search":
+		body = listing(f.search(parts[1], query.Get("q")), limit)
+	case len(parts) == 3 && parts[0] == "r" && parts[2] != "about":
+		body = listing(f.list(parts[1], parts[2]), limit)
+	}
+	if body == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// fault returns the status a request should fail with, or 0. Called with
+// f.mu held.
+func (f *Reddit) fault(uri string) int {
+	for _, ft := range f.faults {
+		if ft.left == 0 || !strings.Contains(uri, ft.match) {
+			continue
+		}
+		if ft.left > 0 {
+			ft.left--
+		}
+		return ft.status
+	}
+	return 0
+}
+
+// search returns the subreddit's posts ("all" for every subreddit) whose
+// title or text contains every word of the query
+func (f *Reddit) search(sub, q string) []types.Post {
+	words := strings.Fields(strings.ToLower(strings.NewReplacer(`"`, " ", "(", " ", ")", " ").Replace(q)))
+	var posts []types.Post
+	for _, post := range f.list(sub, "") {
+		text := strings.ToLower(post.Title + " " + post.Selftext)
+		matched := true
+		for _, w := range words {
+			if !strings.Contains(text, w) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			posts = append(posts, post)
+		}
+	}
+	return posts
+}
+
+// list returns the subreddit's posts: newest first for "new", highest
+// scored first for "top", and in the order they were added otherwise
+func (f *Reddit) list(sub, sortBy string) []types.Post {
+	f.mu.Lock()
+	var posts []types.Post
+	for _, t := range f.threads {
+		if sub == "all" || strings.EqualFold(t.Post.Subreddit, sub) {
+			posts = append(posts, t.Post)
+		}
+	}
+	f.mu.Unlock()
+	switch sortBy {
+	case "new":
+		sort.SliceStable(posts, func(i, j int) bool { return posts[i].Created > posts[j].Created })
+	case "top":
+		sort.SliceStable(posts, func(i, j int) bool { return posts[i].Score > posts[j].Score })
+	}
+	return posts
+}
+
+func (f *Reddit) thread(id string) *types.Thread {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, t := range f.threads {
+		if t.Post.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// threadPage renders a thread as Reddit does: a listing holding the post,
+// then a listing of the comment tree. Top-level comments past the page size
+// or limit are collapsed into one "more" stub listing them and their replies.
+func (f *Reddit) threadPage(t *types.Thread, limit int) any {
+	f.mu.Lock()
+	page := f.pageSize
+	f.mu.Unlock()
+	if limit > 0 && (page == 0 || limit < page) {
+		page = limit
+	}
+
+	postFullname := "t3_" + t.Post.ID
+	shown, collapsed := t.Comments, []*types.Comment(nil)
+	if page > 0 && len(shown) > page {
+		shown, collapsed = t.Comments[:page], t.Comments[page:]
+	}
+	children := make([]any, 0, len(shown)+1)
+	for _, c := range shown {
+		children = append(children, commentThing(c, postFullname, 0, true))
+	}
+	if len(collapsed) > 0 {
+		var ids []string
+		walkComments(collapsed, postFullname, func(c *types.Comment, _ string, _ int) {
+			ids = append(ids, c.ID)
+		})
+		children = append(children, map[string]any{
+			"kind": "more",
+			"data": map[string]any{"id": ids[0], "parent_id": postFullname, "count": len(ids), "children": ids, "depth": 0},
+		})
+	}
+	return []any{
+		map[string]any{"kind": "Listing", "data": map[string]any{"children": []any{postThing(t.Post)}}},
+		map[string]any{"kind": "Listing", "data": map[string]any{"children": children}},
+	}
+}
+
+// moreChildren expands collapsed comment IDs into flat comments naming their
+// parents, as /api/morechildren does
+func (f *Reddit) moreChildren(postID string, ids []string) any {
+	want := map[string]bool{}
+	for _, id := range ids {
+		want[id] = true
+	}
+	things := []any{}
+	if t := f.thread(postID); t != nil {
+		walkComments(t.Comments, "t3_"+postID, func(c *types.Comment, parent string, depth int) {
+			if want[c.ID] {
+				things = append(things, commentThing(c, parent, depth, false))
+			}
+		})
+	}
+	return map[string]any{"json": map[string]any{"data": map[string]any{"things": things}}}
+}
+
+// walkComments visits a comment tree depth first, parents before replies
+func walkComments(comments []*types.Comment, parent string, visit func(c *types.Comment, parent string, depth int)) {
+	var walk func([]*types.Comment, string, int)
+	walk = func(cs []*types.Comment, parent string, depth int) {
+		for _, c := range cs {
+			visit(c, parent, depth)
+			walk(c.Replies, "t1_"+c.ID, depth+1)
+		}
+	}
+	walk(comments, parent, 0)
+}
+
+// listing renders posts as a Reddit listing of at most limit posts
+func listing(posts []types.Post, limit int) any {
+	if limit > 0 && len(posts) > limit {
+		posts = posts[:limit]
+	}
+	children := make([]any, len(posts))
+	for i, p := range posts {
+		children[i] = postThing(p)
+	}
+	return map[string]any{"kind": "Listing", "data": map[string]any{"children": children}}
+}
+
+func postThing(p types.Post) any {
+	return map[string]any{"kind": "t3", "data": map[string]any{
+		"id":              p.ID,
+		"title":           p.Title,
+		"score":           p.Score,
+		"num_comments":    p.NumComments,
+		"domain":          p.Domain,
+		"permalink":       p.Permalink,
+		"selftext":        p.Selftext,
+		"url":             p.URL,
+		"author":          p.Author,
+		"subreddit":       p.Subreddit,
+		"over_18":         p.NSFW,
+		"created_utc":     p.Created,
+		"link_flair_text": p.Flair,
+	}}
+}
+
+// commentThing renders a comment, with its replies nested when nested is set
+func commentThing(c *types.Comment, parent string, depth int, nested bool) any {
+	data := map[string]any{
+		"id":          c.ID,
+		"body":        c.Body,
+		"author":      c.Author,
+		"score":       c.Score,
+		"created_utc": c.Created,
+		"permalink":   c.Permalink,
+		"parent_id":   parent,
+		"depth":       depth,
+		"replies":     "",
+	}
+	if nested && len(c.Replies) > 0 {
+		replies := make([]any, len(c.Replies))
+		for i, r := range c.Replies {
+			replies[i] = commentThing(r, "t1_"+c.ID, depth+1, true)
+		}
+		data["replies"] = map[string]any{"kind": "Listing", "data": map[string]any{"children": replies}}
+	}
+	return map[string]any{"kind": "t1", "data": data}
+}
@@ -0,0 +1,215 @@
+package harness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"belaykit"
+)
+
+// Substrings identifying the prompts of the pipeline's single-prompt agents,
+// for matching them in a ScriptedRunner
+const (
+	EvaluatePrompt = "deciding from a preview whether a Reddit thread" // lite evaluation
+	ExtractPrompt  = "extracting structured information from a Reddit thread"
+	RankPrompt     = "quality and diversity assessor"
+)
+
+// ScriptedRunner is an agent runner that answers prompts from a script
+// instead of a model. Each rule matches prompts containing all of its
+// substrings and replies with its responses in turn, repeating the last one.
+// Rules are tried in the order they were added; a prompt no rule matches
+// gets the default response, or an error if there is none.
+type ScriptedRunner struct {
+	mu       sync.Mutex
+	rules    []*Rule
+	fallback *response
+	calls    []Call
+}
+
+// Rule is one entry of a script
+type Rule struct {
+	runner    *ScriptedRunner
+	match     []string
+	responses []response
+	used      int
+}
+
+type response struct {
+	text string
+	err  error
+}
+
+// Call is a prompt the runner was given
+type Call struct {
+	Prompt string
+	Model  string
+}
+
+// NewScriptedRunner returns a runner with an empty script
+func NewScriptedRunner() *ScriptedRunner {
+	return &ScriptedRunner{}
+}
+
+// On adds a rule for prompts containing every one of match
+func (s *ScriptedRunner) On(match ...string) *Rule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := &Rule{runner: s, match: match}
+	s.rules = append(s.rules, r)
+	return r
+}
+
+// Default sets the reply to prompts no rule matches
+func (s *ScriptedRunner) Default(text string) *ScriptedRunner {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fallback = &response{text: text}
+	return s
+}
+
+// Reply adds a response with the given text
+func (r *Rule) Reply(text string) *Rule {
+	return r.add(response{text: text})
+}
+
+// ReplyJSON adds a response of v encoded as JSON
+func (r *Rule) ReplyJSON(v any) *Rule {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return r.Fail(fmt.Errorf("encoding scripted reply: %w", err))
+	}
+	return r.Reply(string(data))
+}
+
+// Fail adds a response failing with err, as a backend error would
+func (r *Rule) Fail(err error) *Rule {
+	return r.add(response{err: err})
+}
+
+func (r *Rule) add(resp response) *Rule {
+	r.runner.mu.Lock()
+	defer r.runner.mu.Unlock()
+	r.responses = append(r.responses, resp)
+	return r
+}
+
+// Run answers the prompt from the script, passing the reply to the run's
+// event handler as a result event
+func (s *ScriptedRunner) Run(ctx context.Context, prompt string, opts ...belaykit.RunOption) (belaykit.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return belaykit.Result{}, err
+	}
+	cfg := belaykit.NewRunConfig(opts...)
+
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Prompt: prompt, Model: cfg.Model})
+	resp := s.next(prompt)
+	s.mu.Unlock()
+
+	if resp == nil {
+		return belaykit.Result{}, fmt.Errorf("no scripted response for prompt %q", excerpt(prompt))
+	}
+	if resp.err != nil {
+		return belaykit.Result{}, resp.err
+	}
+	if cfg.EventHandler != nil {
+		cfg.EventHandler(belaykit.Event{Type: belaykit.EventResult, Text: resp.text})
+	}
+	return belaykit.Result{Text: resp.text}, nil
+}
+
+// next picks the response to a prompt. Called with s.mu held.
+func (s *ScriptedRunner) next(prompt string) *response {
+	for _, r := range s.rules {
+		if len(r.responses) == 0 || !containsAll(prompt, r.match) {
+			continue
+		}
+		i := min(r.used, len(r.responses)-1)
+		r.used++
+		return &r.responses[i]
+	}
+	return s.fallback
+}
+
+// Calls returns the prompts run so far, in order
+func (s *ScriptedRunner) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// Count returns how many prompts so far contained every one of match
+func (s *ScriptedRunner) Count(match ...string) int {
+	n := 0
+	for _, c := range s.Calls() {
+		if containsAll(c.Prompt, match) {
+			n++
+		}
+	}
+	return n
+}
+
+// Keep is a lite evaluation verdict keeping a thread expected to yield
+// estimate entries
+func Keep(estimate int) string {
+	return fmt.Sprintf(`{"verdict": "keep", "reason": "scripted", "estimated_entries": %d}`, estimate)
+}
+
+// Skip is a lite evaluation verdict skipping a thread
+func Skip(reason string) string {
+	data, _ := json.Marshal(map[string]any{"verdict": "skip", "reason": reason, "estimated_entries": 0})
+	return string(data)
+}
+
+// Extraction is an extraction reply with one entry per map of field IDs to
+// values, each extracted with confidence 0.9
+func Extraction(entries ...map[string]any) string {
+	type field struct {
+		ID         string  `json:"id"`
+		Value      any     `json:"value"`
+		Confidence float64 `json:"confidence"`
+	}
+	type entry struct {
+		Fields []field `json:"fields"`
+	}
+	out := struct {
+		Entries []entry `json:"entries"`
+	}{Entries: []entry{}}
+	for _, values := range entries {
+		ids := make([]string, 0, len(values))
+		for id := range values {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		var e entry
+		for _, id := range ids {
+			e.Fields = append(e.Fields, field{ID: id, Value: values[id], Confidence: 0.9})
+		}
+		out.Entries = append(out.Entries, e)
+	}
+	data, _ := json.Marshal(out)
+	return string(data)
+}
+
+func containsAll(s string, subs []string) bool {
+	for _, sub := range subs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
+
+// excerpt returns the start of a prompt for error messages
+func excerpt(prompt string) string {
+	prompt = strings.Join(strings.Fields(prompt), " ")
+	if r := []rune(prompt); len(r) > 80 {
+		return string(r[:80]) + "…"
+	}
+	return prompt
+}
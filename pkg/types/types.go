@@ -16,8 +16,24 @@ type Post struct {
 	Subreddit   string  `json:"subreddit"`
 	NSFW        bool    `json:"over_18"`
 	Created     float64 `json:"created_utc"`
+	Flair       string  `json:"link_flair_text,omitempty"`
+	Source      string  `json:"source,omitempty"` // empty for regular threads, see Source* constants
+
+	// DiscoveryReason is why the discovery agent picked the post
+	DiscoveryReason string `json:"discovery_reason,omitempty"`
 }
 
+// Source types for content that flows through extraction as a Thread.
+// Regular Reddit threads leave Source empty.
+const (
+	SourceWiki       = "wiki"
+	SourceSidebar    = "sidebar"
+	SourceUser       = "user"       // a page of one user's comment history
+	SourceReviews    = "reviews"    // a product's marketplace reviews, one comment per review
+	SourceTranscript = "transcript" // a podcast or video transcript, one comment per timestamped segment
+	SourceWeb        = "web"        // an article's readable content, no comments
+)
+
 // Comment represents a Reddit comment
 type Comment struct {
 	ID        string     `json:"id"`
@@ -44,6 +60,9 @@ const (
 	FieldTypeNumber  FieldType = "number"
 	FieldTypeBoolean FieldType = "boolean"
 	FieldTypeArray   FieldType = "array"
+	// FieldTypeLocation is a place name, geocoded to coordinates when the
+	// run's geocode phase is enabled
+	FieldTypeLocation FieldType = "location"
 )
 
 // Field represents a single field in a form schema
@@ -53,7 +72,15 @@ type Field struct {
 	Question    string    `json:"question"`
 	SearchHints []string  `json:"search_hints,omitempty"`
 	Required    bool      `json:"required,omitempty"`
+	Critical    bool      `json:"critical,omitempty"` // entries missing this field score zero in ranking
+	Primary     bool      `json:"primary,omitempty"`  // identifies an entry's item for dedup, merging and display
 	Internal    bool      `json:"internal,omitempty"` // Don't show in viewer
+	// Group names related fields extracted in the same prompt when
+	// extraction is decomposed (run --decompose)
+	Group string `json:"group,omitempty"`
+	// DependsOn lists fields to extract before this one, such as the item
+	// name a price belongs to; the prompt extracts fields in stages
+	DependsOn []string `json:"depends_on,omitempty"`
 }
 
 // Form represents a complete extraction form schema
@@ -61,59 +88,441 @@ type Form struct {
 	Title       string   `json:"title"`
 	Description string   `json:"description"`
 	SearchHints []string `json:"search_hints,omitempty"`
+	CommentSort string   `json:"comment_sort,omitempty"` // comment order threads are fetched in: top, best, new, ... (empty = Reddit default)
 	Fields      []Field  `json:"fields"`
+
+	// Saturation tunes the penalty for many entries from one thread; nil uses
+	// the default (-5 per extra entry, capped at -30)
+	Saturation *SaturationPolicy `json:"saturation,omitempty"`
+
+	// EntryCap limits how many entries one thread may contribute; nil uses
+	// the default (25, near-duplicates merged first)
+	EntryCap *EntryCapPolicy `json:"entry_cap,omitempty"`
+
+	// SourceWeights scale the algorithmic score of entries by where their
+	// thread came from ("reddit", "web", "reviews", ...); unlisted sources
+	// weigh 1
+	SourceWeights map[string]float64 `json:"source_weights,omitempty"`
+
+	// PostProcess is a script or webhook each extracted entry is passed
+	// through before ranking; nil keeps entries as extracted
+	PostProcess *PostProcessHook `json:"post_process,omitempty"`
+
+	// Flags is the quality flag taxonomy the ranker assigns from; empty uses
+	// the default (spam, joke, outdated, low_effort, off_topic)
+	Flags []FlagDef `json:"flags,omitempty"`
+
+	// Exclusions describe what the user doesn't want; they're given to
+	// discovery, evaluation and extraction, and matching entries are dropped
+	Exclusions []Exclusion `json:"exclusions,omitempty"`
+
+	// Audience describes who the results are for; every prompt receives it
+	// and entries outside the budget are dropped
+	Audience *Audience `json:"audience,omitempty"`
+
+	// Entity says what one entry is; the extraction prompt is told, and a
+	// thread's entries naming the same entity are merged after extraction
+	Entity *Entity `json:"entity,omitempty"`
+}
+
+// Entity defines a form's entry granularity, e.g. "one entry per distinct
+// hotel, not per comment or per hotel chain"
+type Entity struct {
+	Description string   `json:"description"`
+	Key         []string `json:"key,omitempty"` // fields that together identify an entry (default: the primary field)
+}
+
+// Audience holds structured context about the user a form's results are for
+type Audience struct {
+	Budget    *Budget `json:"budget,omitempty"`
+	Region    string  `json:"region,omitempty"`    // e.g. "Pacific Northwest, US"
+	Household string  `json:"household,omitempty"` // e.g. "two adults, kids aged 4 and 7"
+	Timeframe string  `json:"timeframe,omitempty"` // e.g. "summer 2026"
+}
+
+// Budget is a price range. With Field set, entries whose value for that
+// number field falls outside the range (give or take Tolerance, a fraction)
+// are dropped after extraction.
+type Budget struct {
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	Currency  string   `json:"currency,omitempty"`
+	Field     string   `json:"field,omitempty"`
+	Tolerance float64  `json:"tolerance,omitempty"`
+}
+
+// Exclusion is a negative constraint on a form ("not cruises"). Entries with
+// a field value containing one of Terms (the description itself when none
+// are given) are dropped after extraction.
+type Exclusion struct {
+	Description string   `json:"description"`
+	Terms       []string `json:"terms,omitempty"`  // case-insensitive words or phrases matched against values
+	Fields      []string `json:"fields,omitempty"` // fields the terms are matched against (default: all)
+}
+
+// SaturationPolicy shapes the same-thread penalty: a thread's best Free
+// entries are untouched and each further entry is penalized Step more than
+// the last, up to Cap
+type SaturationPolicy struct {
+	Disabled bool    `json:"disabled,omitempty"` // no same-thread penalty, e.g. for megathread-driven forms
+	Free     int     `json:"free,omitempty"`     // entries per thread before the penalty starts (default 1)
+	Step     float64 `json:"step,omitempty"`     // penalty added per further entry (default 5)
+	Cap      float64 `json:"cap,omitempty"`      // maximum penalty (default 30)
+}
+
+// EntryCapPolicy caps the entries extracted from a single thread. Over the
+// cap, "merge" folds entries naming the same item together and flags what
+// still doesn't fit; "flag" only flags the weakest entries past the cap.
+type EntryCapPolicy struct {
+	Max      int    `json:"max,omitempty"`      // entries per thread (default 25)
+	Overflow string `json:"overflow,omitempty"` // merge or flag (default merge)
+}
+
+// PostProcessHook sends each extracted entry to a command (once per entry,
+// JSON on stdin and stdout) or a webhook (JSON POST), which may return it
+// normalized or enriched, or reject it. Exactly one of Command and URL is set.
+type PostProcessHook struct {
+	Command []string `json:"command,omitempty"`  // executable and arguments; relative paths resolve against the form file
+	URL     string   `json:"url,omitempty"`      // http(s) endpoint
+	Timeout string   `json:"timeout,omitempty"`  // per entry (default 30s)
+	OnError string   `json:"on_error,omitempty"` // keep or reject the entry when the hook fails (default keep)
+}
+
+// FlagDef defines a quality flag the ranker may assign to an entry
+type FlagDef struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"` // minor, moderate or severe
 }
 
 // Evidence represents a quote from a thread supporting an extracted value
 type Evidence struct {
-	Text      string `json:"text"`
-	CommentID string `json:"comment_id,omitempty"`
-	Author    string `json:"author,omitempty"`
-	Score     int    `json:"score,omitempty"`
+	Text      string  `json:"text"`
+	CommentID string  `json:"comment_id,omitempty"`
+	Author    string  `json:"author,omitempty"`
+	Score     int     `json:"score,omitempty"`       // the cited comment's Reddit score
+	Created   float64 `json:"created_utc,omitempty"` // the cited comment's creation time (Unix seconds)
 }
 
 // FieldValue represents an extracted field value
 type FieldValue struct {
-	ID         string     `json:"id"`
+	ID           string             `json:"id"`
+	Value        any                `json:"value"`
+	Confidence   float64            `json:"confidence"`
+	Evidence     []Evidence         `json:"evidence,omitempty"`
+	Links        []string           `json:"links,omitempty"`
+	Reasoning    string             `json:"reasoning,omitempty"`
+	AnsweredBy   string             `json:"answered_by,omitempty"`  // op, commenters or mixed; see AnsweredBy* constants
+	Alternatives []AlternativeValue `json:"alternatives,omitempty"` // conflicting values from duplicate entries
+	Aggregate    *NumericAggregate  `json:"aggregate,omitempty"`    // spread of numeric values across duplicates
+	Geo          *GeoPoint          `json:"geo,omitempty"`          // coordinates of a geocoded location value
+	Agreement    *float64           `json:"agreement,omitempty"`    // share of ensemble models extracting this value, 0-1
+}
+
+// Availability statuses reported by enrichment resolvers
+const (
+	AvailabilityInStock      = "in_stock"
+	AvailabilityOutOfStock   = "out_of_stock"
+	AvailabilityDiscontinued = "discontinued"
+	AvailabilityUnknown      = "unknown"
+)
+
+// Availability is a product's price and stock at a retailer
+type Availability struct {
+	Status    string       `json:"status"` // see Availability* constants
+	Price     *float64     `json:"price,omitempty"`
+	Currency  string       `json:"currency,omitempty"`
+	URL       string       `json:"url,omitempty"`
+	Source    string       `json:"source"` // retailer that answered
+	CheckedAt time.Time    `json:"checked_at"`
+	History   []PricePoint `json:"history,omitempty"` // earlier checks' prices, oldest first
+}
+
+// PricePoint is a price seen by one availability check
+type PricePoint struct {
+	At       time.Time `json:"at"`
+	Price    float64   `json:"price"`
+	Currency string    `json:"currency,omitempty"`
+	Source   string    `json:"source"`
+}
+
+// GeoPoint is a geocoded place
+type GeoPoint struct {
+	Lat  float64 `json:"lat"`
+	Lon  float64 `json:"lon"`
+	Name string  `json:"name,omitempty"` // the provider's normalized place name
+}
+
+// NumericAggregate summarizes the numeric values reported for a field across
+// merged duplicate entries
+type NumericAggregate struct {
+	Count  int     `json:"count"`
+	Min    float64 `json:"min"`
+	Median float64 `json:"median"`
+	Max    float64 `json:"max"`
+}
+
+// Attribution of a field value's evidence to the thread's author (OP) or to
+// other commenters
+const (
+	AnsweredByOP         = "op"
+	AnsweredByCommenters = "commenters"
+	AnsweredByMixed      = "mixed"
+)
+
+// AlternativeValue is a value for a field that a duplicate entry reported
+// differently from the primary value
+type AlternativeValue struct {
 	Value      any        `json:"value"`
 	Confidence float64    `json:"confidence"`
 	Evidence   []Evidence `json:"evidence,omitempty"`
 	Links      []string   `json:"links,omitempty"`
-	Reasoning  string     `json:"reasoning,omitempty"`
+	ThreadID   string     `json:"thread_id,omitempty"` // post the duplicate entry came from
+}
+
+// Provenance records which configuration produced an extracted entry
+type Provenance struct {
+	Model       string    `json:"model,omitempty"`
+	Backend     string    `json:"backend,omitempty"`
+	PromptHash  string    `json:"prompt_hash,omitempty"` // hash of the extraction prompt template
+	RunID       string    `json:"run_id,omitempty"`      // RunLog.InvocationID
+	ExtractedAt time.Time `json:"extracted_at"`
 }
 
 // Entry represents a single distinct item extracted from a thread.
 // For example, one destination recommendation with all its associated fields.
 type Entry struct {
-	Fields     []FieldValue `json:"fields"`
-	Links      []string     `json:"links,omitempty"`
-	RankScore  *float64     `json:"rank_score,omitempty"`
-	RankFlags  []string     `json:"rank_flags,omitempty"`
-	RankReason string       `json:"rank_reason,omitempty"`
+	// ID identifies the entry across re-extraction, ranking, review and
+	// export; it is derived from the thread and the entry's primary value
+	ID        string       `json:"id,omitempty"`
+	Fields    []FieldValue `json:"fields"`
+	Links     []string     `json:"links,omitempty"`
+	RankScore *float64     `json:"rank_score,omitempty"`
+	RawScore  *float64     `json:"raw_score,omitempty"`  // ranker's score before session-wide normalization
+	RankOrder int          `json:"rank_order,omitempty"` // position in the session, 1 = best; breaks score ties
+	// TournamentRank is the entry's place in the head-to-head comparison of
+	// the session's top entries, when that stage ran (1 = best)
+	TournamentRank int         `json:"tournament_rank,omitempty"`
+	RankFlags      []string    `json:"rank_flags,omitempty"`
+	RankReason     string      `json:"rank_reason,omitempty"`
+	Provenance     *Provenance `json:"provenance,omitempty"`
+	Consensus      *Consensus  `json:"consensus,omitempty"`
+
+	// SummaryDerived marks entries extracted only from summaries of an
+	// oversized thread's comments rather than from the comments themselves
+	SummaryDerived bool `json:"summary_derived,omitempty"`
+
+	// Overflow marks entries past their thread's entry cap; they are kept
+	// but left out of ranking
+	Overflow bool `json:"overflow,omitempty"`
+
+	// Availability is the product's current price and stock as last checked
+	// by the enrich phase
+	Availability *Availability `json:"availability,omitempty"`
+
+	// Ensemble records how many models of an ensemble extraction found the
+	// entry
+	Ensemble *Ensemble `json:"ensemble,omitempty"`
+
+	// Verification is the verify phase's check of the entry against its
+	// thread
+	Verification *Verification `json:"verification,omitempty"`
+
+	// AntiRecommendation marks entries whose evidence recommends against
+	// the item; they're kept as warnings rather than ranked as picks
+	AntiRecommendation *AntiRecommendation `json:"anti_recommendation,omitempty"`
+
+	// Caveats are the problems and drawbacks commenters raised about the
+	// item, gathered from every thread once duplicates are merged
+	Caveats []Caveat `json:"caveats,omitempty"`
+}
+
+// Caveat is a problem or drawback commenters raised about an entry's item,
+// such as battery issues
+type Caveat struct {
+	Issue      string     `json:"issue"`
+	Commenters int        `json:"commenters"`        // distinct commenters raising it
+	Threads    []string   `json:"threads,omitempty"` // IDs of the threads it was raised in
+	Evidence   []Evidence `json:"evidence,omitempty"`
+}
+
+// What marked an entry as an anti-recommendation
+const (
+	AntiSourceExtract   = "extract"   // the extraction model
+	AntiSourceVerify    = "verify"    // the verify phase
+	AntiSourceHeuristic = "heuristic" // phrases like "avoid" or "/s" in every quote
+)
+
+// AntiRecommendation records why an entry's evidence advises against its item
+type AntiRecommendation struct {
+	Source string `json:"source"` // see AntiSource* constants
+	Reason string `json:"reason,omitempty"`
+}
+
+// Verification verdicts
+const (
+	VerdictSupported   = "supported"   // the thread backs the entry
+	VerdictPartial     = "partial"     // some field values aren't in the thread
+	VerdictUnsupported = "unsupported" // the thread doesn't recommend the item
+)
+
+// Verification is a second model's review of an extracted entry against the
+// raw thread
+type Verification struct {
+	Verdict string `json:"verdict"` // see Verdict* constants
+	// Issues name why the entry may mislead: joke, sarcasm, negation or
+	// hypothetical
+	Issues []string `json:"issues,omitempty"`
+	// Fields maps each field whose value the thread doesn't support to why
+	Fields map[string]string `json:"fields,omitempty"`
+	// AntiRecommendation is set when the evidence advises against the item
+	AntiRecommendation bool      `json:"anti_recommendation,omitempty"`
+	Reason             string    `json:"reason,omitempty"`
+	Model              string    `json:"model,omitempty"`
+	VerifiedAt         time.Time `json:"verified_at"`
+}
+
+// Ensemble is an entry's agreement across the models that extracted its
+// thread
+type Ensemble struct {
+	Models []string `json:"models"` // the models run
+	Found  int      `json:"found"`  // how many of them extracted the entry
+}
+
+// Consensus summarizes how commenters reacted to an entry's item
+type Consensus struct {
+	Endorsements int     `json:"endorsements"`
+	Warnings     int     `json:"warnings"`
+	NetAgreement float64 `json:"net_agreement"` // (endorsements - warnings) / total, -1 to 1
 }
 
 // ExtractionResult holds all extracted entries for a thread.
 // Each entry represents one distinct recommendation/item found in the thread.
 type ExtractionResult struct {
 	Entries []Entry `json:"entries"`
+
+	// PromptTokens estimates the prompt tokens sent, over every call made
+	PromptTokens int `json:"prompt_tokens,omitempty"`
+	// Summarized counts comments that reached extraction only as summaries
+	Summarized int `json:"summarized,omitempty"`
+}
+
+// ContextUsage records how much of a thread's discussion reached extraction,
+// so results from partial views of long threads can be told apart
+type ContextUsage struct {
+	Comments     int `json:"comments"`             // comments on the thread, per its source
+	Fetched      int `json:"fetched"`              // comments fetched
+	Sent         int `json:"sent"`                 // comments sent to extraction word for word
+	Summarized   int `json:"summarized,omitempty"` // further comments sent only as summaries
+	PromptTokens int `json:"prompt_tokens"`        // estimated, over every extraction call
+}
+
+// Truncated returns how many of the thread's comments extraction never saw
+func (c *ContextUsage) Truncated() int {
+	return max(c.Comments-c.Sent-c.Summarized, 0)
+}
+
+// Coverage returns the share of the thread's comments extraction saw, word
+// for word or summarized; threads without comments are fully covered
+func (c *ContextUsage) Coverage() float64 {
+	if c.Comments == 0 {
+		return 1
+	}
+	return float64(c.Comments-c.Truncated()) / float64(c.Comments)
 }
 
 // ThreadState represents the extraction state of a single thread
 type ThreadState struct {
-	PostID      string        `json:"post_id"`
-	Permalink   string        `json:"permalink"`
-	Title       string        `json:"title"`
-	Subreddit   string        `json:"subreddit"`
-	Score       int           `json:"score"`
-	NumComments int           `json:"num_comments"`
-	Status      string        `json:"status"` // pending, collected, extracted, ranked, failed
-	CollectedAt *time.Time    `json:"collected_at,omitempty"`
-	ExtractedAt *time.Time    `json:"extracted_at,omitempty"`
-	RankedAt    *time.Time    `json:"ranked_at,omitempty"`
-	Entries     []Entry        `json:"entries,omitempty"`
-	Error       string        `json:"error,omitempty"`
+	PostID      string     `json:"post_id"`
+	Permalink   string     `json:"permalink"`
+	Title       string     `json:"title"`
+	Subreddit   string     `json:"subreddit"`
+	Score       int        `json:"score"`
+	NumComments int        `json:"num_comments"`
+	Created     float64    `json:"created_utc,omitempty"` // when the post was made (Unix seconds)
+	Source      string     `json:"source,omitempty"`
+	Pinned      bool       `json:"pinned,omitempty"` // on the session watch list: never skipped, always refreshed
+	Status      string     `json:"status"`           // see Status* constants
+	CollectedAt *time.Time `json:"collected_at,omitempty"`
+	ExtractedAt *time.Time `json:"extracted_at,omitempty"`
+	RankedAt    *time.Time `json:"ranked_at,omitempty"`
+	Entries     []Entry    `json:"entries,omitempty"` // inline only in manifests from before entry files
+	Error       string     `json:"error,omitempty"`
+	ErrorCode   string     `json:"error_code,omitempty"` // failure class of a failed thread, see Err* constants
+
+	// Attempts counts the runs in which processing this thread failed; at
+	// the run's max attempts it is quarantined instead of retried
+	Attempts int `json:"attempts,omitempty"`
+	// ErrorHistory keeps the most recent failures as "code: message"
+	ErrorHistory []string `json:"error_history,omitempty"`
+
+	// DiscoveryReason is why the discovery agent picked the thread
+	DiscoveryReason string `json:"discovery_reason,omitempty"`
+	// EstimatedEntries is how many entries evaluation expected the thread
+	// to yield when it kept it
+	EstimatedEntries int `json:"estimated_entries,omitempty"`
+	// SkipReason is why evaluation or a reviewer skipped the thread
+	SkipReason string `json:"skip_reason,omitempty"`
+
+	// EntriesFile is where the manifest keeps the thread's entries, relative
+	// to the session directory; Entries is loaded from it
+	EntriesFile string `json:"entries_file,omitempty"`
+	EntryCount  int    `json:"entry_count,omitempty"`
+	// ProvisionalEntryIDs marks entry IDs given on load, before the form's
+	// primary field was known; session.SettleEntryIDs rederives them
+	ProvisionalEntryIDs bool `json:"provisional_entry_ids,omitempty"`
+
+	// Context is how much of the thread its last extraction saw
+	Context *ContextUsage `json:"context,omitempty"`
+}
+
+// Thread statuses, in pipeline order
+const (
+	StatusPending     = "pending"     // discovered, not yet evaluated
+	StatusCollected   = "collected"   // kept by evaluation and fetched
+	StatusExtracted   = "extracted"   // entries extracted
+	StatusRanked      = "ranked"      // entries extracted and scored
+	StatusSkipped     = "skipped"     // rejected by evaluation or by hand
+	StatusFailed      = "failed"      // failed this run; retried on resume
+	StatusQuarantined = "quarantined" // failed too often to retry
+)
+
+// ThreadStatuses lists every thread status this version knows, in pipeline
+// order. Sessions written by other versions may hold statuses not listed.
+var ThreadStatuses = []string{
+	StatusPending, StatusCollected, StatusExtracted, StatusRanked,
+	StatusSkipped, StatusFailed, StatusQuarantined,
 }
 
+// KnownStatus reports whether a thread status is in ThreadStatuses
+func KnownStatus(status string) bool {
+	for _, s := range ThreadStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// HasEntries reports whether threads in a status hold extracted entries
+func HasEntries(status string) bool {
+	return status == StatusExtracted || status == StatusRanked
+}
+
+// Failure classes recorded in ThreadState.ErrorCode. Timeouts and other
+// failures are recorded per stage: fetch_timeout, eval_failed, ...
+const (
+	ErrFetch429        = "fetch_429"        // rate limited by Reddit
+	ErrFetch403        = "fetch_403"        // blocked or private
+	ErrFetch404        = "fetch_404"        // deleted or wrong permalink
+	ErrFetch5xx        = "fetch_5xx"        // Reddit server error
+	ErrFetchBudget     = "fetch_budget"     // request budget exhausted
+	ErrParseFailure    = "parse_failure"    // agent response or payload couldn't be parsed
+	ErrContextOverflow = "context_overflow" // prompt exceeded the model's context window
+	ErrWriteFailed     = "write_failed"     // thread payload couldn't be saved
+	ErrUnknown         = "unknown"          // failed before error codes were recorded
+)
+
 // FormRef holds reference to the form used in a session
 type FormRef struct {
 	Title string `json:"title"`
@@ -128,6 +537,75 @@ type RunLog struct {
 	CompletedAt      time.Time `json:"completed_at,omitempty"`
 	Status           string    `json:"status"` // running, completed, interrupted, failed
 	ThreadsProcessed int       `json:"threads_processed"`
+
+	// Requests counts the run's Reddit requests by kind (search, thread, ...),
+	// including those made by agent subprocesses
+	Requests map[string]int `json:"requests,omitempty"`
+
+	// SourceRequests counts the run's requests by site (reddit, amazon, web,
+	// ...), so mixing sources can be checked against each site's limits
+	SourceRequests map[string]int `json:"source_requests,omitempty"`
+
+	// Truncation is the comment truncation strategy threads were pruned with
+	Truncation *TruncationStrategy `json:"truncation,omitempty"`
+
+	// MalformedAssessments lists ranker assessments rejected by validation
+	MalformedAssessments []string `json:"malformed_assessments,omitempty"`
+
+	// Ranking rolls up what the run's ranking pass did
+	Ranking *RankingSummary `json:"ranking,omitempty"`
+
+	// Phases times each phase the run finished, in order
+	Phases []PhaseTiming `json:"phases,omitempty"`
+	// LLMCalls counts the run's agent calls, when the backend reports them
+	LLMCalls int `json:"llm_calls,omitempty"`
+}
+
+// PhaseTiming is how long one phase of a run took and how much it got through
+type PhaseTiming struct {
+	Name     string  `json:"name"`
+	Seconds  float64 `json:"seconds"`
+	Threads  int     `json:"threads,omitempty"`   // threads added or processed
+	Entries  int     `json:"entries,omitempty"`   // entries ranked
+	LLMCalls int     `json:"llm_calls,omitempty"` // agent calls made during the phase
+}
+
+// RankingSummary rolls up a ranking pass, to show whether it actually
+// differentiated the entries
+type RankingSummary struct {
+	Entries    int            `json:"entries"`
+	Duplicates int            `json:"duplicates"`      // penalized as duplicates of a better entry
+	Saturated  int            `json:"saturated"`       // penalized for sharing a thread with better entries
+	Flagged    int            `json:"flagged"`         // carrying a quality flag
+	Flags      map[string]int `json:"flags,omitempty"` // entries per flag, review flags included
+
+	// Final score distribution
+	Min    float64 `json:"min"`
+	Q1     float64 `json:"q1"`
+	Median float64 `json:"median"`
+	Q3     float64 `json:"q3"`
+	Max    float64 `json:"max"`
+
+	// Batches records how each assessment batch was scored
+	Batches []RankBatch `json:"batches,omitempty"`
+}
+
+// RankBatch records the path one ranking assessment batch took: the model
+// whose assessment was applied, after the attempts that failed. Batches
+// without a model kept their algorithmic scores.
+type RankBatch struct {
+	Entries  int      `json:"entries"`
+	Model    string   `json:"model,omitempty"`
+	Failures []string `json:"failures,omitempty"` // "model: error" per failed attempt, in order
+}
+
+// TruncationStrategy decides which comments of a thread reach extraction
+type TruncationStrategy struct {
+	Name             string `json:"name"`                          // "flat" (keep what was fetched) or "score"
+	TopLevelMinScore int    `json:"top_level_min_score,omitempty"` // top-level comments at or above this are always kept
+	HighScore        int    `json:"high_score,omitempty"`          // direct replies to comments at or above this are kept
+	MinScore         int    `json:"min_score,omitempty"`           // other replies below this are dropped with their subtree
+	MaxDepth         int    `json:"max_depth,omitempty"`           // replies deeper than this are dropped unless they score HighScore
 }
 
 // Manifest tracks the complete state of an extraction session
@@ -137,10 +615,89 @@ type Manifest struct {
 	Query                string        `json:"query,omitempty"`
 	Subreddits           []string      `json:"subreddits"`
 	DiscoveredSubreddits bool          `json:"discovered_subreddits,omitempty"`
+	SubredditReview      string        `json:"subreddit_review,omitempty"` // pending or done, when discovered subreddits are reviewed
 	Threads              []ThreadState `json:"threads"`
 	Runs                 []RunLog      `json:"runs"`
-	CreatedAt            time.Time     `json:"created_at"`
-	UpdatedAt            time.Time     `json:"updated_at"`
+	// DiscoveryLog records the searches thread discovery made, to audit why
+	// threads were chosen
+	DiscoveryLog []DiscoverySearch `json:"discovery_log,omitempty"`
+	// SubredditYield is how productive each subreddit's threads were
+	SubredditYield map[string]SubredditYield `json:"subreddit_yield,omitempty"`
+	// Settings holds the run flags the session was last run with (models,
+	// limits, ranking options, ...), reapplied by --session and runs clone
+	Settings  map[string]string `json:"settings,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// DiscoverySearch is one search made while discovering threads
+type DiscoverySearch struct {
+	Run       string    `json:"run,omitempty"` // invocation ID of the run that searched
+	Round     int       `json:"round,omitempty"`
+	Method    string    `json:"method"` // agent, search or listing
+	Query     string    `json:"query,omitempty"`
+	Subreddit string    `json:"subreddit,omitempty"`
+	Results   int       `json:"results"`
+	At        time.Time `json:"at"`
+}
+
+// SubredditYield is how productive a subreddit's threads have been for a form
+type SubredditYield struct {
+	Threads  int     `json:"threads"`             // threads evaluated
+	Skipped  int     `json:"skipped"`             // rejected by evaluation
+	Entries  int     `json:"entries"`             // entries extracted
+	Scored   int     `json:"scored,omitempty"`    // entries ranked
+	ScoreSum float64 `json:"score_sum,omitempty"` // sum of their rank scores
+}
+
+// YieldHistory keeps each session's subreddit yields for a form, so later
+// sessions can favor the productive subreddits
+type YieldHistory struct {
+	Form     string                               `json:"form"`
+	Sessions map[string]map[string]SubredditYield `json:"sessions"`
+}
+
+// SkippedThread records a thread rejected by evaluation or a reviewer
+type SkippedThread struct {
+	PostID    string    `json:"post_id"`
+	Permalink string    `json:"permalink,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	By        string    `json:"by"` // evaluation, reviewer
+	SkippedAt time.Time `json:"skipped_at"`
+}
+
+// SkipReasonGroup is a cluster of similar skip reasons: Reason is the one
+// most like the rest, Threads how many skipped threads gave one of them
+type SkipReasonGroup struct {
+	Reason  string `json:"reason"`
+	Threads int    `json:"threads"`
+}
+
+// FormIndex lists the sessions in a form's folder of the output directory,
+// for browsing it and for tools that don't read manifests
+type FormIndex struct {
+	Form      string           `json:"form"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	Sessions  []FormIndexEntry `json:"sessions"`
+}
+
+// FormIndexEntry summarizes one session in a form index
+type FormIndexEntry struct {
+	Name      string    `json:"name"`
+	Query     string    `json:"query,omitempty"`
+	Status    string    `json:"status"` // the last run's status, empty before the first run
+	Threads   int       `json:"threads"`
+	Entries   int       `json:"entries"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SkipList holds threads rejected for a form across sessions, so discovery
+// doesn't resurface them in future runs
+type SkipList struct {
+	Form    string                   `json:"form"`
+	Threads map[string]SkippedThread `json:"threads"`
 }
 
 // TokenUsage tracks API token usage